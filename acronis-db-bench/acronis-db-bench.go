@@ -4,18 +4,26 @@ package main
 
 import (
 	"fmt"
+	"math"
 	"net/http"
 	"runtime"
 	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	_ "net/http/pprof"
 
+	"github.com/google/uuid"
+
 	"github.com/acronis/perfkit/benchmark"
+	"github.com/acronis/perfkit/benchmark/report"
 	"github.com/acronis/perfkit/db"
+	"github.com/acronis/perfkit/db/pgmbed"
 
-	_ "github.com/acronis/perfkit/db/es"  // es drivers
-	_ "github.com/acronis/perfkit/db/sql" // sql drivers
+	_ "github.com/acronis/perfkit/db/es"     // es drivers
+	_ "github.com/acronis/perfkit/db/scylla" // scylla driver
+	_ "github.com/acronis/perfkit/db/sql"    // sql drivers
 
 	events "github.com/acronis/perfkit/acronis-db-bench/event-bus"
 	tenants "github.com/acronis/perfkit/acronis-db-bench/tenants-cache"
@@ -58,11 +66,79 @@ type BenchOpts struct {
 	TenantConnString  string `long:"tenants-storage-connection-string" description:"connection string for tenant storage" required:"false"`
 	ParquetDataSource string `long:"parquet-data-source" description:"path to the parquet file" required:"false"`
 	CTIsWorkingSet    int    `long:"ctis-working-set" description:"set CTI working set" required:"false" default:"1000"`
-	ProfilerPort      int    `long:"profiler-port" description:"open profiler on given port (e.g. 6060)" required:"false" default:"0"`
+	TenantsPreWarm    bool   `long:"tenants-pre-warm" description:"look up all the tenants of the working set in the database before the test starts" required:"false"`
+	CTIsPreWarm       bool   `long:"ctis-pre-warm" description:"look up all the CTIs of the working set in the database before the test starts" required:"false"`
+	ProfilerPort      int    `long:"profiler-port" description:"open profiler on given port (e.g. 6060), the DB connection pool status is served at /debug/pool" required:"false" default:"0"`
+	PrometheusPort    int    `long:"prometheus-port" description:"serve the Prometheus metrics of the executed tests at /metrics on given port (e.g. 9090)" required:"false" default:"0"`
 	Describe          bool   `long:"describe" description:"describe what test is going to do" required:"false"`
 	DescribeAll       bool   `long:"describe-all" description:"describe all the tests" required:"false"`
+	DescribeMarkdown  string `long:"describe-markdown" description:"write the Markdown reference of all the tests to given file and exit" required:"false"`
 	Explain           bool   `long:"explain" description:"prepend the test queries by EXPLAIN ANALYZE" required:"false"`
+	CollectTableStats bool   `long:"collect-table-stats" description:"collect the test table index usage statistics before and after the test and show the difference" required:"false"`
 	Query             string `short:"q" long:"query" description:"execute given query, one can use:\n{CTI} - for random CTI UUID\n{TENANT} - randon tenant UUID"`
+
+	TenantHotspotFraction float64 `long:"tenant-hotspot-fraction" description:"pick the tenants of the working set by the Zipf distribution in the tenant-aware tests, the given fraction (e.g. 0.1) of them are the hot tenants (0 - uniform selection)" required:"false" default:"0"`
+	TenantHotspotWeight   float64 `long:"tenant-hotspot-weight" description:"how many times more often the hottest tenant is queried than the first tenant out of the hot ones, see --tenant-hotspot-fraction" required:"false" default:"10"`
+
+	PoolStatusInterval int `long:"pool-status-interval" description:"log the DB connection pool status of every worker each given amount of seconds (0 - disabled)" required:"false" default:"0"`
+
+	MaxErrors          int     `long:"max-errors" description:"stop the test after given amount of non-fatal errors (0 - unlimited)" required:"false" default:"0"`
+	ErrorRateThreshold float64 `long:"error-rate-threshold" description:"stop the test if the percentage of loops resulted in errors exceeds given value (0 - unlimited)" required:"false" default:"0"`
+
+	ExportS3Bucket  string `long:"export-s3-bucket" description:"S3 bucket to export the results as Parquet file to" required:"false"`
+	ExportS3Key     string `long:"export-s3-key" description:"S3 object key of the exported results Parquet file" required:"false"`
+	ExportGCSBucket string `long:"export-gcs-bucket" description:"GCS bucket to export the results as Parquet file to" required:"false"`
+	ExportGCSObject string `long:"export-gcs-object" description:"GCS object name of the exported results Parquet file" required:"false"`
+
+	InfluxDBURL    string `long:"influxdb-url" description:"write the score of every executed test to InfluxDB at given URL (e.g. http://localhost:8086) as the 'perfkit_benchmark' measurement" required:"false"`
+	InfluxDBToken  string `long:"influxdb-token" description:"InfluxDB API token, see --influxdb-url" required:"false"`
+	InfluxDBOrg    string `long:"influxdb-org" description:"InfluxDB organization, see --influxdb-url" required:"false"`
+	InfluxDBBucket string `long:"influxdb-bucket" description:"InfluxDB bucket, see --influxdb-url" required:"false"`
+
+	OutputFile   string `long:"output-file" description:"append the result of every test as JSON line to given file (e.g. results.json)" required:"false"`
+	OutputFormat string `long:"output-format" description:"output format, 'json' disables the human-readable output except the fatal errors and prints the JSON lines if --output-file is not set" choice:"text" choice:"json" required:"false" default:"text"`
+
+	HTMLReport string `long:"html-report" description:"write the self-contained HTML report with the rate chart, the --percentiles latencies and the test parameters of all the executed tests to given file (e.g. report.html)" required:"false"`
+
+	BenchmarkName string `long:"benchmark-name" description:"name of the benchmark run stored in the results metadata" required:"false"`
+	BenchmarkTags string `long:"benchmark-tags" description:"comma separated key=value tags stored in the results metadata (e.g. environment=staging,commit=abc123)" required:"false"`
+	BenchmarkID   string `long:"benchmark-id" description:"ID of the benchmark run embedded in the header and the results to correlate them with external events (random UUID if not set)" required:"false"`
+
+	PrefetchIDs bool `long:"prefetch-ids" description:"pre-load up to 100000 random IDs of the test table before the SELECT test and cycle through them instead of generating random IDs" required:"false"`
+
+	ReadOnlyTxn bool `long:"read-only-txn" description:"run the SELECT tests queries in read-only transactions (snapshot isolation for MSSQL)" required:"false"`
+	AutoAnalyze bool `long:"auto-analyze" description:"refresh the query planner statistics (ANALYZE / UPDATE STATISTICS) of the benchmark tables before the test" required:"false"`
+
+	DefragmentAfterInsert bool `long:"defragment-after-insert" description:"defragment the benchmark tables after the 'all' test insert and update phase and measure the SELECT rate improvement (select-after-defrag)" required:"false"`
+
+	LockWaitStats bool `long:"lock-wait-stats" description:"sample the DB lock waits every 5 seconds during the test and report the lock wait incidents (PostgreSQL and MySQL only)" required:"false"`
+
+	CheckpointMonitor bool `long:"checkpoint-monitor" description:"sample the DB checkpoint stats before and after the test and report the difference (PostgreSQL only)" required:"false"`
+
+	ShowActiveQueriesInterval time.Duration `long:"show-active-queries-interval" description:"dump the DB queries running longer than 100ms every given interval during the test (e.g. 5s), 0 means disabled (PostgreSQL, MySQL and MSSQL only)" required:"false" default:"0"`
+
+	Percentiles bool    `long:"percentiles" description:"measure every test loop latency and show the average, p50, p95, p99 and p99.9 latencies of the test" required:"false"`
+	RateLimit   float64 `long:"rate-limit" description:"limit every worker to given number of loops per second to measure the latencies at the controlled load rather than the saturation (0 - unlimited)" required:"false" default:"0"`
+
+	RampupDuration int `long:"rampup-duration" description:"start the workers one by one evenly over given amount of seconds before every measurement, the ramp-up loops are not accounted in the score (0 - start all the workers at once)" required:"false" default:"0"`
+
+	TestMatrix   bool   `long:"test-matrix" description:"run the --test for every worker count of the --workers-range and show how the rate and latencies scale" required:"false"`
+	WorkersRange string `long:"workers-range" description:"worker counts of the --test-matrix as min:max:step (e.g. 1:64:2 runs 1, 3, 5, ..., 63 workers)" required:"false" default:"1:16:1"`
+
+	ChaosMode     bool `long:"chaos-mode" description:"stop and restart the embedded PostgreSQL every --chaos-interval seconds during the test and report the recovery time (embedded PostgreSQL only)" required:"false"`
+	ChaosInterval int  `long:"chaos-interval" description:"interval in seconds between the --chaos-mode DB restarts" required:"false" default:"30"`
+
+	BackgroundLoadTest string `long:"background-load-test" description:"run given test continuously in the background while the --test is measured and compare the --test rate with and without the background load" required:"false"`
+	BackgroundWorkers  int    `long:"background-workers" description:"number of workers of the --background-load-test" required:"false" default:"4"`
+
+	Plugin string `long:"plugin" description:"load the tests implemented by given Go plugin (built with -buildmode=plugin and exporting the BenchmarkPlugin variable implementing benchmark.Plugin)" required:"false"`
+
+	Suite string `long:"suite" description:"run the tests of given YAML file one by one, every entry sets the test, workers, loops, duration, batch and optional label, the geomean of every label is shown at the end" required:"false"`
+
+	RealisticData bool   `long:"realistic-data" description:"generate realistic values (names, emails, phones, cities, etc.) by gofakeit for the columns of the matching type or name (e.g. 'name' or 'email' string column) instead of the random ones" required:"false"`
+	DataLocale    string `long:"data-locale" description:"locale of the --realistic-data values" required:"false" default:"en_US"`
+
+	TransactionBatchSize int `long:"transaction-batch-size" description:"run given number of the worker operations in a single transaction to measure the benefit of the larger transactions (1 - every operation in its own transaction)" required:"false" default:"1"`
 }
 
 // CTIOpts is a structure to store all the CTI options
@@ -72,8 +148,50 @@ type CTIOpts struct {
 
 // TestcaseOpts is a structure to store all the test case options
 type TestcaseOpts struct {
-	MinBlobSize int `long:"min-blob-size" description:"defines min blob size for the 'insert-blob' test (default 0)" required:"false" default:"0"`
-	MaxBlobSize int `long:"max-blob-size" description:"defines max blob size for the 'insert-blob' test (default 52428800)" required:"false" default:"52428800"`
+	MinBlobSize   int `long:"min-blob-size" description:"defines min blob size for the 'insert-blob' test (default 0)" required:"false" default:"0"`
+	MaxBlobSize   int `long:"max-blob-size" description:"defines max blob size for the 'insert-blob' test (default 52428800)" required:"false" default:"52428800"`
+	BlobChunkSize int `long:"blob-chunk-size" description:"defines the chunk size the large object is read by in the 'select-blob-streaming' test (default 65536)" required:"false" default:"65536"`
+
+	PaginationIncludeCount bool `long:"pagination-include-count" description:"issue the COUNT query before every page in the 'select-medium-paginated' test" required:"false"`
+
+	RecursionMaxDepth int `long:"recursion-max-depth" description:"defines max tenants hierarchy depth traversed by the 'select-heavy-recursive-cte' test (default 10)" required:"false" default:"10"`
+
+	MVRefreshInterval int `long:"mv-refresh-interval" description:"refresh the 'heavy' table materialized view every given amount of seconds during the 'insert-heavy' test (PostgreSQL only, 0 - disabled)" required:"false" default:"0"`
+
+	PGParallelWorkers int `long:"pg-parallel-workers" description:"defines max_parallel_workers_per_gather of the 'select-heavy-parallel-scan' and 'select-heavy-parallel-group-by' tests (default 4)" required:"false" default:"4"`
+
+	MySQLForceIndexMerge bool `long:"mysql-force-index-merge" description:"FORCE INDEX on the 'heavy' table tenant_id and state indexes in the 'select-heavy-index-merge' test" required:"false"`
+
+	VerifyPredicatePushdown bool `long:"verify-predicate-pushdown" description:"fail the 'select-heavy-predicate-pushdown' test if the query plan doesn't apply the minmax data skipping index" required:"false"`
+
+	TrgmPatternLength int `long:"trgm-pattern-length" description:"defines the LIKE pattern length of the 'select-heavy-trigram' test, pg_trgm needs at least 3 characters to use the index (default 3)" required:"false" default:"3"`
+
+	ExplainComparison bool `long:"explain-comparison" description:"print the query plans of both the 'select-heavy-exists' and 'select-heavy-count-subq' tests before the test" required:"false"`
+
+	QueueWorkers int `long:"queue-workers" description:"defines the queue consumers count of the 'select-heavy-readpast-mssql' test (0 - use --concurrency)" required:"false" default:"0"`
+
+	SingleTenantPerWorker bool `long:"single-tenant-per-worker" description:"assign a distinct tenant of the working set to every worker, so the worker inserts and selects rows of its own tenant only" required:"false"`
+
+	MixedReadPct int `long:"mixed-read-pct" description:"defines the percentage of the workers running SELECT in the 'insert-medium-concurrent-updates' test, the rest of the workers run INSERT (default 50)" required:"false" default:"50"`
+
+	UniqueViolationsPct int `long:"unique-violations-pct" description:"defines the percentage of rows re-using the last inserted unique key in the 'insert-medium-unique-violations' test (default 10)" required:"false" default:"10"`
+
+	ConflictRate float64 `long:"conflict-rate" description:"defines the share of rows re-using the id of already existing row (0..1) in the 'upsert-light' and 'upsert-heavy' tests (default 0.5)" required:"false" default:"0.5"`
+
+	TSAggregationWindow int `long:"ts-aggregation-window" description:"defines the look-back window in hours of the 'select-ts-sql-aggregated' test (default 24)" required:"false" default:"24"`
+
+	JSONDocumentSizeBytes int `long:"json-document-size-bytes" description:"defines the JSON document size of the 'insert-json-document-store' test (default 8192)" required:"false" default:"8192"`
+
+	FTSDocLength int `long:"fts-doc-length" description:"defines the number of words in the documents of the 'insert-fts' test (default 100)" required:"false" default:"100"`
+
+	JSONPathExpression string `long:"json-path-expression" description:"defines the jsonpath expression used by the 'select-json-path' test" required:"false" default:"$.field0.field0[*] ? (@ == 10)"`
+
+	JSONSchema string `long:"json-schema" description:"path to JSON Schema (draft-07) file, the 'insert-json' and 'insert-blob' tests fill the 'json' and 'blob' columns with the JSON documents conforming to the schema" required:"false"`
+
+	OSKNNEngine string `long:"os-knn-engine" description:"defines the OpenSearch k-NN engine of the 'os vector' table used by the 'insert-os-vector' and 'select-os-knn' tests (faiss, nmslib or lucene)" required:"false" default:"faiss"`
+	OSKNNDims   int    `long:"os-knn-dims" description:"defines the vector dimensions of the 'os vector' table used by the 'insert-os-vector' and 'select-os-knn' tests (default 128)" required:"false" default:"128"`
+
+	VectorDims int `long:"vector-dims" description:"defines the vector dimensions of the 'pgvector' table used by the 'insert-vector' and 'select-vector-knn' tests (default 768)" required:"false" default:"768"`
 }
 
 // DBTestData is a structure to store all the test data
@@ -83,23 +201,48 @@ type DBTestData struct {
 	TenantsCache   *tenants.TenantsCache
 	EffectiveBatch int // EffectiveBatch reflects the default value if the --batch option is not set, it can be different for different tests
 
-	scores map[string][]benchmark.Score
+	scores    map[string][]benchmark.Score
+	results   []BenchmarkResult
+	metadata  BenchmarkMetadata
+	id        string // see --benchmark-id
+	lockWaits uint64 // lock wait incidents sampled during the last test, see --lock-wait-stats
+
+	dbDriver  db.DialectName // see --output-file
+	dbVersion string
+
+	reportScores []report.Score // see --html-report
+
+	metrics *benchmarkMetrics // set if --prometheus-port is set
+	influx  *influxExporter   // set if --influxdb-url is set
+
+	checkpointStats db.CheckpointStats // checkpoints made during the last test, see --checkpoint-monitor
+
+	chaos      *chaosMonkey // set while the --chaos-mode DB restarts are running
+	chaosStats chaosStats   // DB outages made during the last test, see --chaos-mode
+
+	prefetchedIDs []uint64 // random IDs of the test table, see --prefetch-ids
+
+	compareBaseline []BenchmarkResult // results of the --connection-string tests, set while the tests run against the --compare-dsn
+	compareStart    int               // index of the first --compare-dsn test result
+
+	backgroundLoadTest        string  // set while the test runs under the --background-load-test
+	rateWithoutBackgroundLoad float64 // rate of the test measured before the --background-load-test is started
 }
 
 // DBWorkerData is a structure to store all the worker data
 type DBWorkerData struct {
 	workingConn  *DBConnector
 	tenantsCache *DBConnector
+
+	prefetchedIDsPos int // position of the worker in the --prefetch-ids ring buffer
+
+	shardQueriesBefore *db.Stats // ScyllaDB shard queries counters at the test start, see --scylla-shard-count
 }
 
 var header = strings.Repeat("=", 120) + "\n"
 
 // Main is the main function of the acronis-db-bench
 func Main() {
-	fmt.Printf(header) //nolint:staticcheck
-
-	printVersion()
-
 	b := benchmark.New()
 
 	b.AddOpts = func() benchmark.TestOpts {
@@ -128,6 +271,67 @@ func Main() {
 
 		fmt.Printf(format, testData.TestDesc.name, testData.TestDesc.table.RowsCount, score.Seconds, score.Workers, score.Loops,
 			b.Vault.(*DBTestData).EffectiveBatch, score.FormatRate(4), score.Metric)
+
+		if !score.RampupStart.IsZero() {
+			fmt.Printf("test: %s; ramp-up: first worker started at %s; all %d workers running at %s (%.1f sec)\n", testData.TestDesc.name,
+				score.RampupStart.Format("15:04:05.000"), score.Workers, score.RampupEnd.Format("15:04:05.000"), score.RampupEnd.Sub(score.RampupStart).Seconds())
+		}
+
+		if score.WarmupSeconds > 0 {
+			fmt.Printf("test: %s; warmup: %.1f sec; measured: %.1f sec\n", testData.TestDesc.name, score.WarmupSeconds, score.Seconds)
+		}
+
+		if limit := b.TestOpts.(*TestOpts).BenchOpts.RateLimit; limit > 0 && score.Seconds > 0 {
+			fmt.Printf("test: %s; rate limit: %.1f loops/sec per worker (%.1f loops/sec total); achieved: %.1f loops/sec\n",
+				testData.TestDesc.name, limit, limit*float64(score.Workers), float64(score.Loops)/score.Seconds)
+		}
+
+		if score.Retries > 0 {
+			fmt.Printf("test: %s; retries: %d\n", testData.TestDesc.name, score.Retries)
+		}
+
+		if score.Errors > 0 {
+			fmt.Printf("test: %s; errors: %d; error rate: %.2f%%\n", testData.TestDesc.name, score.Errors, score.ErrorRate())
+		}
+
+		if score.BytesProcessed > 0 {
+			fmt.Printf("test: %s; bytes: %d; throughput: %s\n", testData.TestDesc.name, score.BytesProcessed, score.FormatThroughput())
+		}
+
+		if score.ConstraintViolationRate > 0 {
+			fmt.Printf("test: %s; constraint violations: %.1f/sec\n", testData.TestDesc.name, score.ConstraintViolationRate)
+		}
+
+		if b.CommonOpts.TraceMemory {
+			fmt.Printf("test: %s; allocs/op: %.1f; bytes/op: %.0f\n", testData.TestDesc.name, score.AllocsPerOp, score.BytesPerOp)
+		}
+
+		if testData.compareBaseline != nil {
+			// the tests are executed in the same order against both databases
+			if i := len(testData.results) - testData.compareStart; i < len(testData.compareBaseline) && testData.compareBaseline[i].Rate > 0 {
+				fmt.Printf("test: %s; baseline rate: %.1f %s; speedup: %.2fx\n", testData.TestDesc.name,
+					testData.compareBaseline[i].Rate, score.Metric, score.Rate/testData.compareBaseline[i].Rate)
+			}
+		}
+
+		if testData.metrics != nil {
+			testData.metrics.update(testData.TestDesc.name, string(testData.dbDriver), score)
+		}
+
+		if testData.influx != nil {
+			testData.influx.write(testData.TestDesc.name, string(testData.dbDriver), testData.dbVersion, score)
+		}
+
+		if local, cross := getShardQueries(b); local+cross > 0 {
+			fmt.Printf("test: %s; shard-local queries: %.1f%%; cross-shard queries: %.1f%%\n", testData.TestDesc.name,
+				float64(local)*100/float64(local+cross), float64(cross)*100/float64(local+cross))
+		}
+
+		if b.TestOpts.(*TestOpts).BenchOpts.Percentiles && len(score.Latencies) > 0 {
+			fmt.Printf("test: %s; %s\n", testData.TestDesc.name, score.FormatPercentiles())
+		} else if len(score.Latencies) > 0 {
+			fmt.Printf("test: %s; avg latency: %.3f ms; p95 latency: %.3f ms\n", testData.TestDesc.name, durationMs(score.LatencyAvg), durationMs(score.P95))
+		}
 	}
 
 	b.InitOpts()
@@ -137,6 +341,36 @@ func Main() {
 		b.Exit("db type conversion error")
 	}
 
+	testOpts.DBOpts.ConnString = driverConnString(testOpts.DBOpts.ConnString, testOpts.DBOpts.Driver)
+
+	if testOpts.BenchOpts.Plugin != "" {
+		if err := loadTestPlugin(testOpts.BenchOpts.Plugin); err != nil {
+			b.Exit("failed to load --plugin: %v", err)
+		}
+	}
+
+	if testOpts.BenchOpts.TenantHotspotFraction < 0 || testOpts.BenchOpts.TenantHotspotFraction >= 1 || testOpts.BenchOpts.TenantHotspotWeight < 1 {
+		b.Exit("--tenant-hotspot-fraction must be within [0, 1) range and --tenant-hotspot-weight must be >= 1")
+	}
+
+	if testOpts.BenchOpts.RealisticData {
+		var err error
+		if b.Faker, err = benchmark.NewFakerGenerator(testOpts.BenchOpts.DataLocale); err != nil {
+			b.Exit("failed to set up --realistic-data: %v", err)
+		}
+	}
+
+	if testOpts.BenchOpts.OutputFormat == "json" {
+		silenceStdout(b)
+	}
+
+	b.CollectLatencies = testOpts.BenchOpts.Percentiles || testOpts.BenchOpts.PrometheusPort > 0 || testOpts.BenchOpts.InfluxDBURL != ""
+
+	if testOpts.BenchOpts.RampupDuration < 0 {
+		b.Exit("--rampup-duration must not be negative")
+	}
+	b.Rampup = time.Duration(testOpts.BenchOpts.RampupDuration) * time.Second
+
 	d := DBTestData{}
 	b.Vault = &d
 
@@ -146,13 +380,52 @@ func Main() {
 		d.scores[s] = []benchmark.Score{}
 	}
 
+	tags, err := parseBenchmarkTags(testOpts.BenchOpts.BenchmarkTags)
+	if err != nil {
+		b.Exit("failed to parse --benchmark-tags: %v", err)
+	}
+	d.metadata = BenchmarkMetadata{Name: testOpts.BenchOpts.BenchmarkName, Tags: tags}
+
+	d.id = testOpts.BenchOpts.BenchmarkID
+	if d.id == "" {
+		d.id = uuid.NewString()
+	}
+
+	fmt.Printf(header) //nolint:staticcheck
+	fmt.Printf("Benchmark ID: %s\n", d.id)
+	printVersion()
+
+	switch testOpts.TestcaseOpts.OSKNNEngine {
+	case "faiss", "nmslib", "lucene":
+	default:
+		b.Exit("--os-knn-engine must be one of faiss, nmslib or lucene")
+	}
+	if testOpts.TestcaseOpts.OSKNNDims <= 0 {
+		b.Exit("--os-knn-dims must be > 0")
+	}
+	osKNNEngine, osKNNDims = testOpts.TestcaseOpts.OSKNNEngine, testOpts.TestcaseOpts.OSKNNDims
+
+	if testOpts.TestcaseOpts.VectorDims <= 0 {
+		b.Exit("--vector-dims must be > 0")
+	}
+	vectorDims = testOpts.TestcaseOpts.VectorDims
+
+	if testOpts.BenchOpts.ChaosMode {
+		if _, epOpts, epErr := pgmbed.ParseOptions(testOpts.DBOpts.ConnString); epErr != nil || !epOpts.Enabled {
+			b.Exit("--chaos-mode requires the embedded PostgreSQL (embedded-postgres=true connection string parameter)")
+		}
+		if testOpts.BenchOpts.ChaosInterval <= 0 {
+			b.Exit("--chaos-interval must be > 0")
+		}
+	}
+
 	if b.TestOpts.(*TestOpts).BenchOpts.Batch > 0 {
 		b.Vault.(*DBTestData).EffectiveBatch = b.TestOpts.(*TestOpts).BenchOpts.Batch
 	} else {
 		b.Vault.(*DBTestData).EffectiveBatch = 1
 	}
 
-	var dialectName, err = db.GetDialectName(b.TestOpts.(*TestOpts).DBOpts.ConnString)
+	dialectName, err := db.GetDialectName(b.TestOpts.(*TestOpts).DBOpts.ConnString)
 	if err != nil {
 		b.Exit("failed to get dialect name: %v", err)
 	}
@@ -194,6 +467,35 @@ func Main() {
 		b.Exit()
 	}
 
+	if testOpts.BenchOpts.DescribeMarkdown != "" {
+		if err = writeTestsMarkdown(testOpts.BenchOpts.DescribeMarkdown); err != nil {
+			b.Exit(err)
+		}
+		b.Exit()
+	}
+
+	if testOpts.DBOpts.LogConnectionEvents {
+		if connEvents, err = benchmark.NewEventLogger(connEventsLogFile); err != nil {
+			b.Exit(err)
+		}
+		defer connEvents.Close() //nolint:errcheck
+	}
+
+	if testOpts.DBOpts.QueryLogFile != "" {
+		if queriesLog, err = newQueryLog(testOpts.DBOpts.QueryLogFile); err != nil {
+			b.Exit(err)
+		}
+
+		// the benchmark always ends up by b.Exit(), so the log is flushed right before the exit
+		b.PreExit = func() {
+			var summary, closeErr = queriesLog.Close()
+			if closeErr != nil {
+				fmt.Printf("failed to close the query log: %v\n", closeErr)
+			}
+			fmt.Printf("\nQUERY LOG SUMMARY (%s):\n\n%s\n\n", testOpts.DBOpts.QueryLogFile, strings.Join(summary, "\n"))
+		}
+	}
+
 	if testOpts.BenchOpts.Cleanup {
 		cleanupTables(b)
 		b.Exit()
@@ -205,19 +507,34 @@ func Main() {
 	}
 
 	if testOpts.DBOpts.Reconnect {
+		var reconnect = func(c *DBConnector) {
+			c.Close() //nolint:errcheck
+			if err := c.Connect(); err != nil {
+				b.Exit("db: cannot reconnect: %v", err)
+			}
+		}
+
 		b.PreWorker = func(workerId int) {
 			var workerData = b.WorkerData[workerId].(*DBWorkerData)
 
 			if workerData.workingConn != nil {
-				workerData.workingConn.database.Close()
+				reconnect(workerData.workingConn)
 			}
 
 			if workerData.tenantsCache != nil {
-				workerData.tenantsCache.database.Close()
+				reconnect(workerData.tenantsCache)
 			}
 		}
 	}
 
+	var sandbox *DBConnector
+	if testOpts.DBOpts.SchemaSandbox != "" {
+		sandbox = schemaSandboxConnector(b)
+		if err = sandbox.database.CreateSchema(testOpts.DBOpts.SchemaSandbox); err != nil {
+			b.Exit("db: cannot create sandbox schema '%s': %v", testOpts.DBOpts.SchemaSandbox, err)
+		}
+	}
+
 	c := dbConnector(b)
 
 	driver, version, err := c.database.GetVersion()
@@ -225,7 +542,12 @@ func Main() {
 		b.Exit("Failed to get database version: %v", err)
 	}
 
+	d.dbDriver, d.dbVersion = driver, version
+
 	fmt.Printf("Connected to '%s' database: %s\n", driver, version)
+	if affinity := b.AffinityMap(); affinity != "" {
+		fmt.Printf("Worker affinity (worker->CPU): %s\n", affinity)
+	}
 	fmt.Printf(header) //nolint:staticcheck
 
 	content, dbInfo, err := c.database.GetInfo(version)
@@ -245,7 +567,23 @@ func Main() {
 		}
 	}
 
+	if target := testOpts.DBOpts.PGCheckpointCompletionTarget; target != 0 {
+		if target < 0 || target > 1 {
+			b.Exit("--pg-checkpoint-completion-target must be in 0.0 - 1.0 range")
+		}
+		setPGCheckpointCompletionTarget(b, c, target)
+	}
+
+	if testOpts.DBOpts.MySQLOptimizerSwitch != "" && c.database.DialectName() != db.MYSQL {
+		b.Exit("--mysql-optimizer-switch is not supported for '%s' database", c.database.DialectName())
+	}
+
+	if testOpts.DBOpts.CompareDSN != "" && (testOpts.DBOpts.SchemaSandbox != "" || testOpts.BenchOpts.ChaosMode || testOpts.BenchOpts.Query != "") {
+		b.Exit("--compare-dsn can't be used with --schema-sandbox, --chaos-mode or --query")
+	}
+
 	if testOpts.BenchOpts.ProfilerPort > 0 {
+		http.HandleFunc("/debug/pool", poolStatusHandler(b))
 		go func() {
 			err = http.ListenAndServe(fmt.Sprintf("localhost:%d", testOpts.BenchOpts.ProfilerPort), nil)
 			if err != nil {
@@ -254,6 +592,47 @@ func Main() {
 		}()
 		fmt.Printf("running profiler endpoint @ http://localhost:%d/debug/pprof/\n", testOpts.BenchOpts.ProfilerPort)
 		fmt.Printf("to collect the profiler log run: go tool pprof 'http://localhost:%d/debug/pprof/profile?seconds=10'\n", testOpts.BenchOpts.ProfilerPort)
+		fmt.Printf("DB connection pool status endpoint @ http://localhost:%d/debug/pool\n", testOpts.BenchOpts.ProfilerPort)
+	}
+
+	if testOpts.BenchOpts.PrometheusPort > 0 {
+		d.metrics = newBenchmarkMetrics(b, testOpts.BenchOpts.PrometheusPort)
+		fmt.Printf("prometheus metrics endpoint @ http://localhost:%d/metrics\n", testOpts.BenchOpts.PrometheusPort)
+
+		// the server is shut down on exit rather than by b.Finish, which is called after every test of the 'all' test
+		var preExit = b.PreExit
+		b.PreExit = func() {
+			d.metrics.shutdown()
+			preExit()
+		}
+	}
+
+	if testOpts.BenchOpts.InfluxDBURL != "" {
+		if testOpts.BenchOpts.InfluxDBOrg == "" || testOpts.BenchOpts.InfluxDBBucket == "" {
+			b.Exit("--influxdb-url requires --influxdb-org and --influxdb-bucket")
+		}
+		d.influx = newInfluxExporter(b, testOpts.BenchOpts.InfluxDBURL, testOpts.BenchOpts.InfluxDBToken,
+			testOpts.BenchOpts.InfluxDBOrg, testOpts.BenchOpts.InfluxDBBucket)
+
+		// the points of the last test are sent on exit
+		var preExit = b.PreExit
+		b.PreExit = func() {
+			d.influx.close()
+			preExit()
+		}
+	}
+
+	if testOpts.BenchOpts.PoolStatusInterval > 0 {
+		if b.Logger.LogLevel < benchmark.LogInfo {
+			b.Logger.LogLevel = benchmark.LogInfo
+		}
+		var stopPoolStatusLogger = startPoolStatusLogger(b, time.Duration(testOpts.BenchOpts.PoolStatusInterval)*time.Second)
+
+		var preExit = b.PreExit
+		b.PreExit = func() {
+			stopPoolStatusLogger()
+			preExit()
+		}
 	}
 
 	b.Init = func() {
@@ -261,6 +640,7 @@ func Main() {
 
 		b.Vault.(*DBTestData).TenantsCache.SetTenantsWorkingSet(b.TestOpts.(*TestOpts).BenchOpts.TenantsWorkingSet)
 		b.Vault.(*DBTestData).TenantsCache.SetCTIsWorkingSet(b.TestOpts.(*TestOpts).BenchOpts.CTIsWorkingSet)
+		b.Vault.(*DBTestData).TenantsCache.SetTenantsHotspot(testOpts.BenchOpts.TenantHotspotFraction, testOpts.BenchOpts.TenantHotspotWeight)
 
 		if b.Logger.LogLevel > benchmark.LogInfo && !testOpts.BenchOpts.Info {
 			b.Log(benchmark.LogTrace, 0, getDBInfo(b, content))
@@ -287,6 +667,10 @@ func Main() {
 		if b.TestOpts.(*TestOpts).BenchOpts.Events {
 			b.Vault.(*DBTestData).EventBus.Stop()
 		}
+
+		if b.Vault.(*DBTestData).influx != nil {
+			b.Vault.(*DBTestData).influx.flush()
+		}
 	}
 
 	if testOpts.DBOpts.Reconnect {
@@ -317,10 +701,30 @@ func Main() {
 
 	if testOpts.BenchOpts.Query != "" {
 		TestRawQuery.launcherFunc(b, &TestRawQuery)
-	} else if testOpts.BenchOpts.Test != "" {
-		executeTests(b, testOpts)
+	} else if testOpts.BenchOpts.Test != "" || testOpts.BenchOpts.Suite != "" {
+		var runTests = func() error {
+			if testOpts.DBOpts.CompareDSN != "" {
+				executeTestsWithCompare(b, testOpts)
+			} else {
+				executeTests(b, testOpts)
+			}
+			if err := writeHTMLReport(b); err != nil {
+				return err
+			}
+			return exportResults(b)
+		}
+
+		if sandbox != nil {
+			err = sandbox.database.RunInSchemaSandbox(testOpts.DBOpts.SchemaSandbox, runTests)
+		} else {
+			err = runTests()
+		}
+
+		if err != nil {
+			b.Exit("failed to run the tests: %v", err)
+		}
 	} else if !testOpts.BenchOpts.Info {
-		b.Exit("either --test or --info options must be set\n")
+		b.Exit("either --test, --suite or --info options must be set\n")
 	}
 
 	b.Exit()
@@ -331,6 +735,11 @@ func main() {
 }
 
 func executeTests(b *benchmark.Benchmark, testOpts *TestOpts) {
+	if testOpts.BenchOpts.Suite != "" {
+		executeSuite(b, testOpts)
+		return
+	}
+
 	_, tests := GetTests()
 	_, exists := tests[testOpts.BenchOpts.Test]
 	if !exists {
@@ -346,7 +755,269 @@ func executeTests(b *benchmark.Benchmark, testOpts *TestOpts) {
 	if !test.dbIsSupported(dialectName) {
 		b.Exit(fmt.Sprintf("Test: '%s' doesn't support '%s' database\n", testOpts.BenchOpts.Test, dialectName))
 	}
-	test.launcherFunc(b, test)
+
+	if testOpts.BenchOpts.TestMatrix {
+		executeTestMatrix(b, test, testOpts.BenchOpts.WorkersRange)
+		return
+	}
+
+	if testOpts.BenchOpts.BackgroundLoadTest != "" {
+		var background, bgExists = tests[testOpts.BenchOpts.BackgroundLoadTest]
+		if !bgExists || background.name == TestBaseAll.name {
+			b.Exit(fmt.Sprintf("Background load test: '%s' doesn't exist, see the list of available tests using --list option\n", testOpts.BenchOpts.BackgroundLoadTest))
+		}
+		if !background.dbIsSupported(dialectName) {
+			b.Exit(fmt.Sprintf("Background load test: '%s' doesn't support '%s' database\n", testOpts.BenchOpts.BackgroundLoadTest, dialectName))
+		}
+		if testOpts.BenchOpts.BackgroundWorkers <= 0 {
+			b.Exit("--background-workers must be > 0")
+		}
+
+		executeTestUnderBackgroundLoad(b, test, background, testOpts.BenchOpts.BackgroundWorkers)
+		return
+	}
+
+	executeOneTest(b, test)
+}
+
+// parseWorkersRange parses the --workers-range min:max:step value to the list of worker counts
+func parseWorkersRange(s string) ([]int, error) {
+	var parts = strings.Split(s, ":")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("bad workers range '%s', expected min:max:step", s)
+	}
+
+	var bounds [3]int
+	for i, p := range parts {
+		var v, err = strconv.Atoi(strings.TrimSpace(p))
+		if err != nil || v < 1 {
+			return nil, fmt.Errorf("bad workers range '%s', min, max and step must be positive integers", s)
+		}
+		bounds[i] = v
+	}
+
+	if bounds[0] > bounds[1] {
+		return nil, fmt.Errorf("bad workers range '%s', min must not be greater than max", s)
+	}
+
+	var workers []int
+	for w := bounds[0]; w <= bounds[1]; w += bounds[2] {
+		workers = append(workers, w)
+	}
+
+	return workers, nil
+}
+
+// executeTestMatrix runs the test for every worker count of the range and prints how the rate and latencies scale,
+// every run is stored in the results as well
+func executeTestMatrix(b *benchmark.Benchmark, testDesc *TestDesc, workersRange string) {
+	var workers, err = parseWorkersRange(workersRange)
+	if err != nil {
+		b.Exit("failed to parse --workers-range: %v", err)
+	}
+
+	b.CollectLatencies = true
+
+	var scores []benchmark.Score
+	for _, w := range workers {
+		b.CommonOpts.Workers = w
+		executeOneTest(b, testDesc)
+		scores = append(scores, b.Score)
+
+		if b.NeedToExit {
+			break
+		}
+	}
+
+	fmt.Printf("\nTEST MATRIX: %s\n\n", testDesc.name)
+	fmt.Printf("%10s %15s %12s %12s\n", "workers", "rate", "p50, ms", "p99, ms")
+	fmt.Printf("%10s %15s %12s %12s\n", strings.Repeat("-", 10), strings.Repeat("-", 15), strings.Repeat("-", 12), strings.Repeat("-", 12))
+	for _, s := range scores {
+		fmt.Printf("%10d %15s %12.3f %12.3f\n", s.Workers, s.FormatRate(4), durationMs(s.P50), durationMs(s.P99))
+	}
+	fmt.Printf("\n")
+}
+
+// newBackgroundBenchmark creates the benchmark running the --background-load-test by given number of workers until it's stopped
+// by NeedToExit, it shares the DB options with the main benchmark but has its own test data, samplers are disabled
+func newBackgroundBenchmark(b *benchmark.Benchmark, workers int) *benchmark.Benchmark {
+	var bg = benchmark.New()
+
+	var testOpts = *b.TestOpts.(*TestOpts)
+	testOpts.BenchOpts.LockWaitStats = false
+	testOpts.BenchOpts.ShowActiveQueriesInterval = 0
+	testOpts.BenchOpts.ChaosMode = false
+	testOpts.BenchOpts.CollectTableStats = false
+	testOpts.BenchOpts.PrefetchIDs = false
+	testOpts.DBOpts.connPool = "background"
+
+	bg.TestOpts = &testOpts
+	bg.OptsInitialized = true
+	bg.Logger = b.Logger
+
+	bg.CommonOpts = b.CommonOpts
+	bg.CommonOpts.Workers = workers
+	bg.CommonOpts.Loops = 0
+	bg.CommonOpts.Duration = math.MaxInt32
+	bg.CommonOpts.Repeat = 1
+	bg.CommonOpts.WarmupLoops = 0
+	bg.CommonOpts.WarmupDuration = 0
+
+	var testData = &DBTestData{
+		EffectiveBatch: b.Vault.(*DBTestData).EffectiveBatch,
+		scores:         make(map[string][]benchmark.Score),
+		metadata:       b.Vault.(*DBTestData).metadata,
+		id:             b.Vault.(*DBTestData).id,
+	}
+	bg.Vault = testData
+
+	bg.Init = func() {
+		testData.TenantsCache = tenants.NewTenantsCache(bg)
+		testData.TenantsCache.SetTenantsWorkingSet(testOpts.BenchOpts.TenantsWorkingSet)
+		testData.TenantsCache.SetCTIsWorkingSet(testOpts.BenchOpts.CTIsWorkingSet)
+		testData.TenantsCache.SetTenantsHotspot(testOpts.BenchOpts.TenantHotspotFraction, testOpts.BenchOpts.TenantHotspotWeight)
+	}
+
+	bg.PrintScore = func(score benchmark.Score) {
+		fmt.Printf("background load: test: %s; time: %.1f sec; workers: %d; loops: %d; rate: %s %s\n",
+			testData.TestDesc.name, score.Seconds, score.Workers, score.Loops, score.FormatRate(4), score.Metric)
+	}
+
+	return bg
+}
+
+// executeTestUnderBackgroundLoad measures the test rate alone and then while the background test is running continuously,
+// the result of the second run is stored with the rate of the first one
+func executeTestUnderBackgroundLoad(b *benchmark.Benchmark, testDesc *TestDesc, background *TestDesc, workers int) {
+	executeOneTest(b, testDesc)
+	if b.NeedToExit {
+		return
+	}
+	var without = b.Score
+
+	var bg = newBackgroundBenchmark(b, workers)
+	var bgDone = make(chan struct{})
+	var pool = benchmark.NewWorkerPool(1, func(int) {
+		defer close(bgDone)
+		executeOneTest(bg, background)
+	})
+	pool.Start()
+
+	// don't measure the test until the background load is actually running (e.g. its table is created and filled)
+	var ticker = time.NewTicker(100 * time.Millisecond)
+	for bg.DoneLoops() == 0 && !b.NeedToExit {
+		select {
+		case <-bgDone:
+			ticker.Stop()
+			b.Exit("background load test '%s' has finished before the measurement", background.name)
+		case <-ticker.C:
+		}
+	}
+	ticker.Stop()
+
+	var testData = b.Vault.(*DBTestData)
+	testData.backgroundLoadTest = background.name
+	testData.rateWithoutBackgroundLoad = without.Rate
+
+	executeOneTest(b, testDesc)
+	var with = b.Score
+
+	testData.backgroundLoadTest = ""
+	testData.rateWithoutBackgroundLoad = 0
+
+	bg.NeedToExit = true
+	pool.Wait()
+
+	var degradation float64
+	if without.Rate > 0 {
+		degradation = (without.Rate - with.Rate) / without.Rate * 100
+	}
+
+	fmt.Printf("\nBACKGROUND LOAD: %s (%d workers)\n\n", background.name, workers)
+	fmt.Printf("test: %s; rate without background load: %s %s; rate with background load: %s %s; degradation: %.1f%%\n\n",
+		testDesc.name, without.FormatRate(4), without.Metric, with.FormatRate(4), with.Metric, degradation)
+}
+
+// executeTestsWithCompare runs the tests against the --connection-string database and then against the --compare-dsn one
+// with the same options, the scores of the latter are stored under the CompareScoresPrefix keys
+func executeTestsWithCompare(b *benchmark.Benchmark, testOpts *TestOpts) {
+	var testData = b.Vault.(*DBTestData)
+	var commonOpts = b.CommonOpts
+	var start = len(testData.results)
+
+	executeTests(b, testOpts)
+	if b.NeedToExit {
+		return
+	}
+
+	var baseline = append([]BenchmarkResult(nil), testData.results[start:]...)
+	var scores = testData.scores
+	var connString, driver, version = testOpts.DBOpts.ConnString, testData.dbDriver, testData.dbVersion
+
+	// the 'all' test adjusts the workers, loops and duration of every test it runs
+	b.CommonOpts = commonOpts
+	testOpts.DBOpts.ConnString = testOpts.DBOpts.CompareDSN
+
+	var c = dbConnector(b)
+	var err error
+	if testData.dbDriver, testData.dbVersion, err = c.database.GetVersion(); err != nil {
+		b.Exit("Failed to get --compare-dsn database version: %v", err)
+	}
+	c.Release()
+
+	fmt.Printf("\n")
+	fmt.Printf(header) //nolint:staticcheck
+	fmt.Printf("Connected to '%s' compare database: %s\n", testData.dbDriver, testData.dbVersion)
+	fmt.Printf(header) //nolint:staticcheck
+
+	testData.scores = make(map[string][]benchmark.Score)
+	for _, s := range TestCategories {
+		testData.scores[s] = []benchmark.Score{}
+	}
+	testData.compareBaseline, testData.compareStart = baseline, len(testData.results)
+
+	executeTests(b, testOpts)
+
+	var compared = testData.results[testData.compareStart:]
+	testData.compareBaseline = nil
+
+	for k, v := range testData.scores {
+		scores[CompareScoresPrefix+k] = v
+	}
+	testData.scores = scores
+	testOpts.DBOpts.ConnString, testData.dbDriver, testData.dbVersion = connString, driver, version
+
+	printCompareSummary(baseline, compared)
+}
+
+// printCompareSummary prints the rates of the tests run against both databases sorted by the absolute difference
+func printCompareSummary(baseline []BenchmarkResult, compared []BenchmarkResult) {
+	type comparison struct {
+		test          string
+		metric        string
+		baseline, cmp float64
+	}
+
+	var rows []comparison
+	for i := 0; i < len(baseline) && i < len(compared); i++ {
+		rows = append(rows, comparison{test: baseline[i].Test, metric: baseline[i].Metric, baseline: baseline[i].Rate, cmp: compared[i].Rate})
+	}
+
+	sort.SliceStable(rows, func(i, j int) bool {
+		return math.Abs(rows[i].cmp-rows[i].baseline) > math.Abs(rows[j].cmp-rows[j].baseline)
+	})
+
+	fmt.Printf("\nCOMPARISON SUMMARY\n\n")
+	fmt.Printf("%-40s %15s %15s %10s %s\n", "test", "baseline rate", "compare rate", "speedup", "metric")
+	fmt.Printf("%-40s %15s %15s %10s %s\n", strings.Repeat("-", 40), strings.Repeat("-", 15), strings.Repeat("-", 15), strings.Repeat("-", 10), strings.Repeat("-", 10))
+	for _, r := range rows {
+		var speedup = "n/a"
+		if r.baseline > 0 {
+			speedup = fmt.Sprintf("%.2fx", r.cmp/r.baseline)
+		}
+		fmt.Printf("%-40s %15.1f %15.1f %10s %s\n", r.test, r.baseline, r.cmp, speedup, r.metric)
+	}
+	fmt.Printf("\n")
 }
 
 func describeOne(b *benchmark.Benchmark, testDesc *TestDesc) {
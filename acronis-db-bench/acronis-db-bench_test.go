@@ -0,0 +1,36 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseWorkersRange(t *testing.T) {
+	tests := []struct {
+		s       string
+		want    []int
+		wantErr bool
+	}{
+		{"1:1:1", []int{1}, false},
+		{"1:8:1", []int{1, 2, 3, 4, 5, 6, 7, 8}, false},
+		{"2:16:4", []int{2, 6, 10, 14}, false},
+		{" 4 : 8 : 2 ", []int{4, 6, 8}, false},
+		{"1:8", nil, true},
+		{"1:8:1:1", nil, true},
+		{"0:8:1", nil, true},
+		{"1:8:0", nil, true},
+		{"1:-8:1", nil, true},
+		{"a:8:1", nil, true},
+		{"8:1:1", nil, true},
+	}
+	for _, tt := range tests {
+		got, err := parseWorkersRange(tt.s)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseWorkersRange(%q) error = %v, wantErr %v", tt.s, err, tt.wantErr)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseWorkersRange(%q) = %v, want %v", tt.s, got, tt.want)
+		}
+	}
+}
@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
-	"sync"
 	"time"
 
 	guuid "github.com/google/uuid"
@@ -53,8 +52,7 @@ var MaxTopics = 8
 type EventBus struct {
 	workerConn      db.Database
 	workerStarted   bool
-	stopCh          chan bool
-	wg              sync.WaitGroup
+	pool            *benchmark.WorkerPool
 	batchSize       int
 	sleepMsec       int
 	workerIteration uint64
@@ -66,7 +64,6 @@ func NewEventBus(conn db.Database, logger *benchmark.Logger) *EventBus {
 	return &EventBus{
 		workerConn:    conn,
 		workerStarted: false,
-		stopCh:        make(chan bool),
 		batchSize:     500,
 		sleepMsec:     10,
 		logger:        logger,
@@ -79,10 +76,8 @@ func (e *EventBus) Log(LogLevel int, format string, args ...interface{}) {
 	e.logger.Log(LogLevel, -1, msg)
 }
 
-// MainLoop is the main worker loop for the event bus
+// MainLoop is the main worker loop for the event bus, it returns when the queue is empty and the worker is stopped
 func (e *EventBus) MainLoop() {
-	defer e.wg.Done()
-
 	for {
 		if empty, err := e.QueueIsEmpty(); err != nil {
 			e.Log(benchmark.LogError, "cannot check if queue is empty: %v", err)
@@ -90,7 +85,7 @@ func (e *EventBus) MainLoop() {
 			return
 		} else if empty {
 			select {
-			case <-e.stopCh:
+			case <-e.pool.Context().Done():
 				e.Log(benchmark.LogTrace, "stopping main worker loop")
 
 				return
@@ -133,8 +128,10 @@ func (e *EventBus) Start() error {
 
 	e.Log(benchmark.LogTrace, "worker start")
 
-	e.wg.Add(1)
-	go e.MainLoop()
+	e.pool = benchmark.NewWorkerPool(1, func(int) {
+		e.MainLoop()
+	})
+	e.pool.Start()
 
 	return nil
 }
@@ -144,8 +141,8 @@ func (e *EventBus) Stop() {
 	if !e.workerStarted {
 		return
 	}
-	e.stopCh <- true
-	e.wg.Wait()
+	e.pool.Stop()
+	e.pool.Wait()
 	e.Log(benchmark.LogTrace, "worker stop")
 }
 
@@ -175,9 +172,10 @@ func (e *EventBus) CreateTables() error {
 				TopicID:    fmt.Sprintf("cti.a.p.em.topic.v1.0~a.p.my_topic.%d.v1.0", i),
 			}
 
-			if err := tx.BulkInsert("acronis_db_bench_eventbus_topics", [][]interface{}{
-				{eventTopic.InternalID, eventTopic.TopicID},
-			}, []string{"internal_id", "topic_id"}); err != nil {
+			if err := tx.BulkInsert("acronis_db_bench_eventbus_topics", &db.BulkInsertCtrl{
+				Rows:        [][]interface{}{{eventTopic.InternalID, eventTopic.TopicID}},
+				ColumnNames: []string{"internal_id", "topic_id"},
+			}); err != nil {
 				return err
 			}
 
@@ -187,9 +185,10 @@ func (e *EventBus) CreateTables() error {
 				EventType:       fmt.Sprintf("cti.a.p.em.event.v1.0~a.p.my_event.%d.v1.0", i),
 			}
 
-			if err := tx.BulkInsert("acronis_db_bench_eventbus_event_types", [][]interface{}{
-				{eventType.InternalID, eventType.TopicInternalID, eventType.EventType},
-			}, []string{"internal_id", "topic_internal_id", "event_type"}); err != nil {
+			if err := tx.BulkInsert("acronis_db_bench_eventbus_event_types", &db.BulkInsertCtrl{
+				Rows:        [][]interface{}{{eventType.InternalID, eventType.TopicInternalID, eventType.EventType}},
+				ColumnNames: []string{"internal_id", "topic_internal_id", "event_type"},
+			}); err != nil {
 				return err
 			}
 		}
@@ -273,10 +272,10 @@ func (e *EventBus) InsertEvent(rw *benchmark.RandomizerWorker, databaseAccessor
 
 	e.Start()
 
-	return databaseAccessor.BulkInsert("acronis_db_bench_eventbus_events", [][]interface{}{
-		{d.TopicInternalID, d.EventTypeInternalID, d.EventID, d.Source, d.TenantID, d.ConsolidationKey, d.Data},
-	},
-		[]string{"topic_internal_id", "event_type_internal_id", "event_id", "source", "tenant_id", "consolidation_key", "data"})
+	return databaseAccessor.BulkInsert("acronis_db_bench_eventbus_events", &db.BulkInsertCtrl{
+		Rows:        [][]interface{}{{d.TopicInternalID, d.EventTypeInternalID, d.EventID, d.Source, d.TenantID, d.ConsolidationKey, d.Data}},
+		ColumnNames: []string{"topic_internal_id", "event_type_internal_id", "event_id", "source", "tenant_id", "consolidation_key", "data"},
+	})
 }
 
 /*
@@ -0,0 +1,470 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/storage"
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/apache/arrow-go/v18/parquet/pqarrow"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+
+	"github.com/acronis/perfkit/benchmark"
+	"github.com/acronis/perfkit/benchmark/report"
+	"github.com/acronis/perfkit/db"
+)
+
+// BenchmarkMetadata is a structure to store the user given annotation of the benchmark run
+type BenchmarkMetadata struct {
+	Name string            `json:"name"`
+	Tags map[string]string `json:"tags"`
+}
+
+// BenchmarkResult is a structure to store the result of a single test run
+type BenchmarkResult struct {
+	Timestamp      time.Time `json:"timestamp"`
+	Test           string    `json:"test"`
+	Category       string    `json:"category"`
+	Dialect        string    `json:"dialect"`
+	RowsBeforeTest uint64    `json:"rows_before_test"`
+	Workers        int       `json:"workers"`
+	Batch          int       `json:"batch"`
+	Seconds        float64   `json:"seconds"`
+	Loops          uint64    `json:"loops"`
+	Rate           float64   `json:"rate"`
+	Metric         string    `json:"metric"`
+	Retries        uint64    `json:"retries"`
+	Errors         uint64    `json:"errors"`
+	LockWaits      uint64    `json:"lock_waits"`
+	BenchmarkID    string    `json:"benchmark_id"`
+	P50Ms          float64   `json:"latency_p50_ms"` // set by --test-matrix or --percentiles only
+	P99Ms          float64   `json:"latency_p99_ms"` // set by --test-matrix or --percentiles only
+
+	ChaosEvents        uint64  `json:"num_chaos_events"` // set by --chaos-mode only
+	MeanRecoveryTimeMs float64 `json:"mean_recovery_time_ms"`
+	OpsDuringDowntime  uint64  `json:"ops_during_downtime"`
+
+	BackgroundLoadTest        string  `json:"background_load_test"` // set by --background-load-test only
+	RateWithoutBackgroundLoad float64 `json:"rate_without_background_load"`
+
+	ConstraintViolationRate float64 `json:"constraint_violation_rate"`
+
+	CheckpointsTimed      int64   `json:"checkpoints_timed"` // set by --checkpoint-monitor only
+	CheckpointsReq        int64   `json:"checkpoints_req"`
+	CheckpointWriteTimeMs float64 `json:"checkpoint_write_time_ms"`
+	CheckpointSyncTimeMs  float64 `json:"checkpoint_sync_time_ms"`
+	BuffersCheckpoint     int64   `json:"buffers_checkpoint"`
+
+	AllocsPerOp float64 `json:"allocs_per_op"` // set by --trace-memory only
+	BytesPerOp  float64 `json:"bytes_per_op"`
+
+	Metadata BenchmarkMetadata `json:"metadata"`
+}
+
+// benchmarkResultSchema is the Parquet (Arrow) schema of the BenchmarkResult, column names match the JSON field names
+var benchmarkResultSchema = arrow.NewSchema([]arrow.Field{
+	{Name: "timestamp", Type: arrow.FixedWidthTypes.Timestamp_us},
+	{Name: "test", Type: arrow.BinaryTypes.String},
+	{Name: "category", Type: arrow.BinaryTypes.String},
+	{Name: "dialect", Type: arrow.BinaryTypes.String},
+	{Name: "rows_before_test", Type: arrow.PrimitiveTypes.Uint64},
+	{Name: "workers", Type: arrow.PrimitiveTypes.Int64},
+	{Name: "batch", Type: arrow.PrimitiveTypes.Int64},
+	{Name: "seconds", Type: arrow.PrimitiveTypes.Float64},
+	{Name: "loops", Type: arrow.PrimitiveTypes.Uint64},
+	{Name: "rate", Type: arrow.PrimitiveTypes.Float64},
+	{Name: "metric", Type: arrow.BinaryTypes.String},
+	{Name: "retries", Type: arrow.PrimitiveTypes.Uint64},
+	{Name: "errors", Type: arrow.PrimitiveTypes.Uint64},
+	{Name: "lock_waits", Type: arrow.PrimitiveTypes.Uint64},
+	{Name: "benchmark_id", Type: arrow.BinaryTypes.String},
+	{Name: "latency_p50_ms", Type: arrow.PrimitiveTypes.Float64},
+	{Name: "latency_p99_ms", Type: arrow.PrimitiveTypes.Float64},
+	{Name: "num_chaos_events", Type: arrow.PrimitiveTypes.Uint64},
+	{Name: "mean_recovery_time_ms", Type: arrow.PrimitiveTypes.Float64},
+	{Name: "ops_during_downtime", Type: arrow.PrimitiveTypes.Uint64},
+	{Name: "background_load_test", Type: arrow.BinaryTypes.String},
+	{Name: "rate_without_background_load", Type: arrow.PrimitiveTypes.Float64},
+	{Name: "constraint_violation_rate", Type: arrow.PrimitiveTypes.Float64},
+	{Name: "checkpoints_timed", Type: arrow.PrimitiveTypes.Int64},
+	{Name: "checkpoints_req", Type: arrow.PrimitiveTypes.Int64},
+	{Name: "checkpoint_write_time_ms", Type: arrow.PrimitiveTypes.Float64},
+	{Name: "checkpoint_sync_time_ms", Type: arrow.PrimitiveTypes.Float64},
+	{Name: "buffers_checkpoint", Type: arrow.PrimitiveTypes.Int64},
+	{Name: "allocs_per_op", Type: arrow.PrimitiveTypes.Float64},
+	{Name: "bytes_per_op", Type: arrow.PrimitiveTypes.Float64},
+	{Name: "metadata", Type: arrow.StructOf(
+		arrow.Field{Name: "name", Type: arrow.BinaryTypes.String},
+		arrow.Field{Name: "tags", Type: arrow.MapOf(arrow.BinaryTypes.String, arrow.BinaryTypes.String)},
+	)},
+}, nil)
+
+// parseBenchmarkTags parses the comma separated key=value pairs given by the --benchmark-tags option
+func parseBenchmarkTags(s string) (map[string]string, error) {
+	var tags = make(map[string]string)
+	if strings.TrimSpace(s) == "" {
+		return tags, nil
+	}
+
+	for _, pair := range strings.Split(s, ",") {
+		var key, value, found = strings.Cut(strings.TrimSpace(pair), "=")
+		if !found || key == "" {
+			return nil, fmt.Errorf("bad tag '%s', expected key=value", pair)
+		}
+		tags[key] = value
+	}
+
+	return tags, nil
+}
+
+// benchmarkRunID returns the --benchmark-id of the run, suffixed by the repetition number if the test is repeated (see --repeat)
+func benchmarkRunID(b *benchmark.Benchmark) string {
+	var id = b.Vault.(*DBTestData).id
+	if b.CommonOpts.Repeat > 1 {
+		return fmt.Sprintf("%s-%d", id, b.Repetition+1)
+	}
+
+	return id
+}
+
+// durationMs converts the duration to fractional milliseconds
+func durationMs(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// newBenchmarkResult creates BenchmarkResult from the score of the last executed test
+func newBenchmarkResult(b *benchmark.Benchmark, score benchmark.Score) BenchmarkResult {
+	var testData = b.Vault.(*DBTestData)
+	var dialectName, _ = db.GetDialectName(b.TestOpts.(*TestOpts).DBOpts.ConnString)
+
+	return BenchmarkResult{
+		Timestamp:      time.Now().UTC(),
+		Test:           testData.TestDesc.name,
+		Category:       testData.TestDesc.category,
+		Dialect:        string(dialectName),
+		RowsBeforeTest: testData.TestDesc.table.RowsCount,
+		Workers:        score.Workers,
+		Batch:          testData.EffectiveBatch,
+		Seconds:        score.Seconds,
+		Loops:          score.Loops,
+		Rate:           score.Rate,
+		Metric:         score.Metric,
+		Retries:        score.Retries,
+		Errors:         score.Errors,
+		LockWaits:      testData.lockWaits,
+		BenchmarkID:    benchmarkRunID(b),
+		P50Ms:          durationMs(score.P50),
+		P99Ms:          durationMs(score.P99),
+		Metadata:       testData.metadata,
+
+		ChaosEvents:        testData.chaosStats.events,
+		MeanRecoveryTimeMs: durationMs(testData.chaosStats.meanRecoveryTime),
+		OpsDuringDowntime:  testData.chaosStats.opsDuringDowntime,
+
+		BackgroundLoadTest:        testData.backgroundLoadTest,
+		RateWithoutBackgroundLoad: testData.rateWithoutBackgroundLoad,
+
+		ConstraintViolationRate: score.ConstraintViolationRate,
+
+		CheckpointsTimed:      testData.checkpointStats.CheckpointsTimed,
+		CheckpointsReq:        testData.checkpointStats.CheckpointsReq,
+		CheckpointWriteTimeMs: durationMs(testData.checkpointStats.CheckpointWriteTime),
+		CheckpointSyncTimeMs:  durationMs(testData.checkpointStats.CheckpointSyncTime),
+		BuffersCheckpoint:     testData.checkpointStats.BuffersCheckpoint,
+
+		AllocsPerOp: score.AllocsPerOp,
+		BytesPerOp:  score.BytesPerOp,
+	}
+}
+
+// TestOutput is the JSON line of a single test run written to the --output-file, the fields of the BenchmarkResult,
+// the benchmark.ScoreJSON and the TestRun are flattened into a single JSON object, the later ones win on the name collisions
+type TestOutput struct {
+	Result BenchmarkResult
+	Score  benchmark.ScoreJSON
+	Run    TestRun
+}
+
+// TestRun is the environment of the test run reported by the TestOutput
+type TestRun struct {
+	TestName  string `json:"test_name"`
+	BatchSize int    `json:"batch_size"`
+	DBDriver  string `json:"db_driver"`
+	DBVersion string `json:"db_version"`
+	Hostname  string `json:"hostname"`
+}
+
+// MarshalJSON merges the JSON objects of the result, the score and the run
+func (o TestOutput) MarshalJSON() ([]byte, error) {
+	var fields = make(map[string]json.RawMessage)
+	for _, part := range []interface{}{o.Result, o.Score, o.Run} {
+		var data, err = json.Marshal(part)
+		if err != nil {
+			return nil, err
+		}
+		if err = json.Unmarshal(data, &fields); err != nil {
+			return nil, err
+		}
+	}
+
+	return json.Marshal(fields)
+}
+
+// writeTestOutput appends the JSON line of the result and the score of the last executed test to the --output-file,
+// the line is printed to the standard output if --output-format=json is set without the --output-file
+func writeTestOutput(b *benchmark.Benchmark, result BenchmarkResult, score benchmark.Score) error {
+	var opts = b.TestOpts.(*TestOpts).BenchOpts
+	if opts.OutputFile == "" && opts.OutputFormat != "json" {
+		return nil
+	}
+
+	var testData = b.Vault.(*DBTestData)
+	var hostname, _ = os.Hostname()
+
+	var line, err = json.Marshal(TestOutput{
+		Result: result,
+		Score:  score.JSON(),
+		Run: TestRun{
+			TestName:  testData.TestDesc.name,
+			BatchSize: testData.EffectiveBatch,
+			DBDriver:  string(testData.dbDriver),
+			DBVersion: testData.dbVersion,
+			Hostname:  hostname,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	if opts.OutputFile == "" {
+		_, err = stdout.Write(line)
+		return err
+	}
+
+	var f *os.File
+	if f, err = os.OpenFile(opts.OutputFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644); err != nil {
+		return err
+	}
+
+	if _, err = f.Write(line); err != nil {
+		f.Close() //nolint:errcheck
+		return err
+	}
+
+	return f.Close()
+}
+
+// sortedKeys returns the map keys in the stable order
+func sortedKeys(m map[string]string) []string {
+	var keys = make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// resultsToParquet serializes the results to the Parquet file content
+func resultsToParquet(results []BenchmarkResult) ([]byte, error) {
+	var builder = array.NewRecordBuilder(memory.NewGoAllocator(), benchmarkResultSchema)
+	defer builder.Release()
+
+	for _, r := range results {
+		builder.Field(0).(*array.TimestampBuilder).Append(arrow.Timestamp(r.Timestamp.UnixMicro()))
+		builder.Field(1).(*array.StringBuilder).Append(r.Test)
+		builder.Field(2).(*array.StringBuilder).Append(r.Category)
+		builder.Field(3).(*array.StringBuilder).Append(r.Dialect)
+		builder.Field(4).(*array.Uint64Builder).Append(r.RowsBeforeTest)
+		builder.Field(5).(*array.Int64Builder).Append(int64(r.Workers))
+		builder.Field(6).(*array.Int64Builder).Append(int64(r.Batch))
+		builder.Field(7).(*array.Float64Builder).Append(r.Seconds)
+		builder.Field(8).(*array.Uint64Builder).Append(r.Loops)
+		builder.Field(9).(*array.Float64Builder).Append(r.Rate)
+		builder.Field(10).(*array.StringBuilder).Append(r.Metric)
+		builder.Field(11).(*array.Uint64Builder).Append(r.Retries)
+		builder.Field(12).(*array.Uint64Builder).Append(r.Errors)
+		builder.Field(13).(*array.Uint64Builder).Append(r.LockWaits)
+		builder.Field(14).(*array.StringBuilder).Append(r.BenchmarkID)
+		builder.Field(15).(*array.Float64Builder).Append(r.P50Ms)
+		builder.Field(16).(*array.Float64Builder).Append(r.P99Ms)
+		builder.Field(17).(*array.Uint64Builder).Append(r.ChaosEvents)
+		builder.Field(18).(*array.Float64Builder).Append(r.MeanRecoveryTimeMs)
+		builder.Field(19).(*array.Uint64Builder).Append(r.OpsDuringDowntime)
+		builder.Field(20).(*array.StringBuilder).Append(r.BackgroundLoadTest)
+		builder.Field(21).(*array.Float64Builder).Append(r.RateWithoutBackgroundLoad)
+		builder.Field(22).(*array.Float64Builder).Append(r.ConstraintViolationRate)
+		builder.Field(23).(*array.Int64Builder).Append(r.CheckpointsTimed)
+		builder.Field(24).(*array.Int64Builder).Append(r.CheckpointsReq)
+		builder.Field(25).(*array.Float64Builder).Append(r.CheckpointWriteTimeMs)
+		builder.Field(26).(*array.Float64Builder).Append(r.CheckpointSyncTimeMs)
+		builder.Field(27).(*array.Int64Builder).Append(r.BuffersCheckpoint)
+		builder.Field(28).(*array.Float64Builder).Append(r.AllocsPerOp)
+		builder.Field(29).(*array.Float64Builder).Append(r.BytesPerOp)
+
+		var metadataBuilder = builder.Field(30).(*array.StructBuilder)
+		metadataBuilder.Append(true)
+		metadataBuilder.FieldBuilder(0).(*array.StringBuilder).Append(r.Metadata.Name)
+
+		var tagsBuilder = metadataBuilder.FieldBuilder(1).(*array.MapBuilder)
+		tagsBuilder.Append(true)
+		for _, key := range sortedKeys(r.Metadata.Tags) {
+			tagsBuilder.KeyBuilder().(*array.StringBuilder).Append(key)
+			tagsBuilder.ItemBuilder().(*array.StringBuilder).Append(r.Metadata.Tags[key])
+		}
+	}
+
+	var record = builder.NewRecord()
+	defer record.Release()
+
+	var buf bytes.Buffer
+	var writer, err = pqarrow.NewFileWriter(benchmarkResultSchema, &buf, nil, pqarrow.DefaultWriterProps())
+	if err != nil {
+		return nil, fmt.Errorf("error creating parquet writer: %v", err)
+	}
+
+	if err = writer.Write(record); err != nil {
+		return nil, fmt.Errorf("error writing parquet record: %v", err)
+	}
+
+	if err = writer.Close(); err != nil {
+		return nil, fmt.Errorf("error closing parquet writer: %v", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// uploadToS3 puts the content to the S3 bucket, credentials and region are taken from the default AWS config chain
+func uploadToS3(ctx context.Context, bucket string, key string, content []byte) error {
+	var cfg, err = config.LoadDefaultConfig(ctx)
+	if err != nil {
+		return fmt.Errorf("error loading AWS config: %v", err)
+	}
+
+	if _, err = s3.NewFromConfig(cfg).PutObject(ctx, &s3.PutObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+		Body:   bytes.NewReader(content),
+	}); err != nil {
+		return fmt.Errorf("error uploading results to s3://%s/%s: %v", bucket, key, err)
+	}
+
+	return nil
+}
+
+// uploadToGCS writes the content to the GCS bucket, credentials are taken from the Application Default Credentials
+func uploadToGCS(ctx context.Context, bucket string, object string, content []byte) error {
+	var client, err = storage.NewClient(ctx)
+	if err != nil {
+		return fmt.Errorf("error creating GCS client: %v", err)
+	}
+	defer client.Close()
+
+	var writer = client.Bucket(bucket).Object(object).NewWriter(ctx)
+	if _, err = writer.Write(content); err != nil {
+		_ = writer.Close()
+		return fmt.Errorf("error uploading results to gs://%s/%s: %v", bucket, object, err)
+	}
+
+	if err = writer.Close(); err != nil {
+		return fmt.Errorf("error uploading results to gs://%s/%s: %v", bucket, object, err)
+	}
+
+	return nil
+}
+
+// exportResults writes the collected results as Parquet file to the cloud storage locations given by the --export-* options
+func exportResults(b *benchmark.Benchmark) error {
+	var opts = b.TestOpts.(*TestOpts).BenchOpts
+	var toS3 = opts.ExportS3Bucket != "" && opts.ExportS3Key != ""
+	var toGCS = opts.ExportGCSBucket != "" && opts.ExportGCSObject != ""
+
+	if !toS3 && !toGCS {
+		return nil
+	}
+
+	var results = b.Vault.(*DBTestData).results
+	if len(results) == 0 {
+		b.Log(benchmark.LogWarn, 0, "no benchmark results to export")
+		return nil
+	}
+
+	var content, err = resultsToParquet(results)
+	if err != nil {
+		return err
+	}
+
+	var ctx = context.Background()
+
+	if toS3 {
+		if err = uploadToS3(ctx, opts.ExportS3Bucket, opts.ExportS3Key, content); err != nil {
+			return err
+		}
+		b.Log(benchmark.LogInfo, 0, fmt.Sprintf("results exported to s3://%s/%s", opts.ExportS3Bucket, opts.ExportS3Key))
+	}
+
+	if toGCS {
+		if err = uploadToGCS(ctx, opts.ExportGCSBucket, opts.ExportGCSObject, content); err != nil {
+			return err
+		}
+		b.Log(benchmark.LogInfo, 0, fmt.Sprintf("results exported to gs://%s/%s", opts.ExportGCSBucket, opts.ExportGCSObject))
+	}
+
+	return nil
+}
+
+// writeHTMLReport writes the HTML report of the executed tests to the --html-report file
+func writeHTMLReport(b *benchmark.Benchmark) error {
+	var opts = b.TestOpts.(*TestOpts)
+	if opts.BenchOpts.HTMLReport == "" {
+		return nil
+	}
+
+	var testData = b.Vault.(*DBTestData)
+	var title = "Acronis Database Benchmark"
+	if testData.metadata.Name != "" {
+		title += ": " + testData.metadata.Name
+	}
+
+	var parameters = []report.Parameter{
+		{Name: "Test", Value: opts.BenchOpts.Test},
+		{Name: "Workers", Value: strconv.Itoa(b.CommonOpts.Workers)},
+		{Name: "Loops", Value: strconv.Itoa(b.CommonOpts.Loops)},
+		{Name: "Duration, sec", Value: strconv.Itoa(b.CommonOpts.Duration)},
+		{Name: "Batch", Value: strconv.Itoa(testData.EffectiveBatch)},
+	}
+	if opts.BenchOpts.Suite != "" {
+		parameters[0] = report.Parameter{Name: "Suite", Value: opts.BenchOpts.Suite}
+	}
+	for _, key := range sortedKeys(testData.metadata.Tags) {
+		parameters = append(parameters, report.Parameter{Name: key, Value: testData.metadata.Tags[key]})
+	}
+
+	var content = report.Generate(testData.reportScores, report.Meta{
+		Title:       title,
+		BenchmarkID: testData.id,
+		Database:    fmt.Sprintf("%s %s", testData.dbDriver, testData.dbVersion),
+		Timestamp:   time.Now(),
+		Parameters:  parameters,
+		Percentiles: opts.BenchOpts.Percentiles,
+	})
+
+	if err := os.WriteFile(opts.BenchOpts.HTMLReport, content, 0o644); err != nil {
+		return fmt.Errorf("error writing the HTML report: %v", err)
+	}
+	b.Log(benchmark.LogInfo, 0, fmt.Sprintf("HTML report written to %s", opts.BenchOpts.HTMLReport))
+
+	return nil
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseBenchmarkTags(t *testing.T) {
+	tests := []struct {
+		s       string
+		want    map[string]string
+		wantErr bool
+	}{
+		{"", map[string]string{}, false},
+		{"  ", map[string]string{}, false},
+		{"env=ci", map[string]string{"env": "ci"}, false},
+		{"env=ci, host=db1 ,empty=", map[string]string{"env": "ci", "host": "db1", "empty": ""}, false},
+		{"a=1,a=2", map[string]string{"a": "2"}, false},
+		{"k=v=w", map[string]string{"k": "v=w"}, false},
+		{"env", nil, true},
+		{"=ci", nil, true},
+		{"env=ci,,host=db1", nil, true},
+	}
+	for _, tt := range tests {
+		got, err := parseBenchmarkTags(tt.s)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseBenchmarkTags(%q) error = %v, wantErr %v", tt.s, err, tt.wantErr)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseBenchmarkTags(%q) = %v, want %v", tt.s, got, tt.want)
+		}
+	}
+}
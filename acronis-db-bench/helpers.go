@@ -1,23 +1,36 @@
 package main
 
 import (
+	"context"
+	"database/sql"
+	"encoding/json"
 	"fmt"
+	"io"
+	"net/http"
 	"os"
 	"regexp"
+	"runtime"
 	"sort"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/acronis/perfkit/benchmark"
 	"github.com/acronis/perfkit/db"
+	"github.com/acronis/perfkit/db/pgmbed"
 
 	dataset "github.com/acronis/perfkit/acronis-db-bench/dataset-source"
 	events "github.com/acronis/perfkit/acronis-db-bench/event-bus"
 	tenants "github.com/acronis/perfkit/acronis-db-bench/tenants-cache"
 )
 
+// stdout is the original standard output, it's kept for the fatal errors and the JSON results when --output-format=json silences os.Stdout
+var stdout io.Writer = os.Stdout
+
 // FatalError prints error message and exits with code 127
 func FatalError(err string) {
-	fmt.Printf("fatal error: %v", err)
+	fmt.Fprintf(stdout, "fatal error: %v", err)
 	os.Exit(127)
 }
 
@@ -25,6 +38,17 @@ func FatalError(err string) {
  * Helpers
  */
 
+// silenceStdout discards everything printed to os.Stdout, the fatal errors are still printed to the original standard output
+func silenceStdout(b *benchmark.Benchmark) {
+	var devNull, err = os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		b.Exit("cannot open %s: %v", os.DevNull, err)
+	}
+
+	os.Stdout = devNull
+	b.FatalOutput = stdout
+}
+
 func createTables(b *benchmark.Benchmark) {
 	dbOpts := b.TestOpts.(*TestOpts).DBOpts
 	usedTables := benchmark.NewSet()
@@ -43,9 +67,25 @@ func createTables(b *benchmark.Benchmark) {
 
 	fmt.Printf("creating the tables ... ")
 
+	if dbOpts.SchemaSandbox != "" {
+		var sc = schemaSandboxConnector(b)
+		if err = sc.database.CreateSchema(dbOpts.SchemaSandbox); err != nil {
+			b.Exit("db: cannot create sandbox schema '%s': %v", dbOpts.SchemaSandbox, err)
+		}
+		sc.Release()
+	}
+
 	c := dbConnector(b)
+	checkTablesSchema(b, c, usedTables)
+
 	for _, tableDesc := range TestTables {
 		if usedTables.Contains(tableDesc.TableName) && tableDesc.dbIsSupported(dialectName) {
+			if tableDesc.TableName == TestTableVector.TableName {
+				if err = createPGVectorExtension(c.database.Session(c.database.Context(context.Background()))); err != nil {
+					b.Log(benchmark.LogWarn, 0, fmt.Sprintf("skipping table '%s', the pgvector extension is not available: %v", tableDesc.TableName, err))
+					continue
+				}
+			}
 			tableDesc.Create(c, b)
 		}
 	}
@@ -74,6 +114,19 @@ func dbConnector(b *benchmark.Benchmark) *DBConnector {
 	return conn
 }
 
+// schemaSandboxConnector returns the connector to the database given by the original connection string, it manages the --schema-sandbox schema
+func schemaSandboxConnector(b *benchmark.Benchmark) *DBConnector {
+	var dbOpts = b.TestOpts.(*TestOpts).DBOpts
+	dbOpts.SchemaSandbox = ""
+
+	var conn, err = NewDBConnector(&dbOpts, -1, b.Logger, 1)
+	if err != nil {
+		FatalError(err.Error())
+	}
+
+	return conn
+}
+
 func cleanupTables(b *benchmark.Benchmark) {
 	dbOpts := b.TestOpts.(*TestOpts).DBOpts
 
@@ -85,8 +138,36 @@ func cleanupTables(b *benchmark.Benchmark) {
 
 	fmt.Printf("cleaning up the test tables ... ")
 
+	if dbOpts.SchemaSandbox != "" {
+		// dropping the sandbox schema drops all the test tables, sequences and views within it
+		var sc = schemaSandboxConnector(b)
+		if err := sc.database.DropSchema(dbOpts.SchemaSandbox); err != nil {
+			b.Exit("db: cannot drop sandbox schema '%s': %v", dbOpts.SchemaSandbox, err)
+		}
+		sc.Release()
+
+		fmt.Printf("done\n")
+
+		return
+	}
+
 	c := dbConnector(b)
 
+	if c.database.DialectName() == db.POSTGRES {
+		var session = c.database.Session(c.database.Context(context.Background()))
+		if _, err := session.Exec(fmt.Sprintf("DROP MATERIALIZED VIEW IF EXISTS %s", HeavyMaterializedViewName)); err != nil {
+			b.Exit("db: cannot drop materialized view '%s': %v", HeavyMaterializedViewName, err)
+		}
+	}
+
+	if c.database.DialectName() == db.CLICKHOUSE {
+		var session = c.database.Session(c.database.Context(context.Background()))
+		if _, err := session.Exec(fmt.Sprintf("DROP VIEW IF EXISTS %s", TimeSeriesAggregatingViewName)); err != nil {
+			b.Exit("db: cannot drop materialized view '%s': %v", TimeSeriesAggregatingViewName, err)
+		}
+		c.database.DropTable(TimeSeriesAggregatingStateTableName)
+	}
+
 	for tableName := range TestTables {
 		c.database.DropTable(tableName)
 	}
@@ -105,6 +186,58 @@ func cleanupTables(b *benchmark.Benchmark) {
 	fmt.Printf("done\n")
 }
 
+// checkTablesSchema compares the already existing test tables with their definitions, the tables are migrated if --auto-migrate is set,
+// otherwise the difference is printed and the benchmark exits with code 1
+func checkTablesSchema(b *benchmark.Benchmark, c *DBConnector, usedTables *benchmark.Set) {
+	var tableNames []string
+	for _, tableDesc := range TestTables {
+		if usedTables.Contains(tableDesc.TableName) && tableDesc.dbIsSupported(c.database.DialectName()) {
+			tableNames = append(tableNames, tableDesc.TableName)
+		}
+	}
+	sort.Strings(tableNames)
+
+	var schemaInfo, err = c.database.GetTablesSchemaInfo(tableNames)
+	if err != nil {
+		b.Exit("db: cannot get tables schema info: %v", err)
+	}
+
+	var autoMigrate = b.TestOpts.(*TestOpts).DBOpts.AutoMigrate
+	var drifted bool
+	for _, tableName := range tableNames {
+		var tableDesc = TestTables[tableName]
+		var diff = tableDesc.SchemaDiff(c, schemaInfo)
+		if len(diff) == 0 {
+			continue
+		}
+
+		fmt.Printf("\nthe schema of the existing table '%s' differs from the expected one:\n", tableName)
+		for _, d := range diff {
+			fmt.Printf("  - %s\n", d)
+		}
+
+		if !autoMigrate {
+			drifted = true
+			continue
+		}
+
+		var migrationSQL string
+		if migrationSQL, err = tableDesc.MigrationSQL(c.database.DialectName(), diff); err != nil {
+			b.Exit("db: cannot migrate table '%s': %v", tableName, err)
+		}
+		if err = c.database.ApplyMigrations(tableName, migrationSQL); err != nil {
+			b.Exit("db: cannot migrate table '%s': %v", tableName, err)
+		}
+		fmt.Printf("the table '%s' is migrated\n", tableName)
+	}
+
+	if drifted {
+		fmt.Printf("\nuse --auto-migrate to ALTER the tables or --cleanup to drop them\n")
+		b.PreExit()
+		os.Exit(1)
+	}
+}
+
 func getDBInfo(b *benchmark.Benchmark, content []string) (ret string) {
 	c := dbConnector(b)
 
@@ -141,7 +274,7 @@ func getDBInfo(b *benchmark.Benchmark, content []string) (ret string) {
 }
 
 func formatSQL(sqlTemlate string, dialectName db.DialectName) string {
-	if dialectName == db.POSTGRES {
+	if dialectName == db.POSTGRES || dialectName == db.COCKROACHDB {
 		return sqlTemlate
 	}
 
@@ -234,3 +367,887 @@ func (p *DataSetSourcePlugin) GenFakeValue(columnType string, rw *benchmark.Rand
 
 	return true, value
 }
+
+// getIndexUsageStats returns the index usage statistics of given table
+func getIndexUsageStats(b *benchmark.Benchmark, tableName string) []db.IndexUsageStat {
+	c := dbConnector(b)
+	defer c.Release()
+
+	var stats, err = c.database.GetIndexUsageStats(tableName)
+	if err != nil {
+		b.Exit("db: cannot get index usage stats of '%s' table: %v", tableName, err)
+	}
+
+	return stats
+}
+
+// getCheckpointStats returns the current DB checkpoint statistics
+func getCheckpointStats(b *benchmark.Benchmark) db.CheckpointStats {
+	c := dbConnector(b)
+	defer c.Release()
+
+	var stats, err = c.database.GetCheckpointStats()
+	if err != nil {
+		b.Exit("db: cannot get checkpoint stats: %v", err)
+	}
+
+	return stats
+}
+
+// getShardQueries returns the number of the shard-local and the cross-shard ScyllaDB queries made by the workers since the test start,
+// both are zero for the other databases or if --scylla-shard-count is not set
+func getShardQueries(b *benchmark.Benchmark) (local int64, cross int64) {
+	for _, wd := range b.WorkerData {
+		var workerData, ok = wd.(*DBWorkerData)
+		if !ok || workerData.shardQueriesBefore == nil {
+			continue
+		}
+
+		var stats = workerData.workingConn.database.Stats()
+		local += stats.ShardLocalQueries - workerData.shardQueriesBefore.ShardLocalQueries
+		cross += stats.CrossShardQueries - workerData.shardQueriesBefore.CrossShardQueries
+	}
+
+	return local, cross
+}
+
+// getCheckpointStatsDiff returns the checkpoints made between two checkpoint statistics snapshots
+func getCheckpointStatsDiff(before, after db.CheckpointStats) db.CheckpointStats {
+	return db.CheckpointStats{
+		CheckpointsTimed:    after.CheckpointsTimed - before.CheckpointsTimed,
+		CheckpointsReq:      after.CheckpointsReq - before.CheckpointsReq,
+		CheckpointWriteTime: after.CheckpointWriteTime - before.CheckpointWriteTime,
+		CheckpointSyncTime:  after.CheckpointSyncTime - before.CheckpointSyncTime,
+		BuffersCheckpoint:   after.BuffersCheckpoint - before.BuffersCheckpoint,
+	}
+}
+
+// setPGCheckpointCompletionTarget sets the PostgreSQL checkpoint_completion_target server setting given by --pg-checkpoint-completion-target
+func setPGCheckpointCompletionTarget(b *benchmark.Benchmark, c *DBConnector, target float64) {
+	if c.database.DialectName() != db.POSTGRES {
+		b.Log(benchmark.LogWarn, 0, fmt.Sprintf("--pg-checkpoint-completion-target is not supported for '%s' database", c.database.DialectName()))
+		return
+	}
+
+	// checkpoint_completion_target can't be changed by SET, it is reloaded from the server configuration
+	var session = c.database.Session(c.database.Context(context.Background()))
+	if _, err := session.Exec(fmt.Sprintf("ALTER SYSTEM SET checkpoint_completion_target = %v", target)); err != nil {
+		b.Exit("db: cannot set checkpoint_completion_target: %v", err)
+	}
+	if _, err := session.Exec("SELECT pg_reload_conf()"); err != nil {
+		b.Exit("db: cannot reload the server configuration: %v", err)
+	}
+}
+
+// getIndexUsageStatsDiff formats the difference between two index usage statistics snapshots
+func getIndexUsageStatsDiff(before, after []db.IndexUsageStat) []string {
+	var ret []string
+
+	ret = append(ret, fmt.Sprintf("%-64s %12s %12s %15s %15s", "INDEX NAME", "SEQ SCANS", "IDX SCANS", "IDX TUP READ", "IDX TUP FETCH"))
+	ret = append(ret, fmt.Sprintf("%-64s %12s %12s %15s %15s", strings.Repeat("-", 64), strings.Repeat("-", 12), strings.Repeat("-", 12), strings.Repeat("-", 15), strings.Repeat("-", 15)))
+
+	var prev = make(map[string]db.IndexUsageStat, len(before))
+	for _, s := range before {
+		prev[s.IndexName] = s
+	}
+
+	for _, s := range after {
+		var p = prev[s.IndexName]
+		ret = append(ret, fmt.Sprintf("%-64s %12d %12d %15d %15d", s.IndexName,
+			s.SeqScans-p.SeqScans, s.IdxScans-p.IdxScans, s.IdxTupRead-p.IdxTupRead, s.IdxTupFetch-p.IdxTupFetch))
+	}
+
+	return ret
+}
+
+// analyzeTables refreshes the query planner statistics of all the existing benchmark tables
+func analyzeTables(b *benchmark.Benchmark, c *DBConnector) {
+	var format string
+	switch c.database.DialectName() {
+	case db.POSTGRES, db.COCKROACHDB, db.SQLITE:
+		format = "ANALYZE %s"
+	case db.MYSQL:
+		format = "ANALYZE TABLE %s"
+	case db.MSSQL:
+		format = "UPDATE STATISTICS %s"
+	default:
+		b.Log(benchmark.LogWarn, 0, fmt.Sprintf("--auto-analyze is not supported for '%s' database", c.database.DialectName()))
+		return
+	}
+
+	var tableNames = make([]string, 0, len(TestTables))
+	for tableName := range TestTables {
+		tableNames = append(tableNames, tableName)
+	}
+	sort.Strings(tableNames)
+
+	var start = time.Now()
+	var analyzed int
+	var session = c.database.Session(c.database.Context(context.Background()))
+
+	for _, tableName := range tableNames {
+		if exists, err := c.database.TableExists(tableName); err != nil {
+			b.Exit("db: cannot check if table '%s' exists: %v", tableName, err)
+		} else if !exists {
+			continue
+		}
+
+		if _, err := session.Exec(fmt.Sprintf(format, tableName)); err != nil {
+			b.Exit("db: cannot analyze table '%s': %v", tableName, err)
+		}
+		analyzed++
+	}
+
+	fmt.Printf("auto-analyze: %d tables analyzed in %.3f sec\n", analyzed, time.Since(start).Seconds())
+}
+
+// defragmentTables rebuilds all the existing benchmark tables and their indexes
+func defragmentTables(b *benchmark.Benchmark, c *DBConnector) {
+	switch c.database.DialectName() {
+	case db.POSTGRES, db.MYSQL, db.MSSQL, db.SQLITE:
+	default:
+		b.Log(benchmark.LogWarn, 0, fmt.Sprintf("--defragment-after-insert is not supported for '%s' database", c.database.DialectName()))
+		return
+	}
+
+	var tableNames = make([]string, 0, len(TestTables))
+	for tableName := range TestTables {
+		tableNames = append(tableNames, tableName)
+	}
+	sort.Strings(tableNames)
+
+	var start = time.Now()
+	var defragmented int
+
+	for _, tableName := range tableNames {
+		if exists, err := c.database.TableExists(tableName); err != nil {
+			b.Exit("db: cannot check if table '%s' exists: %v", tableName, err)
+		} else if !exists {
+			continue
+		}
+
+		if err := c.database.Defragment(tableName); err != nil {
+			b.Exit("db: cannot defragment table '%s': %v", tableName, err)
+		}
+		defragmented++
+	}
+
+	fmt.Printf("defragment: %d tables defragmented in %.3f sec\n", defragmented, time.Since(start).Seconds())
+}
+
+// hasQueryCache returns true if the MySQL server has the query cache, it was removed in MySQL 8.0 but is still present in MariaDB
+func hasQueryCache(c *DBConnector) (bool, error) {
+	var _, version, err = c.database.GetVersion()
+	if err != nil {
+		return false, err
+	}
+
+	if strings.Contains(version, "MariaDB") {
+		return true, nil
+	}
+
+	var majorStr, _, _ = strings.Cut(version, ".")
+	var major int
+	if major, err = strconv.Atoi(majorStr); err != nil {
+		return false, fmt.Errorf("cannot parse MySQL version '%s': %v", version, err)
+	}
+
+	return major < 8, nil
+}
+
+// setQueryCache switches the MySQL query cache on with the --query-cache-size or off, returns false if the server has no query cache
+func setQueryCache(b *benchmark.Benchmark, c *DBConnector, enabled bool) bool {
+	if c.database.DialectName() != db.MYSQL {
+		b.Log(benchmark.LogWarn, 0, fmt.Sprintf("--enable-query-cache is not supported for '%s' database", c.database.DialectName()))
+		return false
+	}
+
+	if supported, err := hasQueryCache(c); err != nil {
+		b.Exit("db: cannot get MySQL version: %v", err)
+	} else if !supported {
+		b.Log(benchmark.LogWarn, 0, "the query cache was removed in MySQL 8.0, skipping")
+		return false
+	}
+
+	var session = c.database.Session(c.database.Context(context.Background()))
+	if !enabled {
+		if _, err := session.Exec("SET GLOBAL query_cache_type = OFF"); err != nil {
+			b.Exit("db: cannot disable query cache: %v", err)
+		}
+
+		return true
+	}
+
+	if _, err := session.Exec("SET GLOBAL query_cache_type = ON"); err != nil {
+		b.Exit("db: cannot enable query cache: %v", err)
+	}
+	if _, err := session.Exec(fmt.Sprintf("SET GLOBAL query_cache_size = %d", b.TestOpts.(*TestOpts).DBOpts.QueryCacheSize)); err != nil {
+		b.Exit("db: cannot set query cache size: %v", err)
+	}
+
+	return true
+}
+
+// mysqlOptimizerSwitchHook returns the connect hook setting the MySQL optimizer_switch of every new connection
+func mysqlOptimizerSwitchHook(optimizerSwitch string) func(db.Database) error {
+	return func(d db.Database) error {
+		if _, err := d.Session(d.Context(context.Background())).Exec(fmt.Sprintf("SET SESSION optimizer_switch = '%s'", optimizerSwitch)); err != nil {
+			return fmt.Errorf("cannot set optimizer_switch '%s': %v", optimizerSwitch, err)
+		}
+
+		return nil
+	}
+}
+
+// HeavyMaterializedViewName is a name of the PostgreSQL materialized view aggregating the 'heavy' table per tenant
+const HeavyMaterializedViewName = "acronis_db_bench_heavy_mv"
+
+// createHeavyMaterializedView creates the 'heavy' table materialized view and its unique index required for the concurrent refresh
+func createHeavyMaterializedView(session db.Session) error {
+	if _, err := session.Exec(fmt.Sprintf("CREATE MATERIALIZED VIEW IF NOT EXISTS %s AS "+
+		"SELECT tenant_id, COUNT(*) AS rows_count, MAX(completion_time) AS max_completion_time FROM %s GROUP BY tenant_id",
+		HeavyMaterializedViewName, TestTableHeavy.TableName)); err != nil {
+		return err
+	}
+
+	_, err := session.Exec(fmt.Sprintf("CREATE UNIQUE INDEX IF NOT EXISTS %[1]s_tenant_id_idx ON %[1]s (tenant_id)", HeavyMaterializedViewName))
+
+	return err
+}
+
+// refreshHeavyMaterializedView refreshes the 'heavy' table materialized view without locking out the concurrent selects and returns the refresh latency
+func refreshHeavyMaterializedView(session db.Session) (time.Duration, error) {
+	var start = time.Now()
+	_, err := session.Exec(fmt.Sprintf("REFRESH MATERIALIZED VIEW CONCURRENTLY %s", HeavyMaterializedViewName))
+
+	return time.Since(start), err
+}
+
+// startHeavyMaterializedViewRefresher refreshes the 'heavy' table materialized view every --mv-refresh-interval seconds until the returned stop function is called
+func startHeavyMaterializedViewRefresher(b *benchmark.Benchmark) (stop func()) {
+	var interval = b.TestOpts.(*TestOpts).TestcaseOpts.MVRefreshInterval
+	if interval <= 0 {
+		return func() {}
+	}
+
+	if dialectName, err := db.GetDialectName(b.TestOpts.(*TestOpts).DBOpts.ConnString); err != nil || dialectName != db.POSTGRES {
+		b.Log(benchmark.LogWarn, 0, "--mv-refresh-interval is supported for PostgreSQL only")
+		return func() {}
+	}
+
+	var pool *benchmark.WorkerPool
+	pool = benchmark.NewWorkerPool(1, func(int) {
+		var c = dbConnector(b)
+		defer c.Release()
+
+		var session = c.database.Session(c.database.Context(context.Background()))
+		var ticker = time.NewTicker(time.Duration(interval) * time.Second)
+		defer ticker.Stop()
+
+		var created bool
+		var refreshes int
+		var total, maxLatency time.Duration
+
+		for {
+			select {
+			case <-pool.Context().Done():
+				if refreshes > 0 {
+					fmt.Printf("materialized view refresh: refreshes: %d; avg latency: %.3f sec; max latency: %.3f sec\n",
+						refreshes, total.Seconds()/float64(refreshes), maxLatency.Seconds())
+				}
+				return
+			case <-ticker.C:
+				if !created {
+					if err := createHeavyMaterializedView(session); err != nil {
+						b.Exit("db: cannot create materialized view '%s': %v", HeavyMaterializedViewName, err)
+					}
+					created = true
+				}
+
+				var latency, err = refreshHeavyMaterializedView(session)
+				if err != nil {
+					b.Exit("db: cannot refresh materialized view '%s': %v", HeavyMaterializedViewName, err)
+				}
+
+				refreshes++
+				total += latency
+				if latency > maxLatency {
+					maxLatency = latency
+				}
+			}
+		}
+	})
+	pool.Start()
+
+	return func() {
+		pool.Stop()
+		pool.Wait()
+	}
+}
+
+// HeavyTrigramIndexName is a name of the PostgreSQL pg_trgm GIN index on the 'heavy' table resource_name column
+const HeavyTrigramIndexName = "acronis_db_bench_heavy_resource_name_trgm_idx"
+
+// createHeavyTrigramIndex enables the pg_trgm extension and creates the 'heavy' table trigram index accelerating the LIKE '%...%' queries
+func createHeavyTrigramIndex(session db.Session) error {
+	if _, err := session.Exec("CREATE EXTENSION IF NOT EXISTS pg_trgm"); err != nil {
+		return err
+	}
+
+	_, err := session.Exec(fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s ON %s USING gin (resource_name gin_trgm_ops)",
+		HeavyTrigramIndexName, TestTableHeavy.TableName))
+
+	return err
+}
+
+// createPGVectorExtension enables the pgvector extension providing the vector column type of the 'pgvector' table
+func createPGVectorExtension(session db.Session) error {
+	_, err := session.Exec("CREATE EXTENSION IF NOT EXISTS vector")
+
+	return err
+}
+
+// FTSIndexName is a name of the 'fts' table full-text index, FTSKeyIndexName and FTSCatalogName are the MSSQL full-text index key index and catalog
+const (
+	FTSIndexName    = "acronis_db_bench_fts_idx"
+	FTSKeyIndexName = "acronis_db_bench_fts_key_idx"
+	FTSCatalogName  = "acronis_db_bench_fts_catalog"
+)
+
+// createMSSQLFullTextIndex creates the MSSQL full-text catalog and the 'fts' table full-text index if they are missing,
+// MSSQL doesn't allow the full-text DDL within the transaction the table is created by
+func createMSSQLFullTextIndex(session db.Session) error {
+	if _, err := session.Exec(fmt.Sprintf("IF NOT EXISTS (SELECT 1 FROM sys.fulltext_catalogs WHERE name = '%s') CREATE FULLTEXT CATALOG %s",
+		FTSCatalogName, FTSCatalogName)); err != nil {
+		return err
+	}
+
+	_, err := session.Exec(fmt.Sprintf("IF NOT EXISTS (SELECT 1 FROM sys.fulltext_indexes WHERE object_id = OBJECT_ID('%s')) "+
+		"CREATE FULLTEXT INDEX ON %s (body LANGUAGE 1033) KEY INDEX %s ON %s WITH CHANGE_TRACKING AUTO",
+		TestTableFTS.TableName, TestTableFTS.TableName, FTSKeyIndexName, FTSCatalogName))
+
+	return err
+}
+
+// HeavyStateMinMaxIndexName is a name of the ClickHouse minmax data skipping index on the 'heavy' table state column
+const HeavyStateMinMaxIndexName = "acronis_db_bench_heavy_state_minmax_idx"
+
+// createHeavyStateMinMaxIndex creates the 'heavy' table minmax data skipping index and builds it for the already inserted rows
+func createHeavyStateMinMaxIndex(session db.Session) error {
+	if _, err := session.Exec(fmt.Sprintf("ALTER TABLE %s ADD INDEX IF NOT EXISTS %s state TYPE minmax GRANULARITY 1",
+		TestTableHeavy.TableName, HeavyStateMinMaxIndexName)); err != nil {
+		return err
+	}
+
+	_, err := session.Exec(fmt.Sprintf("ALTER TABLE %s MATERIALIZE INDEX %s", TestTableHeavy.TableName, HeavyStateMinMaxIndexName))
+
+	return err
+}
+
+// explainUsesIndex returns true if the query plan given by the explain statement (e.g. EXPLAIN) refers the given index
+func explainUsesIndex(session db.Session, explain string, query string, indexName string) (bool, error) {
+	var rows, err = session.Query(explain + " " + query)
+	if err != nil {
+		return false, err
+	}
+	defer rows.Close()
+
+	var used bool
+	for rows.Next() {
+		var line string
+		if err = rows.Scan(&line); err != nil {
+			return false, err
+		}
+		used = used || strings.Contains(line, indexName)
+	}
+
+	return used, rows.Err()
+}
+
+// explainQueryPlan returns the query plan lines, the columns of the multi-column plans (MySQL, SQLite) are separated by ' | '
+func explainQueryPlan(c *DBConnector, query string) ([]string, error) {
+	var rawDB, ok = c.database.RawSession().(*sql.DB)
+	if !ok {
+		return nil, fmt.Errorf("query plan is not supported for '%s' database", c.database.DialectName())
+	}
+
+	var ctx = context.Background()
+	var conn, err = rawDB.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close() //nolint:errcheck
+
+	switch c.database.DialectName() {
+	case db.MSSQL:
+		// SHOWPLAN_TEXT must be the only statement of the batch, the plan is returned instead of the query results then
+		if _, err = conn.ExecContext(ctx, "SET SHOWPLAN_TEXT ON"); err != nil {
+			return nil, err
+		}
+		defer conn.ExecContext(ctx, "SET SHOWPLAN_TEXT OFF") //nolint:errcheck
+	case db.SQLITE:
+		query = "EXPLAIN QUERY PLAN " + query
+	default:
+		query = "EXPLAIN " + query
+	}
+
+	var rows *sql.Rows
+	if rows, err = conn.QueryContext(ctx, query); err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var columns []string
+	if columns, err = rows.Columns(); err != nil {
+		return nil, err
+	}
+
+	var plan []string
+	for rows.Next() {
+		var values = make([]sql.NullString, len(columns))
+		var dest = make([]interface{}, len(columns))
+		for i := range values {
+			dest[i] = &values[i]
+		}
+		if err = rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+
+		var line = make([]string, len(values))
+		for i, v := range values {
+			line[i] = v.String
+		}
+		plan = append(plan, strings.Join(line, " | "))
+	}
+
+	return plan, rows.Err()
+}
+
+// prefetchIDsLimit is the maximum number of the table IDs pre-loaded with --prefetch-ids
+const prefetchIDsLimit = 100000
+
+// prefetchIDs loads up to prefetchIDsLimit IDs of the table in random order, see --prefetch-ids
+func prefetchIDs(c *DBConnector, tableName string) ([]uint64, error) {
+	var query string
+	switch c.database.DialectName() {
+	case db.POSTGRES, db.COCKROACHDB, db.SQLITE:
+		query = fmt.Sprintf("SELECT id FROM %s ORDER BY RANDOM() LIMIT %d", tableName, prefetchIDsLimit)
+	case db.MYSQL:
+		query = fmt.Sprintf("SELECT id FROM %s ORDER BY RAND() LIMIT %d", tableName, prefetchIDsLimit)
+	case db.CLICKHOUSE:
+		query = fmt.Sprintf("SELECT id FROM %s ORDER BY rand() LIMIT %d", tableName, prefetchIDsLimit)
+	case db.MSSQL:
+		query = fmt.Sprintf("SELECT TOP %d id FROM %s ORDER BY NEWID()", prefetchIDsLimit, tableName)
+	default:
+		return nil, fmt.Errorf("unsupported dialect %s", c.database.DialectName())
+	}
+
+	var session = c.database.Session(c.database.Context(context.Background()))
+	var rows, err = session.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids = make([]uint64, 0, prefetchIDsLimit)
+	for rows.Next() {
+		var id int64
+		if err = rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, uint64(id))
+	}
+
+	return ids, rows.Err()
+}
+
+// randomID returns a random row ID of the test table, the worker cycles through the prefetched IDs if --prefetch-ids is set
+func randomID(b *benchmark.Benchmark, workerID int, testDesc *TestDesc) uint64 {
+	var ids = b.Vault.(*DBTestData).prefetchedIDs
+	if len(ids) == 0 {
+		return b.Randomizer.GetWorker(workerID).Uintn64(testDesc.table.RowsCount - 1)
+	}
+
+	// every worker starts from its own offset not to select the same rows as the other workers in lockstep
+	var workerData = b.WorkerData[workerID].(*DBWorkerData)
+	var id = ids[(workerID*len(ids)/b.CommonOpts.Workers+workerData.prefetchedIDsPos)%len(ids)]
+	workerData.prefetchedIDsPos++
+
+	return id
+}
+
+// peakMemorySampleInterval is the interval the Go memory usage is sampled by startPeakMemorySampler
+const peakMemorySampleInterval = 100 * time.Millisecond
+
+// startPeakMemorySampler samples the Go memory usage until the returned stop function is called and prints the peak values then
+func startPeakMemorySampler() (stop func()) {
+	var pool *benchmark.WorkerPool
+	pool = benchmark.NewWorkerPool(1, func(int) {
+		var ticker = time.NewTicker(peakMemorySampleInterval)
+		defer ticker.Stop()
+
+		var stats runtime.MemStats
+		var peakHeapInuse, peakSys uint64
+
+		var sample = func() {
+			runtime.ReadMemStats(&stats)
+			if stats.HeapInuse > peakHeapInuse {
+				peakHeapInuse = stats.HeapInuse
+			}
+			if stats.Sys > peakSys {
+				peakSys = stats.Sys
+			}
+		}
+
+		for {
+			select {
+			case <-pool.Context().Done():
+				sample()
+				fmt.Printf("peak memory usage: heap in use: %.1f MB; obtained from OS: %.1f MB\n",
+					float64(peakHeapInuse)/(1024*1024), float64(peakSys)/(1024*1024))
+				return
+			case <-ticker.C:
+				sample()
+			}
+		}
+	})
+	pool.Start()
+
+	return func() {
+		pool.Stop()
+		pool.Wait()
+	}
+}
+
+// WorkerPoolStatus is the DB connection pool status of the worker working connection
+type WorkerPoolStatus struct {
+	WorkerID int `json:"worker_id"`
+	PoolStatus
+}
+
+// getPoolStatuses returns the DB connection pool status of every worker of the running test
+func getPoolStatuses(b *benchmark.Benchmark) []WorkerPoolStatus {
+	var statuses = []WorkerPoolStatus{}
+	for workerID, data := range b.WorkerData {
+		if workerData, ok := data.(*DBWorkerData); ok && workerData.workingConn != nil {
+			statuses = append(statuses, WorkerPoolStatus{WorkerID: workerID, PoolStatus: workerData.workingConn.GetConnectionPoolStatus()})
+		}
+	}
+
+	return statuses
+}
+
+// poolStatusHandler serves the DB connection pool status of every worker as JSON
+func poolStatusHandler(b *benchmark.Benchmark) http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(getPoolStatuses(b)); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	}
+}
+
+// startPoolStatusLogger logs the DB connection pool status of every worker each given interval until the returned stop function is called
+func startPoolStatusLogger(b *benchmark.Benchmark, interval time.Duration) (stop func()) {
+	var pool *benchmark.WorkerPool
+
+	pool = benchmark.NewWorkerPool(1, func(int) {
+		var ticker = time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-pool.Context().Done():
+				return
+			case <-ticker.C:
+				for _, s := range getPoolStatuses(b) {
+					b.Log(benchmark.LogInfo, s.WorkerID, fmt.Sprintf("pool status: max open: %d; open: %d; in use: %d; idle: %d; wait count: %d; max idle time: %s; max lifetime: %s",
+						s.MaxOpen, s.Open, s.InUse, s.Idle, s.WaitCount, s.MaxIdleTime, s.MaxLifetime))
+					if r := s.Read; r != nil {
+						b.Log(benchmark.LogInfo, s.WorkerID, fmt.Sprintf("read pool status: max open: %d; open: %d; in use: %d; idle: %d; wait count: %d; max idle time: %s; max lifetime: %s",
+							r.MaxOpen, r.Open, r.InUse, r.Idle, r.WaitCount, r.MaxIdleTime, r.MaxLifetime))
+					}
+				}
+			}
+		}
+	})
+	pool.Start()
+
+	return func() {
+		pool.Stop()
+		pool.Wait()
+	}
+}
+
+// lockWaitSampleInterval is the interval the DB lock waits are sampled by startLockWaitSampler
+const lockWaitSampleInterval = 5 * time.Second
+
+// startLockWaitSampler samples the DB lock waits until the returned stop function is called, stop returns all the sampled lock wait incidents
+func startLockWaitSampler(b *benchmark.Benchmark) (stop func() []db.LockWaitStat) {
+	var c = dbConnector(b)
+	var pool *benchmark.WorkerPool
+	var incidents []db.LockWaitStat
+
+	pool = benchmark.NewWorkerPool(1, func(int) {
+		var ticker = time.NewTicker(lockWaitSampleInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-pool.Context().Done():
+				return
+			case <-ticker.C:
+				var stats, err = c.database.GetLockWaitStats()
+				if err != nil {
+					c.Log(benchmark.LogWarn, "db: cannot get lock wait stats: %v", err)
+					continue
+				}
+				incidents = append(incidents, stats...)
+			}
+		}
+	})
+	pool.Start()
+
+	return func() []db.LockWaitStat {
+		pool.Stop()
+		pool.Wait()
+		c.Close() //nolint:errcheck
+
+		return incidents
+	}
+}
+
+// startActiveQueriesLogger prints the DB queries running longer than db.ActiveQueryMinDuration each given interval until the returned stop function is called
+func startActiveQueriesLogger(b *benchmark.Benchmark, interval time.Duration) (stop func()) {
+	var c = dbConnector(b)
+	var pool *benchmark.WorkerPool
+
+	pool = benchmark.NewWorkerPool(1, func(int) {
+		var ticker = time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-pool.Context().Done():
+				return
+			case <-ticker.C:
+				var queries, err = c.database.GetActiveQueries()
+				if err != nil {
+					c.Log(benchmark.LogWarn, "db: cannot get active queries: %v", err)
+					continue
+				}
+				if len(queries) == 0 {
+					continue
+				}
+				fmt.Printf("\nACTIVE QUERIES (%d):\n\n%s\n\n", len(queries), strings.Join(getActiveQueriesReport(queries), "\n"))
+			}
+		}
+	})
+	pool.Start()
+
+	return func() {
+		pool.Stop()
+		pool.Wait()
+		c.Close() //nolint:errcheck
+	}
+}
+
+// getActiveQueriesReport formats the active queries, one query per line
+func getActiveQueriesReport(queries []db.ActiveQuery) []string {
+	var ret []string
+	ret = append(ret, fmt.Sprintf("%8s %-16s %-12s %12s %-24s  %s", "PID", "USER", "STATE", "DURATION", "WAIT EVENT", "QUERY"))
+	ret = append(ret, fmt.Sprintf("%8s %-16s %-12s %12s %-24s  %s", strings.Repeat("-", 8), strings.Repeat("-", 16), strings.Repeat("-", 12),
+		strings.Repeat("-", 12), strings.Repeat("-", 24), strings.Repeat("-", 64)))
+	for _, q := range queries {
+		ret = append(ret, fmt.Sprintf("%8d %-16s %-12s %12s %-24s  %s", q.PID, q.User, q.State, q.Duration.Round(time.Millisecond), q.WaitEvent,
+			strings.Join(strings.Fields(q.Query), " ")))
+	}
+
+	return ret
+}
+
+// getLockWaitReport formats the lock wait incidents grouped by the waiting query, the most frequent first
+func getLockWaitReport(incidents []db.LockWaitStat) []string {
+	type queryLockWaits struct {
+		query   string
+		count   int
+		maxWait time.Duration
+	}
+
+	var byQuery = make(map[string]*queryLockWaits)
+	for _, s := range incidents {
+		var w, ok = byQuery[s.WaitingQuery]
+		if !ok {
+			w = &queryLockWaits{query: s.WaitingQuery}
+			byQuery[s.WaitingQuery] = w
+		}
+		w.count++
+		if s.WaitDuration > w.maxWait {
+			w.maxWait = s.WaitDuration
+		}
+	}
+
+	var waits = make([]*queryLockWaits, 0, len(byQuery))
+	for _, w := range byQuery {
+		waits = append(waits, w)
+	}
+	sort.Slice(waits, func(i, j int) bool {
+		if waits[i].count != waits[j].count {
+			return waits[i].count > waits[j].count
+		}
+		return waits[i].query < waits[j].query
+	})
+
+	var ret []string
+	ret = append(ret, fmt.Sprintf("%10s %12s  %s", "INCIDENTS", "MAX WAIT", "WAITING QUERY"))
+	ret = append(ret, fmt.Sprintf("%10s %12s  %s", strings.Repeat("-", 10), strings.Repeat("-", 12), strings.Repeat("-", 64)))
+	for _, w := range waits {
+		ret = append(ret, fmt.Sprintf("%10d %12s  %s", w.count, w.maxWait.Round(time.Millisecond), w.query))
+	}
+
+	return ret
+}
+
+// blobBytes returns the total size of the []byte values (e.g. generated blobs) to be accounted by benchmark.AddBytes
+func blobBytes(values []interface{}) int64 {
+	var n int64
+	for _, v := range values {
+		if blob, ok := v.([]byte); ok {
+			n += int64(len(blob))
+		}
+	}
+
+	return n
+}
+
+// chaosMinBackoff and chaosMaxBackoff bound the exponential backoff the DB availability is re-checked with during the --chaos-mode outage
+const (
+	chaosMinBackoff = 100 * time.Millisecond
+	chaosMaxBackoff = 2 * time.Second
+)
+
+// chaosGracePeriod is the time the worker errors are still attributed to the --chaos-mode outage after the DB recovery,
+// it covers the pooled connections broken by the restart
+const chaosGracePeriod = 5 * time.Second
+
+// chaosStats is a summary of the DB outages caused by --chaos-mode during the test
+type chaosStats struct {
+	events            uint64
+	meanRecoveryTime  time.Duration
+	opsDuringDowntime uint64
+}
+
+// chaosMonkey restarts the embedded PostgreSQL during the test and tracks the outages, see --chaos-mode
+type chaosMonkey struct {
+	lock              sync.Mutex
+	down              bool
+	recoveredAt       time.Time
+	events            uint64
+	recoveryTime      time.Duration
+	opsDuringDowntime uint64
+}
+
+// isDown returns true if the DB is stopped or doesn't accept the queries after the restart yet
+func (m *chaosMonkey) isDown() bool {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	return m.down
+}
+
+// isAffected returns true if the DB is down or has been recovered less than gracePeriod ago
+func (m *chaosMonkey) isAffected(gracePeriod time.Duration) bool {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	return m.down || (!m.recoveredAt.IsZero() && time.Since(m.recoveredAt) < gracePeriod)
+}
+
+// outage stops and starts the embedded PostgreSQL, the recovery time is measured till the DB accepts the ping again
+func (m *chaosMonkey) outage(b *benchmark.Benchmark, c *DBConnector) {
+	var loopsBefore = b.DoneLoops()
+	var start = time.Now()
+
+	m.lock.Lock()
+	m.down = true
+	m.lock.Unlock()
+
+	if err := pgmbed.Stop(); err != nil {
+		c.Log(benchmark.LogWarn, "chaos: cannot stop embedded PostgreSQL: %v", err)
+
+		m.lock.Lock()
+		m.down = false
+		m.lock.Unlock()
+
+		return
+	}
+	c.Log(benchmark.LogInfo, "chaos: embedded PostgreSQL is stopped")
+
+	if err := pgmbed.Start(); err != nil {
+		c.Exit("chaos: cannot restart embedded PostgreSQL: %v", err)
+	}
+
+	for backoff := chaosMinBackoff; c.database.Ping(context.Background()) != nil; backoff = min(2*backoff, chaosMaxBackoff) {
+		time.Sleep(backoff)
+	}
+
+	var recoveryTime = time.Since(start)
+	c.Log(benchmark.LogInfo, "chaos: embedded PostgreSQL is recovered in %s", recoveryTime.Round(time.Millisecond))
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.down = false
+	m.recoveredAt = time.Now()
+	m.events++
+	m.recoveryTime += recoveryTime
+	m.opsDuringDowntime += b.DoneLoops() - loopsBefore
+}
+
+// stats returns the summary of the outages made so far
+func (m *chaosMonkey) stats() chaosStats {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	var s = chaosStats{events: m.events, opsDuringDowntime: m.opsDuringDowntime}
+	if m.events > 0 {
+		s.meanRecoveryTime = m.recoveryTime / time.Duration(m.events)
+	}
+
+	return s
+}
+
+// startChaosMonkey restarts the embedded PostgreSQL every given interval until the returned stop function is called,
+// stop returns the summary of the outages
+func startChaosMonkey(b *benchmark.Benchmark, interval time.Duration) (stop func() chaosStats) {
+	var c = dbConnector(b)
+	var pool *benchmark.WorkerPool
+	var m = &chaosMonkey{}
+
+	b.Vault.(*DBTestData).chaos = m
+
+	pool = benchmark.NewWorkerPool(1, func(int) {
+		var ticker = time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-pool.Context().Done():
+				return
+			case <-ticker.C:
+				m.outage(b, c)
+			}
+		}
+	})
+	pool.Start()
+
+	return func() chaosStats {
+		pool.Stop()
+		pool.Wait()
+		c.Close() //nolint:errcheck
+
+		b.Vault.(*DBTestData).chaos = nil
+
+		return m.stats()
+	}
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	influxdb2 "github.com/influxdata/influxdb-client-go/v2"
+	"github.com/influxdata/influxdb-client-go/v2/api"
+
+	"github.com/acronis/perfkit/benchmark"
+)
+
+// influxMeasurement is the InfluxDB measurement of the test scores
+const influxMeasurement = "perfkit_benchmark"
+
+// influxExporter writes the scores of the executed tests to the --influxdb-url bucket, the points are written
+// by the non-blocking client in the background so a slow InfluxDB doesn't delay the tests
+type influxExporter struct {
+	client   influxdb2.Client
+	writeAPI api.WriteAPI
+	host     string
+
+	errors  *benchmark.WorkerPool // logs the write errors until the client is closed
+	flusher *benchmark.WorkerPool // sends the points requested by flush until close is called
+	flushes chan struct{}
+}
+
+// newInfluxExporter creates the exporter to the given InfluxDB bucket, the write errors are logged as warnings
+func newInfluxExporter(b *benchmark.Benchmark, url string, token string, org string, bucket string) *influxExporter {
+	var e = &influxExporter{client: influxdb2.NewClient(url, token), flushes: make(chan struct{}, 1)}
+	e.writeAPI = e.client.WriteAPI(org, bucket)
+	e.host, _ = os.Hostname()
+
+	var writeErrors = e.writeAPI.Errors()
+	e.errors = benchmark.NewWorkerPool(1, func(int) {
+		for err := range writeErrors {
+			b.Log(benchmark.LogWarn, 0, fmt.Sprintf("influxdb: failed to write the test score: %v", err))
+		}
+	})
+	e.errors.Start()
+
+	e.flusher = benchmark.NewWorkerPool(1, func(int) {
+		for {
+			select {
+			case <-e.flusher.Context().Done():
+				return
+			case <-e.flushes:
+				e.writeAPI.Flush()
+			}
+		}
+	})
+	e.flusher.Start()
+
+	return e
+}
+
+// write queues the point of the test score, the point is sent by the next flush
+func (e *influxExporter) write(testName string, dbDriver string, dbVersion string, score benchmark.Score) {
+	e.writeAPI.WritePoint(influxdb2.NewPoint(influxMeasurement,
+		map[string]string{
+			"test_name":  testName,
+			"db_driver":  dbDriver,
+			"db_version": dbVersion,
+			"host":       e.host,
+		},
+		map[string]interface{}{
+			"rate":    score.Rate,
+			"workers": score.Workers,
+			"loops":   score.Loops,
+			"p95_ns":  score.P95.Nanoseconds(),
+			"p99_ns":  score.P99.Nanoseconds(),
+		},
+		time.Now()))
+}
+
+// flush sends the queued points in the background, the client also flushes them every second on its own
+func (e *influxExporter) flush() {
+	select {
+	case e.flushes <- struct{}{}:
+	default: // the flush is already requested
+	}
+}
+
+// close sends the queued points and closes the client
+func (e *influxExporter) close() {
+	e.flusher.Stop()
+	e.flusher.Wait()
+
+	e.client.Close()
+	e.errors.Wait()
+}
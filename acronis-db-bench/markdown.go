@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/acronis/perfkit/db"
+)
+
+// MarkdownDescriber writes the tests reference documentation in Markdown format
+type MarkdownDescriber struct{}
+
+// Write writes a section per test group with the name, metric, supported databases and description of every test
+func (d *MarkdownDescriber) Write(groups []*TestGroup, w io.Writer) error {
+	var databases = db.GetDatabases()
+
+	var header = "| Test | Metric |"
+	var separator = "|------|--------|"
+	for _, database := range databases {
+		header += " " + database.Name + " |"
+		separator += ":---:|"
+	}
+	header += " Description |\n"
+	separator += "-------------|\n"
+
+	if _, err := fmt.Fprint(w, "# Acronis Database Benchmark tests\n"); err != nil {
+		return err
+	}
+
+	for _, g := range groups {
+		var names = make([]string, 0, len(g.tests))
+		for name := range g.tests {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		if _, err := fmt.Fprintf(w, "\n## %s\n\n%s%s", g.name, header, separator); err != nil {
+			return err
+		}
+
+		for _, name := range names {
+			var t = g.tests[name]
+
+			var row = fmt.Sprintf("| `%s` | %s |", t.name, t.metric)
+			for _, database := range databases {
+				if t.dbIsSupported(database.Driver) {
+					row += " ✅ |"
+				} else {
+					row += " ❌ |"
+				}
+			}
+			row += " " + strings.ReplaceAll(t.description, "|", "\\|") + " |\n"
+
+			if _, err := fmt.Fprint(w, row); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// writeTestsMarkdown writes the Markdown reference of all the tests to given file
+func writeTestsMarkdown(path string) error {
+	var f, err = os.Create(path)
+	if err != nil {
+		return fmt.Errorf("cannot create tests description file: %v", err)
+	}
+
+	var groups, _ = GetTests()
+	if err = (&MarkdownDescriber{}).Write(groups, f); err != nil {
+		f.Close() //nolint:errcheck
+		return fmt.Errorf("cannot write tests description file: %v", err)
+	}
+
+	return f.Close()
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/acronis/perfkit/benchmark"
+)
+
+// metricsShutdownTimeout is the time the --prometheus-port server is given to finish the running scrapes on exit
+const metricsShutdownTimeout = 5 * time.Second
+
+// benchmarkMetrics exposes the scores of the executed tests at the --prometheus-port /metrics endpoint
+type benchmarkMetrics struct {
+	server *http.Server
+	pool   *benchmark.WorkerPool // serves the server
+
+	opsPerSecond *prometheus.GaugeVec
+	latencyP99   *prometheus.GaugeVec
+	workers      *prometheus.GaugeVec
+	errors       *prometheus.CounterVec
+}
+
+// newBenchmarkMetrics registers the benchmark metrics and starts serving them on given port
+func newBenchmarkMetrics(b *benchmark.Benchmark, port int) *benchmarkMetrics {
+	var labels = []string{"test_name", "db_driver"}
+	var m = &benchmarkMetrics{
+		opsPerSecond: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "benchmark_ops_per_second",
+			Help: "Rate of the last run of the test in the test metric units per second.",
+		}, labels),
+		latencyP99: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "benchmark_latency_p99_seconds",
+			Help: "99th percentile loop latency of the last run of the test.",
+		}, labels),
+		workers: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "benchmark_workers",
+			Help: "Number of workers of the last run of the test.",
+		}, labels),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "benchmark_errors_total",
+			Help: "Non-fatal errors of all the runs of the test.",
+		}, labels),
+	}
+
+	var registry = prometheus.NewRegistry()
+	registry.MustRegister(m.opsPerSecond, m.latencyP99, m.workers, m.errors)
+
+	var mux = http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+	m.server = &http.Server{Addr: fmt.Sprintf(":%d", port), Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+
+	// the port is bound before the server is started by the pool, b.Exit calls shutdown waiting for the pool
+	var listener, err = net.Listen("tcp", m.server.Addr)
+	if err != nil {
+		b.Exit("Failed to start prometheus metrics server: %v", err)
+	}
+
+	m.pool = benchmark.NewWorkerPool(1, func(int) {
+		if err := m.server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			b.Log(benchmark.LogError, 0, fmt.Sprintf("prometheus metrics server failed: %v", err))
+		}
+	})
+	m.pool.Start()
+
+	return m
+}
+
+// update sets the metrics of the test to the given score
+func (m *benchmarkMetrics) update(testName string, dbDriver string, score benchmark.Score) {
+	m.opsPerSecond.WithLabelValues(testName, dbDriver).Set(score.Rate)
+	m.latencyP99.WithLabelValues(testName, dbDriver).Set(score.P99.Seconds())
+	m.workers.WithLabelValues(testName, dbDriver).Set(float64(score.Workers))
+	m.errors.WithLabelValues(testName, dbDriver).Add(float64(score.Errors))
+}
+
+// shutdown stops the metrics server and waits for it to return
+func (m *benchmarkMetrics) shutdown() {
+	var ctx, cancel = context.WithTimeout(context.Background(), metricsShutdownTimeout)
+	defer cancel()
+
+	m.server.Shutdown(ctx) //nolint:errcheck
+	m.pool.Wait()
+}
@@ -0,0 +1,7 @@
+//go:build mongo
+
+package main
+
+import (
+	_ "github.com/acronis/perfkit/db/mongo" // mongodb driver
+)
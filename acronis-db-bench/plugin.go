@@ -0,0 +1,101 @@
+package main
+
+import (
+	"fmt"
+	"plugin"
+
+	"github.com/acronis/perfkit/benchmark"
+	"github.com/acronis/perfkit/db"
+)
+
+// PluginSymbolName is the name of the variable implementing benchmark.Plugin the --plugin shared object must export
+const PluginSymbolName = "BenchmarkPlugin"
+
+// pluginTests is the group of the tests loaded by --plugin, it's merged into GetTests
+var pluginTests *TestGroup
+
+// loadTestPlugin opens the Go plugin built with -buildmode=plugin, registers it and merges its tests group
+func loadTestPlugin(path string) error {
+	var p, err = plugin.Open(path)
+	if err != nil {
+		return fmt.Errorf("cannot open plugin %s: %v", path, err)
+	}
+
+	sym, err := p.Lookup(PluginSymbolName)
+	if err != nil {
+		return fmt.Errorf("cannot find %s symbol in plugin %s: %v", PluginSymbolName, path, err)
+	}
+
+	var benchmarkPlugin benchmark.Plugin
+	switch s := sym.(type) {
+	case benchmark.Plugin:
+		benchmarkPlugin = s
+	case *benchmark.Plugin:
+		benchmarkPlugin = *s
+	default:
+		return fmt.Errorf("%s symbol of plugin %s doesn't implement benchmark.Plugin interface", PluginSymbolName, path)
+	}
+
+	var groups, tests = GetTests()
+	var names = make(map[string][]string, len(groups))
+	for _, g := range groups {
+		for name := range g.tests {
+			names[g.name] = append(names[g.name], name)
+		}
+	}
+
+	var group = benchmarkPlugin.Register(names)
+	if group == nil || len(group.Tests) == 0 {
+		return fmt.Errorf("plugin %s registered no tests", path)
+	}
+
+	var groupName = group.Name
+	if groupName == "" {
+		groupName = "Plugin tests group"
+	}
+
+	var pluginGroup = NewTestGroup(groupName)
+	for _, testPlugin := range group.Tests {
+		if _, exists := tests[testPlugin.Name()]; exists {
+			return fmt.Errorf("plugin %s test name '%s' collides with existing test", path, testPlugin.Name())
+		}
+		if _, exists := pluginGroup.tests[testPlugin.Name()]; exists {
+			return fmt.Errorf("plugin %s test name '%s' is registered twice", path, testPlugin.Name())
+		}
+		pluginGroup.tests[testPlugin.Name()] = newPluginTestDesc(testPlugin)
+	}
+	pluginTests = pluginGroup
+
+	return nil
+}
+
+// newPluginTestDesc returns the test description running the plugin test
+func newPluginTestDesc(p benchmark.TestPlugin) *TestDesc {
+	var databases []db.DialectName
+	for _, d := range p.Databases() {
+		databases = append(databases, db.DialectName(d))
+	}
+
+	return &TestDesc{
+		name:        p.Name(),
+		metric:      "ops/sec",
+		description: p.Description(),
+		category:    TestOther,
+		databases:   databases,
+		launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+			b.Vault.(*DBTestData).TestDesc = testDesc
+			b.Metric = func() (metric string) {
+				return testDesc.metric
+			}
+			// the worker data is up to the plugin
+			b.InitPerWorker = func(int) {}
+			b.FinishPerWorker = func(int) {}
+
+			// the plugin sets the worker, the workers are run the same way as the ones of the tool tests
+			p.Run(b, testDesc)
+			runWorkers(b)
+
+			b.Vault.(*DBTestData).scores[testDesc.category] = append(b.Vault.(*DBTestData).scores[testDesc.category], b.Score)
+		},
+	}
+}
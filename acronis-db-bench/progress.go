@@ -0,0 +1,114 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mattn/go-isatty"
+
+	"github.com/acronis/perfkit/benchmark"
+)
+
+// progressInterval is the period of the real-time throughput line refresh
+const progressInterval = time.Second
+
+// progressLine prints the current throughput of the running test on a single line overwritten every progressInterval
+type progressLine struct {
+	b     *benchmark.Benchmark
+	lock  sync.Mutex
+	width int // length of the last printed line, it's blanked out on clear
+	start time.Time
+	total time.Duration // expected duration of the run, 0 if the run is limited by --loops
+}
+
+// startProgress starts the real-time throughput display of the test run by b.Run, the returned function stops it;
+// nothing is displayed with --quiet or if stdout is not a terminal
+func startProgress(b *benchmark.Benchmark) (stop func()) {
+	if b.CommonOpts.Quiet || !isatty.IsTerminal(os.Stdout.Fd()) {
+		return func() {}
+	}
+
+	var p = &progressLine{b: b, start: time.Now()}
+	if b.CommonOpts.Loops == 0 {
+		var seconds = b.CommonOpts.Repeat * (b.CommonOpts.WarmupDuration + b.CommonOpts.Duration)
+		p.total = time.Duration(seconds)*time.Second + b.Rampup
+	}
+
+	// the score is printed on a clean line, the display is resumed after it by the next tick
+	var printScore = b.PrintScore
+	b.PrintScore = func(score benchmark.Score) {
+		p.lock.Lock()
+		defer p.lock.Unlock()
+
+		p.clear()
+		printScore(score)
+	}
+
+	var pool *benchmark.WorkerPool
+	pool = benchmark.NewWorkerPool(1, func(int) {
+		var ticker = time.NewTicker(progressInterval)
+		defer ticker.Stop()
+
+		var lastLoops uint64
+		for {
+			select {
+			case <-pool.Context().Done():
+				return
+			case <-ticker.C:
+				var loops = b.DoneLoops()
+				var tps = loops
+				if loops >= lastLoops {
+					// the counter is reset by every repetition and at the end of the warmup
+					tps = loops - lastLoops
+				}
+				lastLoops = loops
+
+				p.print(tps, loops)
+			}
+		}
+	})
+	pool.Start()
+
+	return func() {
+		pool.Stop()
+		pool.Wait()
+
+		p.lock.Lock()
+		p.clear()
+		p.lock.Unlock()
+
+		b.PrintScore = printScore
+	}
+}
+
+// print overwrites the progress line with the current rate, elapsed time and the completion percentage
+func (p *progressLine) print(tps uint64, loops uint64) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	var elapsed = time.Since(p.start)
+	var percent float64
+	if p.total > 0 {
+		percent = 100 * float64(elapsed) / float64(p.total)
+	} else if p.b.CommonOpts.Loops > 0 {
+		percent = 100 * float64(loops) / float64(p.b.CommonOpts.Loops)
+	}
+	if percent > 100 {
+		percent = 100
+	}
+
+	var line = fmt.Sprintf("%d loops/sec; elapsed: %s; done: %.0f%%", tps, elapsed.Truncate(time.Second), percent)
+	fmt.Printf("\r%-*s", p.width, line)
+	p.width = len(line)
+}
+
+// clear blanks out the progress line and moves the cursor to its beginning
+func (p *progressLine) clear() {
+	if p.width > 0 {
+		fmt.Printf("\r%s\r", strings.Repeat(" ", p.width))
+		p.width = 0
+	}
+}
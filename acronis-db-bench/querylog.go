@@ -0,0 +1,183 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/acronis/perfkit/db"
+)
+
+// queryLogSlowestCount is the number of the slowest queries shown in the --query-log-file summary
+const queryLogSlowestCount = 10
+
+// queryLogEntry is a single query executed by the worker
+type queryLogEntry struct {
+	timestamp time.Time
+	workerID  int
+	query     string
+	args      []interface{}
+	duration  time.Duration
+}
+
+// queryLog writes the executed queries to the --query-log-file as
+// <timestamp>\t<worker_id>\t<query>\t<args_json> lines by a single writer goroutine and collects the queries summary
+type queryLog struct {
+	file    *os.File
+	entries chan queryLogEntry
+	done    chan struct{}
+
+	lock   sync.RWMutex // guards the entries channel from being written after Close
+	closed bool
+
+	// accessed by the writer goroutine only until done is closed
+	total    uint64
+	patterns map[string]struct{}
+	slowest  []queryLogEntry
+}
+
+// queriesLog is a global query log, nil if --query-log-file is not set
+var queriesLog *queryLog
+
+// newQueryLog creates the query log truncating the given file and starts its writer goroutine
+func newQueryLog(path string) (*queryLog, error) {
+	var file, err = os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create query log file '%s': %v", path, err)
+	}
+
+	var l = &queryLog{
+		file:     file,
+		entries:  make(chan queryLogEntry, 10000),
+		done:     make(chan struct{}),
+		patterns: make(map[string]struct{}),
+	}
+
+	go l.write()
+
+	return l, nil
+}
+
+// recorder returns the db.QueryRecorder passing the queries of the given worker to the log
+func (l *queryLog) recorder(workerID int) db.QueryRecorder {
+	return &queryLogRecorder{log: l, workerID: workerID}
+}
+
+// write writes the entries to the file until the entries channel is closed
+func (l *queryLog) write() {
+	defer close(l.done)
+
+	var w = bufio.NewWriter(l.file)
+	for e := range l.entries {
+		_, _ = fmt.Fprintf(w, "%s\t%d\t%s\t%s\n", e.timestamp.Format(time.RFC3339Nano), e.workerID, queryLogEscape(e.query), queryLogArgs(e.args))
+
+		l.total++
+		l.patterns[queryFingerprint(e.query)] = struct{}{}
+		l.addSlowest(e)
+	}
+	_ = w.Flush()
+}
+
+// addSlowest keeps the entry if it's one of the queryLogSlowestCount slowest queries
+func (l *queryLog) addSlowest(e queryLogEntry) {
+	if len(l.slowest) == queryLogSlowestCount && l.slowest[len(l.slowest)-1].duration >= e.duration {
+		return
+	}
+
+	var i = sort.Search(len(l.slowest), func(i int) bool { return l.slowest[i].duration < e.duration })
+	l.slowest = append(l.slowest, queryLogEntry{})
+	copy(l.slowest[i+1:], l.slowest[i:])
+	l.slowest[i] = e
+
+	if len(l.slowest) > queryLogSlowestCount {
+		l.slowest = l.slowest[:queryLogSlowestCount]
+	}
+}
+
+// Close waits for all the entries to be written, closes the file and returns the queries summary
+func (l *queryLog) Close() ([]string, error) {
+	l.lock.Lock()
+	l.closed = true
+	close(l.entries)
+	l.lock.Unlock()
+
+	<-l.done
+
+	var summary = []string{
+		fmt.Sprintf("total queries: %d; unique query patterns: %d", l.total, len(l.patterns)),
+		"",
+		fmt.Sprintf("%-6s %12s  %s", "worker", "duration, ms", "slowest queries"),
+	}
+	for _, e := range l.slowest {
+		summary = append(summary, fmt.Sprintf("%6d %12.3f  %s", e.workerID, durationMs(e.duration), queryLogEscape(e.query)))
+	}
+
+	return summary, l.file.Close()
+}
+
+// queryLogRecorder is the db.QueryRecorder of the worker connection
+type queryLogRecorder struct {
+	log      *queryLog
+	workerID int
+}
+
+func (r *queryLogRecorder) RecordQuery(query string, args []interface{}, duration time.Duration) {
+	r.log.lock.RLock()
+	defer r.log.lock.RUnlock()
+
+	if !r.log.closed {
+		r.log.entries <- queryLogEntry{timestamp: time.Now(), workerID: r.workerID, query: query, args: args, duration: duration}
+	}
+}
+
+// queryLogEscape replaces the line and field separators in the query by spaces to keep one query per line
+func queryLogEscape(query string) string {
+	return strings.NewReplacer("\r\n", " ", "\n", " ", "\r", " ", "\t", " ").Replace(query)
+}
+
+// queryLogArgs returns the query arguments as JSON array, the values not supported by JSON are written as strings
+func queryLogArgs(args []interface{}) string {
+	if len(args) == 0 {
+		return "[]"
+	}
+
+	if data, err := json.Marshal(args); err == nil {
+		return string(data)
+	}
+
+	var values = make([]string, len(args))
+	for i, a := range args {
+		values[i] = fmt.Sprint(a)
+	}
+	var data, _ = json.Marshal(values)
+
+	return string(data)
+}
+
+var (
+	queryStringLiteral  = regexp.MustCompile(`'(?:[^']|'')*'`)
+	queryNumberLiteral  = regexp.MustCompile(`\b\d+(?:\.\d+)?\b`)
+	queryPlaceholders   = regexp.MustCompile(`\$\d+|@p\d+`)
+	queryValuesList     = regexp.MustCompile(`\(\s*\?(?:\s*,\s*\?)*\s*\)`)
+	queryRepeatedValues = regexp.MustCompile(`\(\?\+\)(?:\s*,\s*\(\?\+\))+`)
+	queryWhitespace     = regexp.MustCompile(`\s+`)
+)
+
+// queryFingerprint returns the query pattern with the literals replaced by '?' and the value lists collapsed
+// in the same way pt-query-digest does, e.g. "SELECT id FROM t WHERE x IN (1, 2)" -> "select id from t where x in (?+)"
+func queryFingerprint(query string) string {
+	var s = queryStringLiteral.ReplaceAllString(query, "?")
+	s = queryPlaceholders.ReplaceAllString(s, "?")
+	s = queryNumberLiteral.ReplaceAllString(s, "?")
+	s = queryValuesList.ReplaceAllString(s, "(?+)")
+	s = queryRepeatedValues.ReplaceAllString(s, "(?+)")
+	s = queryWhitespace.ReplaceAllString(s, " ")
+
+	return strings.ToLower(strings.TrimSpace(s))
+}
@@ -0,0 +1,82 @@
+//go:build redis
+
+package main
+
+import (
+	"github.com/acronis/perfkit/benchmark"
+	"github.com/acronis/perfkit/db"
+	_ "github.com/acronis/perfkit/db/redis" // redis driver
+)
+
+// redisDialects is appended to the databases of the tables and tests served by the redis driver
+var redisDialects = []db.DialectName{db.REDIS, db.VALKEY}
+
+// redisTests are the tests registered in the 'base' group with the redis driver only
+var redisTests = []*TestDesc{&TestInsertRedisLight, &TestSelectRedisMediumRand, &TestValkeyPing, &TestInsertValkeyHash}
+
+// TestInsertRedisLight inserts a row into the 'light' table stored in Redis
+var TestInsertRedisLight = TestDesc{
+	name:        "insert-redis-light",
+	metric:      "ops/sec",
+	description: "insert a row into the 'light' table stored in Redis by pipelined HSET (the same as 'insert-light') and show the average command latency",
+	category:    TestInsert,
+	isReadonly:  false,
+	isDBRTest:   false,
+	databases:   []db.DialectName{db.REDIS},
+	table:       TestTableLight,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		withCommandLatencies(b, func() {
+			testInsertGeneric(b, testDesc)
+		})
+	},
+}
+
+// TestSelectRedisMediumRand selects random row from the 'medium' table stored in Redis
+var TestSelectRedisMediumRand = TestDesc{
+	name:        "select-redis-medium-rand",
+	metric:      "ops/sec",
+	description: "select random row from the 'medium' table stored in Redis by ZRANGEBYSCORE (the same as 'select-medium-rand') and show the average command latency",
+	category:    TestSelect,
+	isReadonly:  true,
+	isDBRTest:   false,
+	databases:   []db.DialectName{db.REDIS},
+	table:       TestTableMedium,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		withCommandLatencies(b, func() {
+			TestSelectMediumRand.launcherFunc(b, testDesc)
+		})
+	},
+}
+
+// TestValkeyPing pings Valkey
+var TestValkeyPing = TestDesc{
+	name:        "valkey-ping",
+	metric:      "ping/sec",
+	description: "just ping Valkey (the same as 'ping') and show the average command latency",
+	category:    TestOther,
+	isReadonly:  true,
+	isDBRTest:   false,
+	databases:   []db.DialectName{db.VALKEY},
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		withCommandLatencies(b, func() {
+			TestPing.launcherFunc(b, testDesc)
+		})
+	},
+}
+
+// TestInsertValkeyHash inserts a row into the 'medium' table stored in Valkey
+var TestInsertValkeyHash = TestDesc{
+	name:        "insert-valkey-hash",
+	metric:      "ops/sec",
+	description: "insert a row into the 'medium' table stored in Valkey as the hash of the row columns by pipelined HSET and show the average command latency",
+	category:    TestInsert,
+	isReadonly:  false,
+	isDBRTest:   false,
+	databases:   []db.DialectName{db.VALKEY},
+	table:       TestTableMedium,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		withCommandLatencies(b, func() {
+			testInsertGeneric(b, testDesc)
+		})
+	},
+}
@@ -0,0 +1,13 @@
+//go:build !redis
+
+package main
+
+import (
+	"github.com/acronis/perfkit/db"
+)
+
+// redisDialects is empty without the redis build tag, so Redis and Valkey are not listed as supported by the tables and tests
+var redisDialects []db.DialectName
+
+// redisTests is empty without the redis build tag
+var redisTests []*TestDesc
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/acronis/perfkit/benchmark"
+	"github.com/acronis/perfkit/db"
+)
+
+// SuiteEntry is a single test run of the --suite file, zero values keep the command line options
+type SuiteEntry struct {
+	Test     string `yaml:"test"`
+	Workers  int    `yaml:"workers"`
+	Loops    int    `yaml:"loops"`
+	Duration int    `yaml:"duration"`
+	Batch    int    `yaml:"batch"`
+	Label    string `yaml:"label"` // scores of the entries with the same label are aggregated to one geomean, the test name by default
+}
+
+// loadSuite reads the --suite YAML file, the file is expected to be a list of the entries:
+/*
+ * - test: insert-light
+ *   workers: 16
+ *   loops: 100000
+ *   batch: 100
+ *   label: insert
+ * - test: select-medium-rand
+ *   workers: 32
+ *   duration: 60
+ *   label: select
+ */
+func loadSuite(path string, tests map[string]*TestDesc, dialectName db.DialectName) ([]SuiteEntry, error) {
+	var content, err = os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading suite file %s: %v", path, err)
+	}
+
+	var entries []SuiteEntry
+	if err = yaml.Unmarshal(content, &entries); err != nil {
+		return nil, fmt.Errorf("error parsing suite file %s: %v", path, err)
+	}
+
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("suite file %s has no tests", path)
+	}
+
+	for i, e := range entries {
+		var test, exists = tests[e.Test]
+		if !exists {
+			return nil, fmt.Errorf("suite file %s entry #%d: test '%s' doesn't exist, see the list of available tests using --list option", path, i+1, e.Test)
+		}
+		if !test.dbIsSupported(dialectName) {
+			return nil, fmt.Errorf("suite file %s entry #%d: test '%s' doesn't support '%s' database", path, i+1, e.Test, dialectName)
+		}
+		if e.Workers < 0 || e.Loops < 0 || e.Duration < 0 || e.Batch < 0 {
+			return nil, fmt.Errorf("suite file %s entry #%d: workers, loops, duration and batch must not be negative", path, i+1)
+		}
+		if e.Label == "" {
+			entries[i].Label = e.Test
+		}
+	}
+
+	return entries, nil
+}
+
+// executeSuite runs the tests of the --suite file one by one with the entry options and prints the raw scores
+// along with the geomean of every label
+func executeSuite(b *benchmark.Benchmark, testOpts *TestOpts) {
+	var dialectName, err = db.GetDialectName(testOpts.DBOpts.ConnString)
+	if err != nil {
+		b.Exit("failed to get dialect name: %v", err)
+	}
+
+	_, tests := GetTests()
+	entries, err := loadSuite(testOpts.BenchOpts.Suite, tests, dialectName)
+	if err != nil {
+		b.Exit("failed to load --suite: %v", err)
+	}
+
+	var testData = b.Vault.(*DBTestData)
+	var commonOpts, batch, effectiveBatch = b.CommonOpts, testOpts.BenchOpts.Batch, testData.EffectiveBatch
+
+	var labels []string
+	var labelScores = make(map[string][]benchmark.Score)
+	var scores []benchmark.Score
+
+	for _, e := range entries {
+		b.CommonOpts = commonOpts
+		if e.Workers > 0 {
+			b.CommonOpts.Workers = e.Workers
+		}
+		// the loops take priority over the duration, so the entry sets both of them
+		if e.Loops > 0 || e.Duration > 0 {
+			b.CommonOpts.Loops, b.CommonOpts.Duration = e.Loops, e.Duration
+		}
+
+		testOpts.BenchOpts.Batch, testData.EffectiveBatch = batch, effectiveBatch
+		if e.Batch > 0 {
+			testOpts.BenchOpts.Batch, testData.EffectiveBatch = e.Batch, e.Batch
+		}
+
+		executeOneTest(b, tests[e.Test])
+
+		if _, ok := labelScores[e.Label]; !ok {
+			labels = append(labels, e.Label)
+		}
+		labelScores[e.Label] = append(labelScores[e.Label], b.Score)
+		scores = append(scores, b.Score)
+
+		if b.NeedToExit {
+			break
+		}
+	}
+
+	b.CommonOpts = commonOpts
+	testOpts.BenchOpts.Batch, testData.EffectiveBatch = batch, effectiveBatch
+
+	fmt.Printf("\nSUITE: %s\n\n", testOpts.BenchOpts.Suite)
+	fmt.Printf("%-20s %-40s %10s %10s %15s %s\n", "label", "test", "workers", "loops", "rate", "metric")
+	fmt.Printf("%-20s %-40s %10s %10s %15s %s\n", strings.Repeat("-", 20), strings.Repeat("-", 40), strings.Repeat("-", 10),
+		strings.Repeat("-", 10), strings.Repeat("-", 15), strings.Repeat("-", 10))
+	for i, s := range scores {
+		fmt.Printf("%-20s %-40s %10d %10d %15s %s\n", entries[i].Label, entries[i].Test, s.Workers, s.Loops, s.FormatRate(4), s.Metric)
+	}
+	fmt.Printf("\n")
+
+	for _, l := range labels {
+		fmt.Printf("%s geomean: %.0f\n", l, b.Geomean(labelScores[l]))
+	}
+	fmt.Printf("\n")
+}
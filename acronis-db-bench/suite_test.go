@@ -0,0 +1,69 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/acronis/perfkit/db"
+)
+
+func TestLoadSuite(t *testing.T) {
+	var tests = map[string]*TestDesc{
+		"insert-light": {name: "insert-light", databases: []db.DialectName{db.POSTGRES, db.MYSQL}},
+		"select-1":     {name: "select-1", databases: []db.DialectName{db.POSTGRES}},
+	}
+
+	cases := []struct {
+		name    string
+		content string
+		dialect db.DialectName
+		want    []SuiteEntry
+		wantErr string
+	}{
+		{
+			name:    "entries",
+			content: "- test: insert-light\n  workers: 16\n  loops: 1000\n  batch: 100\n  label: insert\n- test: select-1\n  duration: 10\n",
+			dialect: db.POSTGRES,
+			want: []SuiteEntry{
+				{Test: "insert-light", Workers: 16, Loops: 1000, Batch: 100, Label: "insert"},
+				{Test: "select-1", Duration: 10, Label: "select-1"},
+			},
+		},
+		{name: "no tests", content: "[]\n", dialect: db.POSTGRES, wantErr: "has no tests"},
+		{name: "bad yaml", content: "test: insert-light\n", dialect: db.POSTGRES, wantErr: "error parsing suite file"},
+		{name: "unknown test", content: "- test: no-such-test\n", dialect: db.POSTGRES, wantErr: "entry #1: test 'no-such-test' doesn't exist"},
+		{name: "unsupported database", content: "- test: insert-light\n- test: select-1\n", dialect: db.MYSQL, wantErr: "entry #2: test 'select-1' doesn't support 'mysql' database"},
+		{name: "negative workers", content: "- test: select-1\n  workers: -1\n", dialect: db.POSTGRES, wantErr: "must not be negative"},
+		{name: "negative loops", content: "- test: select-1\n  loops: -1\n", dialect: db.POSTGRES, wantErr: "must not be negative"},
+		{name: "negative duration", content: "- test: select-1\n  duration: -1\n", dialect: db.POSTGRES, wantErr: "must not be negative"},
+		{name: "negative batch", content: "- test: select-1\n  batch: -1\n", dialect: db.POSTGRES, wantErr: "must not be negative"},
+	}
+	for _, tt := range cases {
+		path := filepath.Join(t.TempDir(), "suite.yaml")
+		if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+			t.Fatalf("cannot write suite file: %v", err)
+		}
+
+		got, err := loadSuite(path, tests, tt.dialect)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("loadSuite(%s) error = %v, want %q", tt.name, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("loadSuite(%s) error = %v", tt.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("loadSuite(%s) = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+
+	if _, err := loadSuite(filepath.Join(t.TempDir(), "missing.yaml"), tests, db.POSTGRES); err == nil || !strings.Contains(err.Error(), "error reading suite file") {
+		t.Errorf("loadSuite() of missing file error = %v, want read error", err)
+	}
+}
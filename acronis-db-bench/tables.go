@@ -3,6 +3,7 @@ package main
 import (
 	"fmt"
 	"os"
+	"regexp"
 	"strings"
 
 	"github.com/acronis/perfkit/benchmark"
@@ -24,6 +25,7 @@ type TestTable struct {
 	CreateQuery           string
 	CreateQueryPatchFuncs []CreateQueryPatchFunc
 	Indexes               [][]string
+	UniqueIndexes         [][]string
 
 	// runtime information
 	RowsCount uint64
@@ -147,6 +149,135 @@ func (t *TestTable) GetColumnsForUpdate(withAutoInc bool) *[]benchmark.DBFakeCol
 	return t.GetColumnsConf(t.UpdateColumns, withAutoInc)
 }
 
+// ColumnDiff is a difference of the existing table column from the TableDefinition one
+type ColumnDiff struct {
+	Column       string
+	ExpectedType db.DataType
+	ActualType   string // empty if the column is missing
+}
+
+func (d ColumnDiff) String() string {
+	if d.ActualType == "" {
+		return fmt.Sprintf("missing column '%s' of type '%s'", d.Column, d.ExpectedType)
+	}
+
+	return fmt.Sprintf("column '%s' has type '%s', expected '%s'", d.Column, d.ActualType, d.ExpectedType)
+}
+
+// SchemaDiff compares the columns of the existing table given by GetTablesSchemaInfo with the TableDefinition ones,
+// the tables created by the CreateQuery are not checked
+func (t *TestTable) SchemaDiff(c *DBConnector, schemaInfo []string) []ColumnDiff {
+	if t.TableDefinition == nil {
+		return nil
+	}
+
+	var columns, exists = parseSchemaInfoColumns(schemaInfo)[t.TableName]
+	if !exists {
+		return nil
+	}
+
+	var diff []ColumnDiff
+	for _, row := range t.TableDefinition(c.database.DialectName()).TableRows {
+		var actualType, found = columns[strings.ToLower(row.Name)]
+		if !found {
+			diff = append(diff, ColumnDiff{Column: row.Name, ExpectedType: row.Type})
+			continue
+		}
+
+		var expectedFamily, actualFamily = logicalTypeFamily(row.Type), nativeTypeFamily(actualType)
+		if expectedFamily != "" && actualFamily != "" && expectedFamily != actualFamily {
+			diff = append(diff, ColumnDiff{Column: row.Name, ExpectedType: row.Type, ActualType: actualType})
+		}
+	}
+
+	return diff
+}
+
+// MigrationSQL returns the ALTER TABLE queries bringing the table columns up to date, the types are the db.DataType placeholders
+func (t *TestTable) MigrationSQL(dialectName db.DialectName, diff []ColumnDiff) (string, error) {
+	var queries []string
+	for _, d := range diff {
+		if d.ActualType == "" {
+			switch dialectName {
+			case db.MSSQL, db.CASSANDRA:
+				queries = append(queries, fmt.Sprintf("ALTER TABLE %s ADD %s %s", t.TableName, d.Column, d.ExpectedType))
+			default:
+				queries = append(queries, fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", t.TableName, d.Column, d.ExpectedType))
+			}
+			continue
+		}
+
+		switch dialectName {
+		case db.POSTGRES:
+			queries = append(queries, fmt.Sprintf("ALTER TABLE %[1]s ALTER COLUMN %[2]s TYPE %[3]s USING %[2]s::%[3]s", t.TableName, d.Column, d.ExpectedType))
+		case db.MYSQL, db.CLICKHOUSE:
+			queries = append(queries, fmt.Sprintf("ALTER TABLE %s MODIFY COLUMN %s %s", t.TableName, d.Column, d.ExpectedType))
+		case db.MSSQL:
+			queries = append(queries, fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s %s", t.TableName, d.Column, d.ExpectedType))
+		default:
+			return "", fmt.Errorf("changing the column type is not supported for '%s' database", dialectName)
+		}
+	}
+
+	return strings.Join(queries, ";"), nil
+}
+
+// parseSchemaInfoColumns returns the lower-cased column types per table from the GetTablesSchemaInfo output
+func parseSchemaInfoColumns(schemaInfo []string) map[string]map[string]string {
+	var tables = make(map[string]map[string]string)
+	var columns map[string]string
+	var inColumns bool
+
+	for _, line := range schemaInfo {
+		switch {
+		case strings.HasPrefix(line, "TABLE: "):
+			columns = make(map[string]string)
+			tables[strings.TrimPrefix(line, "TABLE: ")] = columns
+			inColumns = false
+		case strings.TrimSpace(line) == "Columns:":
+			inColumns = true
+		case strings.TrimSpace(line) == "Indexes:":
+			inColumns = false
+		case inColumns && columns != nil:
+			var name, typ, ok = strings.Cut(strings.TrimPrefix(strings.TrimSpace(line), "- "), ": ")
+			if ok {
+				columns[strings.ToLower(name)] = strings.ToLower(typ)
+			}
+		}
+	}
+
+	return tables
+}
+
+var (
+	integerTypeRe = regexp.MustCompile(`^(nullable\()?(u?int\d*|integer|(tiny|small|medium|big)int|(small|big)?serial|varint)\b`)
+	stringTypeRe  = regexp.MustCompile(`^(nullable\(|lowcardinality\()*(n?varchar|n?char|character|text|tinytext|mediumtext|longtext|string|ascii)\b`)
+)
+
+// logicalTypeFamily returns the integer or string family of the logical column type, the empty string if the family can't be told apart
+func logicalTypeFamily(dataType db.DataType) string {
+	switch dataType {
+	case db.DataTypeInt, db.DataTypeBigInt, db.DataTypeBigIntAutoInc, db.DataTypeBigIntAutoIncPK, db.DataTypeTinyInt:
+		return "integer"
+	case db.DataTypeString, db.DataTypeString256, db.DataTypeText, db.DataTypeLongText, db.DataTypeVarCharUUID:
+		return "string"
+	default:
+		return ""
+	}
+}
+
+// nativeTypeFamily returns the integer or string family of the database reported column type, the empty string if unknown
+func nativeTypeFamily(nativeType string) string {
+	switch {
+	case integerTypeRe.MatchString(nativeType):
+		return "integer"
+	case stringTypeRe.MatchString(nativeType):
+		return "string"
+	default:
+		return ""
+	}
+}
+
 // Create creates table in DB using provided DBConnector
 func (t *TestTable) Create(c *DBConnector, b *benchmark.Benchmark) {
 	if t.TableName == "" {
@@ -181,6 +312,12 @@ func (t *TestTable) Create(c *DBConnector, b *benchmark.Benchmark) {
 	for _, columns := range t.Indexes {
 		c.database.CreateIndex(fmt.Sprintf("%s_%s_idx", t.TableName, strings.Join(columns, "_")), t.TableName, columns, db.IndexTypeBtree)
 	}
+
+	for _, columns := range t.UniqueIndexes {
+		if err := c.database.CreateUniqueIndex(fmt.Sprintf("%s_%s_uidx", t.TableName, strings.Join(columns, "_")), t.TableName, columns); err != nil {
+			b.Exit("db: cannot create unique index on '%s': %v", t.TableName, err)
+		}
+	}
 }
 
 /*
@@ -190,7 +327,7 @@ func (t *TestTable) Create(c *DBConnector, b *benchmark.Benchmark) {
 // TestTableLight is table to store light objects
 var TestTableLight = TestTable{
 	TableName: "acronis_db_bench_light",
-	Databases: ALL,
+	Databases: append(allAnd(db.SCYLLA), redisDialects...),
 	columns: [][]interface{}{
 		{"id", "autoinc"},
 		{"uuid", "uuid"},
@@ -213,7 +350,7 @@ var TestTableLight = TestTable{
 // TestTableMedium is table to store medium objects
 var TestTableMedium = TestTable{
 	TableName: "acronis_db_bench_medium",
-	Databases: ALL,
+	Databases: append(allAnd(db.MONGODB, db.SCYLLA), redisDialects...),
 	columns: [][]interface{}{
 		{"id", "autoinc"},
 		{"uuid", "uuid"},
@@ -245,6 +382,29 @@ var TestTableMedium = TestTable{
 	Indexes: [][]string{{"tenant_id"}},
 }
 
+// TestTableMediumUnique is the 'medium' table with the unique index on the euc_id column
+var TestTableMediumUnique = TestTable{
+	TableName: "acronis_db_bench_medium_unique",
+	Databases: RELATIONAL,
+	columns: [][]interface{}{
+		{"id", "autoinc"},
+		{"uuid", "uuid"},
+		{"tenant_id", "tenant_uuid"},
+		{"euc_id", "int", 2147483647},
+		{"progress", "int", 100},
+	},
+	InsertColumns: []string{}, // all
+	CreateQuery: `create table {table} (
+			id {$bigint_autoinc_pk},
+			tenant_id {$varchar_uuid} {$notnull},
+			uuid {$varchar_uuid} {$notnull},
+			euc_id int {$notnull},
+			progress int {$null}
+			) {$engine};`,
+	Indexes:       [][]string{{"tenant_id"}},
+	UniqueIndexes: [][]string{{"euc_id"}},
+}
+
 var tableHeavySchema = `
 	id {$bigint_autoinc_pk},
 	uuid                      {$uuid}        not null {$unique},
@@ -312,7 +472,7 @@ var tableHeavySchema = `
 // TestTableHeavy is table to store heavy objects
 var TestTableHeavy = TestTable{
 	TableName: "acronis_db_bench_heavy",
-	Databases: ALL,
+	Databases: allAnd(db.COCKROACHDB),
 	columns: [][]interface{}{
 		{"id", "autoinc"},
 		{"uuid", "uuid", 0},
@@ -517,6 +677,49 @@ var TestTableVector768 = TestTable{
 	},
 }
 
+// osKNNEngine and osKNNDims are the --os-knn-engine and --os-knn-dims values the 'os vector' table is created with
+var (
+	osKNNEngine = "faiss"
+	osKNNDims   = 128
+)
+
+// TestTableOSVector is table to store vectors indexed by the OpenSearch k-NN plugin
+var TestTableOSVector = TestTable{
+	TableName: "acronis_db_bench_os_vector",
+	Databases: []db.DialectName{db.OPENSEARCH},
+	TableDefinition: func(dialect db.DialectName) *db.TableDefinition {
+		return &db.TableDefinition{
+			TableRows: []db.TableRow{
+				{Name: "id", Type: db.DataTypeBigInt, Indexed: true},
+				{Name: "embedding", Type: db.DataTypeVectorFloat32, Dims: osKNNDims, Indexed: true},
+			},
+			KNNEngine: osKNNEngine,
+		}
+	},
+}
+
+// vectorDims is the --vector-dims value the 'pgvector' table is created with
+var vectorDims = 768
+
+// TestTableVector is table to store vectors in PostgreSQL with the pgvector extension installed
+var TestTableVector = TestTable{
+	TableName: "acronis_db_bench_vector",
+	Databases: []db.DialectName{db.POSTGRES},
+	TableDefinition: func(dialect db.DialectName) *db.TableDefinition {
+		var embedding = db.TableRow{Name: "embedding", Type: db.DataTypeVector768Float32}
+		if vectorDims != 768 {
+			embedding = db.TableRow{Name: "embedding", Type: db.DataTypeVectorFloat32, Dims: vectorDims}
+		}
+
+		return &db.TableDefinition{
+			TableRows: []db.TableRow{
+				{Name: "id", Type: db.DataTypeBigIntAutoIncPK},
+				embedding,
+			},
+		}
+	},
+}
+
 // TestTableEmailSecurity is table to store email security objects
 var TestTableEmailSecurity = TestTable{
 	TableName: "acronis_db_bench_email_security",
@@ -627,7 +830,7 @@ var TestTableJSON = TestTable{
 			previous bigint,
 			sequence bigint,
 			time text,
-			json_data {$json_type},
+			json_data {$jsonb},
 			database64 text,
 			source text not null,
 			specversion text not null,
@@ -651,6 +854,91 @@ var TestTableJSON = TestTable{
 	Indexes:               [][]string{{"sequence"}, {"created_at"}},
 }
 
+// jsonNestedValueCardinality is the cardinality of the level1.level2.level3_field value of the nested JSON documents
+const jsonNestedValueCardinality = 1000
+
+// TestTableJSONNested is table to store deeply nested JSON documents without JSON index
+var TestTableJSONNested = TestTable{
+	TableName: "acronis_db_bench_json_nested",
+	Databases: []db.DialectName{db.POSTGRES},
+	columns: [][]interface{}{
+		{"tenant_id", "uuid", 0},
+		{"json_data", "json_nested", jsonNestedValueCardinality},
+	},
+	InsertColumns: []string{}, // all
+	CreateQuery: `create table {table} (
+			id {$bigint_autoinc_pk},
+			tenant_id {$uuid} not null,
+			json_data {$jsonb} not null
+			) {$engine};`,
+}
+
+// TestTableJSONNestedGIN is table to store deeply nested JSON documents with GIN index on the nested object
+var TestTableJSONNestedGIN = TestTable{
+	TableName: "acronis_db_bench_json_nested_gin",
+	Databases: []db.DialectName{db.POSTGRES},
+	columns: [][]interface{}{
+		{"tenant_id", "uuid", 0},
+		{"json_data", "json_nested", jsonNestedValueCardinality},
+	},
+	InsertColumns: []string{}, // all
+	CreateQuery: `create table {table} (
+			id {$bigint_autoinc_pk},
+			tenant_id {$uuid} not null,
+			json_data {$jsonb} not null
+			) {$engine};
+			CREATE INDEX acronis_db_bench_json_nested_gin_idx_level2 ON {table} USING GIN ((json_data->'level1'->'level2') jsonb_path_ops)`,
+}
+
+// jsonDocumentValueCardinality is the cardinality of the owner.region value of the JSON documents
+const jsonDocumentValueCardinality = 1000
+
+// TestTableJSONDocument is table to store the application entities as single JSON documents with GIN index on the whole document
+var TestTableJSONDocument = TestTable{
+	TableName: "acronis_db_bench_json_document",
+	Databases: []db.DialectName{db.POSTGRES},
+	columns: [][]interface{}{
+		{"tenant_id", "uuid", 0},
+		{"json_data", "json_document", jsonDocumentValueCardinality, 8192},
+	},
+	InsertColumns: []string{}, // all
+	CreateQuery: `create table {table} (
+			id {$bigint_autoinc_pk},
+			tenant_id {$uuid} not null,
+			json_data {$jsonb} not null
+			) {$engine};
+			CREATE INDEX acronis_db_bench_json_document_gin_idx ON {table} USING GIN (json_data jsonb_path_ops)`,
+}
+
+// TestTableJSONPathIndex is table to store JSON data with partial functional index on the jsonpath expression
+var TestTableJSONPathIndex = TestTable{
+	TableName: "acronis_db_bench_json_path_idx",
+	Databases: []db.DialectName{db.POSTGRES},
+	columns: [][]interface{}{
+		{"tenant_id", "uuid", 0},
+		{"json_data", "json", 0},
+	},
+	InsertColumns: []string{}, // all
+	CreateQuery: `create table {table} (
+			id {$bigint_autoinc_pk},
+			tenant_id {$uuid} not null,
+			json_data {$jsonb} not null
+			) {$engine};
+			CREATE INDEX acronis_db_bench_json_path_idx_f0f0 ON {table} ((jsonb_path_query_first(json_data, '$.field0.field0'))) WHERE json_data @? '$.field0.field0'`,
+}
+
+// TestTableFTS is table to store text documents indexed by the database native full-text search
+var TestTableFTS = TestTable{
+	TableName: "acronis_db_bench_fts",
+	Databases: []db.DialectName{db.POSTGRES, db.MYSQL, db.MSSQL},
+	CreateQuery: `create table {table} (
+			id {$bigint_autoinc_pk},
+			body {$longtext} {$notnull}
+			) {$engine};
+			{$fts_index}`,
+	CreateQueryPatchFuncs: []CreateQueryPatchFunc{FTSTableCreateQueryPatchFunc},
+}
+
 // TestTableTimeSeriesSQL is table to store time series data
 var TestTableTimeSeriesSQL = TestTable{
 	TableName: "acronis_db_bench_ts_sql",
@@ -687,6 +975,69 @@ var TestTableTimeSeriesSQL = TestTable{
 	Indexes: [][]string{{"tenant_id"}, {"device_id"}, {"metric_id"}},
 }
 
+// TimeSeriesAggregatingStateTableName is a name of the ClickHouse AggregatingMergeTree table pre-aggregating the 'timeseries aggregating' table per hour
+const TimeSeriesAggregatingStateTableName = "acronis_db_bench_ts_agg_state"
+
+// TimeSeriesAggregatingViewName is a name of the ClickHouse materialized view feeding the 'timeseries aggregating' state table
+const TimeSeriesAggregatingViewName = "acronis_db_bench_ts_agg_mv"
+
+// TestTableTimeSeriesAggregating is table to store time series data pre-aggregated by the ClickHouse AggregatingMergeTree table
+var TestTableTimeSeriesAggregating = TestTable{
+	TableName: "acronis_db_bench_ts_agg",
+	Databases: []db.DialectName{db.CLICKHOUSE},
+	columns: [][]interface{}{
+		{"id", "autoinc", 0},
+		{"tenant_id", "tenant_uuid", 0},
+		{"device_id", "tenant_uuid_bound_id", 50}, // up to 50 devices per tenant
+		{"metric_id", "cti_uuid", 10},             // up to 10 metrics to be used per every device
+		{"ts", "now", 0},
+		{"value", "int", 100},
+	},
+	InsertColumns: []string{}, // all
+	CreateQuery: `create table {table} (
+			id UInt64,
+			tenant_id String,
+			device_id String,
+			metric_id String,
+			ts DateTime64(6),
+			value Int32
+		) ENGINE = MergeTree() ORDER BY (tenant_id, device_id, metric_id, ts);
+		create table ` + TimeSeriesAggregatingStateTableName + ` (
+			tenant_id String,
+			device_id String,
+			metric_id String,
+			ts_hour DateTime,
+			avg_value AggregateFunction(avg, Float64),
+			max_value AggregateFunction(max, Int32),
+			cnt AggregateFunction(count)
+		) ENGINE = AggregatingMergeTree() ORDER BY (tenant_id, device_id, metric_id, ts_hour);
+		create materialized view ` + TimeSeriesAggregatingViewName + ` TO ` + TimeSeriesAggregatingStateTableName + ` AS
+			SELECT tenant_id, device_id, metric_id, toStartOfHour(ts) AS ts_hour,
+				avgState(toFloat64(value)) AS avg_value, maxState(value) AS max_value, countState() AS cnt
+			FROM {table}
+			GROUP BY tenant_id, device_id, metric_id, ts_hour;`,
+}
+
+// TestTableNetworkAddresses is table to store IPv4 and IPv6 network addresses
+var TestTableNetworkAddresses = TestTable{
+	TableName: "acronis_db_bench_network",
+	Databases: RELATIONAL,
+	columns: [][]interface{}{
+		{"tenant_id", "tenant_uuid", 0},
+		{"ip_address", "ip_address", 0},
+		{"created_at", "timestamp", 0},
+	},
+	InsertColumns: []string{}, // all
+	CreateQuery: `create table acronis_db_bench_network(
+			id {$bigint_autoinc_pk},
+			tenant_id {$varchar_uuid} {$notnull},
+			ip_address {$inet} {$notnull},
+			created_at timestamp {$notnull}
+		) {$engine};`,
+	// PostgreSQL uses btree index on INET column for the '<<' (is contained by) operator
+	Indexes: [][]string{{"tenant_id"}, {"ip_address"}},
+}
+
 // TestTableAdvmTasks is table to store tasks
 var TestTableAdvmTasks = TestTable{
 	TableName: "acronis_db_bench_advm_tasks",
@@ -1073,13 +1424,23 @@ var TestTableCTIEntities = TestTable{}
 var TestTables = map[string]TestTable{
 	"acronis_db_bench_light":                     TestTableLight,
 	"acronis_db_bench_medium":                    TestTableMedium,
+	"acronis_db_bench_medium_unique":             TestTableMediumUnique,
 	"acronis_db_bench_heavy":                     TestTableHeavy,
 	"acronis_db_bench_vector_768":                TestTableVector768,
+	"acronis_db_bench_os_vector":                 TestTableOSVector,
+	"acronis_db_bench_vector":                    TestTableVector,
 	"acronis_db_bench_email_security":            TestTableEmailSecurity,
 	"acronis_db_bench_blob":                      TestTableBlob,
 	"acronis_db_bench_largeobj":                  TestTableLargeObj,
 	"acronis_db_bench_json":                      TestTableJSON,
+	"acronis_db_bench_json_nested":               TestTableJSONNested,
+	"acronis_db_bench_json_nested_gin":           TestTableJSONNestedGIN,
+	"acronis_db_bench_json_document":             TestTableJSONDocument,
+	"acronis_db_bench_json_path_idx":             TestTableJSONPathIndex,
+	"acronis_db_bench_fts":                       TestTableFTS,
 	"acronis_db_bench_ts_sql":                    TestTableTimeSeriesSQL,
+	"acronis_db_bench_ts_agg":                    TestTableTimeSeriesAggregating,
+	"acronis_db_bench_network":                   TestTableNetworkAddresses,
 	"acronis_db_bench_cybercache_tenants":        TestTableTenants,
 	"acronis_db_bench_cybercache_tenant_closure": TestTableTenantsClosure,
 	"acronis_db_bench_advm_tasks":                TestTableAdvmTasks,
@@ -1097,14 +1458,12 @@ var TestTables = map[string]TestTable{
 func JSONTableCreateQueryPatchFunc(table string, query string, dialect db.DialectName) (string, error) { //nolint:revive
 	switch dialect {
 	case db.MYSQL:
-		query = strings.ReplaceAll(query, "{$json_type}", "json")
 		query = strings.ReplaceAll(query, "{$json_index}",
 			"ALTER TABLE acronis_db_bench_json ADD COLUMN _data_f0f0 VARCHAR(1024) AS (JSON_EXTRACT(json_data, '$.field0.field0')) STORED;"+
 				"ALTER TABLE acronis_db_bench_json ADD COLUMN _data_f0f0f0 VARCHAR(1024) AS (JSON_EXTRACT(json_data, '$.field0.field0.field0')) STORED;"+
 				"CREATE INDEX acronis_db_bench_json_idx_data_f0f0 ON acronis_db_bench_json(_data_f0f0);"+
 				"CREATE INDEX acronis_db_bench_json_idx_data_f0f0f0 ON acronis_db_bench_json(_data_f0f0f0);")
 	case db.POSTGRES:
-		query = strings.ReplaceAll(query, "{$json_type}", "jsonb")
 		query = strings.ReplaceAll(query, "{$json_index}",
 			"CREATE INDEX acronis_db_bench_json_idx_data ON acronis_db_bench_json USING GIN (json_data jsonb_path_ops)")
 	default:
@@ -1113,3 +1472,23 @@ func JSONTableCreateQueryPatchFunc(table string, query string, dialect db.Dialec
 
 	return query, nil
 }
+
+// FTSTableCreateQueryPatchFunc creates the 'fts' table full-text index, MSSQL gets the unique key index only,
+// the full-text index itself can't be created within the transaction there, see createMSSQLFullTextIndex
+func FTSTableCreateQueryPatchFunc(table string, query string, dialect db.DialectName) (string, error) { //nolint:revive
+	switch dialect {
+	case db.POSTGRES:
+		query = strings.ReplaceAll(query, "{$fts_index}",
+			fmt.Sprintf("CREATE INDEX %s ON {table} USING GIN (to_tsvector('english', body))", FTSIndexName))
+	case db.MYSQL:
+		query = strings.ReplaceAll(query, "{$fts_index}",
+			fmt.Sprintf("CREATE FULLTEXT INDEX %s ON {table} (body)", FTSIndexName))
+	case db.MSSQL:
+		query = strings.ReplaceAll(query, "{$fts_index}",
+			fmt.Sprintf("CREATE UNIQUE INDEX %s ON {table} (id)", FTSKeyIndexName))
+	default:
+		return "", fmt.Errorf("unsupported driver: '%v', supported drivers are: postgres|mysql|sqlserver", dialect)
+	}
+
+	return query, nil
+}
@@ -6,6 +6,8 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
+	"math/rand"
 	"sort"
 	"sync"
 	"sync/atomic"
@@ -69,6 +71,10 @@ type TenantsCache struct {
 	parentUUIDs               map[string][]TenantUUID
 	ctiUuids                  []CTIUUID
 	tenantStructureRandomizer *tenantStructureRandomizer
+	workerTenants             map[*benchmark.RandomizerWorker]guuid.UUID // see PinWorkerTenants
+	hotspotFraction           float64                                    // see SetTenantsHotspot
+	hotspotWeight             float64
+	hotspotSamplers           sync.Map // *rand.Zipf by hotspotSamplerKey, see GetHotRandomTenantUUID
 	exitLock                  sync.Mutex
 }
 
@@ -115,6 +121,20 @@ func (tc *TenantsCache) SetCTIsWorkingSet(limit int) {
 	tc.ctisWorkingSetLimit = limit
 }
 
+// SetTenantsHotspot makes GetHotRandomTenantUUID pick the tenants of the working set by the Zipf distribution, the first
+// given fraction (e.g. 0.1) of them are the hot ones driving most of the load, the hottest tenant is picked given weight
+// times (e.g. 10) more often than the first tenant out of the hotspot, zero fraction disables the hotspot
+func (tc *TenantsCache) SetTenantsHotspot(fraction float64, weight float64) {
+	tc.logger.Log(benchmark.LogTrace, 0, fmt.Sprintf("adjust tenants hotspot to: fraction %g, weight %g", fraction, weight))
+	tc.hotspotFraction = fraction
+	tc.hotspotWeight = weight
+}
+
+// HotspotEnabled returns true if the tenants hotspot is set by SetTenantsHotspot
+func (tc *TenantsCache) HotspotEnabled() bool {
+	return tc.hotspotFraction > 0
+}
+
 // Exit prints message and exits with -1 code
 func (tc *TenantsCache) Exit(msg string) {
 	tc.exitLock.Lock() // ugly, but prevents multiple messages on exit
@@ -301,7 +321,7 @@ engine = MergeTree() ORDER BY (tenant_id, cti_entity_uuid);`, TableNameCtiProvis
 // Init initializes tenants cache and creates tables if needed
 func (tc *TenantsCache) Init(database db.Database) error {
 	var dialect = database.DialectName()
-	if dialect != db.CLICKHOUSE && dialect != db.CASSANDRA && dialect != db.MYSQL && dialect != db.POSTGRES && dialect != db.SQLITE {
+	if dialect != db.CLICKHOUSE && dialect != db.CASSANDRA && dialect != db.MYSQL && dialect != db.POSTGRES && dialect != db.COCKROACHDB && dialect != db.SQLITE {
 		tc.logger.Log(benchmark.LogTrace, 0, fmt.Sprintf("unsupported dialect: %s", dialect))
 		return fmt.Errorf("unsupported dialect: %s", dialect)
 	}
@@ -560,9 +580,10 @@ func (tc *TenantsCache) CreateTenant(rw *benchmark.RandomizerWorker, tx db.Datab
 	}
 
 	var tenantUuid, _ = guuid.ParseBytes([]byte(t.UUID))
-	if err = tx.BulkInsert(TableNameTenants, [][]interface{}{
-		{t.ID, tenantUuid, t.Name, t.Kind, t.ParentID, t.NestingLevel, t.IsDeleted, t.ParentHasAccess},
-	}, []string{"id", "uuid", "name", "kind", "parent_id", "nesting_level", "is_deleted", "parent_has_access"}); err != nil {
+	if err = tx.BulkInsert(TableNameTenants, &db.BulkInsertCtrl{
+		Rows:        [][]interface{}{{t.ID, tenantUuid, t.Name, t.Kind, t.ParentID, t.NestingLevel, t.IsDeleted, t.ParentHasAccess}},
+		ColumnNames: []string{"id", "uuid", "name", "kind", "parent_id", "nesting_level", "is_deleted", "parent_has_access"},
+	}); err != nil {
 		tc.logger.Log(benchmark.LogTrace, 0, fmt.Sprintf("error inserting into table %s: %v", TableNameTenants, err))
 		return "", err
 	}
@@ -604,8 +625,10 @@ func (tc *TenantsCache) CreateTenant(rw *benchmark.RandomizerWorker, tx db.Datab
 		})
 	}
 
-	if err = tx.BulkInsert(TableNameTenantClosure, tcToCreate,
-		[]string{"parent_id", "child_id", "parent_kind", "barrier"}); err != nil {
+	if err = tx.BulkInsert(TableNameTenantClosure, &db.BulkInsertCtrl{
+		Rows:        tcToCreate,
+		ColumnNames: []string{"parent_id", "child_id", "parent_kind", "barrier"},
+	}); err != nil {
 		tc.logger.Log(benchmark.LogTrace, 0, fmt.Sprintf("error inserting into table %s: %v", TableNameTenantClosure, err))
 		return "", err
 	}
@@ -626,9 +649,10 @@ func (tc *TenantsCache) CreateCTIEntity(rw *benchmark.RandomizerWorker, tx db.Da
 	cti.GlobalState = 1
 
 	var ctiUUID, _ = guuid.ParseBytes([]byte(cti.UUID))
-	if err = tx.BulkInsert(TableNameCtiEntities, [][]interface{}{
-		{ctiUUID, cti.CTI, cti.Final, cti.GlobalState, cti.EntitySchema, cti.Annotations, cti.Traits, cti.TraitsSchema, cti.TraitsAnnotations},
-	}, []string{"uuid", "cti", "final", "global_state", "entity_schema", "annotations", "traits", "traits_schema", "traits_annotations"}); err != nil {
+	if err = tx.BulkInsert(TableNameCtiEntities, &db.BulkInsertCtrl{
+		Rows:        [][]interface{}{{ctiUUID, cti.CTI, cti.Final, cti.GlobalState, cti.EntitySchema, cti.Annotations, cti.Traits, cti.TraitsSchema, cti.TraitsAnnotations}},
+		ColumnNames: []string{"uuid", "cti", "final", "global_state", "entity_schema", "annotations", "traits", "traits_schema", "traits_annotations"},
+	}); err != nil {
 		return fmt.Errorf("error inserting into table %s: %v", TableNameCtiEntities, err)
 	}
 
@@ -898,6 +922,66 @@ func convertIntToKind(kind int) (string, error) {
 
 // GetRandomTenantUUID returns random tenant uuid from cache
 func (tc *TenantsCache) GetRandomTenantUUID(rw *benchmark.RandomizerWorker, testCardinality int, kind string) (guuid.UUID, error) {
+	var tenantList, cardinality = tc.workingSet(testCardinality, kind)
+
+	var value, _ = guuid.ParseBytes([]byte(tenantList[rw.IntnExp(cardinality)]))
+	return value, nil
+}
+
+// GetHotRandomTenantUUID returns random tenant uuid from cache picking the hot tenants more often, see SetTenantsHotspot
+/*
+ * The tenant of rank k of the working set is picked with the (v + k)^-s Zipf probability, s and v are chosen so that
+ * the first tenant is picked hotspot weight times more often than the tenant of rank hot, the first one out of
+ * the hotspot fraction of the working set (at least one tenant), i.e. ((v + hot) / v)^s = weight.
+ */
+func (tc *TenantsCache) GetHotRandomTenantUUID(rw *benchmark.RandomizerWorker, testCardinality int, kind string) (guuid.UUID, error) {
+	var tenantList, cardinality = tc.workingSet(testCardinality, kind)
+
+	var index int
+	if cardinality > 1 && tc.hotspotWeight > 1 {
+		index = int(tc.hotspotSampler(rw, cardinality).Uint64())
+	} else {
+		index = rw.Intn(cardinality)
+	}
+
+	var value, _ = guuid.ParseBytes([]byte(tenantList[index]))
+	return value, nil
+}
+
+// hotspotSamplerKey identifies the Zipf sampler of the worker randomizer and the working set size
+type hotspotSamplerKey struct {
+	rw          *benchmark.RandomizerWorker
+	cardinality int
+}
+
+// hotspotMinExponent is the Zipf exponent of the mild hotspots, rand.NewZipf requires it to be > 1
+const hotspotMinExponent = 1.01
+
+// hotspotSampler returns the Zipf sampler of the working set ranks drawing from the worker seeded randomizer
+func (tc *TenantsCache) hotspotSampler(rw *benchmark.RandomizerWorker, cardinality int) *rand.Zipf {
+	var key = hotspotSamplerKey{rw, cardinality}
+	if sampler, ok := tc.hotspotSamplers.Load(key); ok {
+		return sampler.(*rand.Zipf)
+	}
+
+	var hot = float64(Max(1, Min(cardinality-1, int(math.Ceil(tc.hotspotFraction*float64(cardinality))))))
+
+	// the steep hotspots are reached by the exponent with v = 1, the mild ones by v > 1 with the minimal exponent
+	var s, v = math.Log(tc.hotspotWeight) / math.Log(1+hot), 1.0
+	if s <= hotspotMinExponent {
+		s = hotspotMinExponent
+		v = math.Max(1, hot/(math.Pow(tc.hotspotWeight, 1/s)-1))
+	}
+
+	var sampler = rand.NewZipf(rw.Seeded(), s, v, uint64(cardinality-1))
+	tc.hotspotSamplers.Store(key, sampler)
+
+	return sampler
+}
+
+// workingSet returns the tenants of given kind and the number of the working set tenants among them, the test is aborted
+// if there are not enough tenants
+func (tc *TenantsCache) workingSet(testCardinality int, kind string) ([]TenantUUID, int) {
 	var cardinality int
 	if testCardinality == 0 {
 		cardinality = tc.tenantsWorkingSetLimit
@@ -928,8 +1012,7 @@ func (tc *TenantsCache) GetRandomTenantUUID(rw *benchmark.RandomizerWorker, test
 		tc.Exit(msg)
 	}
 
-	var value, _ = guuid.ParseBytes([]byte(tenantList[rw.IntnExp(cardinality)]))
-	return value, nil
+	return tenantList, cardinality
 }
 
 // GetTenantUuidBoundId returns tenant uuid bound id
@@ -977,11 +1060,86 @@ func (tc *TenantsCache) GetRandomCTIUUID(rw *benchmark.RandomizerWorker, testCar
 	return value, nil
 }
 
+// PreWarmTenants looks up every tenant of the working set in the database once, so a cold database cache doesn't skew
+// the first test loops, the workers randomizers are not used not to change the sequence of the generated values
+func (tc *TenantsCache) PreWarmTenants(database db.Database) {
+	var uuids = make([]string, Min(len(tc.uuids), tc.tenantsWorkingSetLimit))
+	for i := range uuids {
+		uuids[i] = string(tc.uuids[i])
+	}
+
+	tc.preWarm(database, "tenant", TableNameTenants, uuids)
+}
+
+// PreWarmCTIs looks up every CTI of the working set in the database once, see PreWarmTenants
+func (tc *TenantsCache) PreWarmCTIs(database db.Database) {
+	var uuids = make([]string, Min(len(tc.ctiUuids), tc.ctisWorkingSetLimit))
+	for i := range uuids {
+		uuids[i] = string(tc.ctiUuids[i])
+	}
+
+	tc.preWarm(database, "cti", TableNameCtiEntities, uuids)
+}
+
+func (tc *TenantsCache) preWarm(database db.Database, what string, table string, uuids []string) {
+	var session = database.Session(database.Context(context.Background()))
+	var quiet = tc.benchmark.CommonOpts.Quiet
+	var step = Max(1, len(uuids)/100)
+
+	for i, uuid := range uuids {
+		var found int
+		if err := session.QueryRow(fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE uuid = '%s';", table, uuid)).Scan(&found); err != nil {
+			tc.Exit(fmt.Sprintf("cannot warm up %s cache: %v", what, err))
+		}
+
+		if !quiet && ((i+1)%step == 0 || i+1 == len(uuids)) {
+			fmt.Printf("\rwarming up %s cache: %d/%d", what, i+1, len(uuids))
+		}
+	}
+
+	if !quiet && len(uuids) > 0 {
+		fmt.Printf("\n")
+	}
+}
+
+// PinWorkerTenants assigns a distinct tenant of the working set to every given randomizer worker, so all the 'tenant_uuid'
+// values generated by the worker belong to its own tenant, nil workers unpin the tenants
+/*
+ * Must be called before the workers start, the pinned tenants are read without locking.
+ */
+func (tc *TenantsCache) PinWorkerTenants(workers []*benchmark.RandomizerWorker) error {
+	if len(workers) == 0 {
+		tc.workerTenants = nil
+		return nil
+	}
+
+	var limit = Min(len(tc.uuids), tc.tenantsWorkingSetLimit)
+	if limit < len(workers) {
+		return fmt.Errorf("the tenants working set has %d tenants, while %d workers require a tenant each, "+
+			"add tenants by '-t insert-tenant' first or increase --tenants-working-set", limit, len(workers))
+	}
+
+	tc.workerTenants = make(map[*benchmark.RandomizerWorker]guuid.UUID, len(workers))
+	for i, rw := range workers {
+		var value, err = guuid.ParseBytes([]byte(tc.uuids[i]))
+		if err != nil {
+			return err
+		}
+		tc.workerTenants[rw] = value
+	}
+
+	return nil
+}
+
 func (tc *TenantsCache) GenCommonFakeValue(columnType string, rw *benchmark.RandomizerWorker, cardinality int) (bool, interface{}) {
 	if columnType != "tenant_uuid" && columnType != "customer_uuid" && columnType != "partner_uuid" {
 		return false, nil
 	}
 
+	if tenantUUID, ok := tc.workerTenants[rw]; ok && columnType == "tenant_uuid" {
+		return true, tenantUUID
+	}
+
 	var kind string
 	switch columnType {
 	case "customer_uuid":
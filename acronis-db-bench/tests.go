@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	mssql "github.com/denisenkom/go-mssqldb"
@@ -15,6 +16,7 @@ import (
 	"github.com/lib/pq"
 
 	"github.com/acronis/perfkit/benchmark"
+	"github.com/acronis/perfkit/benchmark/report"
 	"github.com/acronis/perfkit/db"
 
 	tenants "github.com/acronis/perfkit/acronis-db-bench/tenants-cache"
@@ -57,6 +59,12 @@ func (g *TestGroup) add(t *TestDesc) {
 // TestCategories is a list of all test categories
 var TestCategories = []string{TestSelect, TestUpdate, TestInsert, TestDelete, TestTransaction}
 
+// SelectAfterDefrag is the scores key of the SELECT rates measured after the defragmentation, see --defragment-after-insert
+const SelectAfterDefrag = "select-after-defrag"
+
+// CompareScoresPrefix prefixes the scores keys of the --compare-dsn database tests
+const CompareScoresPrefix = "compare-"
+
 type testWorkerFunc func(b *benchmark.Benchmark, c *DBConnector, testDesc *TestDesc, batch int) (loops int)
 type orderByFunc func(b *benchmark.Benchmark) string //nolint:unused
 type launcherFunc func(b *benchmark.Benchmark, testDesc *TestDesc)
@@ -114,6 +122,16 @@ var (
 	VECTOR = []db.DialectName{db.ELASTICSEARCH, db.OPENSEARCH}
 )
 
+// allAnd returns a list of all supported databases extended with the given ones
+func allAnd(extra ...db.DialectName) []db.DialectName {
+	return append(append([]db.DialectName{}, ALL...), extra...)
+}
+
+// relationalAnd returns a list of all supported relational databases extended with the given ones
+func relationalAnd(extra ...db.DialectName) []db.DialectName {
+	return append(append([]db.DialectName{}, RELATIONAL...), extra...)
+}
+
 // TestBaseAll tests all tests in the 'base' group
 var TestBaseAll = TestDesc{
 	name:        "all",
@@ -134,6 +152,7 @@ var TestPing = TestDesc{
 	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
 		worker := func(b *benchmark.Benchmark, c *DBConnector, testDesc *TestDesc, batch int) (loops int) { //nolint:revive
 			if err := c.database.Ping(context.Background()); err != nil {
+				accountError(b, c.WorkerID)
 				return 0
 			}
 
@@ -180,7 +199,7 @@ var TestRawQuery = TestDesc{
 				fmt.Printf("query %s\n", q)
 
 				var session = c.database.Session(c.database.Context(context.Background()))
-				if _, err := session.Query(q); err != nil {
+				if _, err := session.Query(q); err != nil && !isNonFatalError(b, c.WorkerID, err) {
 					b.Exit(err)
 				}
 
@@ -189,7 +208,7 @@ var TestRawQuery = TestDesc{
 		} else {
 			worker = func(b *benchmark.Benchmark, c *DBConnector, testDesc *TestDesc, batch int) (loops int) {
 				var session = c.database.Session(c.database.Context(context.Background()))
-				if _, err := session.Query(query); err != nil {
+				if _, err := session.Query(query); err != nil && !isNonFatalError(b, c.WorkerID, err) {
 					b.Exit(err)
 				}
 
@@ -214,11 +233,11 @@ var TestSelectOne = TestDesc{
 			var ret int
 			switch rawSession := c.database.RawSession().(type) {
 			case *dbr.Session:
-				if err := rawSession.Select("1").LoadOne(&ret); err != nil {
+				if err := rawSession.Select("1").LoadOne(&ret); err != nil && !isNonFatalError(b, c.WorkerID, err) {
 					b.Exit("DBRSelect load error: %v", err)
 				}
 			case *sql.DB:
-				if err := rawSession.QueryRow("SELECT 1").Scan(&ret); err != nil {
+				if err := rawSession.QueryRow("SELECT 1").Scan(&ret); err != nil && !isNonFatalError(b, c.WorkerID, err) {
 					b.Exit("can't do 'SELECT 1': %v", err)
 				}
 			case *es8.Client:
@@ -227,6 +246,9 @@ var TestSelectOne = TestDesc{
 					rawSession.Search.WithBody(strings.NewReader(`{"size": 1}`)),
 				)
 				if err != nil {
+					if isNonFatalError(b, c.WorkerID, err) {
+						return 1
+					}
 					b.Exit("can't do 'SELECT 1': %v", err)
 				}
 
@@ -267,7 +289,7 @@ var TestSelectNextVal = TestDesc{
 
 		worker := func(b *benchmark.Benchmark, c *DBConnector, testDesc *TestDesc, batch int) (loops int) { //nolint:revive
 			var session = c.database.Session(c.database.Context(context.Background()))
-			if _, err := session.GetNextVal(SequenceName); err != nil {
+			if _, err := session.GetNextVal(SequenceName); err != nil && !isNonFatalError(b, c.WorkerID, err) {
 				b.Exit(err)
 			}
 
@@ -318,11 +340,11 @@ var TestSelectMediumRand = TestDesc{
 	category:    TestSelect,
 	isReadonly:  true,
 	isDBRTest:   false,
-	databases:   ALL,
+	databases:   allAnd(db.SCYLLA),
 	table:       TestTableMedium,
 	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
 		var where = func(b *benchmark.Benchmark, workerId int) map[string][]string {
-			id := b.Randomizer.GetWorker(workerId).Uintn64(testDesc.table.RowsCount - 1)
+			id := randomID(b, workerId, testDesc)
 
 			return map[string][]string{"id": {fmt.Sprintf("ge(%d)", id)}}
 		}
@@ -335,6 +357,43 @@ var TestSelectMediumRand = TestDesc{
 	},
 }
 
+// TestSelectMediumRandQueryCache runs the 'select-medium-rand' test without and with the MySQL query cache and shows the rate difference
+var TestSelectMediumRandQueryCache = TestDesc{
+	name:        "select-medium-rand-query-cache",
+	metric:      "rows/sec",
+	description: "run 'select-medium-rand' with the MySQL query cache off and then on with --query-cache-size and show the rate difference (MySQL 5.x only)",
+	category:    TestSelect,
+	isReadonly:  true,
+	isDBRTest:   false,
+	databases:   []db.DialectName{db.MYSQL},
+	table:       TestTableMedium,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		var c = dbConnector(b)
+		defer c.Release()
+
+		if !setQueryCache(b, c, false) {
+			b.Exit("the query cache is not supported by the database")
+		}
+		TestSelectMediumRand.launcherFunc(b, testDesc)
+		var before = b.Score
+
+		setQueryCache(b, c, true)
+		TestSelectMediumRand.launcherFunc(b, testDesc)
+		var after = b.Score
+
+		if !b.TestOpts.(*TestOpts).DBOpts.EnableQueryCache {
+			setQueryCache(b, c, false)
+		}
+
+		var difference float64
+		if before.Rate > 0 {
+			difference = (after.Rate/before.Rate - 1) * 100
+		}
+		fmt.Printf("test: %s; rate without query cache: %s; rate with query cache: %s; difference: %+.1f%%\n",
+			testDesc.name, before.FormatRate(4), after.FormatRate(4), difference)
+	},
+}
+
 // TestSelectMediumRandDBR selects random row from the 'medium' table using golang DBR query builder
 var TestSelectMediumRandDBR = TestDesc{
 	name:        "dbr-select-medium-rand",
@@ -347,7 +406,7 @@ var TestSelectMediumRandDBR = TestDesc{
 	table:       TestTableMedium,
 	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
 		var where = func(b *benchmark.Benchmark, workerId int) map[string][]string {
-			var id = b.Randomizer.GetWorker(workerId).Uintn64(testDesc.table.RowsCount - 1)
+			var id = randomID(b, workerId, testDesc)
 
 			return map[string][]string{"id": {fmt.Sprintf("gt(%d)", id)}}
 		}
@@ -359,6 +418,101 @@ var TestSelectMediumRandDBR = TestDesc{
 	},
 }
 
+// paginationMaxPage is the max page number used by the pagination tests
+const paginationMaxPage = 10
+
+// selectPageWorker returns a worker selecting a random page of a random tenant rows, optionally preceded by COUNT(0) for the same tenant
+func selectPageWorker(withCount func(b *benchmark.Benchmark) bool) testWorkerFunc {
+	var colConfs = &[]benchmark.DBFakeColumnConf{{ColumnName: "tenant_id", ColumnType: "tenant_uuid"}}
+
+	return func(b *benchmark.Benchmark, c *DBConnector, testDesc *TestDesc, batch int) (loops int) {
+		var w = b.GenFakeDataAsMap(c.WorkerID, colConfs, false)
+		var where = map[string][]string{"tenant_id": {fmt.Sprintf("%s", (*w)["tenant_id"])}}
+
+		var session = c.database.Session(c.database.Context(context.Background()))
+
+		if withCount(b) {
+			var rows, err = session.Select(testDesc.table.TableName, &db.SelectCtrl{
+				Fields: []string{"COUNT(0)"},
+				Where:  where,
+			})
+			if err != nil {
+				if isNonFatalError(b, c.WorkerID, err) {
+					return batch
+				}
+				b.Exit("db: cannot count rows: %v", err)
+			}
+
+			var total int64
+			for rows.Next() {
+				if err = rows.Scan(&total); err != nil {
+					b.Exit("db: cannot scan rows count: %v", err)
+				}
+			}
+			rows.Close()
+		}
+
+		var page = b.Randomizer.GetWorker(c.WorkerID).Intn(paginationMaxPage)
+		var rows, err = session.Select(testDesc.table.TableName, &db.SelectCtrl{
+			Fields: []string{"id"},
+			Where:  where,
+			Order:  []string{"asc(id)"},
+			Page: db.Page{
+				Limit:  int64(batch),
+				Offset: int64(page * batch),
+			},
+		})
+		if err != nil {
+			if isNonFatalError(b, c.WorkerID, err) {
+				return batch
+			}
+			b.Exit("db: cannot select rows: %v", err)
+		}
+
+		for rows.Next() {
+		}
+		rows.Close()
+
+		return 1
+	}
+}
+
+// TestSelectMediumPaginated selects random page of the random tenant rows from the 'medium' table
+var TestSelectMediumPaginated = TestDesc{
+	name:        "select-medium-paginated",
+	metric:      "pages/sec",
+	description: "select random page from the 'medium' table WHERE tenant_id = {} ORDER BY id LIMIT {batch} OFFSET {page}, use --pagination-include-count to issue COUNT(0) before every page",
+	category:    TestSelect,
+	isReadonly:  true,
+	isDBRTest:   false,
+	databases:   ALL,
+	table:       TestTableMedium,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		var withCount = func(b *benchmark.Benchmark) bool {
+			return b.TestOpts.(*TestOpts).TestcaseOpts.PaginationIncludeCount
+		}
+
+		testGeneric(b, testDesc, selectPageWorker(withCount), 1)
+	},
+}
+
+// TestSelectMediumPaginatedCount selects COUNT(0) and then random page of the random tenant rows from the 'medium' table
+var TestSelectMediumPaginatedCount = TestDesc{
+	name:        "select-medium-paginated-count",
+	metric:      "pages/sec",
+	description: "select COUNT(0) and then random page from the 'medium' table WHERE tenant_id = {} ORDER BY id LIMIT {batch} OFFSET {page}",
+	category:    TestSelect,
+	isReadonly:  true,
+	isDBRTest:   false,
+	databases:   ALL,
+	table:       TestTableMedium,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		var withCount = func(b *benchmark.Benchmark) bool { return true }
+
+		testGeneric(b, testDesc, selectPageWorker(withCount), 1)
+	},
+}
+
 // TestSelectHeavyLast selects last row from the 'heavy' table
 var TestSelectHeavyLast = TestDesc{
 	name:        "select-heavy-last",
@@ -404,7 +558,7 @@ var TestSelectHeavyRand = TestDesc{
 	table:       TestTableHeavy,
 	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
 		var where = func(b *benchmark.Benchmark, workerId int) map[string][]string {
-			id := b.Randomizer.GetWorker(workerId).Uintn64(testDesc.table.RowsCount - 1)
+			id := randomID(b, workerId, testDesc)
 
 			return map[string][]string{"id": {fmt.Sprintf("gt(%d)", id)}}
 		}
@@ -428,7 +582,7 @@ var TestSelectHeavyRandDBR = TestDesc{
 	table:       TestTableHeavy,
 	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
 		var where = func(b *benchmark.Benchmark, workerId int) map[string][]string {
-			id := b.Randomizer.GetWorker(workerId).Uintn64(testDesc.table.RowsCount - 1)
+			id := randomID(b, workerId, testDesc)
 
 			return map[string][]string{"id": {fmt.Sprintf("gt(%d)", id)}}
 		}
@@ -680,6 +834,49 @@ var TestSelectHeavyRandCustomerCount = TestDesc{
 	},
 }
 
+// TestSelectHeavyAggregateAPI selects COUNT(*), SUM(progress) and AVG(completion_time_ns) of the customer rows from the 'heavy' table using db.SelectCtrl.Aggregates
+var TestSelectHeavyAggregateAPI = TestDesc{
+	name:        "select-heavy-aggregate-api",
+	metric:      "queries/sec",
+	description: "select COUNT(*), SUM(progress), AVG(completion_time_ns) from the 'heavy' table WHERE tenant_id = {} using the typed aggregates API (compare with 'select-heavy-rand-in-customer-count')",
+	category:    TestSelect,
+	isReadonly:  true,
+	isDBRTest:   false,
+	databases:   ALL,
+	table:       TestTableHeavy,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		var colConfs = &[]benchmark.DBFakeColumnConf{{ColumnName: "customer_id", ColumnType: "customer_uuid"}}
+
+		var aggregates = []db.AggregateField{
+			{Function: db.AggregateCount},
+			{Function: db.AggregateSum, Column: "progress"},
+			{Function: db.AggregateAvg, Column: "completion_time_ns"},
+		}
+
+		testGeneric(b, testDesc, func(b *benchmark.Benchmark, c *DBConnector, testDesc *TestDesc, batch int) (loops int) { //nolint:revive
+			var w = b.GenFakeDataAsMap(c.WorkerID, colConfs, false)
+
+			var session = c.database.Session(c.database.Context(context.Background()))
+			var rows, err = session.Select(testDesc.table.TableName, &db.SelectCtrl{
+				Aggregates: aggregates,
+				Where:      map[string][]string{"tenant_vis_list": {fmt.Sprintf("%s", (*w)["customer_id"])}},
+			})
+			if err != nil {
+				if isNonFatalError(b, c.WorkerID, err) {
+					return 1
+				}
+				b.Exit("db: cannot aggregate rows: %v", err)
+			}
+
+			for rows.Next() {
+			}
+			rows.Close()
+
+			return 1
+		}, 1)
+	},
+}
+
 // TestSelectHeavyRandPartnerRecent selects random page from the 'heavy' table WHERE tenant_id = {} AND ordered by enqueue_time DESC
 var TestSelectHeavyRandPartnerRecent = TestDesc{
 	name:        "select-heavy-rand-in-partner-recent",
@@ -781,7 +978,8 @@ var TestSelectHeavyForUpdateSkipLocked = TestDesc{
 
 		worker := func(b *benchmark.Benchmark, c *DBConnector, testDesc *TestDesc, batch int) (loops int) { //nolint:revive
 
-			var session = c.database.Session(c.database.Context(context.Background()))
+			var dbCtx = c.database.Context(context.Background())
+			var session = c.database.Session(dbCtx)
 			if txErr := session.Transact(func(tx db.DatabaseAccessor) error {
 				var id int64
 				var progress int
@@ -798,6 +996,104 @@ var TestSelectHeavyForUpdateSkipLocked = TestDesc{
 			}); txErr != nil {
 				b.Exit(txErr.Error())
 			}
+			b.AddRetries(dbCtx.TxRetries)
+
+			return 1
+		}
+		testGeneric(b, testDesc, worker, 10000)
+	},
+}
+
+// TestSelectHeavyReadPastMSSQL dequeues a row from the 'heavy' table skipping the rows locked by other workers by the READPAST hint
+var TestSelectHeavyReadPastMSSQL = TestDesc{
+	name:        "select-heavy-readpast-mssql",
+	metric:      "updates/sec",
+	description: "do SELECT TOP(1) WITH (UPDLOCK, READPAST, ROWLOCK) WHERE state = 0 and then UPDATE WITH (ROWLOCK) as the job queue dequeue, use --queue-workers to set the consumers count (compare with 'select-heavy-for-update-skip-locked')",
+	category:    TestOther,
+	isReadonly:  false,
+	isDBRTest:   false,
+	databases:   []db.DialectName{db.MSSQL},
+	table:       TestTableHeavy,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		if queueWorkers := b.TestOpts.(*TestOpts).TestcaseOpts.QueueWorkers; queueWorkers > 0 {
+			b.CommonOpts.Workers = queueWorkers
+		}
+
+		// UPDLOCK keeps the selected row locked till the UPDATE, so READPAST makes the other consumers skip it
+		var query = fmt.Sprintf("SELECT TOP(1) id, progress FROM %s WITH (UPDLOCK, READPAST, ROWLOCK) WHERE state = 0", testDesc.table.TableName)
+
+		worker := func(b *benchmark.Benchmark, c *DBConnector, testDesc *TestDesc, batch int) (loops int) { //nolint:revive
+			var dbCtx = c.database.Context(context.Background())
+			var session = c.database.Session(dbCtx)
+			if txErr := session.Transact(func(tx db.DatabaseAccessor) error {
+				var id int64
+				var progress int
+
+				if err := tx.QueryRow(query).Scan(&id, &progress); err != nil {
+					if errors.Is(err, sql.ErrNoRows) {
+						// all the queued rows are locked by the other consumers
+						return nil
+					}
+					return err
+				}
+
+				if _, err := tx.Exec(fmt.Sprintf("UPDATE %s WITH (ROWLOCK) SET progress = %d WHERE id = %d", testDesc.table.TableName, progress+1, id)); err != nil {
+					return err
+				}
+
+				return nil
+			}); txErr != nil {
+				if isNonFatalError(b, c.WorkerID, txErr) {
+					return 1
+				}
+				b.Exit(txErr.Error())
+			}
+			b.AddRetries(dbCtx.TxRetries)
+
+			return 1
+		}
+		testGeneric(b, testDesc, worker, 10000)
+	},
+}
+
+// TestSelectHeavyForUpdateCockroachDB selects a row from the 'heavy' table FOR UPDATE and then updates it in CockroachDB
+var TestSelectHeavyForUpdateCockroachDB = TestDesc{
+	name:        "select-heavy-for-update-cockroachdb",
+	metric:      "updates/sec",
+	description: "do SELECT FOR UPDATE of a random row of the first --workers*2+1 ones and then UPDATE in the SERIALIZABLE transaction retried on SQLSTATE 40001 (see --max-retries), CockroachDB waits for the locked rows instead of skipping them (compare with 'select-heavy-for-update-skip-locked')",
+	category:    TestOther,
+	isReadonly:  false,
+	isDBRTest:   false,
+	databases:   []db.DialectName{db.COCKROACHDB},
+	table:       TestTableHeavy,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		max := b.CommonOpts.Workers*2 + 1
+
+		worker := func(b *benchmark.Benchmark, c *DBConnector, testDesc *TestDesc, batch int) (loops int) { //nolint:revive
+			// SKIP LOCKED is honored under READ COMMITTED only, so the workers contend for the same rows as SKIP LOCKED ones would do
+			var id = 1 + b.Randomizer.GetWorker(c.WorkerID).Intn(max)
+
+			var dbCtx = c.database.Context(context.Background())
+			var session = c.database.Session(dbCtx)
+			if txErr := session.Transact(func(tx db.DatabaseAccessor) error {
+				var progress int
+
+				if err := tx.QueryRow(fmt.Sprintf("SELECT progress FROM %s WHERE id = %d FOR UPDATE", testDesc.table.TableName, id)).Scan(&progress); err != nil {
+					return err
+				}
+
+				if _, err := tx.Exec(fmt.Sprintf("UPDATE %s SET progress = %d WHERE id = %d", testDesc.table.TableName, progress+1, id)); err != nil {
+					return err
+				}
+
+				return nil
+			}); txErr != nil {
+				if isNonFatalError(b, c.WorkerID, txErr) {
+					return 1
+				}
+				b.Exit(txErr.Error())
+			}
+			b.AddRetries(dbCtx.TxRetries)
 
 			return 1
 		}
@@ -813,18 +1109,74 @@ var TestInsertLight = TestDesc{
 	category:    TestInsert,
 	isReadonly:  false,
 	isDBRTest:   false,
-	databases:   ALL,
+	databases:   allAnd(db.SCYLLA),
 	table:       TestTableLight,
 	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
 		testInsertGeneric(b, testDesc)
 	},
 }
 
+// withCommandLatencies runs the test collecting the loop latencies, so the average and p95 ones are printed along with the score
+func withCommandLatencies(b *benchmark.Benchmark, run func()) {
+	var collectLatencies = b.CollectLatencies
+	b.CollectLatencies = true
+	defer func() { b.CollectLatencies = collectLatencies }()
+
+	run()
+}
+
+// TestInsertLightIgnoreDuplicates re-inserts existing rows into the 'light' table skipping the primary key conflicts
+var TestInsertLightIgnoreDuplicates = TestDesc{
+	name:        "insert-light-ignore-duplicates",
+	metric:      "rows/sec",
+	description: "insert {batch} rows with already existing ids into the 'light' table skipping the conflicts (ON CONFLICT DO NOTHING / INSERT IGNORE, row by row on MSSQL)",
+	category:    TestInsert,
+	isReadonly:  false,
+	isDBRTest:   false,
+	databases:   RELATIONAL,
+	table:       TestTableLight,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		colConfs := testDesc.table.GetColumnsForInsert(db.WithAutoInc(getDBDriver(b)))
+
+		worker := func(b *benchmark.Benchmark, c *DBConnector, testDesc *TestDesc, batch int) (loops int) {
+			var columns []string
+			var rows = make([][]interface{}, 0, batch)
+			for i := 0; i < batch; i++ {
+				var id = b.Randomizer.GetWorker(c.WorkerID).Uintn64(testDesc.table.RowsCount-1) + 1
+				var fakeColumns, values = b.GenFakeData(c.WorkerID, colConfs, false)
+				columns = append([]string{"id"}, fakeColumns...)
+				rows = append(rows, append([]interface{}{id}, values...))
+			}
+
+			var session = c.database.Session(c.database.Context(context.Background()))
+			if err := session.Transact(func(tx db.DatabaseAccessor) error {
+				if c.database.DialectName() == db.MSSQL {
+					// explicit values can't be inserted into the identity column by default
+					if _, err := tx.Exec(fmt.Sprintf("SET IDENTITY_INSERT %s ON", testDesc.table.TableName)); err != nil {
+						return err
+					}
+				}
+
+				return tx.BulkInsert(testDesc.table.TableName, &db.BulkInsertCtrl{Rows: rows, ColumnNames: columns, IgnoreConflicts: true})
+			}); err != nil {
+				if isNonFatalError(b, c.WorkerID, err) {
+					return batch
+				}
+				b.Exit(err.Error())
+			}
+
+			return batch
+		}
+		testGeneric(b, testDesc, worker, 2)
+	},
+}
+
 // insertByPreparedDataWorker inserts a row into the 'light' table using prepared statement for the batch
 func insertByPreparedDataWorker(b *benchmark.Benchmark, c *DBConnector, testDesc *TestDesc, batch int) (loops int) {
 	colConfs := testDesc.table.GetColumnsForInsert(db.WithAutoInc(c.database.DialectName()))
 	workerID := c.WorkerID
-	sess := c.database.Session(c.database.Context(context.Background()))
+	var dbCtx = c.database.Context(context.Background())
+	sess := c.database.Session(dbCtx)
 
 	if txErr := sess.Transact(func(tx db.DatabaseAccessor) error {
 		columns, _ := b.GenFakeData(workerID, colConfs, false)
@@ -857,6 +1209,7 @@ func insertByPreparedDataWorker(b *benchmark.Benchmark, c *DBConnector, testDesc
 	}); txErr != nil {
 		c.Exit(txErr.Error())
 	}
+	b.AddRetries(dbCtx.TxRetries)
 
 	return batch
 }
@@ -891,12 +1244,14 @@ func insertMultiValueDataWorker(b *benchmark.Benchmark, c *DBConnector, testDesc
 		}
 	}
 
-	var session = c.database.Session(c.database.Context(context.Background()))
+	var dbCtx = c.database.Context(context.Background())
+	var session = c.database.Session(dbCtx)
 	if txErr := session.Transact(func(tx db.DatabaseAccessor) error {
-		return tx.BulkInsert(testDesc.table.TableName, values, columns)
+		return tx.BulkInsert(testDesc.table.TableName, &db.BulkInsertCtrl{Rows: values, ColumnNames: columns})
 	}); txErr != nil {
 		b.Exit(txErr.Error())
 	}
+	b.AddRetries(dbCtx.TxRetries)
 
 	return batch
 }
@@ -921,9 +1276,12 @@ func copyDataWorker(b *benchmark.Benchmark, c *DBConnector, testDesc *TestDesc,
 	var sql string
 	colConfs := testDesc.table.GetColumnsForInsert(db.WithAutoInc(c.database.DialectName()))
 	workerID := c.WorkerID
-	sess := c.database.Session(c.database.Context(context.Background()))
+	var dbCtx = c.database.Context(context.Background())
+	sess := c.database.Session(dbCtx)
+	var bytes int64
 
 	if txErr := sess.Transact(func(tx db.DatabaseAccessor) error {
+		bytes = 0
 		columns, _ := b.GenFakeData(workerID, colConfs, false)
 
 		switch c.database.DialectName() {
@@ -953,6 +1311,7 @@ func copyDataWorker(b *benchmark.Benchmark, c *DBConnector, testDesc *TestDesc,
 				stmt.Close()
 				c.Exit(err.Error())
 			}
+			bytes += blobBytes(values)
 		}
 
 		_, err = stmt.Exec()
@@ -965,6 +1324,8 @@ func copyDataWorker(b *benchmark.Benchmark, c *DBConnector, testDesc *TestDesc,
 	}); txErr != nil {
 		c.Exit(txErr.Error())
 	}
+	b.AddRetries(dbCtx.TxRetries)
+	b.AddBytes(bytes)
 
 	return batch
 }
@@ -1007,10 +1368,100 @@ var TestInsertMedium = TestDesc{
 	category:    TestInsert,
 	isReadonly:  false,
 	isDBRTest:   false,
+	databases:   append(allAnd(db.SCYLLA), redisDialects...),
+	table:       TestTableMedium,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		testInsertGeneric(b, testDesc)
+	},
+}
+
+// TestInsertMongoMedium inserts a document into the 'medium' collection of MongoDB
+var TestInsertMongoMedium = TestDesc{
+	name:        "insert-mongo-medium",
+	metric:      "rows/sec",
+	description: "insert a document into the 'medium' collection by the MongoDB bulk write API (the same as 'insert-medium')",
+	category:    TestInsert,
+	isReadonly:  false,
+	isDBRTest:   false,
+	databases:   []db.DialectName{db.MONGODB},
+	table:       TestTableMedium,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		testInsertGeneric(b, testDesc)
+	},
+}
+
+// TestInsertMediumConcurrentUpdates inserts rows into and selects random rows from the 'medium' table by concurrent workers
+var TestInsertMediumConcurrentUpdates = TestDesc{
+	name:        "insert-medium-concurrent-updates",
+	metric:      "rows/sec",
+	description: "run 'select-medium-rand' by --mixed-read-pct percent of the workers and 'insert-medium' by the rest of them simultaneously on the 'medium' table",
+	category:    TestInsert,
+	isReadonly:  false,
+	isDBRTest:   false,
+	databases:   ALL,
+	table:       TestTableMedium,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		var where = func(b *benchmark.Benchmark, workerId int) map[string][]string {
+			id := randomID(b, workerId, testDesc)
+
+			return map[string][]string{"id": {fmt.Sprintf("ge(%d)", id)}}
+		}
+
+		var orderBy = func(b *benchmark.Benchmark, workerId int) []string { //nolint:revive
+			return []string{"asc(id)"}
+		}
+
+		testReadWriteMix(b, testDesc,
+			newSelectWorker(b, testDesc, nil, []string{"id"}, where, orderBy),
+			newInsertWorker(b, testDesc),
+			1)
+	},
+}
+
+// TestInsertMediumMultiTenantParallel runs the 'insert-medium' test with the workers sharing the tenants and with a tenant per worker
+var TestInsertMediumMultiTenantParallel = TestDesc{
+	name:        "insert-medium-multi-tenant-parallel",
+	metric:      "rows/sec",
+	description: "run 'insert-medium' with the workers sharing the tenants working set and then with a distinct tenant per worker (see --single-tenant-per-worker) and show the rate difference",
+	category:    TestInsert,
+	isReadonly:  false,
+	isDBRTest:   false,
 	databases:   ALL,
 	table:       TestTableMedium,
 	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		var opts = &b.TestOpts.(*TestOpts).TestcaseOpts
+		var singleTenantPerWorker = opts.SingleTenantPerWorker
+		defer func() { opts.SingleTenantPerWorker = singleTenantPerWorker }()
+
+		opts.SingleTenantPerWorker = false
+		testInsertGeneric(b, testDesc)
+		var shared = b.Score
+
+		opts.SingleTenantPerWorker = true
 		testInsertGeneric(b, testDesc)
+		var perWorker = b.Score
+
+		var difference float64
+		if shared.Rate > 0 {
+			difference = (perWorker.Rate/shared.Rate - 1) * 100
+		}
+		fmt.Printf("test: %s; rate with shared tenants: %s; rate with tenant per worker: %s; difference: %+.1f%%\n",
+			testDesc.name, shared.FormatRate(4), perWorker.FormatRate(4), difference)
+	},
+}
+
+// TestInsertMediumUniqueViolations inserts rows into the 'medium unique' table, part of them violate the unique index
+var TestInsertMediumUniqueViolations = TestDesc{
+	name:        "insert-medium-unique-violations",
+	metric:      "rows/sec",
+	description: "insert {batch} rows one by one into the 'medium unique' table, --unique-violations-pct of them re-use the last inserted euc_id and are rejected by the unique index",
+	category:    TestInsert,
+	isReadonly:  false,
+	isDBRTest:   false,
+	databases:   RELATIONAL,
+	table:       TestTableMediumUnique,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		testInsertUniqueViolations(b, testDesc, "euc_id")
 	},
 }
 
@@ -1078,7 +1529,7 @@ var TestInsertMediumDBR = TestDesc{
 var TestInsertBlob = TestDesc{
 	name:        "insert-blob",
 	metric:      "rows/sec",
-	description: "insert a row with large random blob into the 'blob' table",
+	description: "insert a row with large random blob into the 'blob' table, see --json-schema",
 	category:    TestInsert,
 	isReadonly:  false,
 	isDBRTest:   false,
@@ -1092,10 +1543,42 @@ var TestInsertBlob = TestDesc{
 				testDesc.table.ColumnsConf[i].MinSize = b.TestOpts.(*TestOpts).TestcaseOpts.MinBlobSize
 			}
 		}
+		useJSONSchema(b, testDesc, "blob")
 		testInsertGeneric(b, testDesc)
 	},
 }
 
+// useJSONSchema makes the columns of given type ('json' or 'blob') of the test table filled with the JSON documents
+// conforming to the --json-schema, the columns are left intact if the option is not set
+func useJSONSchema(b *benchmark.Benchmark, testDesc *TestDesc, columnType string) {
+	var path = b.TestOpts.(*TestOpts).TestcaseOpts.JSONSchema
+	if path == "" {
+		return
+	}
+
+	var gen, err = benchmark.LoadJSONSchemaGenerator(path)
+	if err != nil {
+		b.Exit("cannot use --json-schema: %v", err)
+	}
+
+	var generatorType = "json_schema"
+	if columnType == "blob" {
+		generatorType = "json_schema_blob"
+		benchmark.RegisterColumnGenerator(generatorType, benchmark.ColumnGeneratorFunc(func(workerID int, c benchmark.ColumnConf) interface{} {
+			return []byte(gen.GenerateValue(workerID, c).(string))
+		}))
+	} else {
+		benchmark.RegisterColumnGenerator(generatorType, gen)
+	}
+
+	testDesc.table.InitColumnsConf()
+	for i := range testDesc.table.ColumnsConf {
+		if testDesc.table.ColumnsConf[i].ColumnType == columnType {
+			testDesc.table.ColumnsConf[i].ColumnType = generatorType
+		}
+	}
+}
+
 // TestCopyBlob copies a row with large random blob into the 'blob' table
 var TestCopyBlob = TestDesc{
 	name:        "copy-blob",
@@ -1124,7 +1607,8 @@ func createLargeObjectWorker(b *benchmark.Benchmark, c *DBConnector, testDesc *T
 	parametersPlaceholder := db.GenDBParameterPlaceholders(0, len(*colConfs))
 	workerID := c.WorkerID
 
-	var session = c.database.Session(c.database.Context(context.Background()))
+	var dbCtx = c.database.Context(context.Background())
+	var session = c.database.Session(dbCtx)
 
 	if txErr := session.Transact(func(tx db.DatabaseAccessor) error {
 		var sql string
@@ -1172,6 +1656,7 @@ func createLargeObjectWorker(b *benchmark.Benchmark, c *DBConnector, testDesc *T
 	}); txErr != nil {
 		c.Exit(txErr.Error())
 	}
+	b.AddRetries(dbCtx.TxRetries)
 
 	return batch
 }
@@ -1199,29 +1684,123 @@ var TestInsertHeavy = TestDesc{
 	category:    TestInsert,
 	isReadonly:  false,
 	isDBRTest:   false,
-	databases:   ALL,
+	databases:   allAnd(db.COCKROACHDB),
 	table:       TestTableHeavy,
 	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		var stop = startHeavyMaterializedViewRefresher(b)
 		testInsertGeneric(b, testDesc)
+		stop()
 	},
 }
 
-// TestInsertHeavyPrepared inserts a row into the 'heavy' table using prepared statement for the batch
-var TestInsertHeavyPrepared = TestDesc{
-	name:        "insert-heavy-prepared",
+// TestInsertHeavyColumnar inserts a batch of rows into the 'heavy' table using ClickHouse column-oriented batch API
+var TestInsertHeavyColumnar = TestDesc{
+	name:        "insert-heavy-columnar",
 	metric:      "rows/sec",
-	description: "insert a row into the 'heavy' table using prepared statement for the batch",
+	description: "insert a batch of rows into the 'heavy' table using the ClickHouse native column-oriented batch (compare with 'insert-heavy')",
 	category:    TestInsert,
 	isReadonly:  false,
 	isDBRTest:   false,
-	databases:   RELATIONAL,
+	databases:   []db.DialectName{db.CLICKHOUSE},
 	table:       TestTableHeavy,
 	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
-		testGeneric(b, testDesc, insertByPreparedDataWorker, 0)
+		var dbOpts = &b.TestOpts.(*TestOpts).DBOpts
+		var origColumnar = dbOpts.CHColumnar
+		dbOpts.CHColumnar = true
+
+		testInsertGeneric(b, testDesc)
+
+		dbOpts.CHColumnar = origColumnar
 	},
 }
 
-// TestInsertHeavyMultivalue inserts a row into the 'heavy' table using INSERT INTO t (x, y, z) VALUES (..., ..., ...) "
+// withCassandraConsistency runs the test launcher with connections using given Cassandra consistency level
+func withCassandraConsistency(level string, launcher func(b *benchmark.Benchmark, testDesc *TestDesc)) func(b *benchmark.Benchmark, testDesc *TestDesc) {
+	return func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		var dbOpts = &b.TestOpts.(*TestOpts).DBOpts
+		var origConsistency = dbOpts.CassandraConsistency
+		dbOpts.CassandraConsistency = level
+
+		launcher(b, testDesc)
+
+		dbOpts.CassandraConsistency = origConsistency
+	}
+}
+
+// TestInsertMediumConsistencyOne inserts a row into the 'medium' table with the Cassandra ONE consistency level
+var TestInsertMediumConsistencyOne = TestDesc{
+	name:         "insert-medium-consistency-one",
+	metric:       "rows/sec",
+	description:  "insert a row into the 'medium' table with the Cassandra ONE consistency level (compare with 'insert-medium-consistency-quorum')",
+	category:     TestInsert,
+	isReadonly:   false,
+	isDBRTest:    false,
+	databases:    []db.DialectName{db.CASSANDRA},
+	table:        TestTableMedium,
+	launcherFunc: withCassandraConsistency("ONE", testInsertGeneric),
+}
+
+// TestInsertMediumConsistencyQuorum inserts a row into the 'medium' table with the Cassandra QUORUM consistency level
+var TestInsertMediumConsistencyQuorum = TestDesc{
+	name:         "insert-medium-consistency-quorum",
+	metric:       "rows/sec",
+	description:  "insert a row into the 'medium' table with the Cassandra QUORUM consistency level (compare with 'insert-medium-consistency-one')",
+	category:     TestInsert,
+	isReadonly:   false,
+	isDBRTest:    false,
+	databases:    []db.DialectName{db.CASSANDRA},
+	table:        TestTableMedium,
+	launcherFunc: withCassandraConsistency("QUORUM", testInsertGeneric),
+}
+
+// selectMediumLast selects the last row from the 'medium' table
+func selectMediumLast(b *benchmark.Benchmark, testDesc *TestDesc) {
+	var orderBy = func(b *benchmark.Benchmark, workerId int) []string { return []string{"desc(id)"} } //nolint:revive
+	testSelect(b, testDesc, nil, []string{"id"}, nil, orderBy, 1)
+}
+
+// TestSelectMediumLastConsistencyOne selects the last row from the 'medium' table with the Cassandra ONE consistency level
+var TestSelectMediumLastConsistencyOne = TestDesc{
+	name:         "select-medium-last-consistency-one",
+	metric:       "rows/sec",
+	description:  "select last row from the 'medium' table with the Cassandra ONE consistency level (compare with 'select-medium-last-consistency-quorum')",
+	category:     TestSelect,
+	isReadonly:   true,
+	isDBRTest:    false,
+	databases:    []db.DialectName{db.CASSANDRA},
+	table:        TestTableMedium,
+	launcherFunc: withCassandraConsistency("ONE", selectMediumLast),
+}
+
+// TestSelectMediumLastConsistencyQuorum selects the last row from the 'medium' table with the Cassandra QUORUM consistency level
+var TestSelectMediumLastConsistencyQuorum = TestDesc{
+	name:         "select-medium-last-consistency-quorum",
+	metric:       "rows/sec",
+	description:  "select last row from the 'medium' table with the Cassandra QUORUM consistency level (compare with 'select-medium-last-consistency-one')",
+	category:     TestSelect,
+	isReadonly:   true,
+	isDBRTest:    false,
+	databases:    []db.DialectName{db.CASSANDRA},
+	table:        TestTableMedium,
+	launcherFunc: withCassandraConsistency("QUORUM", selectMediumLast),
+}
+
+// TestInsertHeavyPrepared inserts a row into the 'heavy' table using prepared statement for the batch
+var TestInsertHeavyPrepared = TestDesc{
+	name:        "insert-heavy-prepared",
+	metric:      "rows/sec",
+	description: "insert a row into the 'heavy' table using prepared statement for the batch",
+	category:    TestInsert,
+	isReadonly:  false,
+	isDBRTest:   false,
+	databases:   RELATIONAL,
+	table:       TestTableHeavy,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		testGeneric(b, testDesc, insertByPreparedDataWorker, 0)
+	},
+}
+
+// TestInsertHeavyMultivalue inserts a row into the 'heavy' table using INSERT INTO t (x, y, z) VALUES (..., ..., ...) "
 var TestInsertHeavyMultivalue = TestDesc{
 	name:        "insert-heavy-multivalue",
 	metric:      "rows/sec",
@@ -1305,6 +1884,189 @@ var TestSelectVector768NearestL2 = TestDesc{
 	},
 }
 
+// osKNNNeighbours is the amount of nearest neighbours (k) requested by the 'select-os-knn' test
+const osKNNNeighbours = 10
+
+// randomOSVector returns random vector of the 'os vector' table dimensions
+func randomOSVector(b *benchmark.Benchmark, workerID int) []float32 {
+	var rnd = b.Randomizer.GetWorker(workerID).Seeded()
+	var vec = make([]float32, osKNNDims)
+	for i := range vec {
+		vec[i] = rnd.Float32()
+	}
+
+	return vec
+}
+
+// TestInsertOSVector inserts rows with random vectors into the OpenSearch k-NN 'os vector' table
+var TestInsertOSVector = TestDesc{
+	name:        "insert-os-vector",
+	metric:      "rows/sec",
+	description: "insert {batch} random vectors (see --os-knn-dims) into the 'os vector' table indexed by the OpenSearch k-NN plugin (see --os-knn-engine)",
+	category:    TestInsert,
+	isReadonly:  false,
+	databases:   []db.DialectName{db.OPENSEARCH},
+	table:       TestTableOSVector,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		var lastID int64
+
+		worker := func(b *benchmark.Benchmark, c *DBConnector, testDesc *TestDesc, batch int) (loops int) {
+			var rows = make([][]interface{}, 0, batch)
+			for i := 0; i < batch; i++ {
+				rows = append(rows, []interface{}{atomic.AddInt64(&lastID, 1), randomOSVector(b, c.WorkerID)})
+			}
+
+			var session = c.database.Session(c.database.Context(context.Background()))
+			if err := session.BulkInsert(testDesc.table.TableName, &db.BulkInsertCtrl{Rows: rows, ColumnNames: []string{"id", "embedding"}}); err != nil {
+				if isNonFatalError(b, c.WorkerID, err) {
+					return batch
+				}
+				b.Exit(err.Error())
+			}
+
+			return batch
+		}
+
+		initCommon(b, testDesc, 0)
+		lastID = int64(testDesc.table.RowsCount)
+		testGeneric(b, testDesc, worker, 0)
+	},
+}
+
+// TestSelectOSKNN selects k nearest vectors from the OpenSearch k-NN 'os vector' table using the knn query
+var TestSelectOSKNN = TestDesc{
+	name:        "select-os-knn",
+	metric:      "rows/sec",
+	description: "select 10 nearest vectors to the random one from the 'os vector' table using the OpenSearch k-NN plugin knn query",
+	category:    TestSelect,
+	isReadonly:  true,
+	databases:   []db.DialectName{db.OPENSEARCH},
+	table:       TestTableOSVector,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		worker := func(b *benchmark.Benchmark, c *DBConnector, testDesc *TestDesc, batch int) (loops int) { //nolint:revive
+			var vec = "[" + strings.Trim(strings.Replace(fmt.Sprint(randomOSVector(b, c.WorkerID)), " ", ", ", -1), "[]") + "]"
+
+			var session = c.database.Session(c.database.Context(context.Background()))
+			var rows, err = session.Select(testDesc.table.TableName, &db.SelectCtrl{
+				Fields: []string{"id"},
+				Order:  []string{fmt.Sprintf("nearest(embedding;L2;%s)", vec)},
+				Page:   db.Page{Limit: osKNNNeighbours},
+			})
+			if err != nil {
+				if isNonFatalError(b, c.WorkerID, err) {
+					return 1
+				}
+				b.Exit("db: cannot select rows: %v", err)
+			}
+
+			for rows.Next() {
+			}
+			rows.Close() //nolint:errcheck
+
+			return 1
+		}
+		testGeneric(b, testDesc, worker, 1)
+	},
+}
+
+// vectorKNNNeighbours is the amount of nearest neighbours requested by the 'select-vector-knn' test
+const vectorKNNNeighbours = 10
+
+// randomVector returns random vector of the 'pgvector' table dimensions
+func randomVector(b *benchmark.Benchmark, workerID int) []float32 {
+	var rnd = b.Randomizer.GetWorker(workerID).Seeded()
+	var vec = make([]float32, vectorDims)
+	for i := range vec {
+		vec[i] = rnd.Float32()
+	}
+
+	return vec
+}
+
+// ensurePGVector enables the pgvector extension, the 'pgvector' table tests can't run without it
+func ensurePGVector(b *benchmark.Benchmark) {
+	var c = dbConnector(b)
+	defer c.Release()
+
+	if err := createPGVectorExtension(c.database.Session(c.database.Context(context.Background()))); err != nil {
+		b.Exit("db: the pgvector extension is not available: %v", err)
+	}
+}
+
+// TestInsertVector inserts rows with random vectors into the 'pgvector' table
+var TestInsertVector = TestDesc{
+	name:        "insert-vector",
+	metric:      "rows/sec",
+	description: "insert {batch} random vectors (see --vector-dims) into the 'pgvector' table and show the average and p95 latency",
+	category:    TestInsert,
+	isReadonly:  false,
+	databases:   []db.DialectName{db.POSTGRES},
+	table:       TestTableVector,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		ensurePGVector(b)
+
+		worker := func(b *benchmark.Benchmark, c *DBConnector, testDesc *TestDesc, batch int) (loops int) {
+			var rows = make([][]interface{}, 0, batch)
+			for i := 0; i < batch; i++ {
+				rows = append(rows, []interface{}{randomVector(b, c.WorkerID)})
+			}
+
+			var session = c.database.Session(c.database.Context(context.Background()))
+			if err := session.BulkInsert(testDesc.table.TableName, &db.BulkInsertCtrl{Rows: rows, ColumnNames: []string{"embedding"}}); err != nil {
+				if isNonFatalError(b, c.WorkerID, err) {
+					return batch
+				}
+				b.Exit(err.Error())
+			}
+
+			return batch
+		}
+
+		withCommandLatencies(b, func() {
+			testGeneric(b, testDesc, worker, 0)
+		})
+	},
+}
+
+// TestSelectVectorKNN selects the nearest vectors from the 'pgvector' table ordered by the cosine distance
+var TestSelectVectorKNN = TestDesc{
+	name:        "select-vector-knn",
+	metric:      "rows/sec",
+	description: "select 10 nearest vectors to the random one by the cosine distance (<=>) from the 'pgvector' table and show the average and p95 latency",
+	category:    TestSelect,
+	isReadonly:  true,
+	databases:   []db.DialectName{db.POSTGRES},
+	table:       TestTableVector,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		ensurePGVector(b)
+
+		var query = fmt.Sprintf("SELECT id, embedding <=> $1 AS distance FROM %s ORDER BY distance LIMIT %d", testDesc.table.TableName, vectorKNNNeighbours)
+
+		worker := func(b *benchmark.Benchmark, c *DBConnector, testDesc *TestDesc, batch int) (loops int) { //nolint:revive
+			var vec = "[" + strings.Trim(strings.Replace(fmt.Sprint(randomVector(b, c.WorkerID)), " ", ",", -1), "[]") + "]"
+
+			var session = c.database.Session(c.database.Context(context.Background()))
+			var rows, err = session.Query(query, vec)
+			if err != nil {
+				if isNonFatalError(b, c.WorkerID, err) {
+					return 1
+				}
+				b.Exit("db: cannot select rows: %v", err)
+			}
+
+			for rows.Next() {
+			}
+			rows.Close() //nolint:errcheck
+
+			return 1
+		}
+
+		withCommandLatencies(b, func() {
+			testGeneric(b, testDesc, worker, 1)
+		})
+	},
+}
+
 // TestInsertEmailSecurityMultiValue inserts email security data into the 'email_security' table
 var TestInsertEmailSecurityMultiValue = TestDesc{
 	name:        "insert-email-security-multivalue",
@@ -1349,13 +2111,14 @@ var TestSelectEmailByEmbeddingNearestL2 = TestDesc{
 var TestInsertJSON = TestDesc{
 	name:        "insert-json",
 	metric:      "rows/sec",
-	description: "insert a row into a table with JSON(b) column",
+	description: "insert a row into a table with JSON(b) column, see --json-schema",
 	category:    TestInsert,
 	isReadonly:  false,
 	isDBRTest:   false,
 	databases:   RELATIONAL,
 	table:       TestTableJSON,
 	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		useJSONSchema(b, testDesc, "json")
 		testInsertGeneric(b, testDesc)
 	},
 }
@@ -1387,7 +2150,7 @@ var TestSelectJSONByIndexedValue = TestDesc{
 	table:       TestTableJSON,
 	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
 		where := func(b *benchmark.Benchmark, workerId int) string {
-			id := b.Randomizer.GetWorker(workerId).Uintn64(testDesc.table.RowsCount - 1)
+			id := randomID(b, workerId, testDesc)
 
 			var dialectName, err = db.GetDialectName(b.TestOpts.(*TestOpts).DBOpts.ConnString)
 			if err != nil {
@@ -1412,6 +2175,78 @@ var TestSelectJSONByIndexedValue = TestDesc{
 	},
 }
 
+// TestSelectJSONPath selects a row from the 'json' table by the jsonpath expression
+var TestSelectJSONPath = TestDesc{
+	name:        "select-json-path",
+	metric:      "rows/sec",
+	description: "select a row from the 'json' table WHERE jsonb_path_query_first(json_data, {--json-path-expression}) IS NOT NULL (PostgreSQL 12+, compare with 'select-json-by-indexed-value')",
+	category:    TestSelect,
+	isReadonly:  true,
+	isDBRTest:   false,
+	databases:   []db.DialectName{db.POSTGRES},
+	table:       TestTableJSON,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		var expression = strings.ReplaceAll(b.TestOpts.(*TestOpts).TestcaseOpts.JSONPathExpression, "'", "''")
+
+		where := func(b *benchmark.Benchmark, workerId int) string {
+			id := randomID(b, workerId, testDesc)
+			return fmt.Sprintf("jsonb_path_query_first(json_data, '%s') IS NOT NULL AND id > %d", expression, id)
+		}
+		orderby := func(b *benchmark.Benchmark) string { //nolint:revive
+			return "id ASC"
+		}
+		testSelectRawSQLQuery(b, testDesc, nil, "id", where, orderby, 1)
+	},
+}
+
+// TestInsertJSONPathIndex inserts a row into the 'json path index' table with partial jsonpath functional index
+var TestInsertJSONPathIndex = TestDesc{
+	name:        "insert-json-path-index",
+	metric:      "rows/sec",
+	description: "insert a row into the 'json path index' table with partial functional index on jsonb_path_query_first(json_data, '$.field0.field0') (compare with 'insert-json')",
+	category:    TestInsert,
+	isReadonly:  false,
+	isDBRTest:   false,
+	databases:   []db.DialectName{db.POSTGRES},
+	table:       TestTableJSONPathIndex,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		testInsertGeneric(b, testDesc)
+	},
+}
+
+// TestInsertNetwork inserts a row with IPv4 or IPv6 address into the 'network' table
+var TestInsertNetwork = TestDesc{
+	name:        "insert-network",
+	metric:      "rows/sec",
+	description: "insert a row with random IPv4 or IPv6 address into the 'network' table",
+	category:    TestInsert,
+	isReadonly:  false,
+	isDBRTest:   false,
+	databases:   RELATIONAL,
+	table:       TestTableNetworkAddresses,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		testInsertGeneric(b, testDesc)
+	},
+}
+
+// TestSelectNetworkSubnet selects rows from the 'network' table with addresses within the 10.0.0.0/8 subnet
+var TestSelectNetworkSubnet = TestDesc{
+	name:        "select-network-subnet",
+	metric:      "rows/sec",
+	description: "select rows from the 'network' table WHERE ip_address << '10.0.0.0/8'::inet",
+	category:    TestSelect,
+	isReadonly:  true,
+	isDBRTest:   false,
+	databases:   []db.DialectName{db.POSTGRES},
+	table:       TestTableNetworkAddresses,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		where := func(b *benchmark.Benchmark, workerId int) string { //nolint:revive
+			return "ip_address << '10.0.0.0/8'::inet"
+		}
+		testSelectRawSQLQuery(b, testDesc, nil, "id, ip_address", where, nil, 1)
+	},
+}
+
 // TestSearchJSONByIndexedValue searches a row from the 'json' table using some json condition using LIKE {}
 var TestSearchJSONByIndexedValue = TestDesc{
 	name:        "search-json-by-indexed-value",
@@ -1424,7 +2259,7 @@ var TestSearchJSONByIndexedValue = TestDesc{
 	table:       TestTableJSON,
 	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
 		where := func(b *benchmark.Benchmark, workerId int) string {
-			id := b.Randomizer.GetWorker(workerId).Uintn64(testDesc.table.RowsCount - 1)
+			id := randomID(b, workerId, testDesc)
 
 			var dialectName, err = db.GetDialectName(b.TestOpts.(*TestOpts).DBOpts.ConnString)
 			if err != nil {
@@ -1461,7 +2296,7 @@ var TestSelectJSONByNonIndexedValue = TestDesc{
 	table:       TestTableJSON,
 	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
 		where := func(b *benchmark.Benchmark, workerId int) string {
-			id := b.Randomizer.GetWorker(workerId).Uintn64(testDesc.table.RowsCount - 1)
+			id := randomID(b, workerId, testDesc)
 
 			var dialectName, err = db.GetDialectName(b.TestOpts.(*TestOpts).DBOpts.ConnString)
 			if err != nil {
@@ -1486,35 +2321,35 @@ var TestSelectJSONByNonIndexedValue = TestDesc{
 	},
 }
 
-// TestSearchJSONByNonIndexedValue searches a row from the 'json' table using some json condition using LIKE {}
-var TestSearchJSONByNonIndexedValue = TestDesc{
-	name:        "search-json-by-nonindexed-value",
+// TestInsertJSONNested inserts a row with deeply nested JSON document into the 'json nested' table
+var TestInsertJSONNested = TestDesc{
+	name:        "insert-json-nested",
 	metric:      "rows/sec",
-	description: "search a row from the 'json' table using some json condition using LIKE {}",
+	description: "insert a row with 3-level nested ~1KB JSON document into the 'json nested' table without JSON index",
+	category:    TestInsert,
+	isReadonly:  false,
+	isDBRTest:   false,
+	databases:   []db.DialectName{db.POSTGRES},
+	table:       TestTableJSONNested,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		testInsertGeneric(b, testDesc)
+	},
+}
+
+// TestSelectJSONNestedByDeepValue selects a row from the 'json nested' table by the deep JSON value without index
+var TestSelectJSONNestedByDeepValue = TestDesc{
+	name:        "select-json-nested-by-deep-value",
+	metric:      "rows/sec",
+	description: "select a row from the 'json nested' table WHERE json_data->'level1'->'level2'->>'level3_field' = {} (no index)",
 	category:    TestSelect,
 	isReadonly:  true,
 	isDBRTest:   false,
-	databases:   RELATIONAL,
-	table:       TestTableJSON,
+	databases:   []db.DialectName{db.POSTGRES},
+	table:       TestTableJSONNested,
 	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
 		where := func(b *benchmark.Benchmark, workerId int) string {
-			id := b.Randomizer.GetWorker(workerId).Uintn64(testDesc.table.RowsCount - 1)
-
-			var dialectName, err = db.GetDialectName(b.TestOpts.(*TestOpts).DBOpts.ConnString)
-			if err != nil {
-				b.Exit(err)
-			}
-
-			switch dialectName {
-			case db.MYSQL:
-				return "JSON_EXTRACT(json_data, '$.field0.field1') LIKE '%eedl%' AND id > " + strconv.FormatUint(id, 10)
-			case db.POSTGRES:
-				return "json_data->'field0'->'field0'->>'field0' LIKE '%eedl%' AND id > " + strconv.FormatUint(id, 10) // searching for the 'needle' word
-			default:
-				b.Exit("The %s test is not supported on driver: %s", testDesc.name, dialectName)
-			}
-
-			return ""
+			var value = b.Randomizer.GetWorker(workerId).Intn(jsonNestedValueCardinality)
+			return fmt.Sprintf("json_data->'level1'->'level2'->>'level3_field' = 'value_%d'", value)
 		}
 		orderby := func(b *benchmark.Benchmark) string { //nolint:revive
 			return "id ASC"
@@ -1523,62 +2358,200 @@ var TestSearchJSONByNonIndexedValue = TestDesc{
 	},
 }
 
-// TestUpdateMedium updates random row in the 'medium' table
-var TestUpdateMedium = TestDesc{
-	name:        "update-medium",
+// TestInsertJSONNestedWithGIN inserts a row with deeply nested JSON document into the 'json nested' table with GIN index
+var TestInsertJSONNestedWithGIN = TestDesc{
+	name:        "insert-json-nested-with-gin",
 	metric:      "rows/sec",
-	description: "update random row in the 'medium' table",
-	category:    TestUpdate,
+	description: "insert a row with 3-level nested ~1KB JSON document into the 'json nested' table with GIN index on json_data->'level1'->'level2'",
+	category:    TestInsert,
 	isReadonly:  false,
 	isDBRTest:   false,
-	databases:   RELATIONAL,
-	table:       TestTableMedium,
+	databases:   []db.DialectName{db.POSTGRES},
+	table:       TestTableJSONNestedGIN,
 	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
-		testUpdateGeneric(b, testDesc, 1, nil)
+		testInsertGeneric(b, testDesc)
 	},
 }
 
-// TestUpdateMediumDBR updates random row in the 'medium' table using golang DBR query builder
-var TestUpdateMediumDBR = TestDesc{
-	name:        "dbr-update-medium",
+// TestSelectJSONNestedWithGIN selects a row from the 'json nested' table by the deep JSON value using GIN index
+var TestSelectJSONNestedWithGIN = TestDesc{
+	name:        "select-json-nested-with-gin",
 	metric:      "rows/sec",
-	description: "update random row in the 'medium' table using golang DB driver",
-	category:    TestUpdate,
-	isReadonly:  false,
-	isDBRTest:   true,
-	databases:   RELATIONAL,
-	table:       TestTableMedium,
+	description: "select a row from the 'json nested' table WHERE json_data->'level1'->'level2' @> {} using GIN index",
+	category:    TestSelect,
+	isReadonly:  true,
+	isDBRTest:   false,
+	databases:   []db.DialectName{db.POSTGRES},
+	table:       TestTableJSONNestedGIN,
 	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
-		testUpdateGeneric(b, testDesc, 1, nil)
+		where := func(b *benchmark.Benchmark, workerId int) string {
+			var value = b.Randomizer.GetWorker(workerId).Intn(jsonNestedValueCardinality)
+			return fmt.Sprintf("json_data->'level1'->'level2' @> '{\"level3_field\": \"value_%d\"}'", value)
+		}
+		orderby := func(b *benchmark.Benchmark) string { //nolint:revive
+			return "id ASC"
+		}
+		testSelectRawSQLQuery(b, testDesc, nil, "id", where, orderby, 1)
 	},
 }
 
-// TestUpdateHeavy updates random row in the 'heavy' table
-var TestUpdateHeavy = TestDesc{
-	name:        "update-heavy",
+// TestInsertJSONDocumentStore inserts a row with the application entity JSON document into the 'json document' table
+var TestInsertJSONDocumentStore = TestDesc{
+	name:        "insert-json-document-store",
 	metric:      "rows/sec",
-	description: "update random row in the 'heavy' table",
-	category:    TestUpdate,
+	description: "insert a row with the whole application entity as a single {--json-document-size-bytes} JSON document (nested objects and arrays) into the 'json document' table with GIN index",
+	category:    TestInsert,
 	isReadonly:  false,
 	isDBRTest:   false,
-	databases:   RELATIONAL,
-	table:       TestTableHeavy,
+	databases:   []db.DialectName{db.POSTGRES},
+	table:       TestTableJSONDocument,
 	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
-		testUpdateGeneric(b, testDesc, 1, nil)
+		testDesc.table.InitColumnsConf()
+		for i := range testDesc.table.ColumnsConf {
+			if testDesc.table.ColumnsConf[i].ColumnType == "json_document" {
+				testDesc.table.ColumnsConf[i].MaxSize = b.TestOpts.(*TestOpts).TestcaseOpts.JSONDocumentSizeBytes
+			}
+		}
+		testInsertGeneric(b, testDesc)
 	},
 }
 
-// TestUpdateHeavyDBR updates random row in the 'heavy' table using golang DBR query builder
-var TestUpdateHeavyDBR = TestDesc{
-	name:        "dbr-update-heavy",
+// TestSelectJSONDocumentByID selects the whole JSON document by the primary key from the 'json document' table
+var TestSelectJSONDocumentByID = TestDesc{
+	name:        "select-json-document-by-id",
 	metric:      "rows/sec",
-	description: "update random row in the 'heavy' table using golang DB driver",
-	category:    TestUpdate,
-	isReadonly:  false,
-	isDBRTest:   true,
-	databases:   RELATIONAL,
-	table:       TestTableHeavy,
-	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+	description: "select the whole JSON document from the 'json document' table WHERE id >= {} ORDER BY id LIMIT 1 (primary key lookup)",
+	category:    TestSelect,
+	isReadonly:  true,
+	isDBRTest:   false,
+	databases:   []db.DialectName{db.POSTGRES},
+	table:       TestTableJSONDocument,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		where := func(b *benchmark.Benchmark, workerId int) string {
+			return "id >= " + strconv.FormatUint(randomID(b, workerId, testDesc), 10)
+		}
+		orderby := func(b *benchmark.Benchmark) string { //nolint:revive
+			return "id ASC"
+		}
+		testSelectRawSQLQuery(b, testDesc, nil, "id, json_data", where, orderby, 1)
+	},
+}
+
+// TestSelectJSONDocumentByAttr selects the whole JSON document by the nested attribute from the 'json document' table using GIN index
+var TestSelectJSONDocumentByAttr = TestDesc{
+	name:        "select-json-document-by-attr",
+	metric:      "rows/sec",
+	description: "select the whole JSON document from the 'json document' table WHERE json_data @> '{\"owner\": {\"region\": {}}}' using GIN index",
+	category:    TestSelect,
+	isReadonly:  true,
+	isDBRTest:   false,
+	databases:   []db.DialectName{db.POSTGRES},
+	table:       TestTableJSONDocument,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		where := func(b *benchmark.Benchmark, workerId int) string {
+			var value = b.Randomizer.GetWorker(workerId).Intn(jsonDocumentValueCardinality)
+			return fmt.Sprintf("json_data @> '{\"owner\": {\"region\": \"region_%d\"}}'", value)
+		}
+		orderby := func(b *benchmark.Benchmark) string { //nolint:revive
+			return "id ASC"
+		}
+		testSelectRawSQLQuery(b, testDesc, nil, "id, json_data", where, orderby, 1)
+	},
+}
+
+// TestSearchJSONByNonIndexedValue searches a row from the 'json' table using some json condition using LIKE {}
+var TestSearchJSONByNonIndexedValue = TestDesc{
+	name:        "search-json-by-nonindexed-value",
+	metric:      "rows/sec",
+	description: "search a row from the 'json' table using some json condition using LIKE {}",
+	category:    TestSelect,
+	isReadonly:  true,
+	isDBRTest:   false,
+	databases:   RELATIONAL,
+	table:       TestTableJSON,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		where := func(b *benchmark.Benchmark, workerId int) string {
+			id := randomID(b, workerId, testDesc)
+
+			var dialectName, err = db.GetDialectName(b.TestOpts.(*TestOpts).DBOpts.ConnString)
+			if err != nil {
+				b.Exit(err)
+			}
+
+			switch dialectName {
+			case db.MYSQL:
+				return "JSON_EXTRACT(json_data, '$.field0.field1') LIKE '%eedl%' AND id > " + strconv.FormatUint(id, 10)
+			case db.POSTGRES:
+				return "json_data->'field0'->'field0'->>'field0' LIKE '%eedl%' AND id > " + strconv.FormatUint(id, 10) // searching for the 'needle' word
+			default:
+				b.Exit("The %s test is not supported on driver: %s", testDesc.name, dialectName)
+			}
+
+			return ""
+		}
+		orderby := func(b *benchmark.Benchmark) string { //nolint:revive
+			return "id ASC"
+		}
+		testSelectRawSQLQuery(b, testDesc, nil, "id", where, orderby, 1)
+	},
+}
+
+// TestUpdateMedium updates random row in the 'medium' table
+var TestUpdateMedium = TestDesc{
+	name:        "update-medium",
+	metric:      "rows/sec",
+	description: "update random row in the 'medium' table",
+	category:    TestUpdate,
+	isReadonly:  false,
+	isDBRTest:   false,
+	databases:   RELATIONAL,
+	table:       TestTableMedium,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		testUpdateGeneric(b, testDesc, 1, nil)
+	},
+}
+
+// TestUpdateMediumDBR updates random row in the 'medium' table using golang DBR query builder
+var TestUpdateMediumDBR = TestDesc{
+	name:        "dbr-update-medium",
+	metric:      "rows/sec",
+	description: "update random row in the 'medium' table using golang DB driver",
+	category:    TestUpdate,
+	isReadonly:  false,
+	isDBRTest:   true,
+	databases:   RELATIONAL,
+	table:       TestTableMedium,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		testUpdateGeneric(b, testDesc, 1, nil)
+	},
+}
+
+// TestUpdateHeavy updates random row in the 'heavy' table
+var TestUpdateHeavy = TestDesc{
+	name:        "update-heavy",
+	metric:      "rows/sec",
+	description: "update random row in the 'heavy' table",
+	category:    TestUpdate,
+	isReadonly:  false,
+	isDBRTest:   false,
+	databases:   RELATIONAL,
+	table:       TestTableHeavy,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		testUpdateGeneric(b, testDesc, 1, nil)
+	},
+}
+
+// TestUpdateHeavyDBR updates random row in the 'heavy' table using golang DBR query builder
+var TestUpdateHeavyDBR = TestDesc{
+	name:        "dbr-update-heavy",
+	metric:      "rows/sec",
+	description: "update random row in the 'heavy' table using golang DB driver",
+	category:    TestUpdate,
+	isReadonly:  false,
+	isDBRTest:   true,
+	databases:   RELATIONAL,
+	table:       TestTableHeavy,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
 		testUpdateGeneric(b, testDesc, 1, nil)
 	},
 }
@@ -1631,6 +2604,53 @@ var TestUpdateHeavyBulkDBR = TestDesc{
 	},
 }
 
+// TestUpdateHeavyReturning updates random row in the 'heavy' table and reads the new completion_time_ns in the same statement
+var TestUpdateHeavyReturning = TestDesc{
+	name:        "update-heavy-returning",
+	metric:      "rows/sec",
+	description: "update progress of random row in the 'heavy' table and read the new completion_time_ns by UPDATE ... RETURNING",
+	category:    TestUpdate,
+	isReadonly:  false,
+	isDBRTest:   false,
+	databases:   []db.DialectName{db.POSTGRES},
+	table:       TestTableHeavy,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		worker := func(b *benchmark.Benchmark, c *DBConnector, testDesc *TestDesc, batch int) (loops int) {
+			var session = c.database.Session(c.database.Context(context.Background()))
+			var rw = b.Randomizer.GetWorker(c.WorkerID)
+
+			for i := 0; i < batch; i++ {
+				var id = int64(rw.Uintn64(testDesc.table.RowsCount)) + 1
+				var rows, err = session.UpdateReturning(testDesc.table.TableName, &db.UpdateCtrl{
+					Set: map[string]interface{}{
+						"progress":           int64(rw.Intn(100)),
+						"completion_time_ns": time.Now().UnixNano(),
+					},
+					Where:     map[string][]string{"id": {strconv.FormatInt(id, 10)}},
+					Returning: []string{"completion_time_ns"},
+				})
+				if err != nil {
+					if isNonFatalError(b, c.WorkerID, err) {
+						continue
+					}
+					b.Exit("db: cannot update row with returning: %v", err)
+				}
+
+				var completionTimeNs int64
+				for rows.Next() {
+					if err = rows.Scan(&completionTimeNs); err != nil {
+						b.Exit("db: cannot scan returned completion_time_ns: %v", err)
+					}
+				}
+				rows.Close()
+			}
+
+			return batch
+		}
+		testGeneric(b, testDesc, worker, 1)
+	},
+}
+
 // TestUpdateHeavySameVal updates random row in the 'heavy' table putting the value which already exists
 var TestUpdateHeavySameVal = TestDesc{
 	name:        "update-heavy-sameval",
@@ -1663,6 +2683,105 @@ var TestUpdateHeavyPartialSameVal = TestDesc{
 	},
 }
 
+// TestUpsertLight inserts or updates a row in the 'light' table
+var TestUpsertLight = TestDesc{
+	name:        "upsert-light",
+	metric:      "rows/sec",
+	description: "insert a row into the 'light' table or update it if the id already exists (ON CONFLICT DO UPDATE / ON DUPLICATE KEY UPDATE / MERGE), see --conflict-rate",
+	category:    TestInsert,
+	isReadonly:  false,
+	isDBRTest:   false,
+	databases:   RELATIONAL,
+	table:       TestTableLight,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		testUpsertGeneric(b, testDesc)
+	},
+}
+
+// TestUpsertHeavy inserts or updates a row in the 'heavy' table
+var TestUpsertHeavy = TestDesc{
+	name:        "upsert-heavy",
+	metric:      "rows/sec",
+	description: "insert a row into the 'heavy' table or update it if the id already exists (ON CONFLICT DO UPDATE / ON DUPLICATE KEY UPDATE / MERGE), see --conflict-rate",
+	category:    TestInsert,
+	isReadonly:  false,
+	isDBRTest:   false,
+	databases:   RELATIONAL,
+	table:       TestTableHeavy,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		testUpsertGeneric(b, testDesc)
+	},
+}
+
+// TestDeleteLight deletes random row from the 'light' table
+var TestDeleteLight = TestDesc{
+	name:        "delete-light",
+	metric:      "rows/sec",
+	description: "delete random row from the 'light' table",
+	category:    TestDelete,
+	isReadonly:  false,
+	isDBRTest:   false,
+	databases:   RELATIONAL,
+	table:       TestTableLight,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		testDeleteGeneric(b, testDesc, 1)
+	},
+}
+
+// TestDeleteMedium deletes random row from the 'medium' table
+var TestDeleteMedium = TestDesc{
+	name:        "delete-medium",
+	metric:      "rows/sec",
+	description: "delete random row from the 'medium' table",
+	category:    TestDelete,
+	isReadonly:  false,
+	isDBRTest:   false,
+	databases:   RELATIONAL,
+	table:       TestTableMedium,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		testDeleteGeneric(b, testDesc, 1)
+	},
+}
+
+// TestDeleteHeavy deletes random row from the 'heavy' table
+var TestDeleteHeavy = TestDesc{
+	name:        "delete-heavy",
+	metric:      "rows/sec",
+	description: "delete random row from the 'heavy' table",
+	category:    TestDelete,
+	isReadonly:  false,
+	isDBRTest:   false,
+	databases:   RELATIONAL,
+	table:       TestTableHeavy,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		testDeleteGeneric(b, testDesc, 1)
+	},
+}
+
+// TestDeleteHeavyBulk deletes N rows (see --batch=, default 1000) from the 'heavy' table by single transaction
+var TestDeleteHeavyBulk = TestDesc{
+	name:        "bulkdelete-heavy",
+	metric:      "rows/sec",
+	description: "delete N rows (see --batch=, default 1000) from the 'heavy' table by single transaction",
+	category:    TestDelete,
+	isReadonly:  false,
+	isDBRTest:   false,
+	databases:   RELATIONAL,
+	table:       TestTableHeavy,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		origBatch := b.Vault.(*DBTestData).EffectiveBatch
+		testBatch := origBatch
+		if b.TestOpts.(*TestOpts).BenchOpts.Batch == 0 {
+			testBatch = 1000 // smaller than the 'bulkupdate-heavy' default not to empty the table too fast
+		}
+		b.Vault.(*DBTestData).EffectiveBatch = 1
+
+		testDeleteGeneric(b, testDesc, uint64(testBatch))
+
+		b.Vault.(*DBTestData).EffectiveBatch = origBatch
+	},
+}
+
 /*
  * Tenant-specific tests
  */
@@ -1758,38 +2877,152 @@ var TestSelectTimeSeriesSQL = TestDesc{
 	},
 }
 
-/*
- * Advanced monitoring simulation tests
- */
-
-// TestInsertAdvmTasks inserts into the 'adv monitoring tasks' table
-var TestInsertAdvmTasks = TestDesc{
-	name:        "insert-advmtasks",
-	metric:      "rows/sec",
-	description: "insert into the 'adv monitoring tasks' table",
-	category:    TestInsert,
-	isReadonly:  false,
+// TestSelectTimeSeriesSQLAggregated selects the hourly averages of the random tenant from the 'timeseries' SQL table
+var TestSelectTimeSeriesSQLAggregated = TestDesc{
+	name:        "select-ts-sql-aggregated",
+	metric:      "queries/sec",
+	description: "select the hourly AVG(value) of the random tenant for the last {--ts-aggregation-window} hours from the 'timeseries' SQL table GROUP BY hour (compare with 'select-ts-agg-ch')",
+	category:    TestSelect,
+	isReadonly:  true,
 	isDBRTest:   false,
-	databases:   []db.DialectName{db.POSTGRES},
-	table:       TestTableAdvmTasks,
+	databases:   []db.DialectName{db.POSTGRES, db.MYSQL},
+	table:       TestTableTimeSeriesSQL,
 	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
-		testInsertGeneric(b, testDesc)
+		var window = b.TestOpts.(*TestOpts).TestcaseOpts.TSAggregationWindow
+		if window <= 0 {
+			b.Exit("--ts-aggregation-window must be > 0")
+		}
+
+		var dialectName, err = db.GetDialectName(b.TestOpts.(*TestOpts).DBOpts.ConnString)
+		if err != nil {
+			b.Exit(err)
+		}
+
+		var format string
+		switch dialectName {
+		case db.MYSQL:
+			format = "SELECT DATE_FORMAT(ts, '%%Y-%%m-%%d %%H:00:00') AS hour, AVG(value) FROM %s WHERE tenant_id = '%%s' AND ts > NOW() - INTERVAL %d HOUR GROUP BY 1 ORDER BY 1"
+		default:
+			format = "SELECT date_trunc('hour', ts) AS hour, AVG(value) FROM %s WHERE tenant_id = '%%s' AND ts > NOW() - INTERVAL '%d hours' GROUP BY 1 ORDER BY 1"
+		}
+		format = fmt.Sprintf(format, testDesc.table.TableName, window)
+
+		var colConfs = testDesc.table.GetColumnsConf([]string{"tenant_id"}, false)
+
+		worker := func(b *benchmark.Benchmark, c *DBConnector, testDesc *TestDesc, batch int) (loops int) { //nolint:revive
+			var session = c.database.Session(c.database.Context(context.Background()))
+			var w = b.GenFakeDataAsMap(c.WorkerID, colConfs, false)
+			var rows, err = session.Query(fmt.Sprintf(format, (*w)["tenant_id"]))
+			if err != nil {
+				if isNonFatalError(b, c.WorkerID, err) {
+					return 1
+				}
+				b.Exit("db: cannot select hourly aggregates: %v", err)
+			}
+
+			for rows.Next() {
+			}
+			rows.Close()
+
+			return 1
+		}
+		testGeneric(b, testDesc, worker, 1)
 	},
 }
 
-// TestSelectAdvmTasksLast selects last inserted row from the 'adv monitoring tasks' table
-var TestSelectAdvmTasksLast = TestDesc{
-	name:        "select-advmtasks-last",
+// TestInsertTimeSeriesAggregating inserts into the 'timeseries aggregating' ClickHouse table feeding the AggregatingMergeTree table
+var TestInsertTimeSeriesAggregating = TestDesc{
+	name:        "insert-ts-agg-ch",
 	metric:      "values/sec",
-	description: "get number of rows grouped by week+result_code",
-	category:    TestSelect,
+	description: "batch insert into the 'timeseries aggregating' table pre-aggregated per hour by the materialized view into the AggregatingMergeTree table (compare with 'insert-ts-sql')",
+	category:    TestInsert,
 	isReadonly:  false,
 	isDBRTest:   false,
-	databases:   []db.DialectName{db.POSTGRES},
-	table:       TestTableAdvmTasks,
+	databases:   []db.DialectName{db.CLICKHOUSE},
+	table:       TestTableTimeSeriesAggregating,
 	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
-		var where = func(b *benchmark.Benchmark, workerId int) map[string][]string { //nolint:revive
-			return map[string][]string{
+
+		origBatch := b.Vault.(*DBTestData).EffectiveBatch
+		if b.TestOpts.(*TestOpts).BenchOpts.Batch == 0 {
+			b.Vault.(*DBTestData).EffectiveBatch = 256
+		}
+
+		testInsertGeneric(b, testDesc)
+
+		b.Vault.(*DBTestData).EffectiveBatch = origBatch
+	},
+}
+
+// TestSelectTimeSeriesAggregating selects the hourly aggregates from the ClickHouse AggregatingMergeTree table
+var TestSelectTimeSeriesAggregating = TestDesc{
+	name:        "select-ts-agg-ch",
+	metric:      "values/sec",
+	description: "batch select of the hourly avg/max/count aggregates from the AggregatingMergeTree table fed by the 'timeseries aggregating' table (compare with 'select-ts-sql')",
+	category:    TestSelect,
+	isReadonly:  true,
+	isDBRTest:   false,
+	databases:   []db.DialectName{db.CLICKHOUSE},
+	table:       TestTableTimeSeriesAggregating,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+
+		origBatch := b.Vault.(*DBTestData).EffectiveBatch
+		if b.TestOpts.(*TestOpts).BenchOpts.Batch == 0 {
+			b.Vault.(*DBTestData).EffectiveBatch = 256
+		}
+
+		colConfs := testDesc.table.GetColumnsConf([]string{"tenant_id", "device_id", "metric_id"}, false)
+
+		from := func(b *benchmark.Benchmark, workerId int) string { //nolint:revive
+			return TimeSeriesAggregatingStateTableName
+		}
+		// the partial aggregation states are merged by GROUP BY which goes right after the WHERE clause
+		where := func(b *benchmark.Benchmark, workerId int) string {
+			w := b.GenFakeDataAsMap(workerId, colConfs, false)
+
+			return fmt.Sprintf("tenant_id = '%s' AND device_id = '%s' AND metric_id = '%s' GROUP BY ts_hour", (*w)["tenant_id"], (*w)["device_id"], (*w)["metric_id"])
+		}
+		orderby := func(b *benchmark.Benchmark) string { //nolint:revive
+			return "ts_hour DESC"
+		}
+
+		testSelectRawSQLQuery(b, testDesc, from, "ts_hour, avgMerge(avg_value), maxMerge(max_value), countMerge(cnt)", where, orderby, 1)
+
+		b.Vault.(*DBTestData).EffectiveBatch = origBatch
+	},
+}
+
+/*
+ * Advanced monitoring simulation tests
+ */
+
+// TestInsertAdvmTasks inserts into the 'adv monitoring tasks' table
+var TestInsertAdvmTasks = TestDesc{
+	name:        "insert-advmtasks",
+	metric:      "rows/sec",
+	description: "insert into the 'adv monitoring tasks' table",
+	category:    TestInsert,
+	isReadonly:  false,
+	isDBRTest:   false,
+	databases:   []db.DialectName{db.POSTGRES},
+	table:       TestTableAdvmTasks,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		testInsertGeneric(b, testDesc)
+	},
+}
+
+// TestSelectAdvmTasksLast selects last inserted row from the 'adv monitoring tasks' table
+var TestSelectAdvmTasksLast = TestDesc{
+	name:        "select-advmtasks-last",
+	metric:      "values/sec",
+	description: "get number of rows grouped by week+result_code",
+	category:    TestSelect,
+	isReadonly:  false,
+	isDBRTest:   false,
+	databases:   []db.DialectName{db.POSTGRES},
+	table:       TestTableAdvmTasks,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		var where = func(b *benchmark.Benchmark, workerId int) map[string][]string { //nolint:revive
+			return map[string][]string{
 				"origin": {"1", "2", "3"},
 			}
 		}
@@ -1958,7 +3191,8 @@ var TestInsertAdvmDevices = TestDesc{
 
 // CreateTenantWorker creates a tenant and optionally inserts an event into the event bus
 func CreateTenantWorker(b *benchmark.Benchmark, c *DBConnector, testDesc *TestDesc, batch int) (loops int) { //nolint:revive
-	var session = c.database.Session(c.database.Context(context.Background()))
+	var dbCtx = c.database.Context(context.Background())
+	var session = c.database.Session(dbCtx)
 	if txErr := session.Transact(func(tx db.DatabaseAccessor) error {
 		for i := 0; i < batch; i++ {
 			var tenantUUID, err = b.Vault.(*DBTestData).TenantsCache.CreateTenant(b.Randomizer.GetWorker(c.WorkerID), tx)
@@ -1977,12 +3211,14 @@ func CreateTenantWorker(b *benchmark.Benchmark, c *DBConnector, testDesc *TestDe
 	}); txErr != nil {
 		c.Exit(txErr.Error())
 	}
+	b.AddRetries(dbCtx.TxRetries)
 
 	return batch
 }
 
 func CreateCTIEntityWorker(b *benchmark.Benchmark, c *DBConnector, testDesc *TestDesc, batch int) (loops int) { //nolint:revive
-	var session = c.database.Session(c.database.Context(context.Background()))
+	var dbCtx = c.database.Context(context.Background())
+	var session = c.database.Session(dbCtx)
 	if txErr := session.Transact(func(tx db.DatabaseAccessor) error {
 		for i := 0; i < batch; i++ {
 			if err := b.Vault.(*DBTestData).TenantsCache.CreateCTIEntity(b.Randomizer.GetWorker(c.WorkerID), tx); err != nil {
@@ -1994,6 +3230,7 @@ func CreateCTIEntityWorker(b *benchmark.Benchmark, c *DBConnector, testDesc *Tes
 	}); txErr != nil {
 		c.Exit(txErr.Error())
 	}
+	b.AddRetries(dbCtx.TxRetries)
 
 	return batch
 }
@@ -2029,151 +3266,1215 @@ func tenantAwareCTIAwareWorker(b *benchmark.Benchmark, c *DBConnector, testDesc
 			tenants.TableNameCtiEntities, tableName, tenants.TableNameCtiProvisioning, ctiUUID.String())
 	}
 
-	return tenantAwareGenericWorker(b, c, ctiAwareQuery, orderBy)
+	return tenantAwareGenericWorker(b, c, ctiAwareQuery, orderBy)
+}
+
+func tenantAwareWorker(b *benchmark.Benchmark, c *DBConnector, testDesc *TestDesc, orderBy string, batch int) (loops int) { //nolint:revive
+	query := buildTenantAwareQuery(c.database.DialectName(), testDesc.table.TableName)
+
+	return tenantAwareGenericWorker(b, c, query, orderBy)
+}
+
+func buildTenantAwareQuery(dialectName db.DialectName, tableName string) string {
+	var tenantAwareQuery string
+
+	// TODO: Unify the query building for all dialects
+	switch dialectName {
+	case db.POSTGRES:
+		tenantAwareQuery = fmt.Sprintf("SELECT `%[1]s`.`id` id, `%[1]s`.`tenant_id` FROM `%[1]s` "+
+			"JOIN `%[2]s` AS `tenants_child` ON ((`tenants_child`.`uuid`::uuid = `%[1]s`.`tenant_id`) AND (`tenants_child`.`is_deleted` != {true})) "+
+			"JOIN `%[3]s` AS `tenants_closure` ON ((`tenants_closure`.`child_id` = `tenants_child`.`id`) AND (`tenants_closure`.`barrier` <= 0)) "+
+			"JOIN `%[2]s` AS `tenants_parent` ON ((`tenants_parent`.`id` = `tenants_closure`.`parent_id`) "+
+			"AND (`tenants_parent`.`uuid` IN ('{tenant_uuid}')) AND (`tenants_parent`.`is_deleted` != {true}))",
+			tableName, tenants.TableNameTenants, tenants.TableNameTenantClosure)
+	default:
+		tenantAwareQuery = fmt.Sprintf("SELECT `%[1]s`.`id` id, `%[1]s`.`tenant_id` FROM `%[1]s` "+
+			"JOIN `%[2]s` AS `tenants_child` ON ((`tenants_child`.`uuid` = `%[1]s`.`tenant_id`) AND (`tenants_child`.`is_deleted` != {true})) "+
+			"JOIN `%[3]s` AS `tenants_closure` ON ((`tenants_closure`.`child_id` = `tenants_child`.`id`) AND (`tenants_closure`.`barrier` <= 0)) "+
+			"JOIN `%[2]s` AS `tenants_parent` ON ((`tenants_parent`.`id` = `tenants_closure`.`parent_id`) "+
+			"AND (`tenants_parent`.`uuid` IN ('{tenant_uuid}')) AND (`tenants_parent`.`is_deleted` != {true}))",
+			tableName, tenants.TableNameTenants, tenants.TableNameTenantClosure)
+	}
+
+	return tenantAwareQuery
+}
+
+func tenantAwareGenericWorker(b *benchmark.Benchmark, c *DBConnector, query string, orderBy string) (loops int) {
+	c.Log(benchmark.LogTrace, "tenant-aware SELECT test iteration")
+
+	var tenantsCache, rw = b.Vault.(*DBTestData).TenantsCache, b.Randomizer.GetWorker(c.WorkerID)
+
+	var getTenantUUID = tenantsCache.GetRandomTenantUUID
+	if tenantsCache.HotspotEnabled() {
+		getTenantUUID = tenantsCache.GetHotRandomTenantUUID
+	}
+
+	uuid, err := getTenantUUID(rw, 0, "")
+	if err != nil {
+		b.Exit(err)
+	}
+
+	var valTrue string
+
+	if c.database.DialectName() == db.POSTGRES {
+		valTrue = "true"
+	} else {
+		valTrue = "1"
+	}
+	query = strings.ReplaceAll(query, "{true}", valTrue)
+	query = strings.ReplaceAll(query, "{tenant_uuid}", uuid.String())
+	if orderBy != "" {
+		query += " " + orderBy
+	}
+	query += " LIMIT 1"
+
+	var id, tenantID string
+
+	if c.database.DialectName() == db.POSTGRES {
+		query = strings.ReplaceAll(query, "`", "\"")
+	}
+
+	c.Log(benchmark.LogTrace, "executing query: %s", query)
+
+	var session = c.database.Session(c.database.Context(context.Background()))
+	if err = session.QueryRow(query).Scan(&id, &tenantID); err != nil {
+		if !errors.Is(sql.ErrNoRows, err) {
+			c.Exit(err.Error())
+		}
+	}
+
+	return 1
+}
+
+// TestSelectMediumLastTenant is the same as TestSelectMediumLast but with tenant-awareness
+var TestSelectMediumLastTenant = TestDesc{
+	name:        "select-medium-last-in-tenant",
+	metric:      "rows/sec",
+	description: "select the last row from the 'medium' table WHERE tenant_id = {random tenant uuid}",
+	category:    TestSelect,
+	isReadonly:  true,
+	isDBRTest:   false,
+	databases:   ALL,
+	table:       TestTableMedium,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		worker := func(b *benchmark.Benchmark, c *DBConnector, testDesc *TestDesc, batch int) (loops int) { //nolint:revive
+			return tenantAwareWorker(b, c, testDesc, "ORDER BY enqueue_time DESC", 1)
+		}
+		testGeneric(b, testDesc, worker, 1)
+	},
+}
+
+// TestSelectBlobLastTenant is the same as TestSelectBlobLast but with tenant-awareness
+var TestSelectBlobLastTenant = TestDesc{
+	name:        "select-blob-last-in-tenant",
+	metric:      "rows/sec",
+	description: "select the last row from the 'blob' table WHERE tenant_id = {random tenant uuid}",
+	category:    TestSelect,
+	isReadonly:  true,
+	isDBRTest:   false,
+	databases:   ALL,
+	table:       TestTableBlob,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		worker := func(b *benchmark.Benchmark, c *DBConnector, testDesc *TestDesc, batch int) (loops int) { //nolint:revive
+			return tenantAwareWorker(b, c, testDesc, "ORDER BY timestamp DESC", 1)
+		}
+		var stop = startPeakMemorySampler()
+		testGeneric(b, testDesc, worker, 1)
+		stop()
+	},
+}
+
+// readLargeObjectWorker reads a random large object from the 'largeobject' table by --blob-chunk-size chunks
+func readLargeObjectWorker(b *benchmark.Benchmark, c *DBConnector, testDesc *TestDesc, batch int) (loops int) {
+	var chunkSize = b.TestOpts.(*TestOpts).TestcaseOpts.BlobChunkSize
+
+	var dbCtx = c.database.Context(context.Background())
+	var session = c.database.Session(dbCtx)
+
+	for i := 0; i < batch; i++ {
+		var id = b.Randomizer.GetWorker(c.WorkerID).Uintn64(testDesc.table.RowsCount-1) + 1
+
+		var bytes int64
+
+		// large object descriptors are valid within the transaction only
+		if txErr := session.Transact(func(tx db.DatabaseAccessor) error {
+			bytes = 0
+			var oid int
+			if err := tx.QueryRow(fmt.Sprintf("SELECT oid FROM %s WHERE id >= %d ORDER BY id LIMIT 1", testDesc.table.TableName, id)).Scan(&oid); err != nil {
+				if errors.Is(err, sql.ErrNoRows) {
+					return nil
+				}
+				return err
+			}
+
+			var fd int
+			if err := tx.QueryRow(fmt.Sprintf("SELECT lo_open(%d, 262144)", oid)).Scan(&fd); err != nil { // 262144 == 0x40000 - read mode
+				return err
+			}
+
+			var chunk []byte
+			for {
+				if err := tx.QueryRow("SELECT loread($1, $2)", fd, chunkSize).Scan(&chunk); err != nil {
+					return err
+				}
+				bytes += int64(len(chunk))
+				if len(chunk) < chunkSize {
+					break
+				}
+			}
+
+			_, err := tx.Exec("SELECT lo_close($1)", fd)
+			return err
+		}); txErr != nil {
+			if isNonFatalError(b, c.WorkerID, txErr) {
+				continue
+			}
+			c.Exit(txErr.Error())
+		}
+		b.AddBytes(bytes)
+	}
+	b.AddRetries(dbCtx.TxRetries)
+
+	return batch
+}
+
+// TestSelectBlobStreaming reads a random large object from the 'largeobject' table by chunks
+var TestSelectBlobStreaming = TestDesc{
+	name:        "select-blob-streaming",
+	metric:      "rows/sec",
+	description: "read a random large object from the 'largeobject' table by lo_open/loread chunks (see --blob-chunk-size) instead of fetching the whole blob (compare with 'select-blob-last-in-tenant')",
+	category:    TestSelect,
+	isReadonly:  true,
+	isDBRTest:   false,
+	databases:   []db.DialectName{db.POSTGRES},
+	table:       TestTableLargeObj,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		if b.TestOpts.(*TestOpts).TestcaseOpts.BlobChunkSize <= 0 {
+			b.Exit("--blob-chunk-size must be > 0")
+		}
+
+		var stop = startPeakMemorySampler()
+		testGeneric(b, testDesc, readLargeObjectWorker, 1)
+		stop()
+	},
+}
+
+// TestSelectHeavyLastTenant is the same as TestSelectHeavyLast but with tenant-awareness
+var TestSelectHeavyLastTenant = TestDesc{
+	name:        "select-heavy-last-in-tenant",
+	metric:      "rows/sec",
+	description: "select the last row from the 'heavy' table WHERE tenant_id = {random tenant uuid}",
+	category:    TestSelect,
+	isReadonly:  true,
+	isDBRTest:   false,
+	databases:   RELATIONAL,
+	table:       TestTableHeavy,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		worker := func(b *benchmark.Benchmark, c *DBConnector, testDesc *TestDesc, batch int) (loops int) { //nolint:revive
+			return tenantAwareWorker(b, c, testDesc, "ORDER BY enqueue_time DESC", 1)
+		}
+		testGeneric(b, testDesc, worker, 1)
+	},
+}
+
+// TestExistsHeavyByTenant checks whether the random tenant has any row in the 'heavy' table using db.ExistsRow
+var TestExistsHeavyByTenant = TestDesc{
+	name:        "exists-heavy-by-tenant",
+	metric:      "queries/sec",
+	description: "check if any row exists in the 'heavy' table WHERE tenant_id = {random tenant uuid} by SELECT EXISTS (compare with 'select-heavy-last-in-tenant')",
+	category:    TestSelect,
+	isReadonly:  true,
+	isDBRTest:   false,
+	databases:   RELATIONAL,
+	table:       TestTableHeavy,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		worker := func(b *benchmark.Benchmark, c *DBConnector, testDesc *TestDesc, batch int) (loops int) { //nolint:revive
+			uuid, err := b.Vault.(*DBTestData).TenantsCache.GetRandomTenantUUID(b.Randomizer.GetWorker(c.WorkerID), 0, "")
+			if err != nil {
+				b.Exit(err)
+			}
+
+			var session = c.database.Session(c.database.Context(context.Background()))
+			if _, err = session.ExistsRow(testDesc.table.TableName, &db.SelectCtrl{
+				Where: map[string][]string{"tenant_id": {uuid.String()}},
+			}); err != nil {
+				if isNonFatalError(b, c.WorkerID, err) {
+					return 1
+				}
+				c.Exit(err.Error())
+			}
+
+			return 1
+		}
+		testGeneric(b, testDesc, worker, 1)
+	},
+}
+
+// TestSelectHeavyLastTenantCTI is the same as TestSelectHeavyLastTenant but with CTI-awareness
+var TestSelectHeavyLastTenantCTI = TestDesc{
+	name:        "select-heavy-last-in-tenant-and-cti",
+	metric:      "rows/sec",
+	description: "select the last row from the 'heavy' table WHERE tenant_id = {} AND cti = {}",
+	category:    TestSelect,
+	isReadonly:  true,
+	isDBRTest:   false,
+	databases:   RELATIONAL,
+	table:       TestTableHeavy,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		worker := func(b *benchmark.Benchmark, c *DBConnector, testDesc *TestDesc, batch int) (loops int) { //nolint:revive
+			return tenantAwareCTIAwareWorker(b, c, testDesc, "ORDER BY enqueue_time DESC", 1)
+		}
+		testGeneric(b, testDesc, worker, 1)
+	},
+}
+
+// TestSelectHeavyRecursiveCTE selects a row from the 'heavy' table belonging to the random tenant subtree traversed by WITH RECURSIVE
+var TestSelectHeavyRecursiveCTE = TestDesc{
+	name:        "select-heavy-recursive-cte",
+	metric:      "rows/sec",
+	description: "select a row from the 'heavy' table WHERE tenant_id IN (WITH RECURSIVE {random tenant subtree}), use --recursion-max-depth to limit the subtree depth",
+	category:    TestSelect,
+	isReadonly:  true,
+	isDBRTest:   false,
+	databases:   []db.DialectName{db.POSTGRES},
+	table:       TestTableHeavy,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		var maxDepth = b.TestOpts.(*TestOpts).TestcaseOpts.RecursionMaxDepth
+		if maxDepth < 1 {
+			b.Exit("--recursion-max-depth must be > 0")
+		}
+
+		// the root tenant may refer to itself as a parent, so self-references are skipped to avoid the endless recursion
+		var query = fmt.Sprintf("WITH RECURSIVE tenant_tree AS ("+
+			"SELECT id, uuid, 1 AS depth FROM %[2]s WHERE uuid = '{tenant_uuid}' AND is_deleted != true "+
+			"UNION ALL "+
+			"SELECT t.id, t.uuid, tt.depth + 1 FROM %[2]s t JOIN tenant_tree tt ON t.parent_id = tt.id "+
+			"WHERE t.id != tt.id AND t.is_deleted != true AND tt.depth < %[3]d"+
+			") SELECT h.id, h.tenant_id FROM %[1]s h JOIN tenant_tree tt ON h.tenant_id = tt.uuid::uuid LIMIT 1",
+			testDesc.table.TableName, tenants.TableNameTenants, maxDepth)
+
+		worker := func(b *benchmark.Benchmark, c *DBConnector, testDesc *TestDesc, batch int) (loops int) { //nolint:revive
+			uuid, err := b.Vault.(*DBTestData).TenantsCache.GetRandomTenantUUID(b.Randomizer.GetWorker(c.WorkerID), 0, "")
+			if err != nil {
+				b.Exit(err)
+			}
+
+			var q = strings.ReplaceAll(query, "{tenant_uuid}", uuid.String())
+			c.Log(benchmark.LogTrace, "executing query: %s", q)
+
+			var id, tenantID string
+
+			var session = c.database.Session(c.database.Context(context.Background()))
+			if err = session.QueryRow(q).Scan(&id, &tenantID); err != nil {
+				if isNonFatalError(b, c.WorkerID, err) {
+					return 1
+				}
+				if !errors.Is(err, sql.ErrNoRows) {
+					c.Exit(err.Error())
+				}
+			}
+
+			return 1
+		}
+		testGeneric(b, testDesc, worker, 1)
+	},
+}
+
+// TestSelectHeavyAntiJoin selects rows from the 'heavy' table whose tenant is deleted using NOT EXISTS anti-join
+var TestSelectHeavyAntiJoin = TestDesc{
+	name:   "select-heavy-anti-join",
+	metric: "rows/sec",
+	description: "select {batch} rows from the 'heavy' table WHERE NOT EXISTS (a deleted tenant with the same uuid), the anti-join usually wins over " +
+		"the LEFT JOIN ... IS NULL form (see 'select-heavy-anti-join-left') when the tenants table is large, as the planner can stop probing on the first match " +
+		"instead of building the joined rows and filtering them afterwards",
+	category:   TestSelect,
+	isReadonly: true,
+	isDBRTest:  false,
+	databases:  RELATIONAL,
+	table:      TestTableHeavy,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		var from = func(b *benchmark.Benchmark, workerId int) string { //nolint:revive
+			return testDesc.table.TableName + " h"
+		}
+		var where = func(b *benchmark.Benchmark, workerId int) string {
+			var c = b.WorkerData[workerId].(*DBWorkerData).workingConn
+			return fmt.Sprintf("NOT EXISTS (SELECT 1 FROM %s t WHERE %s AND t.is_deleted = %s)",
+				tenants.TableNameTenants, antiJoinTenantCondition(c.database.DialectName()), antiJoinTrue(c.database.DialectName()))
+		}
+		testSelectRawSQLQuery(b, testDesc, from, "h.id", where, nil, 1)
+	},
+}
+
+// TestSelectHeavyAntiJoinLeft is the same as TestSelectHeavyAntiJoin but uses LEFT JOIN ... WHERE t.uuid IS NULL
+var TestSelectHeavyAntiJoinLeft = TestDesc{
+	name:        "select-heavy-anti-join-left",
+	metric:      "rows/sec",
+	description: "select {batch} rows from the 'heavy' table LEFT JOIN deleted tenants WHERE t.uuid IS NULL (compare with 'select-heavy-anti-join')",
+	category:    TestSelect,
+	isReadonly:  true,
+	isDBRTest:   false,
+	databases:   RELATIONAL,
+	table:       TestTableHeavy,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		var from = func(b *benchmark.Benchmark, workerId int) string {
+			var c = b.WorkerData[workerId].(*DBWorkerData).workingConn
+			return fmt.Sprintf("%s h LEFT JOIN %s t ON %s AND t.is_deleted = %s",
+				testDesc.table.TableName, tenants.TableNameTenants, antiJoinTenantCondition(c.database.DialectName()), antiJoinTrue(c.database.DialectName()))
+		}
+		var where = func(b *benchmark.Benchmark, workerId int) string { //nolint:revive
+			return "t.uuid IS NULL"
+		}
+		testSelectRawSQLQuery(b, testDesc, from, "h.id", where, nil, 1)
+	},
+}
+
+// TestSelectHeavyExists selects rows from the 'heavy' table having a live tenant checked by EXISTS
+var TestSelectHeavyExists = TestDesc{
+	name:   "select-heavy-exists",
+	metric: "rows/sec",
+	description: "select {batch} rows from the 'heavy' table WHERE EXISTS (a live tenant with the same uuid), EXISTS stops probing on the first match " +
+		"(compare with 'select-heavy-count-subq', see --explain-comparison)",
+	category:   TestSelect,
+	isReadonly: true,
+	isDBRTest:  false,
+	databases:  RELATIONAL,
+	table:      TestTableHeavy,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		testSelectHeavyTenantSubquery(b, testDesc, false)
+	},
+}
+
+// TestSelectHeavyCountSubquery is the same as TestSelectHeavyExists but uses (SELECT COUNT(*) ...) > 0
+var TestSelectHeavyCountSubquery = TestDesc{
+	name:   "select-heavy-count-subq",
+	metric: "rows/sec",
+	description: "select {batch} rows from the 'heavy' table WHERE (SELECT COUNT(*) of the live tenants with the same uuid) > 0, COUNT visits all the matches " +
+		"(compare with 'select-heavy-exists', see --explain-comparison)",
+	category:   TestSelect,
+	isReadonly: true,
+	isDBRTest:  false,
+	databases:  RELATIONAL,
+	table:      TestTableHeavy,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		testSelectHeavyTenantSubquery(b, testDesc, true)
+	},
+}
+
+// heavyTenantSubqueryCondition returns the condition checking the 'heavy' table row has a live tenant by EXISTS or by COUNT(*) > 0
+func heavyTenantSubqueryCondition(dialectName db.DialectName, count bool) string {
+	var subquery = fmt.Sprintf("FROM %s t WHERE %s AND t.is_deleted = %s",
+		tenants.TableNameTenants, antiJoinTenantCondition(dialectName), antiJoinFalse(dialectName))
+	if count {
+		return fmt.Sprintf("(SELECT COUNT(*) %s) > 0", subquery)
+	}
+
+	return fmt.Sprintf("EXISTS (SELECT 1 %s)", subquery)
+}
+
+// testSelectHeavyTenantSubquery runs the 'select-heavy-exists' or 'select-heavy-count-subq' test, the plans of both are printed first if --explain-comparison is set
+func testSelectHeavyTenantSubquery(b *benchmark.Benchmark, testDesc *TestDesc, count bool) {
+	if b.TestOpts.(*TestOpts).TestcaseOpts.ExplainComparison {
+		var c = dbConnector(b)
+		for _, countForm := range []bool{false, true} {
+			var query = fmt.Sprintf("SELECT h.id FROM %s h WHERE %s", testDesc.table.TableName, heavyTenantSubqueryCondition(c.database.DialectName(), countForm))
+			var plan, err = explainQueryPlan(c, query)
+			if err != nil {
+				b.Exit("db: cannot explain the query: %v", err)
+			}
+			fmt.Printf("\nQUERY PLAN: %s\n\n%s\n\n", query, strings.Join(plan, "\n"))
+		}
+		c.Release()
+	}
+
+	var from = func(b *benchmark.Benchmark, workerId int) string { //nolint:revive
+		return testDesc.table.TableName + " h"
+	}
+	var where = func(b *benchmark.Benchmark, workerId int) string {
+		var c = b.WorkerData[workerId].(*DBWorkerData).workingConn
+		return heavyTenantSubqueryCondition(c.database.DialectName(), count)
+	}
+	testSelectRawSQLQuery(b, testDesc, from, "h.id", where, nil, 1)
+}
+
+// antiJoinTenantCondition returns the condition matching the tenants table row to the 'heavy' table row
+func antiJoinTenantCondition(dialectName db.DialectName) string {
+	if dialectName == db.POSTGRES {
+		return "t.uuid::uuid = h.tenant_id"
+	}
+
+	return "t.uuid = h.tenant_id"
+}
+
+// antiJoinTrue returns boolean true literal for the dialect
+func antiJoinTrue(dialectName db.DialectName) string {
+	if dialectName == db.POSTGRES {
+		return "true"
+	}
+
+	return "1"
+}
+
+// antiJoinFalse returns boolean false literal for the dialect
+func antiJoinFalse(dialectName db.DialectName) string {
+	if dialectName == db.POSTGRES {
+		return "false"
+	}
+
+	return "0"
+}
+
+// joinRowsLimit is the number of the joined rows selected by the 'join-medium-heavy' and 'join-three-way' tests
+const joinRowsLimit = 10
+
+// TestJoinMediumHeavy joins the 'medium' and 'heavy' tables rows of the random tenant
+var TestJoinMediumHeavy = TestDesc{
+	name:        "join-medium-heavy",
+	metric:      "rows/sec",
+	description: "select first 10 rows of the 'heavy' table JOIN the 'medium' table ON tenant_id WHERE tenant_id = {} ORDER BY enqueue_time_ns DESC (compare with 'join-three-way')",
+	category:    TestSelect,
+	isReadonly:  true,
+	isDBRTest:   false,
+	databases:   RELATIONAL,
+	table:       TestTableHeavy,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		testJoinHeavy(b, testDesc, false)
+	},
+}
+
+// TestJoinThreeWay joins the 'medium', 'heavy' and tenants tables rows of the random tenant
+var TestJoinThreeWay = TestDesc{
+	name:        "join-three-way",
+	metric:      "rows/sec",
+	description: "select first 10 rows of the 'heavy' table JOIN the 'medium' table ON tenant_id JOIN the tenants table WHERE tenant_id = {} ORDER BY enqueue_time_ns DESC (compare with 'join-medium-heavy')",
+	category:    TestSelect,
+	isReadonly:  true,
+	isDBRTest:   false,
+	databases:   RELATIONAL,
+	table:       TestTableHeavy,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		testJoinHeavy(b, testDesc, true)
+	},
+}
+
+// testJoinHeavy selects the 'heavy' table rows of the random tenant joined with the 'medium' table rows of the same tenant
+// and optionally with the tenant itself, the database is free to choose the hash, merge or nested loop join
+func testJoinHeavy(b *benchmark.Benchmark, testDesc *TestDesc, withTenants bool) {
+	var colConfs = &[]benchmark.DBFakeColumnConf{{ColumnName: "tenant_id", ColumnType: "tenant_uuid"}}
+
+	var c = dbConnector(b)
+	TestTableMedium.Create(c, b)
+	c.Release()
+
+	var dialectName = getDBDriver(b)
+
+	var what, from = "h.id, m.id", fmt.Sprintf("%s h JOIN %s m ON m.tenant_id = h.tenant_id", testDesc.table.TableName, TestTableMedium.TableName)
+	if withTenants {
+		what += ", t.id"
+		from += fmt.Sprintf(" JOIN %s t ON %s", tenants.TableNameTenants, antiJoinTenantCondition(dialectName))
+	}
+
+	var query string
+	if dialectName == db.MSSQL {
+		query = fmt.Sprintf("SELECT TOP %d %s FROM %s WHERE h.tenant_id = $1 ORDER BY h.enqueue_time_ns DESC", joinRowsLimit, what, from)
+	} else {
+		query = fmt.Sprintf("SELECT %s FROM %s WHERE h.tenant_id = $1 ORDER BY h.enqueue_time_ns DESC LIMIT %d", what, from, joinRowsLimit)
+	}
+	query = formatSQL(query, dialectName)
+
+	worker := func(b *benchmark.Benchmark, c *DBConnector, testDesc *TestDesc, batch int) (loops int) { //nolint:revive
+		var w = b.GenFakeDataAsMap(c.WorkerID, colConfs, false)
+
+		var session = c.database.Session(c.database.Context(context.Background()))
+		if err := inReadOnlyTxn(b, testDesc, session, func(q db.DatabaseAccessor) error {
+			var rows, err = q.Query(query, fmt.Sprintf("%s", (*w)["tenant_id"]))
+			if err != nil {
+				return err
+			}
+
+			for rows.Next() {
+			}
+
+			return rows.Close()
+		}); err != nil {
+			if isNonFatalError(b, c.WorkerID, err) {
+				return joinRowsLimit
+			}
+			b.Exit("db: cannot join rows: %v", err)
+		}
+
+		return joinRowsLimit
+	}
+	testGeneric(b, testDesc, worker, 1)
+}
+
+// TestSelectHeavyMV selects the random tenant aggregates from the 'heavy' table materialized view
+var TestSelectHeavyMV = TestDesc{
+	name:        "select-heavy-materialized-view",
+	metric:      "rows/sec",
+	description: "select the random tenant row from the 'heavy' table materialized view (the view is created if missing)",
+	category:    TestSelect,
+	isReadonly:  true,
+	isDBRTest:   false,
+	databases:   []db.DialectName{db.POSTGRES},
+	table:       TestTableHeavy,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		var c = dbConnector(b)
+		if err := createHeavyMaterializedView(c.database.Session(c.database.Context(context.Background()))); err != nil {
+			b.Exit("db: cannot create materialized view '%s': %v", HeavyMaterializedViewName, err)
+		}
+		c.Release()
+
+		var colConfs = &[]benchmark.DBFakeColumnConf{{ColumnName: "tenant_id", ColumnType: "tenant_uuid"}}
+
+		worker := func(b *benchmark.Benchmark, c *DBConnector, testDesc *TestDesc, batch int) (loops int) { //nolint:revive
+			var session = c.database.Session(c.database.Context(context.Background()))
+			var w = b.GenFakeDataAsMap(c.WorkerID, colConfs, false)
+			var rows, err = session.Query(fmt.Sprintf("SELECT tenant_id, rows_count, max_completion_time FROM %s WHERE tenant_id = '%s'",
+				HeavyMaterializedViewName, (*w)["tenant_id"]))
+			if err != nil {
+				if isNonFatalError(b, c.WorkerID, err) {
+					return 1
+				}
+				b.Exit("db: cannot select from materialized view '%s': %v", HeavyMaterializedViewName, err)
+			}
+
+			for rows.Next() {
+			}
+			rows.Close()
+
+			return 1
+		}
+		testGeneric(b, testDesc, worker, 1)
+	},
+}
+
+// TestSelectHeavyMVRefresh refreshes the 'heavy' table materialized view concurrently
+var TestSelectHeavyMVRefresh = TestDesc{
+	name:        "select-heavy-materialized-view-refresh",
+	metric:      "refreshes/sec",
+	description: "REFRESH MATERIALIZED VIEW CONCURRENTLY of the 'heavy' table per tenant aggregates (the view is created if missing)",
+	category:    TestOther,
+	isReadonly:  true,
+	isDBRTest:   false,
+	databases:   []db.DialectName{db.POSTGRES},
+	table:       TestTableHeavy,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		var c = dbConnector(b)
+		if err := createHeavyMaterializedView(c.database.Session(c.database.Context(context.Background()))); err != nil {
+			b.Exit("db: cannot create materialized view '%s': %v", HeavyMaterializedViewName, err)
+		}
+		c.Release()
+
+		var latencies = make([]time.Duration, b.CommonOpts.Workers)
+		var refreshes = make([]int, b.CommonOpts.Workers)
+
+		worker := func(b *benchmark.Benchmark, c *DBConnector, testDesc *TestDesc, batch int) (loops int) { //nolint:revive
+			var latency, err = refreshHeavyMaterializedView(c.database.Session(c.database.Context(context.Background())))
+			if err != nil {
+				if isNonFatalError(b, c.WorkerID, err) {
+					return 1
+				}
+				b.Exit("db: cannot refresh materialized view '%s': %v", HeavyMaterializedViewName, err)
+			}
+
+			latencies[c.WorkerID] += latency
+			refreshes[c.WorkerID]++
+
+			return 1
+		}
+		testGeneric(b, testDesc, worker, 1)
+
+		var total time.Duration
+		var count int
+		for i := range latencies {
+			total += latencies[i]
+			count += refreshes[i]
+		}
+
+		if count > 0 {
+			fmt.Printf("test: %s; avg refresh latency: %.3f sec\n", testDesc.name, total.Seconds()/float64(count))
+		}
+	},
+}
+
+// heavyIndexMergeIndexes are the 'heavy' table indexes MySQL can merge for the tenant_id = {} OR state = {} condition
+const heavyIndexMergeIndexes = "acronis_db_bench_heavy_tenant_id_idx, acronis_db_bench_heavy_state_completion_time_ns_idx"
+
+// TestSelectHeavyIndexMerge selects rows from the 'heavy' table by the OR condition on the separately indexed columns
+var TestSelectHeavyIndexMerge = TestDesc{
+	name:        "select-heavy-index-merge",
+	metric:      "rows/sec",
+	description: "select {batch} rows from the 'heavy' table WHERE tenant_id = {} OR state = {} to make MySQL consider the index merge, use --mysql-force-index-merge to FORCE INDEX (compare with 'select-heavy-index-merge-single')",
+	category:    TestSelect,
+	isReadonly:  true,
+	isDBRTest:   false,
+	databases:   []db.DialectName{db.MYSQL},
+	table:       TestTableHeavy,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		var from = func(b *benchmark.Benchmark, workerId int) string { //nolint:revive
+			if b.TestOpts.(*TestOpts).TestcaseOpts.MySQLForceIndexMerge {
+				return fmt.Sprintf("%s FORCE INDEX (%s)", testDesc.table.TableName, heavyIndexMergeIndexes)
+			}
+
+			return testDesc.table.TableName
+		}
+		var where = func(b *benchmark.Benchmark, workerId int) string {
+			return fmt.Sprintf("%s OR state = %d", heavyIndexMergeTenantCond(b, workerId), b.Randomizer.GetWorker(workerId).Intn(16))
+		}
+		testSelectRawSQLQuery(b, testDesc, from, "id", where, nil, 1)
+	},
+}
+
+// TestSelectHeavyIndexMergeSingle is the same as TestSelectHeavyIndexMerge but with the single indexed column condition
+var TestSelectHeavyIndexMergeSingle = TestDesc{
+	name:        "select-heavy-index-merge-single",
+	metric:      "rows/sec",
+	description: "select {batch} rows from the 'heavy' table WHERE tenant_id = {} using the single index (compare with 'select-heavy-index-merge')",
+	category:    TestSelect,
+	isReadonly:  true,
+	isDBRTest:   false,
+	databases:   []db.DialectName{db.MYSQL},
+	table:       TestTableHeavy,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		var where = func(b *benchmark.Benchmark, workerId int) string {
+			return heavyIndexMergeTenantCond(b, workerId)
+		}
+		testSelectRawSQLQuery(b, testDesc, nil, "id", where, nil, 1)
+	},
+}
+
+// HeavySkipScanIndexName is a name of the 'heavy' table composite index MySQL can skip scan by the state column
+const HeavySkipScanIndexName = "acronis_db_bench_heavy_tenant_id_state_idx"
+
+// TestSelectHeavySkipScan selects distinct states from the 'heavy' table with the MySQL skip scan off and then on and shows the rate difference
+var TestSelectHeavySkipScan = TestDesc{
+	name:        "select-heavy-skip-scan",
+	metric:      "rows/sec",
+	description: "select DISTINCT state from the 'heavy' table WHERE state > {} by the (tenant_id, state) index with optimizer_switch skip_scan=off and then skip_scan=on and show the rate difference (the index is created if missing, MySQL 8.0.13+)",
+	category:    TestSelect,
+	isReadonly:  true,
+	isDBRTest:   false,
+	databases:   []db.DialectName{db.MYSQL},
+	table:       TestTableHeavy,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		var c = dbConnector(b)
+		if err := c.database.CreateIndex(HeavySkipScanIndexName, testDesc.table.TableName, []string{"tenant_id", "state"}, db.IndexTypeBtree); err != nil {
+			b.Exit("db: cannot create index '%s': %v", HeavySkipScanIndexName, err)
+		}
+		c.Release()
+
+		var from = func(b *benchmark.Benchmark, workerId int) string { //nolint:revive
+			return fmt.Sprintf("%s FORCE INDEX (%s)", testDesc.table.TableName, HeavySkipScanIndexName)
+		}
+		var where = func(b *benchmark.Benchmark, workerId int) string {
+			return fmt.Sprintf("state > %d", b.Randomizer.GetWorker(workerId).Intn(16))
+		}
+
+		// the workers connections are opened with the optimizer_switch of the run, see --mysql-optimizer-switch
+		var dbOpts = &b.TestOpts.(*TestOpts).DBOpts
+		var optimizerSwitch = dbOpts.MySQLOptimizerSwitch
+		var run = func(skipScan string) benchmark.Score {
+			dbOpts.MySQLOptimizerSwitch = "skip_scan=" + skipScan
+			if optimizerSwitch != "" {
+				dbOpts.MySQLOptimizerSwitch = optimizerSwitch + "," + dbOpts.MySQLOptimizerSwitch
+			}
+			defer func() { dbOpts.MySQLOptimizerSwitch = optimizerSwitch }()
+
+			testSelectRawSQLQuery(b, testDesc, from, "DISTINCT state", where, nil, 1)
+
+			return b.Score
+		}
+
+		var before = run("off")
+		var after = run("on")
+
+		var difference float64
+		if before.Rate > 0 {
+			difference = (after.Rate/before.Rate - 1) * 100
+		}
+		fmt.Printf("test: %s; rate without skip scan: %s; rate with skip scan: %s; difference: %+.1f%%\n",
+			testDesc.name, before.FormatRate(4), after.FormatRate(4), difference)
+	},
+}
+
+// TestSelectHeavyTrigram selects rows from the 'heavy' table by resource_name LIKE '%{}%' using the pg_trgm index
+var TestSelectHeavyTrigram = TestDesc{
+	name:        "select-heavy-trigram",
+	metric:      "rows/sec",
+	description: "select {batch} rows from the 'heavy' table WHERE resource_name LIKE '%{}%' using the pg_trgm GIN index (the index is created if missing, compare with 'select-heavy-rand-in-tenant-like')",
+	category:    TestSelect,
+	isReadonly:  true,
+	isDBRTest:   false,
+	databases:   []db.DialectName{db.POSTGRES},
+	table:       TestTableHeavy,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		var patternLength = b.TestOpts.(*TestOpts).TestcaseOpts.TrgmPatternLength
+		if patternLength <= 0 {
+			b.Exit("--trgm-pattern-length must be > 0")
+		}
+
+		var c = dbConnector(b)
+		var session = c.database.Session(c.database.Context(context.Background()))
+		if err := createHeavyTrigramIndex(session); err != nil {
+			b.Exit("db: cannot create trigram index '%s': %v", HeavyTrigramIndexName, err)
+		}
+
+		var used, err = explainUsesIndex(session, "EXPLAIN", fmt.Sprintf("SELECT id FROM %s WHERE resource_name LIKE '%%%s%%'",
+			testDesc.table.TableName, b.RandStringBytes(0, "", 0, patternLength+1, patternLength, false)), HeavyTrigramIndexName)
+		if err != nil {
+			b.Exit("db: cannot explain the trigram query: %v", err)
+		}
+		if !used {
+			b.Log(benchmark.LogWarn, 0, fmt.Sprintf("the query plan doesn't use the '%s' index, the pattern may be too short or the table too small", HeavyTrigramIndexName))
+		}
+		c.Release()
+
+		var where = func(b *benchmark.Benchmark, workerId int) string {
+			return fmt.Sprintf("resource_name LIKE '%%%s%%'", b.RandStringBytes(workerId, "", 0, patternLength+1, patternLength, false))
+		}
+		testSelectRawSQLQuery(b, testDesc, nil, "id", where, nil, 1)
+	},
+}
+
+// ftsVocabulary is the words the 'fts' table documents are made of, there are no stop words which are not indexed
+var ftsVocabulary = []string{
+	"account", "agent", "alert", "archive", "backup", "balance", "browser", "bucket", "cache", "certificate",
+	"channel", "cluster", "config", "console", "container", "credential", "customer", "dashboard", "database", "deploy",
+	"device", "disaster", "domain", "download", "encryption", "endpoint", "error", "event", "export", "failover",
+	"firewall", "folder", "gateway", "group", "hardware", "incident", "inventory", "invoice", "kernel", "license",
+	"machine", "malware", "mailbox", "memory", "migration", "monitor", "mount", "network", "notification", "partner",
+	"password", "patch", "policy", "portal", "printer", "process", "protection", "quota", "ransomware", "recovery",
+	"region", "registry", "release", "replica", "report", "resource", "restore", "retention", "router", "schedule",
+	"script", "server", "service", "session", "signature", "snapshot", "software", "storage", "subscription", "switch",
+	"system", "tenant", "ticket", "token", "traffic", "tunnel", "update", "upload", "usage", "user",
+	"vault", "vendor", "version", "virtual", "volume", "vulnerability", "warning", "website", "window", "workload",
+}
+
+// ftsWord returns random word of the 'fts' table documents vocabulary
+func ftsWord(b *benchmark.Benchmark, workerID int) string {
+	return ftsVocabulary[b.Randomizer.GetWorker(workerID).Intn(len(ftsVocabulary))]
+}
+
+// ftsSearchQuery returns the query selecting {batch} documents containing the word from the 'fts' table by the native full-text search
+func ftsSearchQuery(dialectName db.DialectName, batch int, word string) string {
+	switch dialectName {
+	case db.MYSQL:
+		return fmt.Sprintf("SELECT id FROM %s WHERE MATCH(body) AGAINST (%s IN NATURAL LANGUAGE MODE) LIMIT %d", TestTableFTS.TableName, word, batch)
+	case db.MSSQL:
+		return fmt.Sprintf("SELECT TOP %d id FROM %s WHERE CONTAINS(body, %s)", batch, TestTableFTS.TableName, word)
+	default:
+		return fmt.Sprintf("SELECT id FROM %s WHERE to_tsvector('english', body) @@ plainto_tsquery('english', %s) LIMIT %d", TestTableFTS.TableName, word, batch)
+	}
+}
+
+// prepareFTSTable creates the 'fts' table and the MSSQL full-text index which is not created along with the table
+func prepareFTSTable(b *benchmark.Benchmark, testDesc *TestDesc) {
+	if getDBDriver(b) != db.MSSQL {
+		return
+	}
+
+	var c = dbConnector(b)
+	defer c.Release()
+
+	testDesc.table.Create(c, b)
+	if err := createMSSQLFullTextIndex(c.database.Session(c.database.Context(context.Background()))); err != nil {
+		b.Exit("db: cannot create full-text index of '%s': %v", testDesc.table.TableName, err)
+	}
+}
+
+// TestInsertFTS inserts a document made of random words into the 'fts' table with the full-text index
+var TestInsertFTS = TestDesc{
+	name:        "insert-fts",
+	metric:      "rows/sec",
+	description: "insert a document of {--fts-doc-length} random words into the 'fts' table with the full-text index (GIN on to_tsvector / FULLTEXT / MSSQL full-text index)",
+	category:    TestInsert,
+	isReadonly:  false,
+	isDBRTest:   false,
+	databases:   []db.DialectName{db.POSTGRES, db.MYSQL, db.MSSQL},
+	table:       TestTableFTS,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		var docLength = b.TestOpts.(*TestOpts).TestcaseOpts.FTSDocLength
+		if docLength <= 0 {
+			b.Exit("--fts-doc-length must be > 0")
+		}
+
+		prepareFTSTable(b, testDesc)
+
+		worker := func(b *benchmark.Benchmark, c *DBConnector, testDesc *TestDesc, batch int) (loops int) {
+			var rows = make([][]interface{}, 0, batch)
+			for i := 0; i < batch; i++ {
+				var words = make([]string, docLength)
+				for j := range words {
+					words[j] = ftsWord(b, c.WorkerID)
+				}
+				rows = append(rows, []interface{}{strings.Join(words, " ")})
+			}
+
+			var session = c.database.Session(c.database.Context(context.Background()))
+			if err := session.BulkInsert(testDesc.table.TableName, &db.BulkInsertCtrl{Rows: rows, ColumnNames: []string{"body"}}); err != nil {
+				if isNonFatalError(b, c.WorkerID, err) {
+					return batch
+				}
+				b.Exit(err.Error())
+			}
+
+			return batch
+		}
+		testGeneric(b, testDesc, worker, 0)
+	},
+}
+
+// TestSearchFTS selects documents containing a random word from the 'fts' table using the full-text index
+var TestSearchFTS = TestDesc{
+	name:        "search-fts",
+	metric:      "rows/sec",
+	description: "select {batch} documents containing a random word from the 'fts' table by to_tsvector @@ plainto_tsquery / MATCH AGAINST / CONTAINS, see --explain to print the query plan",
+	category:    TestSelect,
+	isReadonly:  true,
+	isDBRTest:   false,
+	databases:   []db.DialectName{db.POSTGRES, db.MYSQL, db.MSSQL},
+	table:       TestTableFTS,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		prepareFTSTable(b, testDesc)
+
+		var dialectName = getDBDriver(b)
+		var batch = b.Vault.(*DBTestData).EffectiveBatch
+
+		if b.TestOpts.(*TestOpts).BenchOpts.Explain {
+			var c = dbConnector(b)
+			var query = ftsSearchQuery(dialectName, batch, fmt.Sprintf("'%s'", ftsVocabulary[0]))
+			var plan, err = explainQueryPlan(c, query)
+			if err != nil {
+				b.Exit("db: cannot explain the query: %v", err)
+			}
+			fmt.Printf("\nQUERY PLAN: %s\n\n%s\n\n", query, strings.Join(plan, "\n"))
+			c.Release()
+		}
+
+		var query = formatSQL(ftsSearchQuery(dialectName, batch, "$1"), dialectName)
+
+		worker := func(b *benchmark.Benchmark, c *DBConnector, testDesc *TestDesc, batch int) (loops int) {
+			var session = c.database.Session(c.database.Context(context.Background()))
+			if err := inReadOnlyTxn(b, testDesc, session, func(q db.DatabaseAccessor) error {
+				var rows, err = q.Query(query, ftsWord(b, c.WorkerID))
+				if err != nil {
+					return err
+				}
+
+				for rows.Next() {
+				}
+
+				return rows.Close()
+			}); err != nil {
+				if isNonFatalError(b, c.WorkerID, err) {
+					return batch
+				}
+				b.Exit("db: cannot select rows: %v", err)
+			}
+
+			return batch
+		}
+		testGeneric(b, testDesc, worker, 1)
+	},
 }
 
-func tenantAwareWorker(b *benchmark.Benchmark, c *DBConnector, testDesc *TestDesc, orderBy string, batch int) (loops int) { //nolint:revive
-	query := buildTenantAwareQuery(c.database.DialectName(), testDesc.table.TableName)
+// TestAggregateHeavy selects the per state aggregates of the random tenant rows from the 'heavy' table
+var TestAggregateHeavy = TestDesc{
+	name:        "aggregate-heavy",
+	metric:      "rows/sec",
+	description: "select state, COUNT(*), AVG(progress) from the 'heavy' table WHERE tenant_id = {} GROUP BY state, the rate counts the returned groups (compare with 'aggregate-heavy-multi')",
+	category:    TestSelect,
+	isReadonly:  true,
+	isDBRTest:   false,
+	databases:   relationalAnd(db.CLICKHOUSE),
+	table:       TestTableHeavy,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		testAggregateHeavy(b, testDesc, "state")
+	},
+}
 
-	return tenantAwareGenericWorker(b, c, query, orderBy)
+// TestAggregateHeavyMulti selects the per state and CTI entity aggregates of the random tenant rows from the 'heavy' table
+var TestAggregateHeavyMulti = TestDesc{
+	name:        "aggregate-heavy-multi",
+	metric:      "rows/sec",
+	description: "select state, cti_entity_uuid, COUNT(*), AVG(progress) from the 'heavy' table WHERE tenant_id = {} GROUP BY state, cti_entity_uuid, the rate counts the returned groups (compare with 'aggregate-heavy')",
+	category:    TestSelect,
+	isReadonly:  true,
+	isDBRTest:   false,
+	databases:   relationalAnd(db.CLICKHOUSE),
+	table:       TestTableHeavy,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		testAggregateHeavy(b, testDesc, "state, cti_entity_uuid")
+	},
 }
 
-func buildTenantAwareQuery(dialectName db.DialectName, tableName string) string {
-	var tenantAwareQuery string
+// testAggregateHeavy runs the GROUP BY query of the random tenant rows of the 'heavy' table, the rate counts the returned groups,
+// ClickHouse gets the native aggregate functions and the tenant filter in PREWHERE, so the filter is applied before the other columns are read
+func testAggregateHeavy(b *benchmark.Benchmark, testDesc *TestDesc, groupBy string) {
+	var dialectName = getDBDriver(b)
 
-	// TODO: Unify the query building for all dialects
-	switch dialectName {
-	case db.POSTGRES:
-		tenantAwareQuery = fmt.Sprintf("SELECT `%[1]s`.`id` id, `%[1]s`.`tenant_id` FROM `%[1]s` "+
-			"JOIN `%[2]s` AS `tenants_child` ON ((`tenants_child`.`uuid`::uuid = `%[1]s`.`tenant_id`) AND (`tenants_child`.`is_deleted` != {true})) "+
-			"JOIN `%[3]s` AS `tenants_closure` ON ((`tenants_closure`.`child_id` = `tenants_child`.`id`) AND (`tenants_closure`.`barrier` <= 0)) "+
-			"JOIN `%[2]s` AS `tenants_parent` ON ((`tenants_parent`.`id` = `tenants_closure`.`parent_id`) "+
-			"AND (`tenants_parent`.`uuid` IN ('{tenant_uuid}')) AND (`tenants_parent`.`is_deleted` != {true}))",
-			tableName, tenants.TableNameTenants, tenants.TableNameTenantClosure)
-	default:
-		tenantAwareQuery = fmt.Sprintf("SELECT `%[1]s`.`id` id, `%[1]s`.`tenant_id` FROM `%[1]s` "+
-			"JOIN `%[2]s` AS `tenants_child` ON ((`tenants_child`.`uuid` = `%[1]s`.`tenant_id`) AND (`tenants_child`.`is_deleted` != {true})) "+
-			"JOIN `%[3]s` AS `tenants_closure` ON ((`tenants_closure`.`child_id` = `tenants_child`.`id`) AND (`tenants_closure`.`barrier` <= 0)) "+
-			"JOIN `%[2]s` AS `tenants_parent` ON ((`tenants_parent`.`id` = `tenants_closure`.`parent_id`) "+
-			"AND (`tenants_parent`.`uuid` IN ('{tenant_uuid}')) AND (`tenants_parent`.`is_deleted` != {true}))",
-			tableName, tenants.TableNameTenants, tenants.TableNameTenantClosure)
+	var query string
+	if dialectName == db.CLICKHOUSE {
+		query = fmt.Sprintf("SELECT %s, count(), avg(progress) FROM %s PREWHERE tenant_id = $1 GROUP BY %s", groupBy, testDesc.table.TableName, groupBy)
+	} else {
+		query = fmt.Sprintf("SELECT %s, COUNT(*), AVG(progress) FROM %s WHERE tenant_id = $1 GROUP BY %s", groupBy, testDesc.table.TableName, groupBy)
 	}
 
-	return tenantAwareQuery
+	testSelectTenantRows(b, testDesc, formatSQL(query, dialectName))
 }
 
-func tenantAwareGenericWorker(b *benchmark.Benchmark, c *DBConnector, query string, orderBy string) (loops int) {
-	c.Log(benchmark.LogTrace, "tenant-aware SELECT test iteration")
+// testSelectTenantRows runs the query with the random tenant uuid as the only parameter, the rate counts the returned rows
+func testSelectTenantRows(b *benchmark.Benchmark, testDesc *TestDesc, query string) {
+	var colConfs = &[]benchmark.DBFakeColumnConf{{ColumnName: "tenant_id", ColumnType: "tenant_uuid"}}
 
-	uuid, err := b.Vault.(*DBTestData).TenantsCache.GetRandomTenantUUID(b.Randomizer.GetWorker(c.WorkerID), 0, "")
-	if err != nil {
-		b.Exit(err)
-	}
+	var queries, returned int64
 
-	var valTrue string
+	worker := func(b *benchmark.Benchmark, c *DBConnector, testDesc *TestDesc, batch int) (loops int) { //nolint:revive
+		var w = b.GenFakeDataAsMap(c.WorkerID, colConfs, false)
 
-	if c.database.DialectName() == db.POSTGRES {
-		valTrue = "true"
-	} else {
-		valTrue = "1"
-	}
-	query = strings.ReplaceAll(query, "{true}", valTrue)
-	query = strings.ReplaceAll(query, "{tenant_uuid}", uuid.String())
-	if orderBy != "" {
-		query += " " + orderBy
-	}
-	query += " LIMIT 1"
+		var session = c.database.Session(c.database.Context(context.Background()))
+		if err := inReadOnlyTxn(b, testDesc, session, func(q db.DatabaseAccessor) error {
+			var rows, err = q.Query(query, fmt.Sprintf("%s", (*w)["tenant_id"]))
+			if err != nil {
+				return err
+			}
 
-	var id, tenantID string
+			for rows.Next() {
+				atomic.AddInt64(&returned, 1)
+			}
+			atomic.AddInt64(&queries, 1)
 
-	if c.database.DialectName() == db.POSTGRES {
-		query = strings.ReplaceAll(query, "`", "\"")
-	}
+			return rows.Close()
+		}); err != nil {
+			if isNonFatalError(b, c.WorkerID, err) {
+				return 1
+			}
+			b.Exit("db: cannot select rows: %v", err)
+		}
 
-	c.Log(benchmark.LogTrace, "executing query: %s", query)
+		return 1
+	}
 
-	var session = c.database.Session(c.database.Context(context.Background()))
-	if err = session.QueryRow(query).Scan(&id, &tenantID); err != nil {
-		if !errors.Is(sql.ErrNoRows, err) {
-			c.Exit(err.Error())
+	// the loop is a query, but the rate is the number of the rows returned, the average rows per query
+	// is used rather than the total, so the warmup queries don't leak into the score
+	var getRate = b.GetRate
+	b.GetRate = func(loops uint64, seconds float64) float64 {
+		var n = atomic.LoadInt64(&queries)
+		if n == 0 {
+			return 0
 		}
+
+		return float64(loops) * float64(atomic.LoadInt64(&returned)) / float64(n) / seconds
 	}
+	defer func() { b.GetRate = getRate }()
 
-	return 1
+	testGeneric(b, testDesc, worker, 1)
 }
 
-// TestSelectMediumLastTenant is the same as TestSelectMediumLast but with tenant-awareness
-var TestSelectMediumLastTenant = TestDesc{
-	name:        "select-medium-last-in-tenant",
+// TestWindowHeavy numbers the random tenant rows of the 'heavy' table by the ROW_NUMBER window function
+var TestWindowHeavy = TestDesc{
+	name:        "window-heavy",
 	metric:      "rows/sec",
-	description: "select the last row from the 'medium' table WHERE tenant_id = {random tenant uuid}",
+	description: "select id, ROW_NUMBER() OVER (PARTITION BY tenant_id ORDER BY enqueue_time_ns DESC) from the 'heavy' table WHERE tenant_id = {}, the rate counts the returned rows",
 	category:    TestSelect,
 	isReadonly:  true,
 	isDBRTest:   false,
-	databases:   ALL,
-	table:       TestTableMedium,
+	databases:   relationalAnd(db.CLICKHOUSE),
+	table:       TestTableHeavy,
 	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
-		worker := func(b *benchmark.Benchmark, c *DBConnector, testDesc *TestDesc, batch int) (loops int) { //nolint:revive
-			return tenantAwareWorker(b, c, testDesc, "ORDER BY enqueue_time DESC", 1)
-		}
-		testGeneric(b, testDesc, worker, 1)
+		var dialectName = getDBDriver(b)
+		testSelectTenantRows(b, testDesc, formatSQL(fmt.Sprintf("SELECT id, ROW_NUMBER() OVER (PARTITION BY tenant_id ORDER BY enqueue_time_ns DESC) AS rn "+
+			"FROM %s WHERE tenant_id = $1", testDesc.table.TableName), dialectName))
 	},
 }
 
-// TestSelectBlobLastTenant is the same as TestSelectBlobLast but with tenant-awareness
-var TestSelectBlobLastTenant = TestDesc{
-	name:        "select-blob-last-in-tenant",
+// TestWindowLag selects the previous row completion time of the random tenant rows of the 'heavy' table by the LAG window function
+var TestWindowLag = TestDesc{
+	name:        "window-lag",
 	metric:      "rows/sec",
-	description: "select the last row from the 'blob' table WHERE tenant_id = {random tenant uuid}",
+	description: "select id, LAG(completion_time_ns) OVER (PARTITION BY tenant_id ORDER BY enqueue_time_ns) from the 'heavy' table WHERE tenant_id = {} (lagInFrame on ClickHouse), the rate counts the returned rows",
 	category:    TestSelect,
 	isReadonly:  true,
 	isDBRTest:   false,
-	databases:   ALL,
-	table:       TestTableBlob,
+	databases:   relationalAnd(db.CLICKHOUSE),
+	table:       TestTableHeavy,
 	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
-		worker := func(b *benchmark.Benchmark, c *DBConnector, testDesc *TestDesc, batch int) (loops int) { //nolint:revive
-			return tenantAwareWorker(b, c, testDesc, "ORDER BY timestamp DESC", 1)
+		var dialectName = getDBDriver(b)
+
+		// ClickHouse has no LAG, lagInFrame looks for the previous row within the window frame, the default one ends by the current row
+		var lag = "LAG"
+		if dialectName == db.CLICKHOUSE {
+			lag = "lagInFrame"
 		}
-		testGeneric(b, testDesc, worker, 1)
+
+		testSelectTenantRows(b, testDesc, formatSQL(fmt.Sprintf("SELECT id, %s(completion_time_ns) OVER (PARTITION BY tenant_id ORDER BY enqueue_time_ns) AS prev_completion_time_ns "+
+			"FROM %s WHERE tenant_id = $1", lag, testDesc.table.TableName), dialectName))
 	},
 }
 
-// TestSelectHeavyLastTenant is the same as TestSelectHeavyLast but with tenant-awareness
-var TestSelectHeavyLastTenant = TestDesc{
-	name:        "select-heavy-last-in-tenant",
+// TestSelectHeavyPredicatePushdown selects rows from the 'heavy' table by the non-indexed state column filtered by the ClickHouse minmax skipping index
+var TestSelectHeavyPredicatePushdown = TestDesc{
+	name:        "select-heavy-predicate-pushdown",
 	metric:      "rows/sec",
-	description: "select the last row from the 'heavy' table WHERE tenant_id = {random tenant uuid}",
+	description: "select {batch} rows from the 'heavy' table WHERE state = 3 with the filter pushed down to the minmax data skipping index (the index is created if missing, see --verify-predicate-pushdown)",
 	category:    TestSelect,
 	isReadonly:  true,
 	isDBRTest:   false,
-	databases:   RELATIONAL,
+	databases:   []db.DialectName{db.CLICKHOUSE},
 	table:       TestTableHeavy,
 	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
-		worker := func(b *benchmark.Benchmark, c *DBConnector, testDesc *TestDesc, batch int) (loops int) { //nolint:revive
-			return tenantAwareWorker(b, c, testDesc, "ORDER BY enqueue_time DESC", 1)
+		var where = func(b *benchmark.Benchmark, workerId int) string { //nolint:revive
+			return "state = 3"
 		}
-		testGeneric(b, testDesc, worker, 1)
+
+		var c = dbConnector(b)
+		var session = c.database.Session(c.database.Context(context.Background()))
+		if err := createHeavyStateMinMaxIndex(session); err != nil {
+			b.Exit("db: cannot create minmax index '%s': %v", HeavyStateMinMaxIndexName, err)
+		}
+
+		var applied, err = explainUsesIndex(session, "EXPLAIN indexes = 1",
+			fmt.Sprintf("SELECT id FROM %s WHERE %s", testDesc.table.TableName, where(b, 0)), HeavyStateMinMaxIndexName)
+		if err != nil {
+			b.Exit("db: cannot explain the predicate pushdown query: %v", err)
+		}
+		if !applied {
+			if b.TestOpts.(*TestOpts).TestcaseOpts.VerifyPredicatePushdown {
+				b.Exit("the query plan doesn't apply the '%s' index, the predicate is not pushed down", HeavyStateMinMaxIndexName)
+			}
+			b.Log(benchmark.LogWarn, 0, fmt.Sprintf("the query plan doesn't apply the '%s' index, the predicate is not pushed down", HeavyStateMinMaxIndexName))
+		}
+		c.Release()
+
+		testSelectRawSQLQuery(b, testDesc, nil, "id", where, nil, 1)
 	},
 }
 
-// TestSelectHeavyLastTenantCTI is the same as TestSelectHeavyLastTenant but with CTI-awareness
-var TestSelectHeavyLastTenantCTI = TestDesc{
-	name:        "select-heavy-last-in-tenant-and-cti",
-	metric:      "rows/sec",
-	description: "select the last row from the 'heavy' table WHERE tenant_id = {} AND cti = {}",
+// heavyIndexMergeTenantCond returns the 'heavy' table condition on the random tenant
+func heavyIndexMergeTenantCond(b *benchmark.Benchmark, workerId int) string {
+	var w = b.GenFakeDataAsMap(workerId, &[]benchmark.DBFakeColumnConf{{ColumnName: "tenant_id", ColumnType: "tenant_uuid"}}, false)
+
+	return fmt.Sprintf("tenant_id = '%s'", (*w)["tenant_id"])
+}
+
+// heavyParallelScanQuery and heavyParallelGroupByQuery are the 'heavy' table full scans PostgreSQL can run by the parallel query
+const (
+	heavyParallelScanQuery    = "SELECT COUNT(*) FROM acronis_db_bench_heavy WHERE state > 0"
+	heavyParallelGroupByQuery = "SELECT tenant_id, COUNT(*) FROM acronis_db_bench_heavy GROUP BY tenant_id LIMIT 100"
+)
+
+// TestSelectHeavyParallelScan counts the 'heavy' table rows using the PostgreSQL parallel query
+var TestSelectHeavyParallelScan = TestDesc{
+	name:        "select-heavy-parallel-scan",
+	metric:      "queries/sec",
+	description: "select COUNT(*) from the 'heavy' table WHERE state > 0 with max_parallel_workers_per_gather = {--pg-parallel-workers} by 1 worker (compare with 'select-heavy-serial-scan')",
 	category:    TestSelect,
 	isReadonly:  true,
 	isDBRTest:   false,
-	databases:   RELATIONAL,
+	databases:   []db.DialectName{db.POSTGRES},
 	table:       TestTableHeavy,
 	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
-		worker := func(b *benchmark.Benchmark, c *DBConnector, testDesc *TestDesc, batch int) (loops int) { //nolint:revive
-			return tenantAwareCTIAwareWorker(b, c, testDesc, "ORDER BY enqueue_time DESC", 1)
-		}
-		testGeneric(b, testDesc, worker, 1)
+		testSelectHeavyParallel(b, testDesc, heavyParallelScanQuery, pgParallelWorkers(b))
+	},
+}
+
+// TestSelectHeavySerialScan is the same as TestSelectHeavyParallelScan but with the parallel query disabled
+var TestSelectHeavySerialScan = TestDesc{
+	name:        "select-heavy-serial-scan",
+	metric:      "queries/sec",
+	description: "select COUNT(*) from the 'heavy' table WHERE state > 0 with max_parallel_workers_per_gather = 0 by 1 worker (compare with 'select-heavy-parallel-scan')",
+	category:    TestSelect,
+	isReadonly:  true,
+	isDBRTest:   false,
+	databases:   []db.DialectName{db.POSTGRES},
+	table:       TestTableHeavy,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		testSelectHeavyParallel(b, testDesc, heavyParallelScanQuery, 0)
+	},
+}
+
+// TestSelectHeavyParallelGroupBy counts the 'heavy' table rows per tenant using the PostgreSQL parallel query
+var TestSelectHeavyParallelGroupBy = TestDesc{
+	name:        "select-heavy-parallel-group-by",
+	metric:      "queries/sec",
+	description: "select tenant_id, COUNT(*) from the 'heavy' table GROUP BY tenant_id LIMIT 100 with max_parallel_workers_per_gather = {--pg-parallel-workers} by 1 worker (compare with 'select-heavy-serial-group-by')",
+	category:    TestSelect,
+	isReadonly:  true,
+	isDBRTest:   false,
+	databases:   []db.DialectName{db.POSTGRES},
+	table:       TestTableHeavy,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		testSelectHeavyParallel(b, testDesc, heavyParallelGroupByQuery, pgParallelWorkers(b))
+	},
+}
+
+// TestSelectHeavySerialGroupBy is the same as TestSelectHeavyParallelGroupBy but with the parallel query disabled
+var TestSelectHeavySerialGroupBy = TestDesc{
+	name:        "select-heavy-serial-group-by",
+	metric:      "queries/sec",
+	description: "select tenant_id, COUNT(*) from the 'heavy' table GROUP BY tenant_id LIMIT 100 with max_parallel_workers_per_gather = 0 by 1 worker (compare with 'select-heavy-parallel-group-by')",
+	category:    TestSelect,
+	isReadonly:  true,
+	isDBRTest:   false,
+	databases:   []db.DialectName{db.POSTGRES},
+	table:       TestTableHeavy,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		testSelectHeavyParallel(b, testDesc, heavyParallelGroupByQuery, 0)
 	},
 }
 
+// pgParallelWorkers returns the validated --pg-parallel-workers value
+func pgParallelWorkers(b *benchmark.Benchmark) int {
+	var workers = b.TestOpts.(*TestOpts).TestcaseOpts.PGParallelWorkers
+	if workers < 1 {
+		b.Exit("--pg-parallel-workers must be > 0")
+	}
+
+	return workers
+}
+
+// testSelectHeavyParallel runs the query with given max_parallel_workers_per_gather (0 - serial) by the single worker,
+// so only the DB side parallelism is measured
+func testSelectHeavyParallel(b *benchmark.Benchmark, testDesc *TestDesc, query string, parallelWorkers int) {
+	if b.CommonOpts.Workers > 1 {
+		b.Log(benchmark.LogWarn, 0, fmt.Sprintf("test '%s' runs by 1 worker only, --concurrency=%d is ignored", testDesc.name, b.CommonOpts.Workers))
+		b.CommonOpts.Workers = 1
+	}
+
+	// SET LOCAL keeps the setting within the transaction, so the pooled connection is not affected
+	var setQuery = fmt.Sprintf("SET LOCAL max_parallel_workers_per_gather = %d", parallelWorkers)
+
+	worker := func(b *benchmark.Benchmark, c *DBConnector, testDesc *TestDesc, batch int) (loops int) { //nolint:revive
+		var session = c.database.Session(c.database.Context(context.Background()))
+		if err := session.Transact(func(tx db.DatabaseAccessor) error {
+			if _, err := tx.Exec(setQuery); err != nil {
+				return err
+			}
+
+			c.Log(benchmark.LogTrace, "executing query: %s", query)
+
+			var rows, err = tx.Query(query)
+			if err != nil {
+				return err
+			}
+			defer rows.Close()
+
+			for rows.Next() {
+			}
+
+			return rows.Err()
+		}); err != nil {
+			if isNonFatalError(b, c.WorkerID, err) {
+				return 1
+			}
+			c.Exit(err.Error())
+		}
+
+		return 1
+	}
+	testGeneric(b, testDesc, worker, 1)
+}
+
 // GetTests returns all tests in the package for execution
 func GetTests() ([]*TestGroup, map[string]*TestDesc) {
 	allTests = NewTestGroup("all tests")
@@ -2197,6 +4498,10 @@ func GetTests() ([]*TestGroup, map[string]*TestDesc) {
 	tg.add(&TestInsertLightMultiValue)
 	tg.add(&TestCopyLight)
 	tg.add(&TestInsertMedium)
+	tg.add(&TestInsertMongoMedium)
+	tg.add(&TestInsertMediumConcurrentUpdates)
+	tg.add(&TestInsertMediumUniqueViolations)
+	tg.add(&TestInsertMediumMultiTenantParallel)
 	tg.add(&TestInsertMediumPrepared)
 	tg.add(&TestInsertMediumMultiValue)
 	tg.add(&TestCopyMedium)
@@ -2206,6 +4511,12 @@ func GetTests() ([]*TestGroup, map[string]*TestDesc) {
 	tg.add(&TestCopyHeavy)
 	tg.add(&TestUpdateMedium)
 	tg.add(&TestUpdateHeavy)
+	tg.add(&TestUpsertLight)
+	tg.add(&TestUpsertHeavy)
+	tg.add(&TestDeleteLight)
+	tg.add(&TestDeleteMedium)
+	tg.add(&TestDeleteHeavy)
+	tg.add(&TestDeleteHeavyBulk)
 	tg.add(&TestInsertVector768MultiValue)
 	tg.add(&TestSelectVector768NearestL2)
 	tg.add(&TestInsertEmailSecurityMultiValue)
@@ -2213,6 +4524,8 @@ func GetTests() ([]*TestGroup, map[string]*TestDesc) {
 	tg.add(&TestSelectOne)
 	tg.add(&TestSelectMediumLast)
 	tg.add(&TestSelectMediumRand)
+	tg.add(&TestSelectMediumPaginated)
+	tg.add(&TestSelectMediumPaginatedCount)
 	tg.add(&TestSelectHeavyLast)
 	tg.add(&TestSelectHeavyRand)
 	tg.add(&TestSelectHeavyMinMaxTenant)
@@ -2223,10 +4536,15 @@ func GetTests() ([]*TestGroup, map[string]*TestDesc) {
 	tg.add(&TestSelectHeavyRandCustomerRecentLike)
 	tg.add(&TestSelectHeavyRandCustomerUpdateTimePage)
 	tg.add(&TestSelectHeavyRandCustomerCount)
+	tg.add(&TestSelectHeavyAggregateAPI)
 
 	tg.add(&TestSelectHeavyRandPartnerRecent)
 	tg.add(&TestSelectHeavyRandPartnerStartUpdateTimePage)
 
+	for _, t := range redisTests {
+		tg.add(t)
+	}
+
 	tg.add(&TestBaseAll)
 
 	tg = NewTestGroup("Advanced tests group")
@@ -2235,23 +4553,73 @@ func GetTests() ([]*TestGroup, map[string]*TestDesc) {
 	tg.add(&TestSelectNextVal)
 	tg.add(&TestPing)
 	tg.add(&TestSelectHeavyForUpdateSkipLocked)
+	tg.add(&TestSelectHeavyReadPastMSSQL)
+	tg.add(&TestSelectHeavyForUpdateCockroachDB)
 	tg.add(&TestInsertJSON)
 	tg.add(&TestSelectJSONByIndexedValue)
+	tg.add(&TestSelectJSONPath)
+	tg.add(&TestInsertJSONPathIndex)
 	tg.add(&TestSearchJSONByIndexedValue)
 	tg.add(&TestSelectJSONByNonIndexedValue)
 	tg.add(&TestSearchJSONByNonIndexedValue)
+	tg.add(&TestInsertJSONNested)
+	tg.add(&TestSelectJSONNestedByDeepValue)
+	tg.add(&TestInsertJSONNestedWithGIN)
+	tg.add(&TestSelectJSONNestedWithGIN)
+	tg.add(&TestInsertJSONDocumentStore)
+	tg.add(&TestSelectJSONDocumentByID)
+	tg.add(&TestSelectJSONDocumentByAttr)
+	tg.add(&TestInsertNetwork)
+	tg.add(&TestSelectNetworkSubnet)
 	tg.add(&TestUpdateHeavySameVal)
 	tg.add(&TestUpdateHeavyPartialSameVal)
+	tg.add(&TestUpdateHeavyReturning)
 	tg.add(&TestUpdateHeavyBulk)
 	tg.add(&TestUpdateHeavyBulkDBR)
+	tg.add(&TestInsertHeavyColumnar)
+	tg.add(&TestInsertLightIgnoreDuplicates)
+	tg.add(&TestInsertOSVector)
+	tg.add(&TestSelectOSKNN)
+	tg.add(&TestInsertVector)
+	tg.add(&TestSelectVectorKNN)
+	tg.add(&TestInsertMediumConsistencyOne)
+	tg.add(&TestInsertMediumConsistencyQuorum)
+	tg.add(&TestSelectMediumLastConsistencyOne)
+	tg.add(&TestSelectMediumLastConsistencyQuorum)
+	tg.add(&TestSelectHeavyParallelScan)
+	tg.add(&TestSelectHeavySerialScan)
+	tg.add(&TestSelectHeavyParallelGroupBy)
+	tg.add(&TestSelectHeavySerialGroupBy)
+	tg.add(&TestSelectHeavyIndexMerge)
+	tg.add(&TestSelectHeavyIndexMergeSingle)
+	tg.add(&TestSelectHeavySkipScan)
+	tg.add(&TestSelectHeavyTrigram)
+	tg.add(&TestInsertFTS)
+	tg.add(&TestSearchFTS)
+	tg.add(&TestSelectHeavyPredicatePushdown)
+	tg.add(&TestAggregateHeavy)
+	tg.add(&TestAggregateHeavyMulti)
+	tg.add(&TestWindowHeavy)
+	tg.add(&TestWindowLag)
+	tg.add(&TestSelectMediumRandQueryCache)
 
 	tg = NewTestGroup("Tenant-aware tests")
 	g = append(g, tg)
 
 	tg.add(&TestSelectMediumLastTenant)
 	tg.add(&TestSelectHeavyLastTenant)
+	tg.add(&TestExistsHeavyByTenant)
 	tg.add(&TestSelectHeavyLastTenantCTI)
 	tg.add(&TestSelectHeavyRandTenantLike)
+	tg.add(&TestSelectHeavyRecursiveCTE)
+	tg.add(&TestSelectHeavyAntiJoin)
+	tg.add(&TestSelectHeavyAntiJoinLeft)
+	tg.add(&TestJoinMediumHeavy)
+	tg.add(&TestJoinThreeWay)
+	tg.add(&TestSelectHeavyExists)
+	tg.add(&TestSelectHeavyCountSubquery)
+	tg.add(&TestSelectHeavyMV)
+	tg.add(&TestSelectHeavyMVRefresh)
 
 	tg = NewTestGroup("Blob tests")
 	g = append(g, tg)
@@ -2260,12 +4628,16 @@ func GetTests() ([]*TestGroup, map[string]*TestDesc) {
 	tg.add(&TestCopyBlob)
 	tg.add(&TestInsertLargeObj)
 	tg.add(&TestSelectBlobLastTenant)
+	tg.add(&TestSelectBlobStreaming)
 
 	tg = NewTestGroup("Timeseries tests")
 	g = append(g, tg)
 
 	tg.add(&TestInsertTimeSeriesSQL)
 	tg.add(&TestSelectTimeSeriesSQL)
+	tg.add(&TestSelectTimeSeriesSQLAggregated)
+	tg.add(&TestInsertTimeSeriesAggregating)
+	tg.add(&TestSelectTimeSeriesAggregating)
 
 	tg = NewTestGroup("Golang DBR query builder tests")
 	g = append(g, tg)
@@ -2297,6 +4669,13 @@ func GetTests() ([]*TestGroup, map[string]*TestDesc) {
 	tg.add(&TestInsertAdvmVaults)
 	tg.add(&TestInsertAdvmDevices)
 
+	if pluginTests != nil {
+		g = append(g, pluginTests)
+		for _, t := range pluginTests.tests {
+			allTests.tests[t.name] = t
+		}
+	}
+
 	ret := make(map[string]*TestDesc)
 
 	for _, t := range allTests.tests {
@@ -2336,11 +4715,113 @@ func executeAllTests(b *benchmark.Benchmark, testOpts *TestOpts) {
 		fmt.Printf("%s geomean: %.0f\n", s, b.Geomean(testData.scores[s]))
 	}
 
+	if len(testData.scores[SelectAfterDefrag]) > 0 {
+		fmt.Printf("%s geomean: %.0f\n", SelectAfterDefrag, b.Geomean(testData.scores[SelectAfterDefrag]))
+	}
+
 	cleanupTables(b)
 }
 
 func executeOneTest(b *benchmark.Benchmark, testDesc *TestDesc) {
-	testDesc.launcherFunc(b, testDesc)
+	// the 'all' test lock waits are reported by the inner executeOneTest calls
+	var stopLockWaitSampler func() []db.LockWaitStat
+	if b.TestOpts.(*TestOpts).BenchOpts.LockWaitStats && testDesc.name != TestBaseAll.name {
+		stopLockWaitSampler = startLockWaitSampler(b)
+	}
+
+	var stopActiveQueriesLogger func()
+	if interval := b.TestOpts.(*TestOpts).BenchOpts.ShowActiveQueriesInterval; interval > 0 && testDesc.name != TestBaseAll.name {
+		stopActiveQueriesLogger = startActiveQueriesLogger(b, interval)
+	}
+
+	var stopChaosMonkey func() chaosStats
+	if b.TestOpts.(*TestOpts).BenchOpts.ChaosMode && testDesc.name != TestBaseAll.name {
+		stopChaosMonkey = startChaosMonkey(b, time.Duration(b.TestOpts.(*TestOpts).BenchOpts.ChaosInterval)*time.Second)
+	}
+
+	var checkpointMonitor = b.TestOpts.(*TestOpts).BenchOpts.CheckpointMonitor && testDesc.name != TestBaseAll.name
+	var checkpointsBefore db.CheckpointStats
+	if checkpointMonitor {
+		checkpointsBefore = getCheckpointStats(b)
+	}
+
+	if !b.TestOpts.(*TestOpts).BenchOpts.CollectTableStats || testDesc.table.TableName == "" {
+		testDesc.launcherFunc(b, testDesc)
+	} else {
+		var before = getIndexUsageStats(b, testDesc.table.TableName)
+		testDesc.launcherFunc(b, testDesc)
+		var after = getIndexUsageStats(b, testDesc.table.TableName)
+
+		fmt.Printf("\nINDEX USAGE STATS:\n\n%s\n\n", strings.Join(getIndexUsageStatsDiff(before, after), "\n"))
+	}
+
+	if stopActiveQueriesLogger != nil {
+		stopActiveQueriesLogger()
+	}
+
+	b.Vault.(*DBTestData).checkpointStats = db.CheckpointStats{}
+	if checkpointMonitor {
+		var s = getCheckpointStatsDiff(checkpointsBefore, getCheckpointStats(b))
+		b.Vault.(*DBTestData).checkpointStats = s
+		fmt.Printf("\nCHECKPOINTS: timed: %d; requested: %d; write time: %s; sync time: %s; buffers written: %d\n\n",
+			s.CheckpointsTimed, s.CheckpointsReq, s.CheckpointWriteTime.Round(time.Millisecond), s.CheckpointSyncTime.Round(time.Millisecond), s.BuffersCheckpoint)
+	}
+
+	b.Vault.(*DBTestData).lockWaits = 0
+	if stopLockWaitSampler != nil {
+		var incidents = stopLockWaitSampler()
+		b.Vault.(*DBTestData).lockWaits = uint64(len(incidents))
+		fmt.Printf("\nLOCK WAITS:\n\n%s\n\n", strings.Join(getLockWaitReport(incidents), "\n"))
+	}
+
+	b.Vault.(*DBTestData).chaosStats = chaosStats{}
+	if stopChaosMonkey != nil {
+		var s = stopChaosMonkey()
+		b.Vault.(*DBTestData).chaosStats = s
+		fmt.Printf("\nCHAOS EVENTS: %d; mean recovery time: %s; ops during downtime: %d\n\n", s.events, s.meanRecoveryTime.Round(time.Millisecond), s.opsDuringDowntime)
+	}
+
+	// the 'all' test results are collected by the inner executeOneTest calls
+	if testDesc.name != TestBaseAll.name && b.Vault.(*DBTestData).TestDesc != nil {
+		var result = newBenchmarkResult(b, b.Score)
+		b.Vault.(*DBTestData).results = append(b.Vault.(*DBTestData).results, result)
+
+		if b.TestOpts.(*TestOpts).BenchOpts.HTMLReport != "" {
+			var score = b.Score
+			score.Latencies = nil // the percentiles are already calculated, don't keep the latencies of every test
+			b.Vault.(*DBTestData).reportScores = append(b.Vault.(*DBTestData).reportScores,
+				report.Score{Test: testDesc.name, Category: testDesc.category, Score: score})
+		}
+
+		if err := writeTestOutput(b, result, b.Score); err != nil {
+			b.Exit("failed to write the test output: %v", err)
+		}
+	}
+}
+
+// executeSelectAfterDefrag measures the 'heavy' table SELECT rate before and after the tables defragmentation
+func executeSelectAfterDefrag(b *benchmark.Benchmark) {
+	b.CommonOpts.Duration = 10
+	b.CommonOpts.Workers = 1
+	b.CommonOpts.Loops = 0
+	executeOneTest(b, &TestSelectHeavyRand)
+	var before = b.Score
+
+	var c = dbConnector(b)
+	defragmentTables(b, c)
+	c.Release()
+
+	executeOneTest(b, &TestSelectHeavyRand)
+	var after = b.Score
+
+	var testData = b.Vault.(*DBTestData)
+	testData.scores[SelectAfterDefrag] = append(testData.scores[SelectAfterDefrag], after)
+
+	var improvement float64
+	if before.Rate > 0 {
+		improvement = (after.Rate/before.Rate - 1) * 100
+	}
+	fmt.Printf("%s: rate before: %s; rate after: %s; improvement: %+.1f%%\n", SelectAfterDefrag, before.FormatRate(4), after.FormatRate(4), improvement)
 }
 
 func executeAllTestsOnce(b *benchmark.Benchmark, testOpts *TestOpts, workers int) {
@@ -2401,6 +4882,10 @@ func executeAllTestsOnce(b *benchmark.Benchmark, testOpts *TestOpts, workers int
 	executeOneTest(b, &TestUpdateHeavyPartialSameVal)
 	executeOneTest(b, &TestUpdateHeavySameVal)
 
+	if testOpts.BenchOpts.DefragmentAfterInsert {
+		executeSelectAfterDefrag(b)
+	}
+
 	/* Select */
 
 	b.CommonOpts.Duration = 10
@@ -2452,4 +4937,25 @@ func executeAllTestsOnce(b *benchmark.Benchmark, testOpts *TestOpts, workers int
 	executeOneTest(b, &TestSelectTimeSeriesSQL)
 	executeOneTest(b, &TestSelectHeavyMinMaxTenant)
 	executeOneTest(b, &TestSelectHeavyMinMaxTenantAndState)
+
+	/* Delete */
+
+	b.CommonOpts.Duration = 0
+	b.CommonOpts.Workers = 1
+	b.CommonOpts.Loops = testOpts.BenchOpts.Chunk / 100 * 1
+	executeOneTest(b, &TestDeleteLight)
+	executeOneTest(b, &TestDeleteMedium)
+	executeOneTest(b, &TestDeleteHeavy)
+
+	b.CommonOpts.Duration = 0
+	b.CommonOpts.Workers = workers
+	b.CommonOpts.Loops = testOpts.BenchOpts.Chunk / 100 * 1
+	executeOneTest(b, &TestDeleteLight)
+	executeOneTest(b, &TestDeleteMedium)
+	executeOneTest(b, &TestDeleteHeavy)
+
+	b.CommonOpts.Duration = 0
+	b.CommonOpts.Workers = 1
+	b.CommonOpts.Loops = 1
+	executeOneTest(b, &TestDeleteHeavyBulk)
 }
@@ -2,13 +2,16 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"regexp"
 	"strings"
+	"sync"
 	"sync/atomic"
 	"time"
 
 	"github.com/gocraft/dbr/v2"
+	"golang.org/x/time/rate"
 
 	"github.com/acronis/perfkit/benchmark"
 	"github.com/acronis/perfkit/db"
@@ -89,6 +92,24 @@ func initWorker(b *benchmark.Benchmark, workerID int, testDesc *TestDesc, rowsRe
 						testDesc.table.TableName, testDesc.table.RowsCount, rowsRequired))
 				}
 			}
+
+			testData.prefetchedIDs = nil
+			if b.TestOpts.(*TestOpts).BenchOpts.PrefetchIDs && testDesc.category == TestSelect && testDesc.table.RowsCount > 0 {
+				if ids, err := prefetchIDs(conn, tableName); err != nil {
+					b.Log(benchmark.LogWarn, workerID, fmt.Sprintf("cannot prefetch IDs of table '%s', random IDs are used: %v", tableName, err))
+				} else {
+					testData.prefetchedIDs = ids
+					b.Log(benchmark.LogInfo, workerID, fmt.Sprintf("prefetched %d IDs of table '%s'", len(ids), tableName))
+				}
+			}
+		}
+
+		if b.TestOpts.(*TestOpts).BenchOpts.AutoAnalyze {
+			analyzeTables(b, conn)
+		}
+
+		if b.TestOpts.(*TestOpts).DBOpts.EnableQueryCache {
+			setQueryCache(b, conn, true)
 		}
 
 		var tenantCacheDatabase db.Database
@@ -101,7 +122,30 @@ func initWorker(b *benchmark.Benchmark, workerID int, testDesc *TestDesc, rowsRe
 		if err := b.Vault.(*DBTestData).TenantsCache.Init(tenantCacheDatabase); err != nil {
 			b.Exit("db: cannot initialize tenants cache: %v", err)
 		}
+
+		var pinned []*benchmark.RandomizerWorker
+		if b.TestOpts.(*TestOpts).TestcaseOpts.SingleTenantPerWorker {
+			for i := 0; i < b.CommonOpts.Workers; i++ {
+				pinned = append(pinned, b.Randomizer.GetWorker(i))
+			}
+		}
+		if err := b.Vault.(*DBTestData).TenantsCache.PinWorkerTenants(pinned); err != nil {
+			b.Exit("TEST ABORTED: %v", err)
+		}
+
+		if b.TestOpts.(*TestOpts).BenchOpts.TenantsPreWarm {
+			b.Vault.(*DBTestData).TenantsCache.PreWarmTenants(tenantCacheDatabase)
+		}
+
+		if b.TestOpts.(*TestOpts).BenchOpts.CTIsPreWarm {
+			b.Vault.(*DBTestData).TenantsCache.PreWarmCTIs(tenantCacheDatabase)
+		}
+	}
+
+	if conn := b.WorkerData[workerID].(*DBWorkerData).workingConn; conn.database.DialectName() == db.SCYLLA {
+		b.WorkerData[workerID].(*DBWorkerData).shardQueriesBefore = conn.database.Stats()
 	}
+
 	b.Log(benchmark.LogTrace, workerID, "worker is initialized")
 }
 
@@ -120,6 +164,207 @@ func initCommon(b *benchmark.Benchmark, testDesc *TestDesc, rowsRequired uint64)
 	}
 }
 
+// isNonFatalError returns true if the worker can go on after the query or connection error: the client timeouts and
+// the --chaos-mode outages are always non-fatal, the other errors are non-fatal only if --max-errors or --error-rate-threshold
+// is set, they are logged as the QUERY_ERROR events and accounted so the test is stopped once the threshold is exceeded
+func isNonFatalError(b *benchmark.Benchmark, workerID int, err error) bool {
+	if isClientTimeout(b, workerID, err) {
+		return true
+	}
+
+	var opts = b.TestOpts.(*TestOpts).BenchOpts
+	if opts.MaxErrors == 0 && opts.ErrorRateThreshold == 0 {
+		return false
+	}
+
+	b.Log(benchmark.LogWarn, workerID, fmt.Sprintf("QUERY_ERROR: %v", err))
+	accountError(b, workerID)
+
+	return true
+}
+
+// isClientTimeout logs the CLIENT_TIMEOUT event and returns true if the error is caused by the --query-timeout expiration,
+// the errors caused by the --chaos-mode DB outage are non-fatal as well (see isChaosOutage)
+func isClientTimeout(b *benchmark.Benchmark, workerID int, err error) bool {
+	if !errors.Is(err, db.ErrQueryTimeout) {
+		return isChaosOutage(b, workerID, err)
+	}
+
+	b.Log(benchmark.LogWarn, workerID, fmt.Sprintf("CLIENT_TIMEOUT: %v", err))
+	accountError(b, workerID)
+
+	return true
+}
+
+// isChaosOutage logs the CHAOS_OUTAGE event and returns true if the error happened during the --chaos-mode DB outage,
+// the worker is held with the exponential backoff until the DB is recovered
+func isChaosOutage(b *benchmark.Benchmark, workerID int, err error) bool {
+	var m = b.Vault.(*DBTestData).chaos
+	if m == nil || !m.isAffected(chaosGracePeriod) {
+		return false
+	}
+
+	b.Log(benchmark.LogWarn, workerID, fmt.Sprintf("CHAOS_OUTAGE: %v", err))
+	accountError(b, workerID)
+
+	for backoff := chaosMinBackoff; m.isDown() && !b.NeedToExit; backoff = min(2*backoff, chaosMaxBackoff) {
+		time.Sleep(backoff)
+	}
+
+	return true
+}
+
+// inReadOnlyTxn runs fn in the read-only transaction if --read-only-txn is set and the test is SELECT one, otherwise fn is run on the session as is
+func inReadOnlyTxn(b *benchmark.Benchmark, testDesc *TestDesc, session db.Session, fn func(q db.DatabaseAccessor) error) error {
+	if !b.TestOpts.(*TestOpts).BenchOpts.ReadOnlyTxn || testDesc.category != TestSelect {
+		return fn(session)
+	}
+
+	return session.RunInReadOnlyTransaction(fn)
+}
+
+// errorRateMinLoops is the minimal amount of loops to be done before the --error-rate-threshold is checked
+const errorRateMinLoops = 100
+
+// accountError accounts non-fatal worker error and stops the test if --max-errors or --error-rate-threshold is exceeded
+func accountError(b *benchmark.Benchmark, workerID int) {
+	var opts = b.TestOpts.(*TestOpts).BenchOpts
+	var total = b.AddErrors(1)
+
+	if b.NeedToExit {
+		return
+	}
+
+	if opts.MaxErrors > 0 && total > uint64(opts.MaxErrors) {
+		b.Log(benchmark.LogError, workerID, fmt.Sprintf("stopping the test: %d errors exceed --max-errors=%d", total, opts.MaxErrors))
+		b.NeedToExit = true
+
+		return
+	}
+
+	if opts.ErrorRateThreshold > 0 {
+		var loops = b.DoneLoops()
+		if loops < errorRateMinLoops {
+			return
+		}
+
+		if rate := float64(total) * 100 / float64(loops); rate > opts.ErrorRateThreshold {
+			b.Log(benchmark.LogError, workerID, fmt.Sprintf("stopping the test: error rate %.2f%% exceeds --error-rate-threshold=%.2f%%", rate, opts.ErrorRateThreshold))
+			b.NeedToExit = true
+		}
+	}
+}
+
+/*
+ * Transaction batches
+ */
+
+// runWorkers runs the benchmark, if --transaction-batch-size is > 1 the Worker is wrapped by newTransactionBatchWorker
+func runWorkers(b *benchmark.Benchmark) {
+	var size = b.TestOpts.(*TestOpts).BenchOpts.TransactionBatchSize
+	if size < 1 {
+		b.Exit("--transaction-batch-size must be > 0")
+	}
+
+	if size > 1 {
+		var worker = b.Worker
+		b.Worker = newTransactionBatchWorker(b, worker, size)
+		defer func() { b.Worker = worker }()
+	}
+
+	if limit := b.TestOpts.(*TestOpts).BenchOpts.RateLimit; limit > 0 {
+		var preWorker = b.PreWorker
+		b.PreWorker = newRateLimitedPreWorker(b, preWorker, limit)
+		defer func() { b.PreWorker = preWorker }()
+	}
+
+	var stopProgress = startProgress(b)
+	defer stopProgress()
+
+	b.Run()
+}
+
+// newRateLimitedPreWorker returns the PreWorker waiting for the token of the worker own token bucket filled at given loops per second,
+// the wait precedes the Worker call, so it's not accounted in the latencies
+func newRateLimitedPreWorker(b *benchmark.Benchmark, preWorker func(workerId int), limit float64) func(workerId int) {
+	var limiters = make([]*rate.Limiter, b.CommonOpts.Workers)
+	for i := range limiters {
+		limiters[i] = rate.NewLimiter(rate.Limit(limit), 1)
+	}
+
+	return func(workerId int) {
+		if err := limiters[workerId].Wait(context.Background()); err != nil {
+			b.Exit("rate limiter error: %v", err)
+		}
+		preWorker(workerId)
+	}
+}
+
+// newTransactionBatchWorker returns the worker calling given worker up to size times in a single transaction,
+// the worker queries made through the sessions of its working connection are executed in this transaction,
+// the raw sessions (e.g. dbr) are not affected; the loops of all the calls are accounted, not the transactions
+func newTransactionBatchWorker(b *benchmark.Benchmark, worker func(workerId int) (loops int), size int) func(workerId int) (loops int) {
+	return func(workerId int) (loops int) {
+		var c = b.WorkerData[workerId].(*DBWorkerData).workingConn
+		var database = c.database
+		var dbCtx = database.Context(context.Background())
+		var sess = database.Session(dbCtx)
+
+		if txErr := sess.Transact(func(tx db.DatabaseAccessor) error {
+			// the transaction can be retried, so only the loops of the last attempt are accounted
+			loops = 0
+
+			c.database = &txBatchDatabase{Database: database, session: sess, tx: tx}
+			defer func() { c.database = database }()
+
+			for i := 0; i < size && !b.NeedToExit; i++ {
+				var l = worker(workerId)
+				if l == 0 {
+					break
+				}
+				loops += l
+			}
+
+			return nil
+		}); txErr != nil && !isNonFatalError(b, workerId, txErr) {
+			b.Exit(txErr.Error())
+		}
+		b.AddRetries(dbCtx.TxRetries)
+
+		return loops
+	}
+}
+
+// txBatchDatabase is the database of the working connection during the transaction batch, its sessions run all the queries in the batch transaction
+type txBatchDatabase struct {
+	db.Database
+	session db.Session
+	tx      db.DatabaseAccessor
+}
+
+func (d *txBatchDatabase) Session(*db.Context) db.Session {
+	return &txBatchSession{DatabaseAccessor: d.tx, session: d.session}
+}
+
+// txBatchSession runs the nested transactions as a part of the batch transaction
+type txBatchSession struct {
+	db.DatabaseAccessor
+	session db.Session
+}
+
+func (s *txBatchSession) Transact(fn func(tx db.DatabaseAccessor) error) error {
+	return fn(s.DatabaseAccessor)
+}
+
+func (s *txBatchSession) RunInReadOnlyTransaction(fn func(tx db.DatabaseAccessor) error) error {
+	return fn(s.DatabaseAccessor)
+}
+
+// GetNextVal is not allowed inside the transaction, so it's executed by the session the batch transaction is started from
+func (s *txBatchSession) GetNextVal(sequenceName string) (uint64, error) {
+	return s.session.GetNextVal(sequenceName)
+}
+
 /*
  * SELECT workers
  */
@@ -134,7 +379,7 @@ func testGeneric(b *benchmark.Benchmark, testDesc *TestDesc, workerFunc testWork
 		return workerFunc(b, c, testDesc, batch)
 	}
 
-	b.Run()
+	runWorkers(b)
 
 	b.Vault.(*DBTestData).scores[testDesc.category] = append(b.Vault.(*DBTestData).scores[testDesc.category], b.Score)
 }
@@ -149,6 +394,23 @@ func testSelect(
 	rowsRequired uint64,
 ) {
 	initCommon(b, testDesc, rowsRequired)
+
+	b.Worker = newSelectWorker(b, testDesc, fromFunc, what, whereFunc, orderByFunc)
+
+	runWorkers(b)
+
+	b.Vault.(*DBTestData).scores[testDesc.category] = append(b.Vault.(*DBTestData).scores[testDesc.category], b.Score)
+}
+
+// newSelectWorker returns the worker selecting the batch of rows from the test table, see testSelect
+func newSelectWorker(
+	b *benchmark.Benchmark,
+	testDesc *TestDesc,
+	fromFunc func(b *benchmark.Benchmark, workerId int) string,
+	what []string,
+	whereFunc func(b *benchmark.Benchmark, workerId int) map[string][]string,
+	orderByFunc func(b *benchmark.Benchmark, workerId int) []string,
+) func(workerId int) (loops int) {
 	testOpts, ok := b.TestOpts.(*TestOpts)
 	if !ok {
 		b.Exit("TestOpts type conversion error")
@@ -162,7 +424,7 @@ func testSelect(
 		ID int64 `db:"id"`
 	}
 
-	b.Worker = func(workerId int) (loops int) {
+	return func(workerId int) (loops int) {
 		c := b.WorkerData[workerId].(*DBWorkerData).workingConn
 
 		from := testDesc.table.TableName
@@ -209,33 +471,33 @@ func testSelect(
 		}
 
 		var session = c.database.Session(c.database.Context(context.Background()))
-		var rows, err = session.Select(from, &db.SelectCtrl{
-			Fields: what,
-			Where:  whereCond,
-			Order:  orderBy,
-			Page: db.Page{
-				Limit: int64(batch),
-			},
-			OptimizeConditions: false,
-		})
-		if err != nil {
-			b.Exit("db: cannot select rows: %v", err)
-		}
-
-		for rows.Next() {
+		if err := inReadOnlyTxn(b, testDesc, session, func(q db.DatabaseAccessor) error {
+			var rows, err = q.Select(from, &db.SelectCtrl{
+				Fields: what,
+				Where:  whereCond,
+				Order:  orderBy,
+				Page: db.Page{
+					Limit: int64(batch),
+				},
+				OptimizeConditions: false,
+			})
 			if err != nil {
-				b.Exit(err)
+				return err
 			}
-		}
 
-		rows.Close()
+			for rows.Next() {
+			}
+
+			return rows.Close()
+		}); err != nil {
+			if isNonFatalError(b, workerId, err) {
+				return batch
+			}
+			b.Exit("db: cannot select rows: %v", err)
+		}
 
 		return batch
 	}
-
-	b.Run()
-
-	b.Vault.(*DBTestData).scores[testDesc.category] = append(b.Vault.(*DBTestData).scores[testDesc.category], b.Score)
 }
 
 func testSelectRawSQLQuery(
@@ -304,23 +566,27 @@ func testSelectRawSQLQuery(
 		}
 
 		var session = c.database.Session(c.database.Context(context.Background()))
-		var rows, err = session.Query(query)
-		if err != nil {
-			b.Exit("db: cannot select rows: %v", err)
-		}
-
-		for rows.Next() {
+		if err := inReadOnlyTxn(b, testDesc, session, func(q db.DatabaseAccessor) error {
+			var rows, err = q.Query(query)
 			if err != nil {
-				b.Exit(err)
+				return err
 			}
-		}
 
-		rows.Close()
+			for rows.Next() {
+			}
+
+			return rows.Close()
+		}); err != nil {
+			if isNonFatalError(b, workerId, err) {
+				return batch
+			}
+			b.Exit("db: cannot select rows: %v", err)
+		}
 
 		return batch
 	}
 
-	b.Run()
+	runWorkers(b)
 
 	b.Vault.(*DBTestData).scores[testDesc.category] = append(b.Vault.(*DBTestData).scores[testDesc.category], b.Score)
 }
@@ -339,14 +605,25 @@ func getDBDriver(b *benchmark.Benchmark) db.DialectName {
 }
 
 func testInsertGeneric(b *benchmark.Benchmark, testDesc *TestDesc) {
+	var worker = newInsertWorker(b, testDesc)
+
+	initCommon(b, testDesc, 0)
+
+	b.Worker = worker
+
+	runWorkers(b)
+
+	b.Vault.(*DBTestData).scores[testDesc.category] = append(b.Vault.(*DBTestData).scores[testDesc.category], b.Score)
+}
+
+// newInsertWorker returns the worker inserting the batch of rows into the test table
+func newInsertWorker(b *benchmark.Benchmark, testDesc *TestDesc) func(workerId int) (loops int) {
 	colConfs := testDesc.table.GetColumnsForInsert(db.WithAutoInc(getDBDriver(b)))
 
 	if len(*colConfs) == 0 {
 		b.Exit(fmt.Sprintf("internal error: no columns eligible for INSERT found in '%s' configuration", testDesc.table.TableName))
 	}
 
-	initCommon(b, testDesc, 0)
-
 	batch := b.Vault.(*DBTestData).EffectiveBatch
 	table := &testDesc.table
 
@@ -355,15 +632,49 @@ func testInsertGeneric(b *benchmark.Benchmark, testDesc *TestDesc) {
 		b.Exit(dialErr)
 	}
 
-	if dialectName == db.CLICKHOUSE {
+	var worker func(workerId int) (loops int)
+	if dialectName == db.CLICKHOUSE && b.TestOpts.(*TestOpts).DBOpts.CHColumnar {
+		worker = func(workerId int) (loops int) {
+			workerData := b.WorkerData[workerId].(*DBWorkerData)
+			rows := table.RowsCount
+
+			var c = workerData.workingConn
+			var dbCtx = c.database.Context(context.Background())
+			var sess = c.database.Session(dbCtx)
+
+			var columns []string
+			var batchRows = make([][]interface{}, 0, batch)
+			for i := 0; i < batch; i++ {
+				// clickhouse doesn't support autoincremented ID, so need to maintain it here
+				var fakeColumns, values = b.GenFakeData(workerId, colConfs, false)
+				columns = append([]string{"id"}, fakeColumns...)
+				args := append([]interface{}{atomic.AddUint64(&rows, 1)}, values...)
+
+				for n, v := range args {
+					if t, ok := v.(tenants.TenantUUID); ok {
+						args[n] = string(t)
+					}
+				}
+				batchRows = append(batchRows, args)
+			}
+
+			if err := sess.BulkInsert(table.TableName, &db.BulkInsertCtrl{Rows: batchRows, ColumnNames: columns}); err != nil && !isNonFatalError(b, workerId, err) {
+				b.Exit(err.Error())
+			}
+			b.AddRetries(dbCtx.TxRetries)
+
+			return batch
+		}
+	} else if dialectName == db.CLICKHOUSE {
 		sql := fmt.Sprintf("INSERT INTO %s", table.TableName) //nolint:perfsprint
 
-		b.Worker = func(workerId int) (loops int) {
+		worker = func(workerId int) (loops int) {
 			workerData := b.WorkerData[workerId].(*DBWorkerData)
 			rows := table.RowsCount
 
 			var c = workerData.workingConn
-			var sess = c.database.Session(c.database.Context(context.Background()))
+			var dbCtx = c.database.Context(context.Background())
+			var sess = c.database.Session(dbCtx)
 
 			if txErr := sess.Transact(func(tx db.DatabaseAccessor) error {
 				var txBatch, prepareErr = tx.Prepare(sql)
@@ -395,14 +706,15 @@ func testInsertGeneric(b *benchmark.Benchmark, testDesc *TestDesc) {
 				defer txBatch.Close()
 
 				return nil
-			}); txErr != nil {
+			}); txErr != nil && !isNonFatalError(b, workerId, txErr) {
 				b.Exit(txErr.Error())
 			}
+			b.AddRetries(dbCtx.TxRetries)
 
 			return batch
 		}
 	} else if testDesc.isDBRTest {
-		b.Worker = func(workerId int) (loops int) {
+		worker = func(workerId int) (loops int) {
 			var t time.Time
 			if b.Logger.LogLevel >= benchmark.LogDebug {
 				t = time.Now()
@@ -416,6 +728,9 @@ func testInsertGeneric(b *benchmark.Benchmark, testDesc *TestDesc) {
 			tx, err := dbrSess.Begin()
 			b.Log(benchmark.LogDebug, workerId, "BEGIN")
 			if err != nil {
+				if isNonFatalError(b, workerId, err) {
+					return batch
+				}
 				b.Exit(err.Error())
 			}
 			defer tx.RollbackUnlessCommitted() // Rollback in case of error
@@ -424,12 +739,18 @@ func testInsertGeneric(b *benchmark.Benchmark, testDesc *TestDesc) {
 				columns, values := b.GenFakeData(workerId, colConfs, false)
 				_, err = tx.InsertInto(table.TableName).Columns(columns...).Values(values...).Exec()
 				if err != nil {
+					if isNonFatalError(b, workerId, err) {
+						return batch
+					}
 					b.Exit("aborting")
 				}
 			}
 
 			err = tx.Commit()
 			if err != nil {
+				if isNonFatalError(b, workerId, err) {
+					return batch
+				}
 				b.Exit("Commit() error: %s", err)
 			}
 
@@ -440,19 +761,23 @@ func testInsertGeneric(b *benchmark.Benchmark, testDesc *TestDesc) {
 			return batch
 		}
 	} else {
-		b.Worker = func(workerId int) (loops int) {
+		worker = func(workerId int) (loops int) {
 			workerData := b.WorkerData[workerId].(*DBWorkerData)
 
 			var c = workerData.workingConn
-			var sess = c.database.Session(c.database.Context(context.Background()))
+			var dbCtx = c.database.Context(context.Background())
+			var sess = c.database.Session(dbCtx)
+			var bytes int64
 
 			if txErr := sess.Transact(func(tx db.DatabaseAccessor) error {
+				bytes = 0
 				for i := 0; i < batch; i++ {
 					columns, values := b.GenFakeData(workerId, colConfs, db.WithAutoInc(getDBDriver(b)))
 
-					if err := tx.BulkInsert(table.TableName, [][]interface{}{values}, columns); err != nil {
+					if err := tx.BulkInsert(table.TableName, &db.BulkInsertCtrl{Rows: [][]interface{}{values}, ColumnNames: columns}); err != nil {
 						return err
 					}
+					bytes += blobBytes(values)
 
 					if b.TestOpts.(*TestOpts).BenchOpts.Events {
 						rw := b.Randomizer.GetWorker(workerId)
@@ -464,16 +789,19 @@ func testInsertGeneric(b *benchmark.Benchmark, testDesc *TestDesc) {
 
 				return nil
 			}); txErr != nil {
-				b.Exit(txErr.Error())
+				if !isNonFatalError(b, workerId, txErr) {
+					b.Exit(txErr.Error())
+				}
+			} else {
+				b.AddBytes(bytes)
 			}
+			b.AddRetries(dbCtx.TxRetries)
 
 			return batch
 		}
 	}
 
-	b.Run()
-
-	b.Vault.(*DBTestData).scores[testDesc.category] = append(b.Vault.(*DBTestData).scores[testDesc.category], b.Score)
+	return worker
 }
 
 /*
@@ -565,7 +893,8 @@ func testUpdateGeneric(b *benchmark.Benchmark, testDesc *TestDesc, updateRows ui
 
 		b.Worker = func(workerId int) (loops int) {
 			var c = b.WorkerData[workerId].(*DBWorkerData).workingConn
-			var session = c.database.Session(c.database.Context(context.Background()))
+			var dbCtx = c.database.Context(context.Background())
+			var session = c.database.Session(dbCtx)
 			if txErr := session.Transact(func(tx db.DatabaseAccessor) error {
 				for i := 0; i < batch; i++ {
 					id := int64(b.Randomizer.GetWorker(workerId).Uintn64(table.RowsCount-updateRows) + updateRows)
@@ -589,15 +918,16 @@ func testUpdateGeneric(b *benchmark.Benchmark, testDesc *TestDesc, updateRows ui
 				}
 
 				return nil
-			}); txErr != nil {
+			}); txErr != nil && !isNonFatalError(b, workerId, txErr) {
 				b.Exit(txErr.Error())
 			}
+			b.AddRetries(dbCtx.TxRetries)
 
 			return batch * int(updateRows)
 		}
 	}
 
-	b.Run()
+	runWorkers(b)
 
 	b.Vault.(*DBTestData).scores[testDesc.category] = append(b.Vault.(*DBTestData).scores[testDesc.category], b.Score)
 }
@@ -606,7 +936,7 @@ func testUpdateGeneric(b *benchmark.Benchmark, testDesc *TestDesc, updateRows ui
  * DELETE worker
  */
 // testDeleteGeneric is a generic DELETE worker
-func testDeleteGeneric(b *benchmark.Benchmark, testDesc *TestDesc, deleteRows uint64) { //nolint:unused
+func testDeleteGeneric(b *benchmark.Benchmark, testDesc *TestDesc, deleteRows uint64) {
 	initCommon(b, testDesc, deleteRows)
 
 	batch := b.Vault.(*DBTestData).EffectiveBatch
@@ -670,7 +1000,8 @@ func testDeleteGeneric(b *benchmark.Benchmark, testDesc *TestDesc, deleteRows ui
 
 		b.Worker = func(workerId int) (loops int) {
 			var c = b.WorkerData[workerId].(*DBWorkerData).workingConn
-			var session = c.database.Session(c.database.Context(context.Background()))
+			var dbCtx = c.database.Context(context.Background())
+			var session = c.database.Session(dbCtx)
 			if txErr := session.Transact(func(tx db.DatabaseAccessor) error {
 				for i := 0; i < batch; i++ {
 					id := int64(b.Randomizer.GetWorker(workerId).Uintn64(table.RowsCount-deleteRows) + deleteRows)
@@ -681,7 +1012,7 @@ func testDeleteGeneric(b *benchmark.Benchmark, testDesc *TestDesc, deleteRows ui
 						values = append(values, id-int64(deleteRows))
 					}
 
-					if _, err := tx.Query(deleteSQL, values...); err != nil {
+					if _, err := tx.Exec(deleteSQL, values...); err != nil {
 						return err
 					}
 
@@ -694,15 +1025,257 @@ func testDeleteGeneric(b *benchmark.Benchmark, testDesc *TestDesc, deleteRows ui
 				}
 
 				return nil
-			}); txErr != nil {
+			}); txErr != nil && !isNonFatalError(b, workerId, txErr) {
 				b.Exit(txErr.Error())
 			}
+			b.AddRetries(dbCtx.TxRetries)
 
 			return batch * int(deleteRows)
 		}
 	}
 
-	b.Run()
+	runWorkers(b)
 
 	b.Vault.(*DBTestData).scores[testDesc.category] = append(b.Vault.(*DBTestData).scores[testDesc.category], b.Score)
 }
+
+/*
+ * Mixed read / write workers
+ */
+
+// testReadWriteMix runs readWorker by --mixed-read-pct percent of the workers and writeWorker by the rest of them at the same time,
+// the read rate (select queries/sec) and the write rate (inserted rows/sec) of the measured loops are reported along with the combined one
+func testReadWriteMix(b *benchmark.Benchmark, testDesc *TestDesc, readWorker, writeWorker func(workerId int) (loops int), rowsRequired uint64) {
+	var readPct = b.TestOpts.(*TestOpts).TestcaseOpts.MixedReadPct
+	if readPct < 0 || readPct > 100 {
+		b.Exit("--mixed-read-pct must be between 0 and 100")
+	}
+
+	initCommon(b, testDesc, rowsRequired)
+
+	var readers = b.CommonOpts.Workers * readPct / 100
+	var queries, rows uint64 // the select queries and the inserted rows of the measured loops only
+
+	var preMeasure = b.PreMeasure
+	b.PreMeasure = func() {
+		preMeasure()
+		atomic.StoreUint64(&queries, 0)
+		atomic.StoreUint64(&rows, 0)
+	}
+	defer func() { b.PreMeasure = preMeasure }()
+
+	b.Worker = func(workerId int) (loops int) {
+		if workerId < readers {
+			loops = readWorker(workerId)
+			atomic.AddUint64(&queries, 1)
+		} else {
+			loops = writeWorker(workerId)
+			atomic.AddUint64(&rows, uint64(loops))
+		}
+
+		return loops
+	}
+
+	runWorkers(b)
+
+	b.Vault.(*DBTestData).scores[testDesc.category] = append(b.Vault.(*DBTestData).scores[testDesc.category], b.Score)
+
+	var readRate, writeRate float64
+	if b.Score.Seconds > 0 {
+		readRate = float64(atomic.LoadUint64(&queries)) / b.Score.Seconds
+		writeRate = float64(atomic.LoadUint64(&rows)) / b.Score.Seconds
+	}
+	fmt.Printf("test: %s; read workers: %d; write workers: %d; read rate: %.1f queries/sec; write rate: %.1f rows/sec; combined rate: %.1f %s\n",
+		testDesc.name, readers, b.CommonOpts.Workers-readers, readRate, writeRate, b.Score.Rate, testDesc.metric)
+}
+
+/*
+ * Unique constraint violation workers
+ */
+
+// testInsertUniqueViolations inserts rows one by one, --unique-violations-pct percent of them re-use the unique key inserted last
+// by any of the workers and are expected to be rejected, the rate counts the inserted rows only
+func testInsertUniqueViolations(b *benchmark.Benchmark, testDesc *TestDesc, keyColumn string) {
+	var violationsPct = b.TestOpts.(*TestOpts).TestcaseOpts.UniqueViolationsPct
+	if violationsPct < 0 || violationsPct > 100 {
+		b.Exit("--unique-violations-pct must be between 0 and 100")
+	}
+
+	var colConfs = testDesc.table.GetColumnsForInsert(db.WithAutoInc(getDBDriver(b)))
+
+	var nextKey, lastKey int64
+	var initKeys sync.Once
+
+	worker := func(b *benchmark.Benchmark, c *DBConnector, testDesc *TestDesc, batch int) (loops int) {
+		var session = c.database.Session(c.database.Context(context.Background()))
+
+		initKeys.Do(func() {
+			var query = fmt.Sprintf("SELECT COALESCE(MAX(%s), 0) FROM %s", keyColumn, testDesc.table.TableName)
+			if err := session.QueryRow(query).Scan(&nextKey); err != nil {
+				b.Exit("db: cannot get max '%s' value: %v", keyColumn, err)
+			}
+		})
+
+		for i := 0; i < batch; i++ {
+			var key int64
+			if last := atomic.LoadInt64(&lastKey); last > 0 && b.Randomizer.GetWorker(c.WorkerID).Intn(100) < violationsPct {
+				key = last
+			} else {
+				key = atomic.AddInt64(&nextKey, 1)
+			}
+
+			var columns, values = b.GenFakeData(c.WorkerID, colConfs, false)
+			for j, column := range columns {
+				if column == keyColumn {
+					values[j] = key
+				}
+			}
+
+			var err = session.BulkInsert(testDesc.table.TableName, &db.BulkInsertCtrl{Rows: [][]interface{}{values}, ColumnNames: columns})
+			switch {
+			case err == nil:
+				atomic.StoreInt64(&lastKey, key)
+			case errors.Is(err, db.ErrUniqueViolation):
+				b.AddConstraintViolations(1)
+			case !isNonFatalError(b, c.WorkerID, err):
+				b.Exit(err.Error())
+			}
+		}
+
+		return batch
+	}
+
+	var getRate = b.GetRate
+	b.GetRate = func(loops uint64, seconds float64) float64 {
+		return float64(loops-b.ConstraintViolations()) / seconds
+	}
+	defer func() { b.GetRate = getRate }()
+
+	testGeneric(b, testDesc, worker, 0)
+}
+
+/*
+ * UPSERT workers
+ */
+
+// upsertSQLTemplate returns the dialect specific statement inserting the row or updating the columns of the row with the same id
+func upsertSQLTemplate(dialectName db.DialectName, tableName string, columns []string) string {
+	var placeholders = db.GenDBParameterPlaceholders(0, len(columns))
+
+	var updates []string
+	for _, column := range columns[1:] {
+		switch dialectName {
+		case db.MYSQL:
+			updates = append(updates, fmt.Sprintf("%s = VALUES(%s)", column, column))
+		case db.MSSQL:
+			updates = append(updates, fmt.Sprintf("%s = source.%s", column, column))
+		default:
+			updates = append(updates, fmt.Sprintf("%s = EXCLUDED.%s", column, column))
+		}
+	}
+
+	switch dialectName {
+	case db.MYSQL:
+		return fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s) ON DUPLICATE KEY UPDATE %s",
+			tableName, strings.Join(columns, ", "), placeholders, strings.Join(updates, ", "))
+	case db.MSSQL:
+		var sources, sourceColumns []string
+		for i, column := range columns {
+			sources = append(sources, fmt.Sprintf("$%d AS %s", i+1, column))
+			sourceColumns = append(sourceColumns, "source."+column)
+		}
+
+		// HOLDLOCK makes MERGE atomic, otherwise the concurrent upserts of the same id violate the primary key
+		return fmt.Sprintf("MERGE INTO %s WITH (HOLDLOCK) AS target USING (SELECT %s) AS source ON target.id = source.id "+
+			"WHEN MATCHED THEN UPDATE SET %s WHEN NOT MATCHED THEN INSERT (%s) VALUES (%s);",
+			tableName, strings.Join(sources, ", "), strings.Join(updates, ", "), strings.Join(columns, ", "), strings.Join(sourceColumns, ", "))
+	default:
+		return fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s) ON CONFLICT (id) DO UPDATE SET %s",
+			tableName, strings.Join(columns, ", "), placeholders, strings.Join(updates, ", "))
+	}
+}
+
+// upsertPrologueSQL returns the dialect specific statement preparing the transaction to upsert the rows with the explicit ids,
+// empty if the dialect needs none
+func upsertPrologueSQL(dialectName db.DialectName, tableName string) string {
+	if dialectName == db.MSSQL {
+		// explicit values can't be inserted into the identity column by default
+		return fmt.Sprintf("SET IDENTITY_INSERT %s ON", tableName)
+	}
+
+	return ""
+}
+
+// testUpsertGeneric upserts {batch} rows by single transaction, --conflict-rate of them re-use the id of already existing row
+// and are updated, the rest get the new id and are inserted, every upsert attempt is counted as a loop
+func testUpsertGeneric(b *benchmark.Benchmark, testDesc *TestDesc) {
+	var conflictRate = b.TestOpts.(*TestOpts).TestcaseOpts.ConflictRate
+	if conflictRate < 0 || conflictRate > 1 {
+		b.Exit("--conflict-rate must be between 0 and 1")
+	}
+
+	var dialectName = getDBDriver(b)
+	var colConfs = testDesc.table.GetColumnsForInsert(db.WithAutoInc(dialectName))
+
+	var upsertSQL string
+	var lastID int64
+	var initIDs sync.Once
+
+	worker := func(b *benchmark.Benchmark, c *DBConnector, testDesc *TestDesc, batch int) (loops int) {
+		var dbCtx = c.database.Context(context.Background())
+		var session = c.database.Session(dbCtx)
+
+		initIDs.Do(func() {
+			if err := session.QueryRow(fmt.Sprintf("SELECT COALESCE(MAX(id), 0) FROM %s", testDesc.table.TableName)).Scan(&lastID); err != nil {
+				b.Exit("db: cannot get max id value: %v", err)
+			}
+
+			var columns, _ = b.GenFakeData(c.WorkerID, colConfs, false)
+			upsertSQL = formatSQL(upsertSQLTemplate(dialectName, testDesc.table.TableName, append([]string{"id"}, columns...)), dialectName)
+		})
+
+		if txErr := session.Transact(func(tx db.DatabaseAccessor) error {
+			if prologue := upsertPrologueSQL(dialectName, testDesc.table.TableName); prologue != "" {
+				if _, err := tx.Exec(prologue); err != nil {
+					return err
+				}
+			}
+
+			var rw = b.Randomizer.GetWorker(c.WorkerID)
+			for i := 0; i < batch; i++ {
+				var id int64
+				if last := atomic.LoadInt64(&lastID); last > 0 && rw.Seeded().Float64() < conflictRate {
+					id = int64(rw.Uintn64(uint64(last))) + 1
+				} else {
+					id = atomic.AddInt64(&lastID, 1)
+				}
+
+				var _, values = b.GenFakeData(c.WorkerID, colConfs, false)
+				if _, err := tx.Exec(upsertSQL, append([]interface{}{id}, values...)...); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		}); txErr != nil && !isNonFatalError(b, c.WorkerID, txErr) {
+			b.Exit(txErr.Error())
+		}
+		b.AddRetries(dbCtx.TxRetries)
+
+		return batch
+	}
+
+	testGeneric(b, testDesc, worker, 0)
+
+	if dialectName == db.POSTGRES {
+		// the explicitly inserted ids are not taken from the BIGSERIAL sequence, move it forward not to break the following inserts
+		var c = dbConnector(b)
+		defer c.Release()
+
+		var session = c.database.Session(c.database.Context(context.Background()))
+		if _, err := session.Exec(fmt.Sprintf("SELECT setval(pg_get_serial_sequence('%s', 'id'), (SELECT COALESCE(MAX(id), 0) + 1 FROM %s), false)",
+			testDesc.table.TableName, testDesc.table.TableName)); err != nil {
+			b.Exit("db: cannot move '%s' id sequence forward: %v", testDesc.table.TableName, err)
+		}
+	}
+}
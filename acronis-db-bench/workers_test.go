@@ -0,0 +1,45 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/acronis/perfkit/db"
+)
+
+func TestUpsertSQLTemplate(t *testing.T) {
+	var columns = []string{"id", "uuid", "progress"}
+
+	tests := []struct {
+		dialect db.DialectName
+		want    string
+	}{
+		{db.POSTGRES, "INSERT INTO t (id, uuid, progress) VALUES ($1,$2,$3) ON CONFLICT (id) DO UPDATE SET uuid = EXCLUDED.uuid, progress = EXCLUDED.progress"},
+		{db.SQLITE, "INSERT INTO t (id, uuid, progress) VALUES ($1,$2,$3) ON CONFLICT (id) DO UPDATE SET uuid = EXCLUDED.uuid, progress = EXCLUDED.progress"},
+		{db.MYSQL, "INSERT INTO t (id, uuid, progress) VALUES ($1,$2,$3) ON DUPLICATE KEY UPDATE uuid = VALUES(uuid), progress = VALUES(progress)"},
+		{db.MSSQL, "MERGE INTO t WITH (HOLDLOCK) AS target USING (SELECT $1 AS id, $2 AS uuid, $3 AS progress) AS source ON target.id = source.id " +
+			"WHEN MATCHED THEN UPDATE SET uuid = source.uuid, progress = source.progress " +
+			"WHEN NOT MATCHED THEN INSERT (id, uuid, progress) VALUES (source.id, source.uuid, source.progress);"},
+	}
+	for _, tt := range tests {
+		if got := upsertSQLTemplate(tt.dialect, "t", columns); got != tt.want {
+			t.Errorf("upsertSQLTemplate(%s) = %q, want %q", tt.dialect, got, tt.want)
+		}
+	}
+}
+
+func TestUpsertPrologueSQL(t *testing.T) {
+	tests := []struct {
+		dialect db.DialectName
+		want    string
+	}{
+		{db.MSSQL, "SET IDENTITY_INSERT t ON"},
+		{db.POSTGRES, ""},
+		{db.MYSQL, ""},
+		{db.SQLITE, ""},
+	}
+	for _, tt := range tests {
+		if got := upsertPrologueSQL(tt.dialect, "t"); got != tt.want {
+			t.Errorf("upsertPrologueSQL(%s) = %q, want %q", tt.dialect, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package benchmark
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// parseCPUList parses comma separated list of CPU cores and core ranges (e.g. 0,2,4-7)
+func parseCPUList(s string) ([]int, error) {
+	var cpus []int
+
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			return nil, fmt.Errorf("empty CPU in list '%s'", s)
+		}
+
+		var from, to, isRange = strings.Cut(item, "-")
+
+		first, err := strconv.Atoi(from)
+		if err != nil || first < 0 {
+			return nil, fmt.Errorf("bad CPU '%s'", item)
+		}
+
+		var last = first
+		if isRange {
+			if last, err = strconv.Atoi(to); err != nil || last < first {
+				return nil, fmt.Errorf("bad CPU range '%s'", item)
+			}
+		}
+
+		for cpu := first; cpu <= last; cpu++ {
+			cpus = append(cpus, cpu)
+		}
+	}
+
+	return cpus, nil
+}
+
+// WorkerCPU returns the CPU core the worker is pinned to by the --worker-affinity option, false if the worker is not pinned
+func (b *Benchmark) WorkerCPU(workerID int) (int, bool) {
+	if len(b.affinity) == 0 {
+		return 0, false
+	}
+
+	return b.affinity[workerID%len(b.affinity)], true
+}
+
+// AffinityMap returns human-readable worker to CPU core mapping, empty string if --worker-affinity is not set
+func (b *Benchmark) AffinityMap() string {
+	if len(b.affinity) == 0 {
+		return ""
+	}
+
+	var workers = b.CommonOpts.Workers
+	if workers <= 0 {
+		workers = 1
+	}
+
+	var pairs = make([]string, 0, workers)
+	for i := 0; i < workers; i++ {
+		var cpu, _ = b.WorkerCPU(i)
+		pairs = append(pairs, fmt.Sprintf("%d->%d", i, cpu))
+	}
+
+	return strings.Join(pairs, ", ")
+}
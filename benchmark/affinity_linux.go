@@ -0,0 +1,18 @@
+//go:build linux
+// +build linux
+
+package benchmark
+
+import (
+	"golang.org/x/sys/unix"
+)
+
+// setThreadAffinity pins the current OS thread to given CPU core
+func setThreadAffinity(cpu int) error {
+	var set unix.CPUSet
+	set.Zero()
+	set.Set(cpu)
+
+	// pid 0 means the calling thread
+	return unix.SchedSetaffinity(0, &set)
+}
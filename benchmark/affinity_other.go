@@ -0,0 +1,14 @@
+//go:build !linux && !windows
+// +build !linux,!windows
+
+package benchmark
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// setThreadAffinity is not supported on the platform
+func setThreadAffinity(cpu int) error {
+	return fmt.Errorf("CPU %d: worker affinity is not supported on %s", cpu, runtime.GOOS)
+}
@@ -0,0 +1,61 @@
+package benchmark
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseCPUList(t *testing.T) {
+	var cases = []struct {
+		in      string
+		want    []int
+		wantErr bool
+	}{
+		{in: "0", want: []int{0}},
+		{in: "0,2,4,6", want: []int{0, 2, 4, 6}},
+		{in: "0-3,8", want: []int{0, 1, 2, 3, 8}},
+		{in: " 1 , 3 ", want: []int{1, 3}},
+		{in: "", wantErr: true},
+		{in: "1,,2", wantErr: true},
+		{in: "a", wantErr: true},
+		{in: "-1", wantErr: true},
+		{in: "3-1", wantErr: true},
+	}
+
+	for _, c := range cases {
+		var got, err = parseCPUList(c.in)
+		if c.wantErr {
+			if err == nil {
+				t.Errorf("parseCPUList(%q): expected error, got %v", c.in, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("parseCPUList(%q): unexpected error: %v", c.in, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, c.want) {
+			t.Errorf("parseCPUList(%q) = %v, want %v", c.in, got, c.want)
+		}
+	}
+}
+
+func TestWorkerCPU(t *testing.T) {
+	var b = New()
+	b.CommonOpts.Workers = 3
+
+	if _, ok := b.WorkerCPU(0); ok {
+		t.Error("worker must not be pinned without --worker-affinity")
+	}
+	if b.AffinityMap() != "" {
+		t.Error("affinity map must be empty without --worker-affinity")
+	}
+
+	b.affinity = []int{2, 5}
+	if cpu, ok := b.WorkerCPU(2); !ok || cpu != 2 {
+		t.Errorf("WorkerCPU(2) = %d, %v, want 2, true", cpu, ok)
+	}
+	if got, want := b.AffinityMap(), "0->2, 1->5, 2->2"; got != want {
+		t.Errorf("AffinityMap() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,30 @@
+//go:build windows
+// +build windows
+
+package benchmark
+
+import (
+	"fmt"
+
+	"golang.org/x/sys/windows"
+)
+
+var procSetThreadAffinityMask = windows.NewLazySystemDLL("kernel32.dll").NewProc("SetThreadAffinityMask")
+
+// setThreadAffinity pins the current OS thread to given CPU core
+func setThreadAffinity(cpu int) error {
+	if cpu >= 64 {
+		return fmt.Errorf("CPU %d is out of the affinity mask range", cpu)
+	}
+
+	var thread, err = windows.GetCurrentThread()
+	if err != nil {
+		return err
+	}
+
+	if ret, _, callErr := procSetThreadAffinityMask.Call(uintptr(thread), uintptr(1)<<uint(cpu)); ret == 0 {
+		return callErr
+	}
+
+	return nil
+}
@@ -2,10 +2,14 @@ package benchmark
 
 import (
 	"fmt"
+	"io"
 	"math"
 	"os"
 	"os/signal"
+	"runtime"
+	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -25,6 +29,58 @@ type Score struct {
 	Loops   uint64
 	Rate    float64
 	Metric  string
+	Retries uint64
+	Errors  uint64
+
+	BytesProcessed int64 // bytes written / read by the workers, see Benchmark.AddBytes
+
+	ConstraintViolationRate float64 // rejected by unique constraints operations per second, see Benchmark.AddConstraintViolations
+
+	Latencies  []time.Duration // sorted Worker call latencies, set only if Benchmark.CollectLatencies is enabled, see Percentile
+	LatencyAvg time.Duration   // mean Worker call latency, set only if Benchmark.CollectLatencies is enabled
+	P50        time.Duration   // median Worker call latency, set only if Benchmark.CollectLatencies is enabled
+	P95        time.Duration   // 95th percentile Worker call latency, set only if Benchmark.CollectLatencies is enabled
+	P99        time.Duration   // 99th percentile Worker call latency, set only if Benchmark.CollectLatencies is enabled
+	P999       time.Duration   // 99.9th percentile Worker call latency, set only if Benchmark.CollectLatencies is enabled
+
+	WarmupSeconds float64 // time the workers were ramping up and warming up before the measurement, see CommonOpts.WarmupDuration
+
+	RampupStart time.Time // time the first worker started, set only if Benchmark.Rampup is set
+	RampupEnd   time.Time // time the last worker started, set only if Benchmark.Rampup is set
+
+	AllocsPerOp float64 // heap allocations per loop, set only if CommonOpts.TraceMemory is enabled
+	BytesPerOp  float64 // heap bytes allocated per loop, set only if CommonOpts.TraceMemory is enabled
+}
+
+// ErrorRate returns the percentage of loops resulted in errors
+func (s *Score) ErrorRate() float64 {
+	if s.Loops == 0 {
+		return 0
+	}
+
+	return float64(s.Errors) * 100 / float64(s.Loops)
+}
+
+// Percentile returns the pct-th percentile of the Worker call latencies linearly interpolated between the closest ranks,
+// 0 if the latencies were not collected
+func (s *Score) Percentile(pct float64) time.Duration {
+	if len(s.Latencies) == 0 {
+		return 0
+	}
+
+	var rank = math.Max(0, math.Min(pct, 100)) / 100 * float64(len(s.Latencies)-1)
+	var lower, upper = int(math.Floor(rank)), int(math.Ceil(rank))
+
+	return s.Latencies[lower] + time.Duration(float64(s.Latencies[upper]-s.Latencies[lower])*(rank-float64(lower)))
+}
+
+// FormatPercentiles formats the average and the percentile Worker call latencies in milliseconds
+func (s *Score) FormatPercentiles() string {
+	var ms = func(d time.Duration) float64 {
+		return float64(d) / float64(time.Millisecond)
+	}
+
+	return fmt.Sprintf("avg: %.3f ms; p50: %.3f ms; p95: %.3f ms; p99: %.3f ms; p99.9: %.3f ms", ms(s.LatencyAvg), ms(s.P50), ms(s.P95), ms(s.P99), ms(s.P999))
 }
 
 // FormatRate formats rate to 4 significant figures
@@ -48,9 +104,89 @@ func (s *Score) FormatRate(n int) string { //nolint:revive
 	return fmt.Sprintf(format, s.Rate)
 }
 
+// Throughput returns the number of bytes processed per second
+func (s *Score) Throughput() float64 {
+	if s.Seconds == 0 {
+		return 0
+	}
+
+	return float64(s.BytesProcessed) / s.Seconds
+}
+
+// FormatThroughput formats throughput as MB/s or GB/s
+func (s *Score) FormatThroughput() string {
+	const (
+		mb = 1 << 20
+		gb = 1 << 30
+	)
+
+	var throughput = s.Throughput()
+	if throughput >= gb {
+		return fmt.Sprintf("%.2f GB/s", throughput/gb)
+	}
+
+	return fmt.Sprintf("%.2f MB/s", throughput/mb)
+}
+
+// ScoreJSON is the canonical JSON serialization of the Score, the durations are in fractional seconds and milliseconds
+type ScoreJSON struct {
+	Metric      string  `json:"metric"`
+	RatePerSec  float64 `json:"rate_per_sec"`
+	Workers     int     `json:"workers"`
+	Loops       uint64  `json:"loops"`
+	DurationSec float64 `json:"duration_sec"`
+	WarmupSec   float64 `json:"warmup_sec,omitempty"`
+	Retries     uint64  `json:"retries"`
+	Errors      uint64  `json:"errors"`
+
+	BytesProcessed          int64   `json:"bytes_processed,omitempty"`
+	ConstraintViolationRate float64 `json:"constraint_violation_rate,omitempty"`
+
+	LatencyAvgMs float64 `json:"latency_avg_ms,omitempty"`
+	P50Ms        float64 `json:"latency_p50_ms,omitempty"`
+	P95Ms        float64 `json:"latency_p95_ms,omitempty"`
+	P99Ms        float64 `json:"latency_p99_ms,omitempty"`
+	P999Ms       float64 `json:"latency_p999_ms,omitempty"`
+
+	AllocsPerOp float64 `json:"allocs_per_op,omitempty"`
+	BytesPerOp  float64 `json:"bytes_per_op,omitempty"`
+}
+
+// JSON returns the ScoreJSON of the score
+func (s *Score) JSON() ScoreJSON {
+	var ms = func(d time.Duration) float64 {
+		return float64(d) / float64(time.Millisecond)
+	}
+
+	return ScoreJSON{
+		Metric:      s.Metric,
+		RatePerSec:  s.Rate,
+		Workers:     s.Workers,
+		Loops:       s.Loops,
+		DurationSec: s.Seconds,
+		WarmupSec:   s.WarmupSeconds,
+		Retries:     s.Retries,
+		Errors:      s.Errors,
+
+		BytesProcessed:          s.BytesProcessed,
+		ConstraintViolationRate: s.ConstraintViolationRate,
+
+		LatencyAvgMs: ms(s.LatencyAvg),
+		P50Ms:        ms(s.P50),
+		P95Ms:        ms(s.P95),
+		P99Ms:        ms(s.P99),
+		P999Ms:       ms(s.P999),
+
+		AllocsPerOp: s.AllocsPerOp,
+		BytesPerOp:  s.BytesPerOp,
+	}
+}
+
 // Benchmark is used for running tests
 // Init is called once before InitPerWorker and should initialize program constants, global variables, etc.
 // InitPerWorker is called Benchmark.CommonOpts.Workers times and should initialize data structs required for running Worker method
+// PreMeasure is called every time the score counters are reset before the measured loops (i.e. after the warmup) and should reset
+// the counters the test accounts along with the score
 // Worker runs user logic and should use opts.WorkerData[id] and opts.Vault
 // FinishPerWorker is called Benchmark.CommonOpts.Workers times and should deinit all WorkerData structs
 // Finish is called once after FinishPerWorker and should call some logic(e.g. analyze data) and deinit used data structs
@@ -59,6 +195,7 @@ type Benchmark struct {
 	Init            func()
 	InitPerWorker   func(id int)
 	PreWorker       func(id int)
+	PreMeasure      func()
 	Worker          func(id int) (loops int)
 	FinishPerWorker func(id int)
 	Finish          func()
@@ -73,9 +210,26 @@ type Benchmark struct {
 	ReadOnly        bool
 	Logger          *Logger
 	Randomizer      *Randomizer
+	Faker           *FakerGenerator // Faker generates the realistic values of the columns it supports instead of the random ones if set
+
+	Deterministic *DeterministicGenerator // Deterministic makes the generated rows depend only on the seed, the worker and the row index if set
+
+	CollectLatencies bool // CollectLatencies enables the Worker call latency percentiles in the Score
+
+	FatalOutput io.Writer // FatalOutput receives the Exit error messages, os.Stdout is used if not set
+
+	Rampup time.Duration // Rampup starts the workers one by one evenly over given duration, the ramp-up loops are not accounted in the Score
 
 	NeedToExit bool
 	Score      Score
+	Repetition int // Repetition is the zero-based index of the current --repeat run
+	affinity   []int
+	retries    uint64
+	errors     uint64
+	doneLoops  uint64
+	bytes      int64
+
+	constraintViolations uint64
 
 	CliArgs    []string
 	WorkerData []WorkerData
@@ -96,6 +250,8 @@ func New() *Benchmark {
 		},
 		PreWorker: func(id int) { //nolint:revive
 		},
+		PreMeasure: func() {
+		},
 		Worker: func(id int) (loops int) { //nolint:revive
 			return 0
 		},
@@ -112,7 +268,29 @@ func New() *Benchmark {
 			return float64(loops) / seconds
 		},
 		PrintScore: func(score Score) {
-			fmt.Printf("time: %f sec; threads: %d; loops: %d; rate: %.2f %s;\n", score.Seconds, score.Workers, score.Loops, score.Rate, score.Metric)
+			fmt.Printf("time: %f sec; threads: %d; loops: %d; rate: %.2f %s;", score.Seconds, score.Workers, score.Loops, score.Rate, score.Metric)
+			if score.WarmupSeconds > 0 {
+				fmt.Printf(" warmup: %.1f sec;", score.WarmupSeconds)
+			}
+			if score.Retries > 0 {
+				fmt.Printf(" retries: %d;", score.Retries)
+			}
+			if score.Errors > 0 {
+				fmt.Printf(" errors: %d; error rate: %.2f%%;", score.Errors, score.ErrorRate())
+			}
+			if score.BytesProcessed > 0 {
+				fmt.Printf(" throughput: %s;", score.FormatThroughput())
+			}
+			if score.ConstraintViolationRate > 0 {
+				fmt.Printf(" constraint violations: %.2f/sec;", score.ConstraintViolationRate)
+			}
+			if score.AllocsPerOp > 0 || score.BytesPerOp > 0 {
+				fmt.Printf(" allocs/op: %.1f; bytes/op: %.0f;", score.AllocsPerOp, score.BytesPerOp)
+			}
+			if len(score.Latencies) > 0 {
+				fmt.Printf(" %s;", score.FormatPercentiles())
+			}
+			fmt.Printf("\n")
 		},
 		OptsInitialized: false,
 	}
@@ -148,6 +326,25 @@ func (b *Benchmark) InitOpts() {
 		b.Logger = NewLogger(len(b.CommonOpts.Verbose) + 1)
 	}
 	b.adjustFilenoUlimit()
+
+	if b.CommonOpts.WarmupLoops < 0 {
+		b.Exit("--warmup-loops must not be negative")
+	}
+
+	if b.CommonOpts.WarmupDuration < 0 {
+		b.Exit("--warmup must not be negative")
+	}
+
+	if b.CommonOpts.WarmupLoops > 0 && b.CommonOpts.WarmupDuration > 0 {
+		b.Exit("--warmup-loops and --warmup are mutually exclusive")
+	}
+
+	if b.CommonOpts.WorkerAffinity != "" {
+		var err error
+		if b.affinity, err = parseCPUList(b.CommonOpts.WorkerAffinity); err != nil {
+			b.Exit("bad --worker-affinity value: %v", err)
+		}
+	}
 }
 
 // SetUsage sets usage information
@@ -157,32 +354,64 @@ func (b *Benchmark) SetUsage(usage string) {
 
 // RunOnce runs the test once and prints the score
 func (b *Benchmark) RunOnce(printScore bool) {
-	var requiredLoops = make([]int, b.CommonOpts.Workers)
-
-	if b.CommonOpts.Loops != 0 {
-		l := b.CommonOpts.Loops / b.CommonOpts.Workers
-		rest := b.CommonOpts.Loops % b.CommonOpts.Workers
-		for i := 0; i < b.CommonOpts.Workers; i++ {
-			requiredLoops[i] = l
-			if i < rest {
-				requiredLoops[i]++
-			}
-		}
+	if b.CommonOpts.WarmupLoops > 0 {
+		b.warmup()
 	}
 
-	var wg sync.WaitGroup
-	wg.Add(b.CommonOpts.Workers)
+	var requiredLoops = splitLoops(b.CommonOpts.Loops, b.CommonOpts.Workers)
 
 	loops := make([]int, b.CommonOpts.Workers)
+	latencies := make([][]time.Duration, b.CommonOpts.Workers)
+	b.resetCounters()
+
+	var warmup *warmupPhase
+	if b.CommonOpts.WarmupDuration > 0 || b.Rampup > 0 {
+		warmup = newWarmupPhase(b.CommonOpts.Workers, b.Rampup, time.Duration(b.CommonOpts.WarmupDuration)*time.Second)
+	}
+
+	var pool = NewWorkerPool(b.CommonOpts.Workers, func(workerID int) {
+		runner(workerID, b, &loops[workerID], &latencies[workerID], requiredLoops[workerID], warmup)
+	})
+
+	// the memory stats are process wide, so they are read around the whole run rather than every Worker call,
+	// ReadMemStats stops the world and would skew the rate otherwise
+	var memBefore, memAfter runtime.MemStats
+	if warmup == nil && b.CommonOpts.TraceMemory {
+		runtime.ReadMemStats(&memBefore)
+	}
 
 	startTime := time.Now().UnixNano()
-	for i := 0; i < b.CommonOpts.Workers; i++ {
-		go runner(i, b, &loops[i], requiredLoops[i], &wg)
+	pool.Start()
+
+	b.Score.WarmupSeconds = 0
+	b.Score.RampupStart, b.Score.RampupEnd = time.Time{}, time.Time{}
+	if warmup != nil {
+		// the workers are kept waiting till the counters are reset, so the warmup loops don't leak into the score
+		warmup.done.Wait()
+		b.resetCounters()
+		b.Score.WarmupSeconds = float64(time.Now().UnixNano()-startTime) / float64(time.Second)
+		if b.Rampup > 0 {
+			b.Score.RampupStart, b.Score.RampupEnd = warmup.firstStarted, warmup.lastStarted
+		}
+		b.Log(LogDebug, 0, "warmed up in %.1f sec", b.Score.WarmupSeconds)
+
+		// the workers are still waiting, so none of the measured loops allocations are missed
+		if b.CommonOpts.TraceMemory {
+			runtime.ReadMemStats(&memBefore)
+		}
+
+		startTime = time.Now().UnixNano()
+		close(warmup.measure)
 	}
-	wg.Wait()
+
+	pool.Wait()
 
 	endTime := time.Now().UnixNano()
 
+	if b.CommonOpts.TraceMemory {
+		runtime.ReadMemStats(&memAfter)
+	}
+
 	var totalLoops uint64
 	for _, loop := range loops {
 		totalLoops += uint64(loop)
@@ -197,12 +426,179 @@ func (b *Benchmark) RunOnce(printScore bool) {
 	b.Score.Metric = b.Metric()
 	b.Score.Workers = b.CommonOpts.Workers
 	b.Score.Loops = totalLoops
+	b.Score.Retries = atomic.LoadUint64(&b.retries)
+	b.Score.Errors = atomic.LoadUint64(&b.errors)
+	b.Score.BytesProcessed = atomic.LoadInt64(&b.bytes)
+	b.Score.ConstraintViolationRate = float64(atomic.LoadUint64(&b.constraintViolations)) / b.Score.Seconds
+	b.Score.Latencies = nil
+	b.Score.LatencyAvg, b.Score.P50, b.Score.P95, b.Score.P99, b.Score.P999 = 0, 0, 0, 0, 0
+	b.Score.AllocsPerOp, b.Score.BytesPerOp = 0, 0
+
+	if b.CommonOpts.TraceMemory {
+		b.Score.AllocsPerOp = float64(memAfter.Mallocs-memBefore.Mallocs) / float64(totalLoops)
+		b.Score.BytesPerOp = float64(memAfter.TotalAlloc-memBefore.TotalAlloc) / float64(totalLoops)
+	}
+
+	if b.CollectLatencies {
+		var all []time.Duration
+		for _, l := range latencies {
+			all = append(all, l...)
+		}
+		sort.Slice(all, func(i, j int) bool { return all[i] < all[j] })
+
+		b.Score.Latencies = all
+		b.Score.LatencyAvg = mean(all)
+		b.Score.P50 = b.Score.Percentile(50)
+		b.Score.P95 = b.Score.Percentile(95)
+		b.Score.P99 = b.Score.Percentile(99)
+		b.Score.P999 = b.Score.Percentile(99.9)
+	}
 
 	if printScore {
 		b.PrintScore(b.Score)
 	}
 }
 
+// resetCounters resets the score counters accumulated by the workers and calls PreMeasure to reset the test own ones
+func (b *Benchmark) resetCounters() {
+	atomic.StoreUint64(&b.retries, 0)
+	atomic.StoreUint64(&b.errors, 0)
+	atomic.StoreUint64(&b.doneLoops, 0)
+	atomic.StoreInt64(&b.bytes, 0)
+	atomic.StoreUint64(&b.constraintViolations, 0)
+	b.PreMeasure()
+}
+
+// warmupPhase starts the workers one by one over the ramp-up and keeps them running without accounting their loops
+// until the warmup deadline, then all the workers start the measurement at once
+type warmupPhase struct {
+	start   time.Time
+	step    time.Duration // delay between the workers start
+	until   time.Time
+	done    sync.WaitGroup
+	measure chan struct{}
+
+	lock         sync.Mutex
+	firstStarted time.Time
+	lastStarted  time.Time
+}
+
+// newWarmupPhase creates the warmup phase of given number of workers started over the rampup and running for the warmup after that
+func newWarmupPhase(workers int, rampup time.Duration, warmup time.Duration) *warmupPhase {
+	var now = time.Now()
+	var w = &warmupPhase{start: now, step: rampup / time.Duration(workers), until: now.Add(rampup + warmup), measure: make(chan struct{})}
+	w.done.Add(workers)
+
+	return w
+}
+
+// run delays the worker start by its ramp-up step, calls the Worker until the warmup deadline and waits for the measurement start
+func (w *warmupPhase) run(b *Benchmark, id int) {
+	time.Sleep(time.Until(w.start.Add(time.Duration(id) * w.step)))
+	w.started(time.Now())
+
+	for time.Now().Before(w.until) && !b.NeedToExit {
+		b.PreWorker(id)
+		if b.Worker(id) == 0 {
+			break
+		}
+	}
+
+	w.done.Done()
+	<-w.measure
+}
+
+// started records the worker start time
+func (w *warmupPhase) started(t time.Time) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	if w.firstStarted.IsZero() || t.Before(w.firstStarted) {
+		w.firstStarted = t
+	}
+	if t.After(w.lastStarted) {
+		w.lastStarted = t
+	}
+}
+
+// splitLoops splits the TOTAL number of loops among the workers
+func splitLoops(total int, workers int) []int {
+	var requiredLoops = make([]int, workers)
+
+	if total != 0 {
+		l := total / workers
+		rest := total % workers
+		for i := 0; i < workers; i++ {
+			requiredLoops[i] = l
+			if i < rest {
+				requiredLoops[i]++
+			}
+		}
+	}
+
+	return requiredLoops
+}
+
+// warmup runs CommonOpts.WarmupLoops iterations of the Worker split among the workers, the counters are reset by RunOnce afterwards
+func (b *Benchmark) warmup() {
+	var requiredLoops = splitLoops(b.CommonOpts.WarmupLoops, b.CommonOpts.Workers)
+
+	var pool = NewWorkerPool(b.CommonOpts.Workers, func(workerID int) {
+		for doneLoops := 0; doneLoops < requiredLoops[workerID] && !b.NeedToExit; {
+			b.PreWorker(workerID)
+			var l = b.Worker(workerID)
+			if l == 0 {
+				break
+			}
+			doneLoops += l
+		}
+	})
+
+	b.Log(LogDebug, 0, "warming up by %d loops", b.CommonOpts.WarmupLoops)
+	pool.Start()
+	pool.Wait()
+}
+
+// AddRetries accounts given number of retried operations (e.g. transactions retried on deadlock) in the score
+func (b *Benchmark) AddRetries(n int) {
+	if n > 0 {
+		atomic.AddUint64(&b.retries, uint64(n))
+	}
+}
+
+// AddErrors accounts given number of non-fatal errors (the worker continues) in the score and returns the total errors count
+func (b *Benchmark) AddErrors(n int) uint64 {
+	if n > 0 {
+		return atomic.AddUint64(&b.errors, uint64(n))
+	}
+
+	return atomic.LoadUint64(&b.errors)
+}
+
+// AddBytes accounts given number of bytes written or read by the worker (e.g. blob sizes) in the score throughput
+func (b *Benchmark) AddBytes(n int64) {
+	if n > 0 {
+		atomic.AddInt64(&b.bytes, n)
+	}
+}
+
+// AddConstraintViolations accounts given number of operations rejected by unique constraints (e.g. duplicate key inserts) in the score
+func (b *Benchmark) AddConstraintViolations(n int) {
+	if n > 0 {
+		atomic.AddUint64(&b.constraintViolations, uint64(n))
+	}
+}
+
+// ConstraintViolations returns the number of operations rejected by unique constraints so far in the current run
+func (b *Benchmark) ConstraintViolations() uint64 {
+	return atomic.LoadUint64(&b.constraintViolations)
+}
+
+// DoneLoops returns the number of loops done by all the workers so far in the current run
+func (b *Benchmark) DoneLoops() uint64 {
+	return atomic.LoadUint64(&b.doneLoops)
+}
+
 // Run runs the test and prints the score (if repeat is 1) or the average, min and max scores (if repeat is > 1)
 func (b *Benchmark) Run() {
 	b.InitOpts()
@@ -220,6 +616,16 @@ func (b *Benchmark) Run() {
 	}()
 
 	b.Randomizer = NewRandomizer(b.CommonOpts.RandSeed, b.CommonOpts.Workers)
+	if b.CommonOpts.RandSeedFile != "" {
+		var data, err = NewRandomizerSeedFileLoader(b.CommonOpts.RandSeedFile).Load()
+		if err != nil {
+			b.Exit("cannot load --randomizer-seed-file: %v", err)
+		}
+		b.Randomizer.SetSeedData(data)
+	}
+	if b.CommonOpts.DeterministicData {
+		b.Deterministic = NewDeterministicGenerator(b.CommonOpts.RandSeed)
+	}
 	b.Init()
 
 	b.WorkerData = make([]WorkerData, b.CommonOpts.Workers)
@@ -238,6 +644,7 @@ func (b *Benchmark) Run() {
 	sumRate = 0
 
 	for r := 0; r < b.CommonOpts.Repeat; r++ {
+		b.Repetition = r
 		b.RunOnce(r != b.CommonOpts.Repeat-1)
 		if minRate == -1 || minRate > b.Score.Rate {
 			minRate = b.Score.Rate
@@ -266,18 +673,60 @@ func (b *Benchmark) Run() {
 	}
 }
 
+// mean returns the average of the durations, 0 if there are no durations
+func mean(durations []time.Duration) time.Duration {
+	if len(durations) == 0 {
+		return 0
+	}
+
+	var sum time.Duration
+	for _, d := range durations {
+		sum += d
+	}
+
+	return sum / time.Duration(len(durations))
+}
+
+// callWorker calls the Worker and records the call latency if CollectLatencies is enabled
+func (b *Benchmark) callWorker(id int, latencies *[]time.Duration) int {
+	if !b.CollectLatencies {
+		return b.Worker(id)
+	}
+
+	var start = time.Now()
+	var l = b.Worker(id)
+	*latencies = append(*latencies, time.Since(start))
+
+	return l
+}
+
 // runner is a helper function for running tests in parallel
-func runner(id int, b *Benchmark, loops *int, requiredLoops int, wg *sync.WaitGroup) {
+func runner(id int, b *Benchmark, loops *int, latencies *[]time.Duration, requiredLoops int, warmup *warmupPhase) {
+	if cpu, ok := b.WorkerCPU(id); ok {
+		// the affinity is set for the OS thread, so the goroutine must not migrate to another thread
+		runtime.LockOSThread()
+		defer runtime.UnlockOSThread()
+
+		if err := setThreadAffinity(cpu); err != nil {
+			b.Log(LogError, id, fmt.Sprintf("cannot pin worker to CPU %d: %v", cpu, err))
+		}
+	}
+
+	if warmup != nil {
+		warmup.run(b, id)
+	}
+
 	var l int
 	doneLoops := 0
 	if b.CommonOpts.Loops != 0 {
 		for doneLoops < requiredLoops {
 			b.PreWorker(id)
-			l = b.Worker(id)
+			l = b.callWorker(id, latencies)
 			if l == 0 {
 				break
 			}
 			doneLoops += l
+			atomic.AddUint64(&b.doneLoops, uint64(l))
 
 			if b.NeedToExit {
 				break
@@ -291,11 +740,12 @@ func runner(id int, b *Benchmark, loops *int, requiredLoops int, wg *sync.WaitGr
 		startTime := time.Now().UnixNano()
 		for time.Now().UnixNano()-startTime < int64(b.CommonOpts.Duration*1000000000) {
 			b.PreWorker(id)
-			l = b.Worker(id)
+			l = b.callWorker(id, latencies)
 			if l == 0 {
 				break
 			}
 			doneLoops += l
+			atomic.AddUint64(&b.doneLoops, uint64(l))
 
 			if b.NeedToExit {
 				break
@@ -308,8 +758,6 @@ func runner(id int, b *Benchmark, loops *int, requiredLoops int, wg *sync.WaitGr
 	}
 
 	*loops = doneLoops
-
-	wg.Done()
 }
 
 // Exit calls os.Exit() and sets 127 exit code if there is a message (+ args) passed, otherwise just exit with 0 (successfull exit)
@@ -319,10 +767,15 @@ func (b *Benchmark) Exit(fmtAndArgs ...interface{}) {
 		os.Exit(0)
 	}
 
+	var out io.Writer = os.Stdout
+	if b.FatalOutput != nil {
+		out = b.FatalOutput
+	}
+
 	// Assume the first argument, if present, is the format string
 	fmtStr, ok := fmtAndArgs[0].(string)
 	if !ok {
-		fmt.Println("First argument must be a format string.")
+		fmt.Fprintln(out, "First argument must be a format string.")
 		b.PreExit()
 		os.Exit(127)
 	}
@@ -330,13 +783,13 @@ func (b *Benchmark) Exit(fmtAndArgs ...interface{}) {
 	// If there are more arguments, use them with fmt.Printf
 	if len(fmtAndArgs) > 1 {
 		args := fmtAndArgs[1:]
-		fmt.Printf(fmtStr, args...)
+		fmt.Fprintf(out, fmtStr, args...)
 	} else {
 		// If fmtStr is the only argument, just print it
-		fmt.Print(fmtStr)
+		fmt.Fprint(out, fmtStr)
 	}
 
-	fmt.Println()
+	fmt.Fprintln(out)
 	b.PreExit()
 	os.Exit(127)
 }
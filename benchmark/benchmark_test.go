@@ -1,7 +1,10 @@
 package benchmark
 
 import (
+	"encoding/json"
 	"os"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -26,6 +29,124 @@ func TestRunOnce(t *testing.T) {
 	}
 }
 
+func TestRunOnceRetries(t *testing.T) {
+	b := New()
+	b.CommonOpts.Workers = 2
+	b.CommonOpts.Loops = 4
+	b.Worker = func(id int) (loops int) { //nolint:revive
+		b.AddRetries(1)
+		return 1
+	}
+	b.RunOnce(false)
+	if b.Score.Retries != 4 {
+		t.Errorf("RunOnce() error, retries = %v, want %v", b.Score.Retries, 4)
+	}
+
+	b.Worker = func(id int) (loops int) { //nolint:revive
+		return 1
+	}
+	b.RunOnce(false)
+	if b.Score.Retries != 0 {
+		t.Errorf("RunOnce() error, retries = %v, want %v", b.Score.Retries, 0)
+	}
+}
+
+func TestRunOnceWarmupLoops(t *testing.T) {
+	b := New()
+	b.CommonOpts.Workers = 2
+	b.CommonOpts.Loops = 4
+	b.CommonOpts.WarmupLoops = 6
+
+	var calls int64
+	b.Worker = func(id int) (loops int) { //nolint:revive
+		atomic.AddInt64(&calls, 1)
+		b.AddRetries(1)
+		return 1
+	}
+	b.RunOnce(false)
+	if calls != 10 {
+		t.Errorf("RunOnce() error, worker calls = %v, want %v", calls, 10)
+	}
+	if b.Score.Loops != 4 || b.Score.Retries != 4 {
+		t.Errorf("RunOnce() error, loops = %v, retries = %v, want %v and %v", b.Score.Loops, b.Score.Retries, 4, 4)
+	}
+}
+
+func TestRunOnceWarmupDuration(t *testing.T) {
+	b := New()
+	b.CommonOpts.Workers = 2
+	b.CommonOpts.Duration = 1
+	b.CommonOpts.WarmupDuration = 1
+
+	var calls, measured int64
+	b.PreMeasure = func() {
+		atomic.StoreInt64(&measured, 0)
+	}
+	b.Worker = func(id int) (loops int) { //nolint:revive
+		atomic.AddInt64(&calls, 1)
+		atomic.AddInt64(&measured, 1)
+		b.AddErrors(1)
+		time.Sleep(time.Millisecond)
+		return 1
+	}
+	b.RunOnce(false)
+	if b.Score.WarmupSeconds < 1 || b.Score.Seconds < 1 || b.Score.Seconds > 1.5 {
+		t.Errorf("RunOnce() error, warmup = %v sec, measurement = %v sec", b.Score.WarmupSeconds, b.Score.Seconds)
+	}
+	if b.Score.Loops == 0 || b.Score.Loops >= uint64(calls) || b.Score.Errors != b.Score.Loops {
+		t.Errorf("RunOnce() error, loops = %v, errors = %v, worker calls = %v", b.Score.Loops, b.Score.Errors, calls)
+	}
+	if uint64(measured) != b.Score.Loops {
+		t.Errorf("RunOnce() error, loops = %v, worker calls after PreMeasure = %v", b.Score.Loops, measured)
+	}
+}
+
+func TestRunOnceRampup(t *testing.T) {
+	b := New()
+	b.CommonOpts.Workers = 4
+	b.CommonOpts.Loops = 4
+	b.Rampup = 200 * time.Millisecond
+
+	var calls int64
+	b.Worker = func(id int) (loops int) { //nolint:revive
+		atomic.AddInt64(&calls, 1)
+		time.Sleep(time.Millisecond)
+		return 1
+	}
+	b.RunOnce(false)
+	if b.Score.Loops != 4 || calls <= 4 {
+		t.Errorf("RunOnce() error, loops = %v, worker calls = %v", b.Score.Loops, calls)
+	}
+	if ramp := b.Score.RampupEnd.Sub(b.Score.RampupStart); ramp < 150*time.Millisecond || ramp >= b.Rampup {
+		t.Errorf("RunOnce() error, the last worker started %v after the first one", ramp)
+	}
+	if b.Score.WarmupSeconds < b.Rampup.Seconds() {
+		t.Errorf("RunOnce() error, ramp-up = %v sec, want >= %v sec", b.Score.WarmupSeconds, b.Rampup.Seconds())
+	}
+}
+
+func TestRunOnceErrors(t *testing.T) {
+	b := New()
+	b.CommonOpts.Workers = 2
+	b.CommonOpts.Loops = 4
+	b.Worker = func(id int) (loops int) {
+		if id == 0 {
+			b.AddErrors(1)
+		}
+		return 1
+	}
+	b.RunOnce(false)
+	if b.Score.Errors != 2 {
+		t.Errorf("RunOnce() error, errors = %v, want %v", b.Score.Errors, 2)
+	}
+	if b.Score.ErrorRate() != 50 {
+		t.Errorf("ErrorRate() error, rate = %v, want %v", b.Score.ErrorRate(), 50)
+	}
+	if b.DoneLoops() != 4 {
+		t.Errorf("DoneLoops() error, loops = %v, want %v", b.DoneLoops(), 4)
+	}
+}
+
 func TestFormatRateWithZeroRate(t *testing.T) {
 	score := Score{Rate: 0.0}
 	result := score.FormatRate(4)
@@ -50,6 +171,152 @@ func TestFormatRateWithLargeRate(t *testing.T) {
 	}
 }
 
+func TestThroughput(t *testing.T) {
+	score := Score{Seconds: 2, BytesProcessed: 3 << 20}
+	if score.Throughput() != 1.5*(1<<20) {
+		t.Errorf("Throughput() error, expected %v, got %v", 1.5*(1<<20), score.Throughput())
+	}
+	if result := score.FormatThroughput(); result != "1.50 MB/s" {
+		t.Errorf("FormatThroughput() error, expected '1.50 MB/s', got '%s'", result)
+	}
+
+	score = Score{Seconds: 1, BytesProcessed: 5 << 30}
+	if result := score.FormatThroughput(); result != "5.00 GB/s" {
+		t.Errorf("FormatThroughput() error, expected '5.00 GB/s', got '%s'", result)
+	}
+
+	score = Score{}
+	if score.Throughput() != 0 {
+		t.Errorf("Throughput() error, expected 0, got %v", score.Throughput())
+	}
+}
+
+func TestAddBytes(t *testing.T) {
+	b := New()
+	b.CommonOpts.Workers = 2
+	b.CommonOpts.Loops = 4
+	b.Worker = func(id int) (loops int) { //nolint:revive
+		b.AddBytes(10)
+		return 1
+	}
+	b.RunOnce(false)
+	if b.Score.BytesProcessed != 40 {
+		t.Errorf("RunOnce() error, bytes processed = %v, want %v", b.Score.BytesProcessed, 40)
+	}
+}
+
+func TestAddConstraintViolations(t *testing.T) {
+	b := New()
+	b.CommonOpts.Workers = 2
+	b.CommonOpts.Loops = 4
+	b.Worker = func(id int) (loops int) { //nolint:revive
+		b.AddConstraintViolations(1)
+		return 1
+	}
+	b.RunOnce(false)
+	if b.ConstraintViolations() != 4 {
+		t.Errorf("RunOnce() error, constraint violations = %v, want %v", b.ConstraintViolations(), 4)
+	}
+	if want := 4 / b.Score.Seconds; b.Score.ConstraintViolationRate != want {
+		t.Errorf("RunOnce() error, constraint violation rate = %v, want %v", b.Score.ConstraintViolationRate, want)
+	}
+}
+
+func TestTraceMemory(t *testing.T) {
+	b := New()
+	b.CommonOpts.Workers = 2
+	b.CommonOpts.Loops = 100
+	b.CommonOpts.TraceMemory = true
+
+	var sink [][]byte
+	var lock sync.Mutex
+	b.Worker = func(id int) (loops int) { //nolint:revive
+		lock.Lock()
+		sink = append(sink, make([]byte, 1024))
+		lock.Unlock()
+		return 1
+	}
+	b.RunOnce(false)
+	if b.Score.AllocsPerOp < 1 {
+		t.Errorf("RunOnce() error, allocs per op = %v, want >= 1", b.Score.AllocsPerOp)
+	}
+	if b.Score.BytesPerOp < 1024 {
+		t.Errorf("RunOnce() error, bytes per op = %v, want >= 1024", b.Score.BytesPerOp)
+	}
+
+	b.CommonOpts.TraceMemory = false
+	b.RunOnce(false)
+	if b.Score.AllocsPerOp != 0 || b.Score.BytesPerOp != 0 {
+		t.Errorf("RunOnce() error, allocs per op = %v, bytes per op = %v without trace memory, want 0", b.Score.AllocsPerOp, b.Score.BytesPerOp)
+	}
+}
+
+func TestCollectLatencies(t *testing.T) {
+	b := New()
+	b.CommonOpts.Workers = 2
+	b.CommonOpts.Loops = 4
+	b.Worker = func(id int) (loops int) { //nolint:revive
+		return 1
+	}
+	b.RunOnce(false)
+	if b.Score.P50 != 0 || b.Score.P99 != 0 {
+		t.Errorf("RunOnce() error, latencies must not be collected by default")
+	}
+
+	b.CollectLatencies = true
+	b.Worker = func(id int) (loops int) {
+		time.Sleep(time.Duration(id+1) * time.Millisecond)
+		return 1
+	}
+	b.RunOnce(false)
+	if b.Score.P50 < time.Millisecond || b.Score.P99 < 2*time.Millisecond {
+		t.Errorf("RunOnce() error, p50 = %v, p99 = %v", b.Score.P50, b.Score.P99)
+	}
+	if len(b.Score.Latencies) != 4 || b.Score.P95 < b.Score.P50 || b.Score.P999 < b.Score.P99 {
+		t.Errorf("RunOnce() error, latencies = %v, p95 = %v, p99.9 = %v", b.Score.Latencies, b.Score.P95, b.Score.P999)
+	}
+	if len(b.Score.Latencies) == 4 && (b.Score.LatencyAvg < b.Score.Latencies[0] || b.Score.LatencyAvg > b.Score.Latencies[3]) {
+		t.Errorf("RunOnce() error, avg = %v, latencies = %v", b.Score.LatencyAvg, b.Score.Latencies)
+	}
+}
+
+func TestScoreJSON(t *testing.T) {
+	var s = Score{Workers: 2, Seconds: 1.5, Loops: 30, Rate: 20, Metric: "loops/sec", P99: 1500 * time.Microsecond}
+
+	var content, err = json.Marshal(s.JSON())
+	if err != nil {
+		t.Fatalf("json.Marshal() error: %v", err)
+	}
+
+	var want = `{"metric":"loops/sec","rate_per_sec":20,"workers":2,"loops":30,"duration_sec":1.5,"retries":0,"errors":0,"latency_p99_ms":1.5}`
+	if string(content) != want {
+		t.Errorf("Score.JSON() = %s, want %s", content, want)
+	}
+}
+
+func TestScorePercentile(t *testing.T) {
+	var s = Score{Latencies: []time.Duration{10, 20, 30, 40, 50}}
+	for _, tc := range []struct {
+		pct  float64
+		want time.Duration
+	}{
+		{0, 10},
+		{50, 30},
+		{90, 46},
+		{99.9, 49},
+		{100, 50},
+		{150, 50},
+	} {
+		if p := s.Percentile(tc.pct); p != tc.want {
+			t.Errorf("Percentile(%v) = %v, want %v", tc.pct, p, tc.want)
+		}
+	}
+
+	if p := (&Score{}).Percentile(50); p != 0 {
+		t.Errorf("Percentile() without latencies = %v, want 0", p)
+	}
+}
+
 func TestInitOpts(t *testing.T) {
 	b := New()
 	os.Args = []string{"test", "--duration=1", "--loops=1", "-c=1"}
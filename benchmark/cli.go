@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"reflect"
 
 	"github.com/jessevdk/go-flags"
 )
@@ -18,12 +19,28 @@ type CommonOpts struct {
 	Repeat   int    `short:"r" long:"repeat" description:"repeat the test given amount of times" required:"false" default:"1"`
 	Quiet    bool   `short:"Q" long:"quiet" description:"be quiet and print as less information as possible"`
 	RandSeed int64  `short:"s" long:"randseed" description:"Seed used for random number generation" required:"false" default:"1"`
+
+	TraceMemory bool `long:"trace-memory" description:"report the heap allocations and allocated bytes per loop of testing function (like go test -benchmem)" required:"false"`
+
+	WarmupDuration int `long:"warmup" description:"run the workers given amount of seconds before every measurement, the warmup loops are not accounted in the score and the --duration starts after the warmup" required:"false" default:"0"`
+	WarmupLoops    int `long:"warmup-loops" description:"run given TOTAL number of warmup iterations of testing function before every measurement, the warmup iterations are not accounted in the score" required:"false" default:"0"`
+
+	RandSeedFile string `long:"randomizer-seed-file" description:"path to JSON file with pre-generated values (uuid, string, int, timestamp arrays) used instead of random ones" required:"false"`
+
+	DeterministicData bool `long:"deterministic-data" description:"generate the same rows on every run, the values depend only on --randseed, the worker and the row index (except the time based ones)" required:"false"`
+
+	WorkerAffinity string `long:"worker-affinity" description:"pin the workers to given CPU cores (e.g. 0,2,4,6 or 0-3), workers are assigned to the cores round-robin (Linux and Windows only)" required:"false"`
+
+	Config     string `long:"config" description:"path to YAML file with the options, the keys are the long option names optionally grouped by the options struct name (see --dump-config), the command line options override the file ones" required:"false"`
+	DumpConfig bool   `long:"dump-config" description:"write the effective options to stdout in the --config YAML format and exit" required:"false"`
 }
 
 // CLI is a wrapper for go-flags library
 type CLI struct {
 	parser     *flags.Parser
 	commonOpts *CommonOpts
+	groups     []*flags.Group
+	groupNames map[*flags.Group]string // options struct type names the groups are written under by --dump-config
 }
 
 // Init initializes CLI with given application name and commonOptsPointer.
@@ -39,14 +56,26 @@ func (cli *CLI) SetApplicationName(name string) {
 
 // addDefaultGroup adds default group with common options.
 func (cli *CLI) addDefaultGroup(commonOptsPointer *CommonOpts) {
-	_, err := cli.parser.AddGroup("Common options", "CommonOptions represents common flags for every test", commonOptsPointer)
+	group, err := cli.parser.AddGroup("Common options", "CommonOptions represents common flags for every test", commonOptsPointer)
 	handleAddGroupError(err)
+	cli.registerGroup(group, commonOptsPointer)
 }
 
 // AddFlagGroup adds flags in struct flagsPtr(should be pointer!) to given group.
 func (cli *CLI) AddFlagGroup(groupName, groupDescription string, flagsPtr interface{}) {
-	_, err := cli.parser.AddGroup(groupName, groupDescription, flagsPtr)
+	group, err := cli.parser.AddGroup(groupName, groupDescription, flagsPtr)
 	handleAddGroupError(err)
+	cli.registerGroup(group, flagsPtr)
+}
+
+// registerGroup remembers the group and its options struct type name for --dump-config
+func (cli *CLI) registerGroup(group *flags.Group, flagsPtr interface{}) {
+	if cli.groupNames == nil {
+		cli.groupNames = make(map[*flags.Group]string)
+	}
+
+	cli.groups = append(cli.groups, group)
+	cli.groupNames[group] = reflect.TypeOf(flagsPtr).Elem().Name()
 }
 
 // handleAddGroupError handles error from AddGroup.
@@ -82,10 +111,21 @@ func (cli *CLI) SetDescription(description string) {
 	cli.parser.Usage = cli.parser.Usage + "\n" + description
 }
 
-// Parse initializes CLI arguments.
+// Parse initializes CLI arguments, the options priority is: command line > environment variable (`env` tag) > --config file > default.
 func (cli *CLI) Parse() []string {
 	cli.addDefaultGroup(cli.commonOpts)
-	values, err := cli.parser.Parse()
+
+	var args = os.Args[1:]
+	if path := configPath(args); path != "" {
+		configArgs, err := cli.loadConfig(path)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(0)
+		}
+		args = append(configArgs, args...)
+	}
+
+	values, err := cli.parser.ParseArgs(args)
 	if err != nil {
 		var flagsError *flags.Error
 		errors.As(err, &flagsError)
@@ -101,5 +141,28 @@ func (cli *CLI) Parse() []string {
 		os.Exit(0)
 	}
 
+	cli.warnEnvOverrides()
+
+	if cli.commonOpts.DumpConfig {
+		if err = cli.dumpConfig(os.Stdout); err != nil {
+			fmt.Println(err)
+		}
+		os.Exit(0)
+	}
+
 	return values
 }
+
+// warnEnvOverrides warns about the options which values are taken from the environment variables instead of the command line
+func (cli *CLI) warnEnvOverrides() {
+	for _, group := range cli.groups {
+		for _, opt := range group.Options() {
+			if opt.EnvDefaultKey == "" || !opt.IsSetDefault() {
+				continue
+			}
+			if _, ok := os.LookupEnv(opt.EnvDefaultKey); ok {
+				fmt.Printf("WARNING: option --%s is set by $%s environment variable\n", opt.LongName, opt.EnvDefaultKey)
+			}
+		}
+	}
+}
@@ -0,0 +1,170 @@
+package benchmark
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// ColumnConf is the column configuration given to the ColumnGenerator
+type ColumnConf struct {
+	DBFakeColumnConf
+
+	Rand         *RandomizerWorker      // Rand is the worker randomizer, use it to keep the values reproducible by the --randseed
+	PreGenerated map[string]interface{} // PreGenerated are the row values pre-generated by the RandomizerPlugin, can be nil
+}
+
+// ColumnGenerator generates the fake values of the column type it is registered for, see RegisterColumnGenerator
+type ColumnGenerator interface {
+	GenerateValue(workerID int, colConf ColumnConf) interface{}
+}
+
+// ColumnGeneratorFunc is an adapter to use the ordinary function as the ColumnGenerator
+type ColumnGeneratorFunc func(workerID int, colConf ColumnConf) interface{}
+
+// GenerateValue calls f(workerID, colConf)
+func (f ColumnGeneratorFunc) GenerateValue(workerID int, colConf ColumnConf) interface{} {
+	return f(workerID, colConf)
+}
+
+var (
+	columnGeneratorsLock sync.RWMutex
+	columnGenerators     = map[string]ColumnGenerator{}
+)
+
+// RegisterColumnGenerator registers the generator of the fake values for given column type (e.g. encrypted values
+// or device fingerprints), the generator registered for the built-in type (e.g. 'string') replaces the built-in one
+func RegisterColumnGenerator(typeName string, gen ColumnGenerator) {
+	columnGeneratorsLock.Lock()
+	defer columnGeneratorsLock.Unlock()
+
+	columnGenerators[typeName] = gen
+}
+
+// getColumnGenerator returns the generator registered for given column type
+func getColumnGenerator(typeName string) (ColumnGenerator, bool) {
+	columnGeneratorsLock.RLock()
+	defer columnGeneratorsLock.RUnlock()
+
+	gen, ok := columnGenerators[typeName]
+
+	return gen, ok
+}
+
+// timestampFormat is the format of the 'timestamp' column type values
+const timestampFormat = "2006-01-02 15:04:05.000000"
+
+func init() {
+	var builtins = map[string]ColumnGeneratorFunc{
+		"autoinc": func(int, ColumnConf) interface{} {
+			// the best motonic autoincrement simulation
+			return time.Now().UnixNano()
+		},
+		"now_sec": func(int, ColumnConf) interface{} { return time.Now().Unix() },
+		"now_ms":  func(int, ColumnConf) interface{} { return time.Now().UnixMilli() },
+		"now_mcs": func(int, ColumnConf) interface{} { return time.Now().UnixMicro() },
+		"now_ns":  func(int, ColumnConf) interface{} { return time.Now().UnixNano() },
+		"now":     func(int, ColumnConf) interface{} { return time.Now() },
+		"int": func(_ int, c ColumnConf) interface{} {
+			return c.Rand.Intn(c.Cardinality)
+		},
+		"bigint": func(int, ColumnConf) interface{} {
+			return rand.Int63()
+		},
+		"string": func(_ int, c ColumnConf) interface{} {
+			if c.Cardinality == 0 {
+				if s, ok := c.Rand.seed.nextString(); ok {
+					return s
+				}
+			}
+
+			return randStringBytes(c.Rand, c.ColumnName+"_", c.Cardinality, c.MaxSize, c.MinSize, true)
+		},
+		"rstring": func(_ int, c ColumnConf) interface{} {
+			return randStringBytes(c.Rand, c.ColumnName+"_", c.Cardinality, c.MaxSize, c.MinSize, false)
+		},
+		"uuid": func(_ int, c ColumnConf) interface{} {
+			if c.Cardinality == 0 {
+				return c.Rand.UUID()
+			}
+
+			return c.Rand.UUIDn(c.Cardinality)
+		},
+		"time": func(_ int, c ColumnConf) interface{} {
+			if c.Cardinality == 0 {
+				return time.Now()
+			}
+
+			return c.Rand.RandTime(c.Cardinality)
+		},
+		"time_string": func(_ int, c ColumnConf) interface{} {
+			if c.Cardinality == 0 {
+				return time.Now().String()
+			}
+
+			return c.Rand.RandTime(c.Cardinality).String()
+		},
+		"time_ns": func(_ int, c ColumnConf) interface{} {
+			if c.Cardinality == 0 {
+				return time.Now().Unix()
+			}
+
+			return c.Rand.RandTime(c.Cardinality).Unix()
+		},
+		"timestamp": func(_ int, c ColumnConf) interface{} {
+			if ts, ok := c.Rand.seed.nextTimestamp(); ok {
+				return ts.UTC().Format(timestampFormat)
+			}
+			if c.Cardinality == 0 {
+				return time.Now().UTC().Format(timestampFormat)
+			}
+
+			return c.Rand.RandTime(c.Cardinality).UTC().Format(timestampFormat)
+		},
+		"byte": func(_ int, c ColumnConf) interface{} {
+			return []byte(randStringBytes(c.Rand, "", c.Cardinality, c.MaxSize, c.MinSize, true))
+		},
+		"rbyte": func(_ int, c ColumnConf) interface{} {
+			return []byte(randStringBytes(c.Rand, "", c.Cardinality, c.MaxSize, c.MinSize, false))
+		},
+		"json": func(_ int, c ColumnConf) interface{} {
+			return genRandomJSON(c.Rand, 1024)
+		},
+		"json_nested": func(_ int, c ColumnConf) interface{} {
+			var cardinality = c.Cardinality
+			if cardinality == 0 {
+				cardinality = 1000
+			}
+
+			return genNestedJSON(c.Rand, cardinality)
+		},
+		"json_document": func(_ int, c ColumnConf) interface{} {
+			var cardinality, size = c.Cardinality, c.MaxSize
+			if cardinality == 0 {
+				cardinality = 1000
+			}
+			if size == 0 {
+				size = 8192
+			}
+
+			return genDocumentJSON(c.Rand, size, cardinality)
+		},
+		"bool": func(_ int, c ColumnConf) interface{} {
+			return c.Rand.Intn(2) == 1
+		},
+		"ip_address": func(_ int, c ColumnConf) interface{} {
+			return c.Rand.RandIP()
+		},
+		"blob": func(_ int, c ColumnConf) interface{} {
+			var blob = make([]byte, c.Rand.Intn(c.MaxSize-c.MinSize)+c.MinSize)
+			// the seeded math/rand source never fails to read
+			_ = c.Rand.Read(blob)
+
+			return blob
+		},
+	}
+
+	for typeName, gen := range builtins {
+		RegisterColumnGenerator(typeName, gen)
+	}
+}
@@ -0,0 +1,66 @@
+package benchmark
+
+import (
+	"testing"
+)
+
+type fingerprintGenerator struct {
+	calls int
+}
+
+func (g *fingerprintGenerator) GenerateValue(workerID int, colConf ColumnConf) interface{} {
+	g.calls++
+
+	return colConf.ColumnName + "_fingerprint_" + colConf.Rand.UUID().String()[:8]
+}
+
+func TestRegisterColumnGenerator(t *testing.T) {
+	var gen = &fingerprintGenerator{}
+	RegisterColumnGenerator("test_fingerprint", gen)
+
+	if registered, ok := getColumnGenerator("test_fingerprint"); !ok || registered != gen {
+		t.Fatalf("RegisterColumnGenerator() error, generator is not registered")
+	}
+
+	b := New()
+	b.Randomizer = NewRandomizer(1, 1)
+	_, vals := b.GenFakeData(1, &[]DBFakeColumnConf{{"device", "test_fingerprint", 0, 0, 0}, {"id", "int", 10, 0, 0}}, false)
+
+	if gen.calls != 1 {
+		t.Errorf("GenFakeData() error, expected 1 call of the custom generator, got %d", gen.calls)
+	}
+	if s, ok := vals[0].(string); !ok || len(s) != len("device_fingerprint_")+8 {
+		t.Errorf("GenFakeData() error, unexpected custom generator value %v", vals[0])
+	}
+}
+
+func TestBuiltinColumnGenerators(t *testing.T) {
+	for _, typeName := range []string{"autoinc", "int", "string", "uuid", "timestamp", "json", "bool", "ip_address", "blob"} {
+		if _, ok := getColumnGenerator(typeName); !ok {
+			t.Errorf("built-in generator for type '%s' is not registered", typeName)
+		}
+	}
+
+	b := New()
+	b.Randomizer = NewRandomizer(1, 1)
+
+	if blob, ok := b.GenFakeValue(1, "blob", "data", 0, 64, 16, nil).([]byte); !ok || len(blob) < 16 || len(blob) >= 64 {
+		t.Errorf("GenFakeValue() error, unexpected blob %v", blob)
+	}
+	if _, ok := b.GenFakeValue(1, "int", "id", 10, 0, 0, nil).(int); !ok {
+		t.Errorf("GenFakeValue() error, 'int' value is not int")
+	}
+}
+
+func TestRegisterColumnGeneratorOverridesBuiltin(t *testing.T) {
+	var builtin, _ = getColumnGenerator("bool")
+	defer RegisterColumnGenerator("bool", builtin)
+
+	RegisterColumnGenerator("bool", ColumnGeneratorFunc(func(int, ColumnConf) interface{} { return "yes" }))
+
+	b := New()
+	b.Randomizer = NewRandomizer(1, 1)
+	if v := b.GenFakeValue(1, "bool", "flag", 0, 0, 0, nil); v != "yes" {
+		t.Errorf("GenFakeValue() error, expected overridden 'bool' generator value, got %v", v)
+	}
+}
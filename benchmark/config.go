@@ -0,0 +1,223 @@
+package benchmark
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jessevdk/go-flags"
+	"gopkg.in/yaml.v3"
+)
+
+// configPath returns the value of --config option found in the command line arguments, or empty string
+func configPath(args []string) string {
+	for i, arg := range args {
+		if arg == "--" {
+			break
+		}
+		if strings.HasPrefix(arg, "--config=") {
+			return strings.TrimPrefix(arg, "--config=")
+		}
+		if arg == "--config" && i+1 < len(args) {
+			return args[i+1]
+		}
+	}
+
+	return ""
+}
+
+// options returns all the long-named options registered in the CLI groups keyed by the long name
+func (cli *CLI) options() map[string]*flags.Option {
+	var result = make(map[string]*flags.Option)
+
+	var walk func(group *flags.Group)
+	walk = func(group *flags.Group) {
+		for _, opt := range group.Options() {
+			if opt.LongName != "" {
+				result[opt.LongName] = opt
+			}
+		}
+		for _, sub := range group.Groups() {
+			walk(sub)
+		}
+	}
+	walk(cli.parser.Command.Group)
+
+	return result
+}
+
+// loadConfig reads the YAML config file and converts it into the command line arguments
+/*
+ * The file is expected to be a YAML mapping of the long option names to the values,
+ * the options can also be grouped by the options struct name as --dump-config writes them:
+ * CommonOpts:
+ *   concurrency: 4
+ *   duration: 10
+ * DatabaseOpts:
+ *   connection-string: "postgres://..."
+ * Boolean options are set by true values, counted ones (like --verbose) also accept a number,
+ * lists are converted to the repeated options, the options set by the environment variables are skipped
+ */
+func (cli *CLI) loadConfig(path string) ([]string, error) {
+	var content, err = os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading config file %s: %v", path, err)
+	}
+
+	var values map[string]interface{}
+	if err = yaml.Unmarshal(content, &values); err != nil {
+		return nil, fmt.Errorf("error parsing config file %s: %v", path, err)
+	}
+
+	var opts = cli.options()
+	var args []string
+
+	var add func(values map[string]interface{}) error
+	add = func(values map[string]interface{}) error {
+		// sorted to make the arguments order stable
+		var keys = make([]string, 0, len(values))
+		for key := range values {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			var value = values[key]
+			var opt, ok = opts[key]
+			if !ok {
+				if section, isSection := value.(map[string]interface{}); isSection {
+					if err := add(section); err != nil {
+						return err
+					}
+					continue
+				}
+				return fmt.Errorf("error parsing config file %s: unknown option '%s'", path, key)
+			}
+
+			if key == "config" {
+				continue
+			}
+
+			// the environment variables take precedence over the config file
+			if opt.EnvDefaultKey != "" {
+				if _, isSet := os.LookupEnv(opt.EnvDefaultKey); isSet {
+					continue
+				}
+			}
+
+			optArgs, err := configOptionArgs(opt, value)
+			if err != nil {
+				return fmt.Errorf("error parsing config file %s: %v", path, err)
+			}
+			args = append(args, optArgs...)
+		}
+
+		return nil
+	}
+
+	if err = add(values); err != nil {
+		return nil, err
+	}
+
+	return args, nil
+}
+
+// configOptionArgs converts the config file value of the option into the command line arguments
+func configOptionArgs(opt *flags.Option, value interface{}) ([]string, error) {
+	var name = "--" + opt.LongName
+	var kind = opt.Field().Type.Kind()
+
+	if kind == reflect.Slice && opt.Field().Type.Elem().Kind() == reflect.Bool {
+		var count int
+		switch v := value.(type) {
+		case bool:
+			if v {
+				count = 1
+			}
+		case int:
+			count = v
+		default:
+			return nil, fmt.Errorf("option '%s' expects a boolean or a number, got %v", opt.LongName, value)
+		}
+
+		var args []string
+		for i := 0; i < count; i++ {
+			args = append(args, name)
+		}
+		return args, nil
+	}
+
+	if kind == reflect.Bool {
+		v, ok := value.(bool)
+		if !ok {
+			return nil, fmt.Errorf("option '%s' expects a boolean, got %v", opt.LongName, value)
+		}
+		if v {
+			return []string{name}, nil
+		}
+		return nil, nil
+	}
+
+	if list, ok := value.([]interface{}); ok {
+		var args []string
+		for _, item := range list {
+			args = append(args, fmt.Sprintf("%s=%v", name, item))
+		}
+		return args, nil
+	}
+
+	if value == nil {
+		return nil, nil
+	}
+
+	return []string{fmt.Sprintf("%s=%v", name, value)}, nil
+}
+
+// dumpConfig writes the effective options in the --config YAML format grouped by the options struct name
+func (cli *CLI) dumpConfig(w io.Writer) error {
+	var root = &yaml.Node{Kind: yaml.MappingNode}
+
+	for _, group := range cli.groups {
+		var section = &yaml.Node{Kind: yaml.MappingNode}
+		for _, opt := range group.Options() {
+			if opt.LongName == "" || opt.LongName == "config" || opt.LongName == "dump-config" {
+				continue
+			}
+
+			var valueNode = &yaml.Node{}
+			if err := valueNode.Encode(configOptionValue(opt)); err != nil {
+				return fmt.Errorf("error encoding option '%s': %v", opt.LongName, err)
+			}
+			section.Content = append(section.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: opt.LongName}, valueNode)
+		}
+
+		if len(section.Content) == 0 {
+			continue
+		}
+		root.Content = append(root.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: cli.groupNames[group]}, section)
+	}
+
+	var encoder = yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(root); err != nil {
+		return fmt.Errorf("error writing config: %v", err)
+	}
+
+	return encoder.Close()
+}
+
+// configOptionValue returns the option value as it should be written to the config file
+func configOptionValue(opt *flags.Option) interface{} {
+	switch v := opt.Value().(type) {
+	case time.Duration:
+		return v.String()
+	case []bool:
+		return len(v)
+	default:
+		return v
+	}
+}
@@ -0,0 +1,123 @@
+package benchmark
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestConfigFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	content := `
+duration: 3
+CommonOpts:
+  concurrency: 4
+  loops: 10
+  verbose: 2
+  quiet: true
+`
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("cannot write config file: %v", err)
+	}
+
+	b := New()
+	os.Args = []string{"test", "--config=" + path, "-c=2"}
+	b.InitOpts()
+
+	if b.CommonOpts.Workers != 2 {
+		t.Errorf("InitOpts() error, command line concurrency should override the config one, got %d", b.CommonOpts.Workers)
+	}
+	if b.CommonOpts.Loops != 10 || b.CommonOpts.Duration != 3 || !b.CommonOpts.Quiet {
+		t.Errorf("InitOpts() error, unexpected options from the config file %+v", b.CommonOpts)
+	}
+	if len(b.CommonOpts.Verbose) != 2 {
+		t.Errorf("InitOpts() error, verbose = %d, want %d", len(b.CommonOpts.Verbose), 2)
+	}
+
+	var dump bytes.Buffer
+	if err := b.Cli.dumpConfig(&dump); err != nil {
+		t.Fatalf("dumpConfig() error = %v", err)
+	}
+	for _, want := range []string{"CommonOpts:", "  concurrency: 2", "  loops: 10", "  verbose: 2"} {
+		if !strings.Contains(dump.String(), want+"\n") {
+			t.Errorf("dumpConfig() error, %q not found in:\n%s", want, dump.String())
+		}
+	}
+	if strings.Contains(dump.String(), "config:") {
+		t.Errorf("dumpConfig() error, config options should not be written:\n%s", dump.String())
+	}
+}
+
+func TestConfigFileUnknownOption(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("no-such-option: 1\n"), 0o600); err != nil {
+		t.Fatalf("cannot write config file: %v", err)
+	}
+
+	b := New()
+	b.Cli.addDefaultGroup(b.Cli.commonOpts)
+	if _, err := b.Cli.loadConfig(path); err == nil || !strings.Contains(err.Error(), "no-such-option") {
+		t.Errorf("loadConfig() error = %v, want unknown option error", err)
+	}
+}
+
+func TestConfigPath(t *testing.T) {
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"-c=1"}, ""},
+		{[]string{"--config=a.yaml", "-c=1"}, "a.yaml"},
+		{[]string{"-c=1", "--config", "b.yaml"}, "b.yaml"},
+		{[]string{"--", "--config=c.yaml"}, ""},
+	}
+	for _, tt := range tests {
+		if got := configPath(tt.args); got != tt.want {
+			t.Errorf("configPath(%v) = %q, want %q", tt.args, got, tt.want)
+		}
+	}
+}
+
+type envTestOpts struct {
+	Value string `long:"env-test-value" env:"PERFKIT_TEST_VALUE" default:"default"`
+}
+
+func TestConfigFileEnvPriority(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("env-test-value: config\n"), 0o600); err != nil {
+		t.Fatalf("cannot write config file: %v", err)
+	}
+
+	tests := []struct {
+		env  string
+		args []string
+		want string
+	}{
+		{"", []string{"test"}, "default"},
+		{"", []string{"test", "--config=" + path}, "config"},
+		{"env", []string{"test", "--config=" + path}, "env"},
+		{"env", []string{"test", "--config=" + path, "--env-test-value=cli"}, "cli"},
+	}
+	for _, tt := range tests {
+		if tt.env != "" {
+			t.Setenv("PERFKIT_TEST_VALUE", tt.env)
+		} else {
+			os.Unsetenv("PERFKIT_TEST_VALUE") //nolint:errcheck
+		}
+
+		var opts envTestOpts
+		b := New()
+		b.AddOpts = func() TestOpts {
+			b.Cli.AddFlagGroup("Env test options", "", &opts)
+			return &opts
+		}
+		os.Args = tt.args
+		b.InitOpts()
+
+		if opts.Value != tt.want {
+			t.Errorf("InitOpts() error, env = %q, args = %v, value = %q, want %q", tt.env, tt.args[1:], opts.Value, tt.want)
+		}
+	}
+}
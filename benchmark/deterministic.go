@@ -0,0 +1,102 @@
+package benchmark
+
+import (
+	"encoding/binary"
+	"math/rand"
+	"sync"
+
+	"github.com/cespare/xxhash/v2"
+)
+
+// splitMix64Source is a rand.Source64 whose state is set once per value, it is cheap to reseed unlike the rand.NewSource one
+type splitMix64Source struct {
+	state uint64
+}
+
+// Seed sets the state of the source
+func (s *splitMix64Source) Seed(seed int64) {
+	s.state = uint64(seed) //nolint:gosec
+}
+
+// Uint64 returns the next pseudo-random value of the splitmix64 sequence
+func (s *splitMix64Source) Uint64() uint64 {
+	s.state += 0x9e3779b97f4a7c15
+	z := s.state
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return z ^ (z >> 31)
+}
+
+// Int63 returns the next non-negative pseudo-random int64 value
+func (s *splitMix64Source) Int63() int64 {
+	return int64(s.Uint64() >> 1) //nolint:gosec
+}
+
+// deterministicWorker is the per worker state of DeterministicGenerator
+type deterministicWorker struct {
+	row    uint64
+	source *splitMix64Source
+	rand   *RandomizerWorker
+}
+
+// DeterministicGenerator makes the generated values the function of the seed, the worker ID, the row index and the column name,
+// so the same rows are generated on every run with the same --randseed regardless of the timing and the other workers.
+// The values based on the current time (now*, autoinc, time, timestamp columns) and the seed file values stay non-deterministic.
+type DeterministicGenerator struct {
+	seed    int64
+	lock    sync.Mutex
+	workers map[int]*deterministicWorker
+}
+
+// NewDeterministicGenerator creates a new DeterministicGenerator with the given seed
+func NewDeterministicGenerator(seed int64) *DeterministicGenerator {
+	return &DeterministicGenerator{seed: seed, workers: make(map[int]*deterministicWorker)}
+}
+
+// worker returns the state of the given worker, creating it on the first use
+func (g *DeterministicGenerator) worker(workerID int) *deterministicWorker {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	var w, exists = g.workers[workerID]
+	if !exists {
+		var source = &splitMix64Source{}
+		var r = rand.New(source) //nolint:gosec
+		w = &deterministicWorker{
+			source: source,
+			rand:   &RandomizerWorker{fixed: r, seeded: r, unique: r},
+		}
+		g.workers[workerID] = w
+	}
+
+	return w
+}
+
+// NextRow returns the index of the next row generated by the given worker, the rows are counted from 0
+func (g *DeterministicGenerator) NextRow(workerID int) uint64 {
+	var w = g.worker(workerID)
+	var row = w.row
+	w.row++
+
+	return row
+}
+
+// Worker returns the randomizer of the given worker reseeded for the given row and column,
+// the randomizer is reused by the next calls of the same worker so it must not be retained
+func (g *DeterministicGenerator) Worker(workerID int, row uint64, columnName string) *RandomizerWorker {
+	var w = g.worker(workerID)
+	w.source.state = g.hash(workerID, row, columnName)
+
+	return w.rand
+}
+
+// hash returns the xxhash of the seed, the worker ID, the row index and the column name
+func (g *DeterministicGenerator) hash(workerID int, row uint64, columnName string) uint64 {
+	var buf = make([]byte, 24, 24+len(columnName))
+	binary.LittleEndian.PutUint64(buf[0:], uint64(g.seed))   //nolint:gosec
+	binary.LittleEndian.PutUint64(buf[8:], uint64(workerID)) //nolint:gosec
+	binary.LittleEndian.PutUint64(buf[16:], row)
+	buf = append(buf, columnName...)
+
+	return xxhash.Sum64(buf)
+}
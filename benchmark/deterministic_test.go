@@ -0,0 +1,46 @@
+package benchmark
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDeterministicGenerator(t *testing.T) {
+	columns := []DBFakeColumnConf{
+		{"id", "uuid", 0, 0, 0},
+		{"name", "string", 0, 32, 8},
+		{"count", "int", 1000, 0, 0},
+		{"tag", "rstring", 0, 16, 4},
+	}
+
+	generate := func(seed int64, workerID int, rows int) [][]interface{} {
+		b := New()
+		b.Randomizer = NewRandomizer(seed, 2)
+		b.Deterministic = NewDeterministicGenerator(seed)
+
+		var ret [][]interface{}
+		for i := 0; i < rows; i++ {
+			_, vals := b.GenFakeData(workerID, &columns, false)
+			ret = append(ret, vals)
+		}
+		return ret
+	}
+
+	first := generate(1, 0, 10)
+	if second := generate(1, 0, 10); !reflect.DeepEqual(first, second) {
+		t.Errorf("GenFakeData() error, the same seed, worker and row generate different values: %v != %v", first, second)
+	}
+
+	for i := 1; i < len(first); i++ {
+		if reflect.DeepEqual(first[0], first[i]) {
+			t.Errorf("GenFakeData() error, rows 0 and %d are equal: %v", i, first[i])
+		}
+	}
+
+	if reflect.DeepEqual(first, generate(2, 0, 10)) {
+		t.Errorf("GenFakeData() error, different seeds generate the same values")
+	}
+	if reflect.DeepEqual(first, generate(1, 1, 10)) {
+		t.Errorf("GenFakeData() error, different workers generate the same values")
+	}
+}
@@ -0,0 +1,103 @@
+package benchmark
+
+import (
+	"fmt"
+
+	"github.com/brianvoe/gofakeit/v6"
+	"github.com/google/uuid"
+)
+
+// fakerHints maps the column type hints (and the names of the 'string' / 'rstring' columns) to the realistic value generators
+var fakerHints = map[string]func(f *gofakeit.Faker) string{
+	"name":       (*gofakeit.Faker).Name,
+	"first_name": (*gofakeit.Faker).FirstName,
+	"last_name":  (*gofakeit.Faker).LastName,
+	"email":      (*gofakeit.Faker).Email,
+	"phone":      (*gofakeit.Faker).Phone,
+	"username":   (*gofakeit.Faker).Username,
+	"company":    (*gofakeit.Faker).Company,
+	"job":        (*gofakeit.Faker).JobTitle,
+	"street":     (*gofakeit.Faker).Street,
+	"city":       (*gofakeit.Faker).City,
+	"country":    (*gofakeit.Faker).Country,
+	"zip":        (*gofakeit.Faker).Zip,
+	"url":        (*gofakeit.Faker).URL,
+	"domain":     (*gofakeit.Faker).DomainName,
+	"ip_address": (*gofakeit.Faker).IPv4Address,
+	"user_agent": (*gofakeit.Faker).UserAgent,
+	"uuid":       (*gofakeit.Faker).UUID,
+	"word":       (*gofakeit.Faker).Word,
+	"sentence":   func(f *gofakeit.Faker) string { return f.Sentence(8) },
+}
+
+// FakerLocales are the locales supported by the FakerGenerator, gofakeit provides the English data only
+var FakerLocales = []string{"en_US"}
+
+// FakerGenerator is the ColumnGenerator of the realistic values (names, emails, phones, etc.) backed by gofakeit,
+// the generator is picked by the column type (e.g. 'email') or, for the 'string' and 'rstring' columns, by the column name
+type FakerGenerator struct {
+	locale string
+}
+
+// NewFakerGenerator creates the FakerGenerator of the given locale (e.g. en_US)
+func NewFakerGenerator(locale string) (*FakerGenerator, error) {
+	for _, l := range FakerLocales {
+		if l == locale {
+			return &FakerGenerator{locale: locale}, nil
+		}
+	}
+
+	return nil, fmt.Errorf("unsupported data locale '%s', supported locales: %v", locale, FakerLocales)
+}
+
+// Locale returns the locale of the generated values
+func (g *FakerGenerator) Locale() string {
+	return g.locale
+}
+
+// hint returns the realistic value generator of the column, if any
+func (g *FakerGenerator) hint(c DBFakeColumnConf) (func(f *gofakeit.Faker) string, bool) {
+	if gen, ok := fakerHints[c.ColumnType]; ok {
+		return gen, true
+	}
+
+	if c.ColumnType == "string" || c.ColumnType == "rstring" {
+		gen, ok := fakerHints[c.ColumnName]
+		return gen, ok
+	}
+
+	return nil, false
+}
+
+// Supports returns true if the realistic values can be generated for the column
+func (g *FakerGenerator) Supports(c DBFakeColumnConf) bool {
+	_, ok := g.hint(c)
+	return ok
+}
+
+// GenerateValue generates the realistic value of the column by the worker seeded randomizer, so the values are reproducible
+// by the --randseed; the column cardinality limits the number of distinct values and the max size truncates them
+func (g *FakerGenerator) GenerateValue(_ int, c ColumnConf) interface{} {
+	var gen, ok = g.hint(c.DBFakeColumnConf)
+	if !ok {
+		return nil
+	}
+
+	var faker = &gofakeit.Faker{Rand: c.Rand.Seeded()}
+	if c.Cardinality > 0 {
+		// the same value index always gives the same value, the zero seed is random for gofakeit
+		faker = gofakeit.NewUnlocked(int64(c.Rand.Intn(c.Cardinality)) + 1)
+	}
+
+	var value = gen(faker)
+	if c.ColumnType == "uuid" {
+		// keep the 'uuid' column values of the same type as the built-in generator ones
+		return uuid.MustParse(value)
+	}
+
+	if c.MaxSize > 0 && len(value) > c.MaxSize {
+		value = value[:c.MaxSize]
+	}
+
+	return value
+}
@@ -0,0 +1,56 @@
+package benchmark
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestFakerGenerator(t *testing.T) {
+	if _, err := NewFakerGenerator("xx_XX"); err == nil {
+		t.Errorf("NewFakerGenerator() error, unsupported locale is accepted")
+	}
+
+	faker, err := NewFakerGenerator("en_US")
+	if err != nil {
+		t.Fatalf("NewFakerGenerator() error = %v", err)
+	}
+
+	b := New()
+	b.Randomizer = NewRandomizer(1, 1)
+	b.Faker = faker
+
+	columns := []DBFakeColumnConf{
+		{"contact", "email", 0, 0, 0},
+		{"name", "string", 0, 32, 0},
+		{"id", "uuid", 0, 0, 0},
+		{"checksum", "string", 0, 64, 0},
+		{"count", "int", 10, 0, 0},
+	}
+	_, vals := b.GenFakeData(1, &columns, false)
+
+	if email, ok := vals[0].(string); !ok || !strings.Contains(email, "@") {
+		t.Errorf("GenFakeData() error, unexpected email %v", vals[0])
+	}
+	if name, ok := vals[1].(string); !ok || !strings.Contains(name, " ") || len(name) > 32 {
+		t.Errorf("GenFakeData() error, unexpected name %v", vals[1])
+	}
+	if _, ok := vals[2].(uuid.UUID); !ok {
+		t.Errorf("GenFakeData() error, 'uuid' value is not uuid.UUID: %v", vals[2])
+	}
+	if checksum, ok := vals[3].(string); !ok || len(checksum) == 0 || strings.ContainsAny(checksum, " @") {
+		t.Errorf("GenFakeData() error, the column without hint should be generated by the built-in generator, got %v", vals[3])
+	}
+	if _, ok := vals[4].(int); !ok {
+		t.Errorf("GenFakeData() error, 'int' value is not int")
+	}
+
+	distinct := map[interface{}]bool{}
+	for i := 0; i < 100; i++ {
+		distinct[b.GenFakeValue(1, "city", "city", 3, 0, 0, nil)] = true
+	}
+	if len(distinct) > 3 {
+		t.Errorf("GenFakeValue() error, %d distinct values generated for cardinality 3", len(distinct))
+	}
+}
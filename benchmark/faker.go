@@ -65,12 +65,15 @@ func (cc *cardinalityCacheType) randStringWithCardinality(randID int, pfx string
 
 // RandStringBytes generates random string with given length and other parameters
 func (b *Benchmark) RandStringBytes(workerID int, pfx string, cardinality int, maxsize int, minsize int, seeded bool) string {
+	return randStringBytes(b.Randomizer.GetWorker(workerID), pfx, cardinality, maxsize, minsize, seeded)
+}
+
+// randStringBytes generates random string by the given worker randomizer, see RandStringBytes
+func randStringBytes(rw *RandomizerWorker, pfx string, cardinality int, maxsize int, minsize int, seeded bool) string {
 	if maxsize == minsize {
 		return ""
 	}
 
-	rw := b.Randomizer.GetWorker(workerID)
-
 	if cardinality != 0 {
 		return cardinalityCache.randStringWithCardinality(rw.Intn(cardinality), pfx, cardinality, maxsize, minsize)
 	}
@@ -99,9 +102,10 @@ func (b *Benchmark) RandStringBytes(workerID int, pfx string, cardinality int, m
 
 // RandomizerWorker is a struct for storing randomizer data
 type RandomizerWorker struct {
-	fixed  *rand.Rand // fixed randomizer
-	seeded *rand.Rand // seeded seed'able randomizer
-	unique *rand.Rand // unique always unique randomizer
+	fixed  *rand.Rand  // fixed randomizer
+	seeded *rand.Rand  // seeded seed'able randomizer
+	unique *rand.Rand  // unique always unique randomizer
+	seed   *seedCursor // seed is a cursor over pre-generated values, nil if no seed file is loaded
 }
 
 // Fixed returns fixed randomizer (always returns the same values)
@@ -125,6 +129,10 @@ func (rw *RandomizerWorker) Intn(max int) int {
 		return 0
 	}
 
+	if v, ok := rw.seed.nextInteger(); ok {
+		return int(uint64(v) % uint64(max)) //nolint:gosec
+	}
+
 	return rw.Seeded().Intn(max)
 }
 
@@ -134,11 +142,19 @@ func (rw *RandomizerWorker) Uintn64(max uint64) uint64 {
 		return 0
 	}
 
+	if v, ok := rw.seed.nextInteger(); ok {
+		return uint64(v) % max //nolint:gosec
+	}
+
 	return rw.Seeded().Uint64() % max //nolint:gosec
 }
 
 // UUID returns random UUID	v4 value (RFC 4122)
 func (rw *RandomizerWorker) UUID() uuid.UUID {
+	if id, ok := rw.seed.nextUUID(); ok {
+		return id
+	}
+
 	r := rw.Unique()
 
 	var val = fmt.Sprintf("%04x%04x-%04x-%04x-%04x-%04x%04x%04x",
@@ -178,6 +194,38 @@ func (rw *RandomizerWorker) RandTime(daysAgoLimit int) time.Time {
 	return from.Add(randomDuration)
 }
 
+// IPv6Share is the share of IPv6 addresses (in percents) returned by RandIP
+const IPv6Share = 20
+
+// RandIP returns random IPv4 or IPv6 address in the textual form, IPv6Share percents of addresses are IPv6
+/*
+ * IPv4 addresses are taken from the private 10.0.0.0/8, 172.16.0.0/12 and 192.168.0.0/16 networks
+ * and from the public space, IPv6 addresses are taken from the 2001:db8::/32 documentation and fd00::/8 ULA networks
+ */
+func (rw *RandomizerWorker) RandIP() string {
+	if rw.Intn(100) < IPv6Share {
+		var pfx = "2001:db8"
+		if rw.Intn(2) == 0 {
+			pfx = fmt.Sprintf("fd%02x:%x", rw.Intn(0x100), rw.Intn(0x10000))
+		}
+
+		return fmt.Sprintf("%s:%x:%x:%x:%x:%x:%x", pfx,
+			rw.Intn(0x10000), rw.Intn(0x10000), rw.Intn(0x10000),
+			rw.Intn(0x10000), rw.Intn(0x10000), rw.Intn(0x10000))
+	}
+
+	switch rw.Intn(4) {
+	case 0:
+		return fmt.Sprintf("10.%d.%d.%d", rw.Intn(256), rw.Intn(256), rw.Intn(254)+1)
+	case 1:
+		return fmt.Sprintf("172.%d.%d.%d", 16+rw.Intn(16), rw.Intn(256), rw.Intn(254)+1)
+	case 2:
+		return fmt.Sprintf("192.168.%d.%d", rw.Intn(256), rw.Intn(254)+1)
+	default:
+		return fmt.Sprintf("%d.%d.%d.%d", rw.Intn(223)+1, rw.Intn(256), rw.Intn(256), rw.Intn(254)+1)
+	}
+}
+
 // Read fills the blob with random data
 func (rw *RandomizerWorker) Read(blob []byte) error {
 	_, err := rw.Seeded().Read(blob)
@@ -272,91 +320,49 @@ type DBFakeColumnConf struct {
 	MinSize     int
 }
 
-// GenFakeValue generates fake value for given column type
+// GenFakeValue generates fake value for given column type, the call is a row of its own for the Deterministic generator
 func (b *Benchmark) GenFakeValue(workerID int, columnType string, columnName string, cardinality int, maxsize int, minsize int, preGenerated map[string]interface{}) interface{} {
-	rw := b.Randomizer.GetWorker(workerID)
-
-	switch columnType {
-	case "autoinc":
-		// the best motonic autoincrement simulation
-		return time.Now().UnixNano()
-	case "now_sec":
-		return time.Now().Unix()
-	case "now_ms":
-		return time.Now().UnixMilli()
-	case "now_mcs":
-		return time.Now().UnixMicro()
-	case "now_ns":
-		return time.Now().UnixNano()
-	case "now":
-		return time.Now()
-	case "int":
-		return rw.Intn(cardinality)
-	case "bigint":
-		return rand.Int63()
-	case "string":
-		return b.RandStringBytes(workerID, columnName+"_", cardinality, maxsize, minsize, true)
-	case "rstring":
-		return b.RandStringBytes(workerID, columnName+"_", cardinality, maxsize, minsize, false)
-	case "uuid":
-		if cardinality == 0 {
-			return rw.UUID()
-		} else {
-			return rw.UUIDn(cardinality)
-		}
-	case "time":
-		if cardinality == 0 {
-			return time.Now()
-		} else {
-			return rw.RandTime(cardinality)
-		}
-	case "time_string":
-		if cardinality == 0 {
-			return time.Now().String()
-		} else {
-			return rw.RandTime(cardinality).String()
-		}
-	case "time_ns":
-		// fmt.Printf("dt: %s\n", rw.RandTime(cardinality).UTC().Format("2006-01-02 15:04:05.000000"))
-		if cardinality == 0 {
-			return time.Now().Unix()
-		} else {
-			return rw.RandTime(cardinality).Unix()
-		}
-	case "timestamp":
-		if cardinality == 0 {
-			return time.Now().UTC().Format("2006-01-02 15:04:05.000000")
-		} else {
-			return rw.RandTime(cardinality).UTC().Format("2006-01-02 15:04:05.000000")
-		}
-	case "byte":
-		return []byte(b.RandStringBytes(workerID, "", cardinality, maxsize, minsize, true))
-	case "rbyte":
-		return []byte(b.RandStringBytes(workerID, "", cardinality, maxsize, minsize, false))
-	case "json":
-		return b.GenRandomJson(rw, 1024)
-	case "bool":
-		return rw.Intn(2) == 1
-	case "blob":
-		size := rw.Intn(maxsize-minsize) + minsize
-		blob := make([]byte, size)
-		err := rw.Read(blob)
-		if err != nil {
-			b.Exit(err.Error())
-		}
+	var rw *RandomizerWorker
+	if b.Deterministic != nil {
+		rw = b.Deterministic.Worker(workerID, b.Deterministic.NextRow(workerID), columnName)
+	} else {
+		rw = b.Randomizer.GetWorker(workerID)
+	}
 
-		return blob
-	default:
-		for _, plugin := range b.Randomizer.plugins {
-			if ok, value := plugin.GenFakeValue(columnType, rw, cardinality, preGenerated); ok {
-				return value
-			}
-		}
+	return b.genFakeValue(workerID, rw, columnType, columnName, cardinality, maxsize, minsize, preGenerated)
+}
 
-		b.Exit("generateParameter: unsupported parameter '%s'", columnType)
+// genFakeValue generates fake value for given column type by the given worker randomizer, see GenFakeValue
+func (b *Benchmark) genFakeValue(workerID int, rw *RandomizerWorker, columnType string, columnName string, cardinality int, maxsize int, minsize int, preGenerated map[string]interface{}) interface{} {
+	var colConf = ColumnConf{
+		DBFakeColumnConf: DBFakeColumnConf{
+			ColumnName:  columnName,
+			ColumnType:  columnType,
+			Cardinality: cardinality,
+			MaxSize:     maxsize,
+			MinSize:     minsize,
+		},
+		Rand:         rw,
+		PreGenerated: preGenerated,
+	}
 
-		return ""
+	if b.Faker != nil && b.Faker.Supports(colConf.DBFakeColumnConf) {
+		return b.Faker.GenerateValue(workerID, colConf)
+	}
+
+	if gen, ok := getColumnGenerator(columnType); ok {
+		return gen.GenerateValue(workerID, colConf)
 	}
+
+	for _, plugin := range b.Randomizer.plugins {
+		if ok, value := plugin.GenFakeValue(columnType, rw, cardinality, preGenerated); ok {
+			return value
+		}
+	}
+
+	b.Exit("generateParameter: unsupported parameter '%s'", columnType)
+
+	return ""
 }
 
 // columnRequired returns true if given column is required
@@ -374,11 +380,31 @@ func columnRequired(column string, columns []string) bool { //nolint:unused
 	return false
 }
 
+// rowRandomizer returns the randomizer of the next row generated by the given worker and the row index,
+// the index is only meaningful for the Deterministic generator
+func (b *Benchmark) rowRandomizer(workerID int) (*RandomizerWorker, uint64) {
+	if b.Deterministic == nil {
+		return b.Randomizer.GetWorker(workerID), 0
+	}
+
+	var row = b.Deterministic.NextRow(workerID)
+	return b.Deterministic.Worker(workerID, row, ""), row
+}
+
+// columnRandomizer returns the randomizer of the given column of the row, see rowRandomizer
+func (b *Benchmark) columnRandomizer(workerID int, rw *RandomizerWorker, row uint64, columnName string) *RandomizerWorker {
+	if b.Deterministic == nil {
+		return rw
+	}
+
+	return b.Deterministic.Worker(workerID, row, columnName)
+}
+
 // GenFakeData generates fake data for given column configuration
 func (b *Benchmark) GenFakeData(workerID int, colConfs *[]DBFakeColumnConf, WithAutoInc bool) ([]string, []interface{}) {
 	columns := make([]string, 0, len(*colConfs))
 	values := make([]interface{}, 0, len(*colConfs))
-	rw := b.Randomizer.GetWorker(workerID)
+	rw, row := b.rowRandomizer(workerID)
 
 	var preGenerated map[string]interface{}
 	for _, plugin := range b.Randomizer.plugins {
@@ -397,7 +423,7 @@ func (b *Benchmark) GenFakeData(workerID int, colConfs *[]DBFakeColumnConf, With
 			continue
 		}
 		columns = append(columns, c.ColumnName)
-		values = append(values, b.GenFakeValue(workerID, c.ColumnType, c.ColumnName, c.Cardinality, c.MaxSize, c.MinSize, preGenerated))
+		values = append(values, b.genFakeValue(workerID, b.columnRandomizer(workerID, rw, row, c.ColumnName), c.ColumnType, c.ColumnName, c.Cardinality, c.MaxSize, c.MinSize, preGenerated))
 	}
 
 	return columns, values
@@ -406,7 +432,7 @@ func (b *Benchmark) GenFakeData(workerID int, colConfs *[]DBFakeColumnConf, With
 // GenFakeDataAsMap generates fake data for given column configuration as map
 func (b *Benchmark) GenFakeDataAsMap(workerID int, colConfs *[]DBFakeColumnConf, WithAutoInc bool) *map[string]interface{} {
 	ret := make(map[string]interface{}, len(*colConfs))
-	rw := b.Randomizer.GetWorker(workerID)
+	rw, row := b.rowRandomizer(workerID)
 
 	var preGenerated map[string]interface{}
 	for _, plugin := range b.Randomizer.plugins {
@@ -424,7 +450,7 @@ func (b *Benchmark) GenFakeDataAsMap(workerID int, colConfs *[]DBFakeColumnConf,
 		if c.ColumnType == "autoinc" && !WithAutoInc {
 			continue
 		}
-		ret[c.ColumnName] = b.GenFakeValue(workerID, c.ColumnType, c.ColumnName, c.Cardinality, c.MaxSize, c.MinSize, preGenerated)
+		ret[c.ColumnName] = b.genFakeValue(workerID, b.columnRandomizer(workerID, rw, row, c.ColumnName), c.ColumnType, c.ColumnName, c.Cardinality, c.MaxSize, c.MinSize, preGenerated)
 	}
 
 	return &ret
@@ -1,6 +1,7 @@
 package benchmark
 
 import (
+	"net"
 	"testing"
 )
 
@@ -48,3 +49,24 @@ func TestGenFakeDataWithoutAutoInc(t *testing.T) {
 		t.Errorf("GenFakeData() error, columns and values length mismatch")
 	}
 }
+
+func TestRandIP(t *testing.T) {
+	rw := NewRandomizerWorker(1, 1)
+
+	var v4, v6 int
+	for i := 0; i < 1000; i++ {
+		ip := net.ParseIP(rw.RandIP())
+		if ip == nil {
+			t.Fatalf("RandIP() error, invalid address generated")
+		}
+		if ip.To4() != nil {
+			v4++
+		} else {
+			v6++
+		}
+	}
+
+	if v4 == 0 || v6 == 0 {
+		t.Errorf("RandIP() error, expected mix of IPv4 and IPv6 addresses, got %d IPv4 and %d IPv6", v4, v6)
+	}
+}
@@ -4,6 +4,17 @@ go 1.21
 
 toolchain go1.21.4
 
-require github.com/jessevdk/go-flags v1.5.0
+require (
+	github.com/google/uuid v1.6.0
+	github.com/jessevdk/go-flags v1.5.0
+	gopkg.in/yaml.v3 v3.0.1
+)
 
-require golang.org/x/sys v0.16.0 // indirect
+require (
+	github.com/brianvoe/gofakeit/v6 v6.28.0
+	github.com/cespare/xxhash/v2 v2.3.0
+	github.com/qri-io/jsonschema v0.2.1
+	golang.org/x/sys v0.16.0
+)
+
+require github.com/qri-io/jsonpointer v0.1.1 // indirect
@@ -3,6 +3,7 @@ package benchmark
 import (
 	"encoding/json"
 	"fmt"
+	"math"
 	"sync"
 )
 
@@ -14,6 +15,11 @@ var jsonLock sync.RWMutex
 
 // GenRandomJson generates a random JSON string based on the given schema cardinality.
 func (b *Benchmark) GenRandomJson(rw *RandomizerWorker, schemaCardinality int) string { //nolint:revive
+	return genRandomJSON(rw, schemaCardinality)
+}
+
+// genRandomJSON generates a random JSON string based on the given schema cardinality.
+func genRandomJSON(rw *RandomizerWorker, schemaCardinality int) string {
 	// Generate a random schema with nested objects
 	var schema Schema
 
@@ -96,6 +102,110 @@ func generateRandomData(rw *RandomizerWorker, schema Schema) interface{} {
 	return data
 }
 
+// nestedJSONFieldsPerLevel is the number of fields at every level of the document generated by GenNestedJson
+const nestedJSONFieldsPerLevel = 10
+
+// GenNestedJson generates ~1KB JSON document with 3 levels of nested objects (10 fields at each level),
+// the searchable value of the level1.level2.level3_field path is taken from the given cardinality
+func (b *Benchmark) GenNestedJson(rw *RandomizerWorker, valueCardinality int) string { //nolint:revive
+	return genNestedJSON(rw, valueCardinality)
+}
+
+// genNestedJSON generates ~1KB JSON document with 3 levels of nested objects, see GenNestedJson
+func genNestedJSON(rw *RandomizerWorker, valueCardinality int) string {
+	var level3 = make(map[string]interface{}, nestedJSONFieldsPerLevel)
+	for i := 0; i < nestedJSONFieldsPerLevel; i++ {
+		level3[fmt.Sprintf("level3_field%d", i)] = fmt.Sprintf("%016x", rw.Uintn64(math.MaxUint64))
+	}
+
+	var level2 = map[string]interface{}{
+		"level3":       level3,
+		"level3_field": fmt.Sprintf("value_%d", rw.Intn(valueCardinality)),
+	}
+	for i := 2; i < nestedJSONFieldsPerLevel; i++ {
+		level2[fmt.Sprintf("level2_field%d", i)] = fmt.Sprintf("%016x", rw.Uintn64(math.MaxUint64))
+	}
+
+	var level1 = map[string]interface{}{
+		"level2": level2,
+	}
+	for i := 1; i < nestedJSONFieldsPerLevel; i++ {
+		level1[fmt.Sprintf("level1_field%d", i)] = rw.Intn(valueCardinality)
+	}
+
+	jsonData, err := json.Marshal(map[string]interface{}{"level1": level1})
+	if err != nil {
+		fmt.Println("Error:", err)
+
+		return ""
+	}
+
+	return string(jsonData)
+}
+
+// documentJSONTagsCount is the number of tags of the document generated by GenDocumentJson
+const documentJSONTagsCount = 5
+
+// GenDocumentJson generates the JSON document of an application entity (owner object, tags and items arrays) of about sizeBytes,
+// the searchable value of the owner.region path is taken from the given cardinality
+func (b *Benchmark) GenDocumentJson(rw *RandomizerWorker, sizeBytes int, valueCardinality int) string { //nolint:revive
+	return genDocumentJSON(rw, sizeBytes, valueCardinality)
+}
+
+// genDocumentJSON generates the application entity JSON document, see GenDocumentJson
+func genDocumentJSON(rw *RandomizerWorker, sizeBytes int, valueCardinality int) string {
+	var tags = make([]string, documentJSONTagsCount)
+	for i := range tags {
+		tags[i] = fmt.Sprintf("tag_%d", rw.Intn(valueCardinality))
+	}
+
+	var doc = map[string]interface{}{
+		"uuid":   rw.UUID().String(),
+		"status": randomString(rw, []string{"new", "active", "suspended", "deleted"}),
+		"owner": map[string]interface{}{
+			"id":       fmt.Sprintf("%016x", rw.Uintn64(math.MaxUint64)),
+			"region":   fmt.Sprintf("region_%d", rw.Intn(valueCardinality)),
+			"contacts": []string{fmt.Sprintf("%016x@example.com", rw.Uintn64(math.MaxUint64)), fmt.Sprintf("%016x@example.com", rw.Uintn64(math.MaxUint64))},
+		},
+		"tags": tags,
+	}
+
+	var size = 0
+	if head, err := json.Marshal(doc); err == nil {
+		size = len(head)
+	}
+
+	// the items are added until the document reaches the requested size
+	var items []interface{}
+	for size < sizeBytes {
+		var item = map[string]interface{}{
+			"sku":      fmt.Sprintf("%016x", rw.Uintn64(math.MaxUint64)),
+			"quantity": rw.Intn(100),
+			"price":    rw.Intn(100000),
+			"attributes": map[string]interface{}{
+				"color": randomString(rw, []string{"red", "green", "blue", "black", "white"}),
+				"size":  randomString(rw, []string{"S", "M", "L", "XL"}),
+			},
+		}
+		if itemJSON, err := json.Marshal(item); err == nil {
+			size += len(itemJSON) + 1
+		} else {
+			break
+		}
+		items = append(items, item)
+	}
+	doc["items"] = items
+
+	jsonData, err := json.Marshal(doc)
+	if err != nil {
+		fmt.Println("Error:", err)
+
+		return ""
+	}
+
+	return string(jsonData)
+}
+
 // randomString returns a random element from the given string slice.
 func randomString(rw *RandomizerWorker, choices []string) string {
 	return choices[rw.Intn(len(choices))]
@@ -0,0 +1,278 @@
+package benchmark
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/qri-io/jsonschema"
+)
+
+const (
+	jsonSchemaMaxDepth     = 16      // the recursive $ref schemas are cut at this depth
+	jsonSchemaMaxStringLen = 16      // default string length if the maxLength is not set
+	jsonSchemaMaxItems     = 5       // default array length if the maxItems is not set
+	jsonSchemaMaxNumber    = 1000000 // default number range if the maximum is not set
+)
+
+func init() {
+	// qri-io/jsonschema implements the draft 2019-09 where the subschemas moved from 'definitions' to '$defs',
+	// register the draft-07 keyword too so the '#/definitions/...' references are resolved by the validator
+	jsonschema.LoadDraft2019_09()
+	jsonschema.RegisterKeyword("definitions", jsonschema.NewDefs)
+}
+
+// JSONSchemaGenerator is the ColumnGenerator of the JSON documents conforming to the JSON Schema (draft-07),
+// the generator supports the type, properties, items, minItems, maxItems, minLength, maxLength, minimum, maximum,
+// enum, const and local $ref keywords, the documents are generated from the column Rand so they follow the --randseed
+type JSONSchemaGenerator struct {
+	root   map[string]interface{}
+	schema *jsonschema.Schema
+}
+
+// LoadJSONSchemaGenerator creates a new JSONSchemaGenerator of the JSON Schema in the given file
+func LoadJSONSchemaGenerator(path string) (*JSONSchemaGenerator, error) {
+	var data, err = os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("json schema: cannot read %s: %v", path, err)
+	}
+
+	return NewJSONSchemaGenerator(data)
+}
+
+// NewJSONSchemaGenerator creates a new JSONSchemaGenerator of the given JSON Schema, the schema is rejected
+// if the document generated by the generator doesn't conform to it (e.g. the schema uses the unsupported keywords)
+func NewJSONSchemaGenerator(data []byte) (*JSONSchemaGenerator, error) {
+	var g = &JSONSchemaGenerator{schema: &jsonschema.Schema{}}
+
+	if err := json.Unmarshal(data, g.schema); err != nil {
+		return nil, fmt.Errorf("json schema: cannot parse the schema: %v", err)
+	}
+	if err := json.Unmarshal(data, &g.root); err != nil {
+		return nil, fmt.Errorf("json schema: the schema is not a JSON object: %v", err)
+	}
+
+	var doc, err = g.generate(NewRandomizerWorker(1, 0))
+	if err != nil {
+		return nil, err
+	}
+	if err = g.Validate(doc); err != nil {
+		return nil, fmt.Errorf("json schema: the schema is not supported by the generator: %v", err)
+	}
+
+	return g, nil
+}
+
+// Validate returns an error if the given JSON document doesn't conform to the schema
+func (g *JSONSchemaGenerator) Validate(doc []byte) error {
+	var keyErrs, err = g.schema.ValidateBytes(context.Background(), doc)
+	if err != nil {
+		return err
+	}
+	if len(keyErrs) != 0 {
+		return fmt.Errorf("%s: %s", keyErrs[0].PropertyPath, keyErrs[0].Message)
+	}
+
+	return nil
+}
+
+// GenerateValue generates the JSON document conforming to the schema as the string
+func (g *JSONSchemaGenerator) GenerateValue(_ int, c ColumnConf) interface{} {
+	// the schema was checked by NewJSONSchemaGenerator, so the generation never fails
+	var doc, _ = g.generate(c.Rand)
+
+	return string(doc)
+}
+
+// generate generates the JSON document conforming to the schema
+func (g *JSONSchemaGenerator) generate(rw *RandomizerWorker) ([]byte, error) {
+	var value, err = g.generateValue(rw, g.root, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(value)
+}
+
+// resolve returns the schema referenced by the local $ref JSON pointer (e.g. #/definitions/address)
+func (g *JSONSchemaGenerator) resolve(ref string) (map[string]interface{}, error) {
+	if !strings.HasPrefix(ref, "#") {
+		return nil, fmt.Errorf("json schema: only local $ref is supported, got %s", ref)
+	}
+
+	var node interface{} = g.root
+	for _, token := range strings.Split(strings.TrimPrefix(ref, "#"), "/") {
+		if token == "" {
+			continue
+		}
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+
+		var object, ok = node.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("json schema: cannot resolve $ref %s", ref)
+		}
+		if node, ok = object[token]; !ok {
+			return nil, fmt.Errorf("json schema: cannot resolve $ref %s", ref)
+		}
+	}
+
+	var schema, ok = node.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("json schema: $ref %s is not a schema", ref)
+	}
+
+	return schema, nil
+}
+
+// schemaType returns the type of the value generated for the schema
+func schemaType(rw *RandomizerWorker, schema map[string]interface{}) string {
+	switch t := schema["type"].(type) {
+	case string:
+		return t
+	case []interface{}:
+		if len(t) != 0 {
+			if s, ok := t[rw.Intn(len(t))].(string); ok {
+				return s
+			}
+		}
+	}
+
+	if _, ok := schema["properties"]; ok {
+		return "object"
+	}
+	if _, ok := schema["items"]; ok {
+		return "array"
+	}
+
+	return "string"
+}
+
+// schemaNumber returns the numeric keyword of the schema or the default value if the keyword is not set
+func schemaNumber(schema map[string]interface{}, keyword string, def float64) float64 {
+	if v, ok := schema[keyword].(float64); ok {
+		return v
+	}
+
+	return def
+}
+
+// generateValue generates the value conforming to the schema
+func (g *JSONSchemaGenerator) generateValue(rw *RandomizerWorker, schema map[string]interface{}, depth int) (interface{}, error) {
+	if depth > jsonSchemaMaxDepth {
+		return nil, nil
+	}
+
+	if ref, ok := schema["$ref"].(string); ok {
+		var resolved, err = g.resolve(ref)
+		if err != nil {
+			return nil, err
+		}
+
+		return g.generateValue(rw, resolved, depth+1)
+	}
+
+	if value, ok := schema["const"]; ok {
+		return value, nil
+	}
+
+	if enum, ok := schema["enum"].([]interface{}); ok && len(enum) != 0 {
+		return enum[rw.Intn(len(enum))], nil
+	}
+
+	switch schemaType(rw, schema) {
+	case "object":
+		var properties, _ = schema["properties"].(map[string]interface{})
+		var names = make([]string, 0, len(properties))
+		for name := range properties {
+			names = append(names, name)
+		}
+		// the map iteration order is random, sort the names to keep the documents reproducible
+		sort.Strings(names)
+
+		var object = make(map[string]interface{}, len(names))
+		for _, name := range names {
+			var property, ok = properties[name].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			var value, err = g.generateValue(rw, property, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			object[name] = value
+		}
+
+		return object, nil
+
+	case "array":
+		var minItems = int(schemaNumber(schema, "minItems", 0))
+		var maxItems = int(schemaNumber(schema, "maxItems", float64(minItems+jsonSchemaMaxItems)))
+		var items, _ = schema["items"].(map[string]interface{})
+		if maxItems < minItems {
+			return nil, fmt.Errorf("json schema: maxItems %d is less than minItems %d", maxItems, minItems)
+		}
+
+		var array = make([]interface{}, minItems+rw.Intn(maxItems-minItems+1))
+		for i := range array {
+			var value, err = g.generateValue(rw, items, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			array[i] = value
+		}
+
+		return array, nil
+
+	case "string":
+		switch schema["format"] {
+		case "uuid":
+			return rw.UUID().String(), nil
+		case "date-time":
+			return rw.RandTime(90).UTC().Format(time.RFC3339), nil
+		case "date":
+			return rw.RandTime(90).UTC().Format(time.DateOnly), nil
+		}
+
+		var minLength = int(schemaNumber(schema, "minLength", 0))
+		var maxLength = int(schemaNumber(schema, "maxLength", float64(minLength+jsonSchemaMaxStringLen)))
+		if maxLength < minLength {
+			return nil, fmt.Errorf("json schema: maxLength %d is less than minLength %d", maxLength, minLength)
+		}
+
+		var bytes = make([]byte, minLength+rw.Intn(maxLength-minLength+1))
+		for i := range bytes {
+			bytes[i] = letterBytes[rw.Seeded().Intn(len(letterBytes))]
+		}
+
+		return string(bytes), nil
+
+	case "integer":
+		var minimum = int64(math.Ceil(schemaNumber(schema, "minimum", 0)))
+		var maximum = int64(math.Floor(schemaNumber(schema, "maximum", float64(minimum+jsonSchemaMaxNumber))))
+		if maximum < minimum {
+			return nil, fmt.Errorf("json schema: no integer between minimum and maximum")
+		}
+
+		return minimum + int64(rw.Uintn64(uint64(maximum-minimum)+1)), nil //nolint:gosec
+
+	case "number":
+		var minimum = schemaNumber(schema, "minimum", 0)
+		var maximum = schemaNumber(schema, "maximum", minimum+jsonSchemaMaxNumber)
+
+		return minimum + rw.Seeded().Float64()*(maximum-minimum), nil
+
+	case "boolean":
+		return rw.Intn(2) == 1, nil
+
+	case "null":
+		return nil, nil
+
+	default:
+		return nil, fmt.Errorf("json schema: unsupported type %v", schema["type"])
+	}
+}
@@ -0,0 +1,74 @@
+package benchmark
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+const testJSONSchema = `{
+	"$schema": "http://json-schema.org/draft-07/schema#",
+	"type": "object",
+	"required": ["id", "name", "state", "score", "address", "tags"],
+	"properties": {
+		"id": {"type": "string", "format": "uuid"},
+		"name": {"type": "string", "minLength": 3, "maxLength": 12},
+		"state": {"enum": ["active", "suspended", "deleted"]},
+		"score": {"type": "integer", "minimum": 10, "maximum": 20},
+		"ratio": {"type": "number", "minimum": 0, "maximum": 1},
+		"enabled": {"type": "boolean"},
+		"address": {"$ref": "#/definitions/address"},
+		"tags": {"type": "array", "minItems": 1, "maxItems": 3, "items": {"type": "string", "maxLength": 8}}
+	},
+	"definitions": {
+		"address": {
+			"type": "object",
+			"required": ["city"],
+			"properties": {
+				"city": {"type": "string", "minLength": 1, "maxLength": 32},
+				"zip": {"type": "integer", "minimum": 10000, "maximum": 99999}
+			}
+		}
+	}
+}`
+
+func TestJSONSchemaGenerator(t *testing.T) {
+	gen, err := NewJSONSchemaGenerator([]byte(testJSONSchema))
+	if err != nil {
+		t.Fatalf("NewJSONSchemaGenerator() error = %v", err)
+	}
+
+	b := New()
+	b.Randomizer = NewRandomizer(1, 1)
+	RegisterColumnGenerator("json_schema_test", gen)
+
+	for i := 0; i < 100; i++ {
+		doc, ok := b.GenFakeValue(0, "json_schema_test", "data", 0, 0, 0, nil).(string)
+		if !ok {
+			t.Fatalf("GenFakeValue() error, the value is not string")
+		}
+		if err = gen.Validate([]byte(doc)); err != nil {
+			t.Fatalf("GenFakeValue() error, %s doesn't conform to the schema: %v", doc, err)
+		}
+
+		var object map[string]interface{}
+		if err = json.Unmarshal([]byte(doc), &object); err != nil {
+			t.Fatalf("GenFakeValue() error, %s is not JSON object: %v", doc, err)
+		}
+		if address, ok := object["address"].(map[string]interface{}); !ok || address["city"] == nil {
+			t.Errorf("GenFakeValue() error, $ref is not resolved in %s", doc)
+		}
+	}
+}
+
+func TestJSONSchemaGeneratorErrors(t *testing.T) {
+	for _, schema := range []string{
+		`not a schema`,
+		`{"type": "object", "properties": {"a": {"$ref": "#/definitions/missing"}}}`,
+		`{"type": "string", "minLength": 10, "maxLength": 5}`,
+		`{"type": "string", "pattern": "^[0-9]+$", "minLength": 1}`,
+	} {
+		if _, err := NewJSONSchemaGenerator([]byte(schema)); err == nil {
+			t.Errorf("NewJSONSchemaGenerator() error, %s is accepted", schema)
+		}
+	}
+}
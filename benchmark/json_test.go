@@ -1,6 +1,7 @@
 package benchmark
 
 import (
+	"encoding/json"
 	"testing"
 )
 
@@ -77,3 +78,58 @@ func TestGenerateRandomDataWithNestedSchema(t *testing.T) {
 		t.Errorf("generateRandomData() error, field1 is not a string")
 	}
 }
+
+func TestGenNestedJson(t *testing.T) {
+	b := New()
+	rw := NewRandomizerWorker(1, 1)
+
+	var doc map[string]map[string]interface{}
+	if err := json.Unmarshal([]byte(b.GenNestedJson(rw, 10)), &doc); err != nil {
+		t.Fatalf("GenNestedJson() error, cannot unmarshal json: %v", err)
+	}
+
+	if len(doc["level1"]) != nestedJSONFieldsPerLevel {
+		t.Errorf("GenNestedJson() error, level1 has %d fields, want %d", len(doc["level1"]), nestedJSONFieldsPerLevel)
+	}
+
+	var level2, ok = doc["level1"]["level2"].(map[string]interface{})
+	if !ok || len(level2) != nestedJSONFieldsPerLevel {
+		t.Fatalf("GenNestedJson() error, level2 is not an object of %d fields", nestedJSONFieldsPerLevel)
+	}
+
+	if _, ok = level2["level3_field"].(string); !ok {
+		t.Errorf("GenNestedJson() error, level3_field is not a string")
+	}
+
+	if level3, ok := level2["level3"].(map[string]interface{}); !ok || len(level3) != nestedJSONFieldsPerLevel {
+		t.Errorf("GenNestedJson() error, level3 is not an object of %d fields", nestedJSONFieldsPerLevel)
+	}
+}
+
+func TestGenDocumentJson(t *testing.T) {
+	b := New()
+	rw := NewRandomizerWorker(1, 1)
+
+	var data = b.GenDocumentJson(rw, 8192, 10)
+	if len(data) < 8192 || len(data) > 8192+256 {
+		t.Errorf("GenDocumentJson() error, document size is %d, want about %d", len(data), 8192)
+	}
+
+	var doc struct {
+		Owner struct {
+			Region string `json:"region"`
+		} `json:"owner"`
+		Tags  []string                 `json:"tags"`
+		Items []map[string]interface{} `json:"items"`
+	}
+	if err := json.Unmarshal([]byte(data), &doc); err != nil {
+		t.Fatalf("GenDocumentJson() error, cannot unmarshal json: %v", err)
+	}
+
+	if doc.Owner.Region == "" {
+		t.Errorf("GenDocumentJson() error, owner.region is empty")
+	}
+	if len(doc.Tags) != documentJSONTagsCount || len(doc.Items) == 0 {
+		t.Errorf("GenDocumentJson() error, %d tags and %d items generated", len(doc.Tags), len(doc.Items))
+	}
+}
@@ -2,6 +2,8 @@ package benchmark
 
 import (
 	"fmt"
+	"os"
+	"sync"
 	"time"
 )
 
@@ -72,3 +74,37 @@ func (l *Logger) Logn(LogLevel int, workerID int, format string, args ...interfa
 	}
 	fmt.Print(msg)
 }
+
+// EventLogger writes timestamped events (e.g. connects / disconnects) to a separate file regardless of the log level
+type EventLogger struct {
+	lock sync.Mutex
+	file *os.File
+}
+
+// NewEventLogger creates a new EventLogger appending the events to the given file
+func NewEventLogger(path string) (*EventLogger, error) {
+	var file, err = os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open events log file '%s': %v", path, err)
+	}
+
+	return &EventLogger{file: file}, nil
+}
+
+// Log writes a formatted event of the given worker to the events log file
+func (l *EventLogger) Log(workerID int, format string, args ...interface{}) {
+	var msg = fmt.Sprintf("%s worker %03d: %s\n", time.Now().Format("2006-01-02 15:04:05.000000"), workerID, fmt.Sprintf(format, args...))
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	_, _ = l.file.WriteString(msg)
+}
+
+// Close closes the events log file
+func (l *EventLogger) Close() error {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	return l.file.Close()
+}
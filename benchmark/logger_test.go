@@ -1,6 +1,9 @@
 package benchmark
 
 import (
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -21,3 +24,25 @@ func TestLogMsg(t *testing.T) {
 		t.Errorf("LogMsg() error, message is empty")
 	}
 }
+
+func TestEventLogger(t *testing.T) {
+	var path = filepath.Join(t.TempDir(), "events.log")
+
+	l, err := NewEventLogger(path)
+	if err != nil {
+		t.Fatalf("NewEventLogger() error: %v", err)
+	}
+
+	l.Log(3, "connect, duration %s", "1ms")
+	if err = l.Close(); err != nil {
+		t.Fatalf("Close() error: %v", err)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() error: %v", err)
+	}
+	if !strings.HasSuffix(string(content), " worker 003: connect, duration 1ms\n") {
+		t.Errorf("EventLogger.Log() error, unexpected content: %q", content)
+	}
+}
@@ -0,0 +1,27 @@
+package benchmark
+
+// TestPlugin is a test implemented outside of the benchmark tool and loaded at runtime (e.g. by the Go plugin package),
+// the tool exposes it among its own tests
+/*
+ * Run is called once per test execution and is expected to set Benchmark.Worker (and Benchmark.Init, InitPerWorker
+ * if needed), the tool runs the workers after Run returns, testDesc is the tool specific test description
+ * the plugin test is registered with
+ */
+type TestPlugin interface {
+	Name() string        // test name used to run it, must not collide with the tool tests
+	Description() string // one line test description shown in the list of tests
+	Databases() []string // dialect names of the databases supported by the test (e.g. postgres, mysql)
+	Run(b *Benchmark, testDesc interface{})
+}
+
+// TestGroup is the group of the plugin tests listed together by the tool
+type TestGroup struct {
+	Name  string // group name shown in the list of tests, the tool picks its own one if empty
+	Tests []TestPlugin
+}
+
+// Plugin is the entry point of the plugin, Register is called once the plugin is loaded with the names of the tool tests
+// by the group names (e.g. to check the test names don't collide) and returns the group of the plugin tests
+type Plugin interface {
+	Register(groups map[string][]string) *TestGroup
+}
@@ -0,0 +1,201 @@
+// Package report renders the benchmark scores as the self-contained HTML report with the rate chart,
+// the latency percentiles table and the test parameters, the chart is drawn by Chart.js loaded from the CDN.
+package report
+
+import (
+	"bytes"
+	"html/template"
+	"math"
+	"time"
+
+	"github.com/acronis/perfkit/benchmark"
+)
+
+// Score is the score of a single test shown in the report
+type Score struct {
+	Test     string // Test is the test name
+	Category string // Category groups the tests in the chart, the bars of the same category have the same colour
+
+	benchmark.Score
+}
+
+// Parameter is the test parameter shown in the report
+type Parameter struct {
+	Name  string
+	Value string
+}
+
+// Meta describes the benchmark run the report is generated for
+type Meta struct {
+	Title       string      // Title is the report title, "Benchmark report" if not set
+	BenchmarkID string      // BenchmarkID identifies the run, not shown if empty
+	Database    string      // Database is the database driver and version string
+	Timestamp   time.Time   // Timestamp is the time the report is generated at, not shown if zero
+	Parameters  []Parameter // Parameters are the test parameters in the display order
+	Percentiles bool        // Percentiles shows the latency percentiles table, the scores must have the latencies collected
+}
+
+// categoryColors are the bar colours of the test categories in the order of their first appearance
+var categoryColors = []string{"#4e79a7", "#f28e2b", "#59a14f", "#e15759", "#76b7b2", "#edc948", "#b07aa1", "#ff9da7", "#9c755f", "#bab0ac"}
+
+// chartDataset is the Chart.js bar dataset of a single test category
+type chartDataset struct {
+	Label           string     `json:"label"`
+	Data            []*float64 `json:"data"`
+	BackgroundColor string     `json:"backgroundColor"`
+}
+
+// chartData is the Chart.js data of the rate chart, the bars missing in the dataset are null
+type chartData struct {
+	Labels   []string       `json:"labels"`
+	Datasets []chartDataset `json:"datasets"`
+}
+
+// latencyRow is the row of the latency percentiles table, the latencies are in milliseconds
+type latencyRow struct {
+	Test     string
+	Category string
+	Color    string
+	Workers  int
+	Loops    uint64
+	Rate     float64
+	Metric   string
+
+	Avg, P50, P95, P99, P999 float64
+}
+
+// reportData is the data of the report template
+type reportData struct {
+	Meta
+	Title   string
+	Rows    []latencyRow
+	Chart   chartData
+	Geomean float64
+}
+
+var reportTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<script src="https://cdn.jsdelivr.net/npm/chart.js@4"></script>
+<style>
+body { font-family: sans-serif; margin: 2em; color: #333; }
+table { border-collapse: collapse; margin: 1em 0; }
+th, td { border: 1px solid #ccc; padding: 4px 10px; text-align: right; }
+th:first-child, td:first-child { text-align: left; }
+tr.summary td { font-weight: bold; background: #f4f4f4; }
+.swatch { display: inline-block; width: 10px; height: 10px; margin-right: 4px; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<table>
+{{- if .BenchmarkID}}
+<tr><td>Benchmark ID</td><td>{{.BenchmarkID}}</td></tr>
+{{- end}}
+{{- if .Database}}
+<tr><td>Database</td><td>{{.Database}}</td></tr>
+{{- end}}
+{{- if not .Timestamp.IsZero}}
+<tr><td>Generated</td><td>{{.Timestamp.Format "2006-01-02 15:04:05 MST"}}</td></tr>
+{{- end}}
+{{- range .Parameters}}
+<tr><td>{{.Name}}</td><td>{{.Value}}</td></tr>
+{{- end}}
+</table>
+
+<h2>Rate</h2>
+<canvas id="rate" height="120"></canvas>
+<script>
+new Chart(document.getElementById("rate"), {
+  type: "bar",
+  data: {{.Chart}},
+  options: { skipNull: true, scales: { x: { stacked: true }, y: { beginAtZero: true, title: { display: true, text: "rate" } } } }
+});
+</script>
+
+<h2>Results</h2>
+<table>
+<tr><th>Test</th><th>Category</th><th>Workers</th><th>Loops</th><th>Rate</th><th>Metric</th>
+{{- if .Percentiles}}<th>avg, ms</th><th>p50, ms</th><th>p95, ms</th><th>p99, ms</th><th>p99.9, ms</th>{{end}}</tr>
+{{- range .Rows}}
+<tr><td>{{.Test}}</td><td><span class="swatch" style="background: {{.Color}}"></span>{{.Category}}</td><td>{{.Workers}}</td><td>{{.Loops}}</td><td>{{printf "%.1f" .Rate}}</td><td>{{.Metric}}</td>
+{{- if $.Percentiles}}<td>{{printf "%.3f" .Avg}}</td><td>{{printf "%.3f" .P50}}</td><td>{{printf "%.3f" .P95}}</td><td>{{printf "%.3f" .P99}}</td><td>{{printf "%.3f" .P999}}</td>{{end}}</tr>
+{{- end}}
+<tr class="summary"><td>geomean</td><td></td><td></td><td></td><td>{{printf "%.1f" .Geomean}}</td><td></td>
+{{- if .Percentiles}}<td></td><td></td><td></td><td></td><td></td>{{end}}</tr>
+</table>
+</body>
+</html>
+`))
+
+// durationMs converts the duration to fractional milliseconds
+func durationMs(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// Geomean returns the geometric mean of the scores rates, the scores with zero rate are skipped
+func Geomean(scores []Score) float64 {
+	var s float64
+	var n int
+	for _, score := range scores {
+		if score.Rate > 0 {
+			s += math.Log(score.Rate)
+			n++
+		}
+	}
+
+	if n == 0 {
+		return 0
+	}
+
+	return math.Exp(s / float64(n))
+}
+
+// Generate renders the HTML report of the given scores, the scores are shown in the given order
+func Generate(scores []Score, meta Meta) []byte {
+	var data = reportData{Meta: meta, Title: meta.Title, Geomean: Geomean(scores)}
+	if data.Title == "" {
+		data.Title = "Benchmark report"
+	}
+
+	var datasets = make(map[string]int)
+	for i, score := range scores {
+		var idx, exists = datasets[score.Category]
+		if !exists {
+			idx = len(data.Chart.Datasets)
+			datasets[score.Category] = idx
+			data.Chart.Datasets = append(data.Chart.Datasets, chartDataset{
+				Label:           score.Category,
+				Data:            make([]*float64, len(scores)),
+				BackgroundColor: categoryColors[idx%len(categoryColors)],
+			})
+		}
+
+		var rate = score.Rate
+		data.Chart.Labels = append(data.Chart.Labels, score.Test)
+		data.Chart.Datasets[idx].Data[i] = &rate
+
+		data.Rows = append(data.Rows, latencyRow{
+			Test:     score.Test,
+			Category: score.Category,
+			Color:    data.Chart.Datasets[idx].BackgroundColor,
+			Workers:  score.Workers,
+			Loops:    score.Loops,
+			Rate:     score.Rate,
+			Metric:   score.Metric,
+			Avg:      durationMs(score.LatencyAvg),
+			P50:      durationMs(score.P50),
+			P95:      durationMs(score.P95),
+			P99:      durationMs(score.P99),
+			P999:     durationMs(score.P999),
+		})
+	}
+
+	var buf bytes.Buffer
+	// the template is executed over the own data types into the memory buffer, so it never fails
+	_ = reportTemplate.Execute(&buf, data)
+
+	return buf.Bytes()
+}
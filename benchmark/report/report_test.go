@@ -0,0 +1,49 @@
+package report
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/acronis/perfkit/benchmark"
+)
+
+func TestGenerate(t *testing.T) {
+	scores := []Score{
+		{Test: "insert-light", Category: "INSERT", Score: benchmark.Score{Workers: 4, Loops: 1000, Rate: 100, Metric: "rows/sec", P99: 5 * time.Millisecond}},
+		{Test: "select-1", Category: "SELECT", Score: benchmark.Score{Workers: 4, Loops: 4000, Rate: 400, Metric: "loops/sec"}},
+		{Test: "<script>", Category: "SELECT", Score: benchmark.Score{Workers: 1, Loops: 10, Rate: 10, Metric: "loops/sec"}},
+	}
+
+	html := string(Generate(scores, Meta{
+		BenchmarkID: "run-1",
+		Database:    "postgres 16.2",
+		Parameters:  []Parameter{{"workers", "4"}},
+		Percentiles: true,
+	}))
+
+	for _, s := range []string{"Benchmark report", "run-1", "postgres 16.2", "workers", "chart.js", "insert-light", "p99, ms", "5.000", "geomean", "73.7",
+		`"label":"INSERT"`, `"label":"SELECT"`, `"data":[100,null,null]`, `"data":[null,400,10]`} {
+		if !strings.Contains(html, s) {
+			t.Errorf("Generate() error, the report doesn't contain %q:\n%s", s, html)
+		}
+	}
+
+	if strings.Contains(html, "<td><script>") {
+		t.Errorf("Generate() error, the test name is not escaped")
+	}
+
+	if html = string(Generate(scores, Meta{Title: "run"})); strings.Contains(html, "p99, ms") || !strings.Contains(html, "<title>run</title>") {
+		t.Errorf("Generate() error, the percentiles are shown or the title is not set:\n%s", html)
+	}
+}
+
+func TestGeomean(t *testing.T) {
+	if g := Geomean([]Score{{Score: benchmark.Score{Rate: 10}}, {Score: benchmark.Score{Rate: 1000}}, {Score: benchmark.Score{Rate: 0}}}); math.Abs(g-100) > 1e-9 {
+		t.Errorf("Geomean() = %v, want 100", g)
+	}
+	if g := Geomean(nil); g != 0 {
+		t.Errorf("Geomean() = %v, want 0", g)
+	}
+}
@@ -0,0 +1,124 @@
+package benchmark
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RandomizerSeedData is a struct for storing pre-generated values used by the randomizer instead of runtime generated ones
+type RandomizerSeedData struct {
+	UUIDs      []uuid.UUID `json:"uuid"`
+	Strings    []string    `json:"string"`
+	Integers   []int64     `json:"int"`
+	Timestamps []time.Time `json:"timestamp"`
+}
+
+// RandomizerSeedFileLoader is a loader of the pre-generated randomizer values from the JSON file
+/*
+ * The file is expected to be a JSON object with an array of values per every column type:
+ * {
+ *   "uuid":      ["5f2b...", ...],
+ *   "string":    ["abc", ...],
+ *   "int":       [1, 2, ...],
+ *   "timestamp": ["2024-01-02T15:04:05Z", ...]
+ * }
+ * Every array is optional, the randomizer falls back to the runtime generation for missing ones
+ */
+type RandomizerSeedFileLoader struct {
+	path string
+}
+
+// NewRandomizerSeedFileLoader returns new RandomizerSeedFileLoader object for given file path
+func NewRandomizerSeedFileLoader(path string) *RandomizerSeedFileLoader {
+	return &RandomizerSeedFileLoader{path: path}
+}
+
+// Load reads and parses the seed file
+func (l *RandomizerSeedFileLoader) Load() (*RandomizerSeedData, error) {
+	var content, err = os.ReadFile(l.path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading randomizer seed file %s: %v", l.path, err)
+	}
+
+	var data RandomizerSeedData
+	if err = json.Unmarshal(content, &data); err != nil {
+		return nil, fmt.Errorf("error parsing randomizer seed file %s: %v", l.path, err)
+	}
+
+	return &data, nil
+}
+
+// seedCursor is a struct for storing the worker position in every pre-generated array
+type seedCursor struct {
+	data       *RandomizerSeedData
+	uuids      int
+	strings    int
+	integers   int
+	timestamps int
+}
+
+// seedIndex returns the current cursor position within the array of given length and moves the cursor forward
+func seedIndex(pos *int, length int) int {
+	var ret = *pos % length
+	*pos = ret + 1
+
+	return ret
+}
+
+// nextUUID returns next pre-generated UUID, false if there are no UUIDs in the seed data
+func (sc *seedCursor) nextUUID() (uuid.UUID, bool) {
+	if sc == nil || len(sc.data.UUIDs) == 0 {
+		return uuid.Nil, false
+	}
+
+	return sc.data.UUIDs[seedIndex(&sc.uuids, len(sc.data.UUIDs))], true
+}
+
+// nextString returns next pre-generated string, false if there are no strings in the seed data
+func (sc *seedCursor) nextString() (string, bool) {
+	if sc == nil || len(sc.data.Strings) == 0 {
+		return "", false
+	}
+
+	return sc.data.Strings[seedIndex(&sc.strings, len(sc.data.Strings))], true
+}
+
+// nextInteger returns next pre-generated integer, false if there are no integers in the seed data
+func (sc *seedCursor) nextInteger() (int64, bool) {
+	if sc == nil || len(sc.data.Integers) == 0 {
+		return 0, false
+	}
+
+	return sc.data.Integers[seedIndex(&sc.integers, len(sc.data.Integers))], true
+}
+
+// nextTimestamp returns next pre-generated timestamp, false if there are no timestamps in the seed data
+func (sc *seedCursor) nextTimestamp() (time.Time, bool) {
+	if sc == nil || len(sc.data.Timestamps) == 0 {
+		return time.Time{}, false
+	}
+
+	return sc.data.Timestamps[seedIndex(&sc.timestamps, len(sc.data.Timestamps))], true
+}
+
+// SetSeedData makes every randomizer worker pick values from the pre-generated data
+/*
+ * Every worker starts from its own position (derived from the worker ID) and walks the arrays sequentially,
+ * so the same seed file and the same workers count give the same values in the same order
+ */
+func (rz *Randomizer) SetSeedData(data *RandomizerSeedData) {
+	for workerID, rw := range rz.worker {
+		var start = workerID + 1
+		rw.seed = &seedCursor{
+			data:       data,
+			uuids:      start,
+			strings:    start,
+			integers:   start,
+			timestamps: start,
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package benchmark
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRandomizerSeedFileLoader(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "seed.json")
+	content := `{
+		"uuid": ["5f2b6c3e-1d4a-4b8e-9f1a-2c3d4e5f6a7b"],
+		"string": ["first", "second"],
+		"int": [7, 11, 13],
+		"timestamp": ["2024-01-02T15:04:05Z"]
+	}`
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("cannot write seed file: %v", err)
+	}
+
+	data, err := NewRandomizerSeedFileLoader(path).Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(data.UUIDs) != 1 || len(data.Strings) != 2 || len(data.Integers) != 3 || len(data.Timestamps) != 1 {
+		t.Fatalf("Load() error, unexpected data %+v", data)
+	}
+
+	rz := NewRandomizer(1, 1)
+	rz.SetSeedData(data)
+
+	rw := rz.GetWorker(0)
+	// worker 0 starts from position 1
+	for _, want := range []uint64{11, 13, 7, 11} {
+		if got := rw.Uintn64(100); got != want {
+			t.Errorf("Uintn64() got = %d, want %d", got, want)
+		}
+	}
+	if got := rw.UUID().String(); got != "5f2b6c3e-1d4a-4b8e-9f1a-2c3d4e5f6a7b" {
+		t.Errorf("UUID() got = %s, want value from the seed file", got)
+	}
+}
+
+func TestRandomizerSeedFileLoaderMissingFile(t *testing.T) {
+	if _, err := NewRandomizerSeedFileLoader(filepath.Join(t.TempDir(), "missing.json")).Load(); err == nil {
+		t.Errorf("Load() expected error for missing file")
+	}
+}
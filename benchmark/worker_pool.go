@@ -0,0 +1,82 @@
+package benchmark
+
+import (
+	"context"
+	"sync"
+)
+
+// WorkerPool runs the work function by given number of goroutines, every goroutine gets its own worker ID from 0 to n-1
+// The work function is expected to return when the pool context (see Context) is done
+type WorkerPool struct {
+	n           int
+	work        func(workerID int)
+	contextFunc func() context.Context
+
+	lock    sync.Mutex
+	ctx     context.Context
+	cancel  context.CancelFunc
+	started bool
+	wg      sync.WaitGroup
+}
+
+// NewWorkerPool creates a new WorkerPool of n workers calling work, the workers are not started until Start is called
+func NewWorkerPool(n int, work func(workerID int)) *WorkerPool {
+	return &WorkerPool{
+		n:           n,
+		work:        work,
+		contextFunc: context.Background,
+	}
+}
+
+// SetContextFunc sets the function returning the parent context of the pool (context.Background by default), it must be called before Start
+func (p *WorkerPool) SetContextFunc(contextFunc func() context.Context) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.contextFunc = contextFunc
+}
+
+// Start launches the workers, the subsequent calls do nothing
+func (p *WorkerPool) Start() {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if p.started {
+		return
+	}
+	p.started = true
+
+	p.ctx, p.cancel = context.WithCancel(p.contextFunc())
+
+	p.wg.Add(p.n)
+	for i := 0; i < p.n; i++ {
+		go func(workerID int) {
+			defer p.wg.Done()
+			p.work(workerID)
+		}(i)
+	}
+}
+
+// Context returns the pool context which is done after Stop is called or the parent context is done, it's nil until Start is called
+func (p *WorkerPool) Context() context.Context {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	return p.ctx
+}
+
+// Stop cancels the pool context to make the workers return, use Wait to wait for them
+func (p *WorkerPool) Stop() {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if p.cancel != nil {
+		p.cancel()
+	}
+}
+
+// Wait waits for all the workers to return, the pool context is released afterwards
+func (p *WorkerPool) Wait() {
+	p.wg.Wait()
+	p.Stop()
+}
@@ -0,0 +1,69 @@
+package benchmark
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWorkerPool(t *testing.T) {
+	var calls = make([]int32, 4)
+	var pool = NewWorkerPool(len(calls), func(workerID int) {
+		atomic.AddInt32(&calls[workerID], 1)
+	})
+
+	pool.Start()
+	pool.Start()
+	pool.Wait()
+
+	for id, n := range calls {
+		if n != 1 {
+			t.Errorf("WorkerPool worker %d called %d times, want 1", id, n)
+		}
+	}
+}
+
+func TestWorkerPoolStop(t *testing.T) {
+	var pool *WorkerPool
+	pool = NewWorkerPool(2, func(workerID int) { //nolint:revive
+		<-pool.Context().Done()
+	})
+
+	pool.Start()
+	pool.Stop()
+
+	var waited = make(chan struct{})
+	go func() {
+		pool.Wait()
+		close(waited)
+	}()
+
+	select {
+	case <-waited:
+	case <-time.After(time.Second):
+		t.Fatalf("WorkerPool.Wait() didn't return after Stop()")
+	}
+}
+
+func TestWorkerPoolSetContextFunc(t *testing.T) {
+	type key struct{}
+
+	var got atomic.Value
+	var parent, cancel = context.WithCancel(context.WithValue(context.Background(), key{}, "value"))
+
+	var pool *WorkerPool
+	pool = NewWorkerPool(1, func(workerID int) { //nolint:revive
+		got.Store(pool.Context().Value(key{}))
+		<-pool.Context().Done()
+	})
+	pool.SetContextFunc(func() context.Context { return parent })
+
+	pool.Start()
+	cancel()
+	pool.Wait()
+
+	if got.Load() != "value" {
+		t.Errorf("WorkerPool context value = %v, want %v", got.Load(), "value")
+	}
+}
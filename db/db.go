@@ -2,9 +2,12 @@ package db
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
 	"time"
+
+	"go.opentelemetry.io/otel/trace"
 )
 
 type DialectName string
@@ -20,6 +23,11 @@ const (
 	CASSANDRA     DialectName = "cassandra"     // CASSANDRA is the Cassandra driver name
 	ELASTICSEARCH DialectName = "elasticsearch" // ELASTICSEARCH is the Elasticsearch driver name
 	OPENSEARCH    DialectName = "opensearch"    // OPENSEARCH is the OpenSearch driver name
+	MONGODB       DialectName = "mongodb"       // MONGODB is the MongoDB driver name
+	REDIS         DialectName = "redis"         // REDIS is the Redis driver name
+	VALKEY        DialectName = "valkey"        // VALKEY is the Valkey driver name
+	SCYLLA        DialectName = "scylla"        // SCYLLA is the ScyllaDB driver name
+	COCKROACHDB   DialectName = "cockroachdb"   // COCKROACHDB is the CockroachDB driver name
 )
 
 // Special conditions for searching
@@ -28,6 +36,12 @@ const (
 	SpecialConditionIsNotNull = "notnull()"
 )
 
+// ErrQueryTimeout is returned when a query is cancelled on the client side because of Config.QueryTimeout
+var ErrQueryTimeout = errors.New("client-side query timeout")
+
+// ErrUniqueViolation is returned when an insert or update is rejected by a PRIMARY KEY / UNIQUE constraint or a unique index
+var ErrUniqueViolation = errors.New("unique constraint violation")
+
 // Connector is an interface for registering database connectors without knowing the specific connector implementations
 type Connector interface {
 	ConnectionPool(cfg Config) (Database, error)
@@ -56,18 +70,49 @@ func Register(schema string, conn Connector) error {
 // Config - database configuration
 type Config struct {
 	ConnString      string
+	ReadConnString  string // read replica serving Session Select and Query calls outside of the transactions, supported by the SQL databases only
 	MaxOpenConns    int
 	MaxConnLifetime time.Duration
 	MaxPacketSize   int
+	QueryTimeout    time.Duration
+	DeadlockRetries int           // number of transaction retries with a random backoff on deadlock, 0 keeps the driver default
+	MaxRetries      int           // number of transaction retries on any retriable error (deadlock, serialization failure of CockroachDB, PostgreSQL if set), 0 keeps the driver default
+	RetryBaseDelay  time.Duration // base delay of the transaction retries doubled on every retry and capped at 30s, 0 keeps the default backoffs
 	DryRun          bool
 	UseTruncate     bool
 
+	ClickHouseColumnar bool // send ClickHouse bulk inserts column-oriented by the native protocol batch API
+
+	CassandraReplicationFactor int    // replication factor of the Cassandra / ScyllaDB keyspace created if it doesn't exist yet, 0 doesn't create the keyspace
+	CassandraConsistency       string // consistency level of the Cassandra / ScyllaDB queries (ANY, ONE, QUORUM, ALL), empty keeps the connection string one
+
+	ScyllaShardCount int // number of shards (CPU cores) of every ScyllaDB node, used to tell the shard-local queries from the cross-shard ones, 0 disables it
+
+	ESMaxBulkRetries   int           // number of the Elasticsearch / OpenSearch _bulk request retries on 429 Too Many Requests, 0 disables the retries
+	ESBulkRetryBackoff time.Duration // initial backoff of the _bulk request retries doubled on every retry, the Retry-After header takes precedence
+
 	TLSEnabled bool
 	TLSCACert  []byte
 
 	QueryLogger      Logger
 	ReadedRowsLogger Logger
 	QueryTimeLogger  Logger
+	RetryLogger      Logger // notified about every transaction retry, supported by the SQL databases only
+
+	QueryRecorder QueryRecorder // notified about every executed query, supported by the SQL databases only
+
+	// TracerProvider enables the OpenTelemetry client spans of Session Exec, Query, QueryRow and Transact calls,
+	// the spans are the children of the span of the Context.Ctx, supported by the SQL databases only
+	TracerProvider trace.TracerProvider
+
+	// ConnectHook is called with the database bound to every new connection of the pool (e.g. to run SET search_path),
+	// the connection is discarded if the hook fails, supported by PostgreSQL, MySQL, MSSQL, SQLite and ClickHouse only
+	ConnectHook func(db Database) error
+}
+
+// QueryRecorder receives every query executed by the database with its arguments and duration
+type QueryRecorder interface {
+	RecordQuery(query string, args []interface{}, duration time.Duration)
 }
 
 // Open opens a database connection
@@ -123,12 +168,31 @@ type Page struct {
 	Offset int64
 }
 
+// AggregateFunction is an aggregate function applied by AggregateField
+type AggregateFunction string
+
+// Supported aggregate functions
+const (
+	AggregateCount AggregateFunction = "COUNT"
+	AggregateSum   AggregateFunction = "SUM"
+	AggregateAvg   AggregateFunction = "AVG"
+	AggregateMin   AggregateFunction = "MIN"
+	AggregateMax   AggregateFunction = "MAX"
+)
+
+// AggregateField is an aggregate function of the column selected with SelectCtrl.Aggregates
+type AggregateField struct {
+	Function AggregateFunction
+	Column   string // empty means all rows, allowed for COUNT only
+}
+
 // SelectCtrl is a struct for storing select control information
 type SelectCtrl struct {
-	Fields []string // empty means select count
-	Where  map[string][]string
-	Order  []string
-	Page   Page
+	Fields     []string         // empty means select count
+	Aggregates []AggregateField // if set, a single row of the aggregated values is selected instead of Fields, Order and Page are ignored
+	Where      map[string][]string
+	Order      []string // asc(col), desc(col), asc_nulls_first(col), asc_nulls_last(col), desc_nulls_first(col) or desc_nulls_last(col)
+	Page       Page
 
 	OptimizeConditions bool
 }
@@ -136,6 +200,36 @@ type SelectCtrl struct {
 // databaseSelector is an interface for searching the database
 type databaseSelector interface {
 	Select(tableName string, c *SelectCtrl) (Rows, error)
+	// ExistsRow returns true if at least one row matches the SelectCtrl conditions, Fields, Order and Page are ignored
+	ExistsRow(tableName string, c *SelectCtrl) (bool, error)
+}
+
+// UpdateCtrl is a struct for storing update control information
+type UpdateCtrl struct {
+	Set       map[string]interface{}
+	Where     map[string][]string
+	Returning []string // only for PostgreSQL, empty means no RETURNING clause
+
+	OptimizeConditions bool
+}
+
+// databaseUpdater is an interface for updating the database
+type databaseUpdater interface {
+	UpdateReturning(tableName string, c *UpdateCtrl) (Rows, error)
+}
+
+// DeleteCtrl is a struct for storing delete control information
+type DeleteCtrl struct {
+	Where map[string][]string
+
+	OptimizeConditions bool
+}
+
+// databaseDeleter is an interface for deleting from the database
+type databaseDeleter interface {
+	// Delete deletes the rows matching the conditions and returns the number of deleted rows,
+	// -1 if the database doesn't report it (Cassandra, ScyllaDB), the statement is only logged in the dry-run mode
+	Delete(tableName string, c *DeleteCtrl) (int64, error)
 }
 
 // InsertStats is a struct for storing insert statistics
@@ -144,15 +238,27 @@ type InsertStats struct {
 	Failed            int64
 	Total             int64
 	ExpectedSuccesses int64
+	Retries           int64 // requests retried on 429 Too Many Requests (Elasticsearch / OpenSearch only)
 }
 
 func (s *InsertStats) String() string {
-	return fmt.Sprintf("successful: %d, failed: %d, total: %d", s.Successful, s.Failed, s.Total)
+	return fmt.Sprintf("successful: %d, failed: %d, total: %d, retries: %d", s.Successful, s.Failed, s.Total, s.Retries)
+}
+
+// BulkInsertCtrl is a struct for storing bulk insert control information
+type BulkInsertCtrl struct {
+	Rows        [][]interface{}
+	ColumnNames []string
+
+	IgnoreConflicts bool // skip rows violating unique constraints instead of failing, only for relational databases
 }
 
 // databaseInserter is an interface for inserting data into the database
 type databaseInserter interface {
-	BulkInsert(tableName string, rows [][]interface{}, columnNames []string) error
+	BulkInsert(tableName string, c *BulkInsertCtrl) error
+	// Upsert inserts the rows and updates the updateColumns of the rows conflicting by the conflictColumns unique key instead,
+	// the conflicting rows are left as is if updateColumns is empty, the statement is only logged in the dry-run mode
+	Upsert(tableName string, rows [][]interface{}, columnNames []string, conflictColumns []string, updateColumns []string) error
 }
 
 // databaseQuerier is an interface for low-level querying the database
@@ -183,6 +289,8 @@ type databaseQueryPreparer interface {
 type DatabaseAccessor interface {
 	databaseQueryRegistrator
 	databaseSelector
+	databaseUpdater
+	databaseDeleter
 	databaseInserter
 	databaseQuerier
 	databaseQueryPreparer
@@ -194,6 +302,9 @@ type Session interface {
 
 	Transact(func(tx DatabaseAccessor) error) error
 
+	// RunInReadOnlyTransaction runs fn in the read-only transaction (snapshot isolation for MSSQL), databases without transactions run fn as is
+	RunInReadOnlyTransaction(func(tx DatabaseAccessor) error) error
+
 	// GetNextVal is presented in Session interface to restrict using it inside transaction
 	GetNextVal(sequenceName string) (uint64, error)
 }
@@ -204,6 +315,7 @@ type TableRow struct {
 	PrimaryKey bool
 	NotNull    bool
 	Indexed    bool // only for Elasticsearch
+	Dims       int  // only for DataTypeVectorFloat32
 }
 
 type ResilienceSettings struct {
@@ -217,6 +329,7 @@ type TableDefinition struct {
 	Engine     string
 	Resilience ResilienceSettings
 	LMPolicy   string // only for Elasticsearch
+	KNNEngine  string // only for OpenSearch, k-NN engine (faiss, nmslib or lucene) of the vector fields, empty means dense_vector mapping
 }
 
 type IndexType string
@@ -242,6 +355,7 @@ type databaseMigrator interface {
 
 	IndexExists(indexName string, tableName string) (bool, error)
 	CreateIndex(indexName string, tableName string, columns []string, indexType IndexType) error
+	CreateUniqueIndex(indexName string, tableName string, columns []string) error
 	DropIndex(indexName string, tableName string) error
 
 	ReadConstraints() ([]Constraint, error)
@@ -250,6 +364,12 @@ type databaseMigrator interface {
 
 	CreateSequence(sequenceName string) error
 	DropSequence(sequenceName string) error
+
+	CreateSchema(schemaName string) error
+	DropSchema(schemaName string) error
+
+	// Defragment rebuilds the table and its indexes to reclaim the space left by the updated and deleted rows
+	Defragment(tableName string) error
 }
 
 // databaseDescriber is an interface for describing the database
@@ -258,13 +378,65 @@ type databaseDescriber interface {
 	GetInfo(version string) (ret []string, info *Info, err error)
 	GetTablesSchemaInfo(tableNames []string) ([]string, error)
 	GetTablesVolumeInfo(tableNames []string) ([]string, error)
+	GetIndexUsageStats(tableName string) ([]IndexUsageStat, error)
+	GetLockWaitStats() ([]LockWaitStat, error)
+	GetActiveQueries() ([]ActiveQuery, error)
+	GetCheckpointStats() (CheckpointStats, error)
+}
+
+// IndexUsageStat is a struct for storing index usage statistics of a table
+type IndexUsageStat struct {
+	IndexName   string
+	TableName   string
+	SeqScans    int64 // The number of sequential (full) scans initiated on the table.
+	IdxScans    int64 // The number of index scans initiated on the index.
+	IdxTupRead  int64 // The number of index entries returned by scans on the index.
+	IdxTupFetch int64 // The number of live table rows fetched by scans on the index.
+}
+
+// LockWaitStat is a struct for storing the lock wait of a DB session blocked by another one
+type LockWaitStat struct {
+	PID           int64         // The process (connection) ID of the waiting session.
+	WaitingQuery  string        // The query waiting for the lock.
+	BlockingPID   int64         // The process (connection) ID of the session holding the lock, 0 if unknown.
+	BlockingQuery string        // The last query of the blocking session.
+	WaitDuration  time.Duration // How long the query is waiting.
+}
+
+// ActiveQueryMinDuration is the minimal duration of the queries returned by GetActiveQueries
+const ActiveQueryMinDuration = 100 * time.Millisecond
+
+// ActiveQuery is a struct for storing the query currently executed by a DB session
+type ActiveQuery struct {
+	PID       int64         // The process (connection) ID of the session.
+	User      string        // The user the session is connected as.
+	State     string        // The session state (e.g. active, running, suspended).
+	Query     string        // The query text.
+	Duration  time.Duration // How long the query is running.
+	WaitEvent string        // The event the query is waiting for, empty if the query is not waiting.
+}
+
+// CheckpointStats is a struct for storing the cumulative checkpoint statistics of the database server
+type CheckpointStats struct {
+	CheckpointsTimed    int64         // The number of scheduled checkpoints performed (triggered by checkpoint_timeout).
+	CheckpointsReq      int64         // The number of requested checkpoints performed (triggered by max_wal_size or CHECKPOINT).
+	CheckpointWriteTime time.Duration // The time spent writing the checkpoint files to disk.
+	CheckpointSyncTime  time.Duration // The time spent synchronizing the checkpoint files to disk.
+	BuffersCheckpoint   int64         // The number of buffers written during checkpoints.
 }
 
 // Stats is a struct for storing database statistics
 type Stats struct {
-	OpenConnections int // The number of established connections both in use and idle.
-	InUse           int // The number of connections currently in use.
-	Idle            int // The number of idle connections.
+	MaxOpenConnections int   // Maximum number of open connections to the database.
+	OpenConnections    int   // The number of established connections both in use and idle.
+	InUse              int   // The number of connections currently in use.
+	Idle               int   // The number of idle connections.
+	WaitCount          int64 // The total number of connections waited for.
+
+	ShardLocalQueries int64 // The total number of queries served by a single shard, ScyllaDB with Config.ScyllaShardCount only.
+	CrossShardQueries int64 // The total number of queries spanning several shards, ScyllaDB with Config.ScyllaShardCount only.
+
+	Read *Stats // The read replica pool statistics, nil if Config.ReadConnString is not set.
 }
 
 // Context is a struct for storing database context
@@ -286,6 +458,9 @@ type Database interface {
 	databaseMigrator
 	databaseDescriber
 
+	// RunInSchemaSandbox creates the schema (PostgreSQL schema / MySQL database), runs fn and drops the schema afterwards
+	RunInSchemaSandbox(schemaName string, fn func() error) error
+
 	Context(ctx context.Context) *Context
 	Session(ctx *Context) Session
 	RawSession() interface{}
@@ -329,6 +504,12 @@ const (
 	DataTypeTenantUUIDBoundID DataType = "{$tenant_uuid_bound_id}"
 	DataTypeVector3Float32    DataType = "{$vector_3_float32}"
 	DataTypeVector768Float32  DataType = "{$vector_768_float32}"
+	DataTypeVectorFloat32     DataType = "{$vector_float32}" // vector of TableRow.Dims dimensions, only for Elasticsearch / OpenSearch and PostgreSQL pgvector
+	DataTypeJSON              DataType = "{$json}"           // JSON document, binary representation where the database has a choice
+	DataTypeJSONB             DataType = "{$jsonb}"          // binary JSON (PostgreSQL JSONB), falls back to JSON elsewhere
+	DataTypeJSONText          DataType = "{$json_text}"      // text JSON stored verbatim to avoid reparse costs (PostgreSQL JSON, MySQL LONGTEXT)
+	DataTypeInet              DataType = "{$inet}"           // IPv4 or IPv6 host address (PostgreSQL INET), stored as text elsewhere
+	DataTypeCidr              DataType = "{$cidr}"           // IPv4 or IPv6 network address (PostgreSQL CIDR), stored as text elsewhere
 )
 
 // Dialect is an interface for database dialects
@@ -365,6 +546,14 @@ func GetDatabases() []DBType {
 	ret = append(ret, DBType{Driver: CASSANDRA, Symbol: "A", Name: "Cassandra"})
 	ret = append(ret, DBType{Driver: ELASTICSEARCH, Symbol: "E", Name: "Elasticsearch"})
 	ret = append(ret, DBType{Driver: OPENSEARCH, Symbol: "O", Name: "OpenSearch"})
+	// "G" is used as the latest symbol of the "MongoDB" due to duplicate with MySQL "M"
+	ret = append(ret, DBType{Driver: MONGODB, Symbol: "G", Name: "MongoDB"})
+	ret = append(ret, DBType{Driver: REDIS, Symbol: "R", Name: "Redis"})
+	ret = append(ret, DBType{Driver: VALKEY, Symbol: "V", Name: "Valkey"})
+	// "Y" is used as the latest symbol of the "ScyllaDB" due to duplicate with SQLite "S"
+	ret = append(ret, DBType{Driver: SCYLLA, Symbol: "Y", Name: "ScyllaDB"})
+	// "K" is used as the latest symbol of the "CockroachDB" due to duplicate with ClickHouse "C"
+	ret = append(ret, DBType{Driver: COCKROACHDB, Symbol: "K", Name: "CockroachDB"})
 
 	return ret
 }
@@ -134,6 +134,8 @@ func (c *esConnector) ConnectionPool(cfg db.Config) (db.Database, error) {
 		mig:         rw,
 		dialect:     &elasticSearchDialect{},
 		queryLogger: cfg.QueryLogger,
+		bulkRetry:   newBulkRetryPolicy(cfg),
+		dryRun:      cfg.DryRun,
 	}, nil
 }
 
@@ -264,6 +266,8 @@ func (q *esQuerier) insert(ctx context.Context, idxName indexName, query *BulkIn
 		q.es.Bulk.WithRefresh("wait_for"))
 	if err != nil {
 		return nil, 0, fmt.Errorf("error from elasticsearch while performing bulk insert: %v", err)
+	} else if res.StatusCode == http.StatusTooManyRequests {
+		return nil, 0, newTooManyRequestsError(res.Header, res.String())
 	} else if res.IsError() {
 		return nil, 0, fmt.Errorf("bulk insert error %s", res.String())
 	}
@@ -362,6 +366,72 @@ func (q *esQuerier) count(ctx context.Context, idxName indexName, request *Count
 	return resp.Count, nil
 }
 
+func (q *esQuerier) deleteByQuery(ctx context.Context, idxName indexName, request *DeleteByQueryRequest) (int64, error) {
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(request); err != nil {
+		return 0, fmt.Errorf("request encode error: %v", err)
+	}
+
+	var res, err = q.es.DeleteByQuery(
+		[]string{string(idxName)},
+		&buf,
+		q.es.DeleteByQuery.WithContext(ctx))
+	if err != nil {
+		return 0, fmt.Errorf("failed to perform delete by query: %v", err)
+	}
+
+	// nolint: errcheck // Need to have logger here for deferred errors
+	defer res.Body.Close()
+
+	if res.IsError() {
+		if res.StatusCode == 404 {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("failed to perform delete by query: %s", res.String())
+	}
+
+	var resp struct {
+		Deleted int64 `json:"deleted"`
+	}
+	if err = json.NewDecoder(res.Body).Decode(&resp); err != nil {
+		return 0, fmt.Errorf("delete by query response decode err: %v", err)
+	}
+
+	return resp.Deleted, nil
+}
+
+func (q *esQuerier) aggregate(ctx context.Context, idxName indexName, request *AggregateRequest) (*AggregateResponse, error) {
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(request); err != nil {
+		return nil, fmt.Errorf("request encode error: %v", err)
+	}
+
+	var res, err = q.es.Search(
+		q.es.Search.WithContext(ctx),
+		q.es.Search.WithIndex(string(idxName)),
+		q.es.Search.WithBody(&buf))
+	if err != nil {
+		return nil, fmt.Errorf("failed to perform aggregation: %v", err)
+	}
+
+	// nolint: errcheck // Need to have logger here for deferred errors
+	defer res.Body.Close()
+
+	if res.IsError() {
+		if res.StatusCode == 404 {
+			return &AggregateResponse{}, nil
+		}
+		return nil, fmt.Errorf("failed to perform aggregation: %s", res.String())
+	}
+
+	var resp = AggregateResponse{}
+	if err = json.NewDecoder(res.Body).Decode(&resp); err != nil {
+		return nil, fmt.Errorf("aggregation response decode err: %v", err)
+	}
+
+	return &resp, nil
+}
+
 func (q *esQuerier) checkILMPolicyExists(policyName string) (bool, error) {
 	var res, err = q.es.ILM.GetLifecycle(
 		q.es.ILM.GetLifecycle.WithContext(context.Background()),
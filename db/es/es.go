@@ -15,6 +15,8 @@ type querier interface {
 	insert(ctx context.Context, idxName indexName, query *BulkIndexRequest) (*BulkIndexResult, int, error)
 	search(ctx context.Context, idxName indexName, query *SearchRequest) ([]map[string]interface{}, error)
 	count(ctx context.Context, idxName indexName, query *CountRequest) (int64, error)
+	deleteByQuery(ctx context.Context, idxName indexName, query *DeleteByQueryRequest) (int64, error)
+	aggregate(ctx context.Context, idxName indexName, query *AggregateRequest) (*AggregateResponse, error)
 }
 
 type accessor interface {
@@ -29,6 +31,10 @@ type accessor interface {
 type esGateway struct {
 	q   querier
 	ctx *db.Context
+
+	bulkRetry   bulkRetryPolicy
+	queryLogger db.Logger
+	dryRun      bool
 }
 
 type esSession struct {
@@ -39,12 +45,18 @@ func (s *esSession) Transact(fn func(tx db.DatabaseAccessor) error) error {
 	return fn(s)
 }
 
+func (s *esSession) RunInReadOnlyTransaction(fn func(tx db.DatabaseAccessor) error) error {
+	return fn(s)
+}
+
 type esDatabase struct {
 	rw      accessor
 	mig     migrator
 	dialect dialect
 
 	queryLogger db.Logger
+	bulkRetry   bulkRetryPolicy
+	dryRun      bool
 }
 
 // Ping pings the DB
@@ -77,6 +89,10 @@ func (d *esDatabase) TableExists(tableName string) (bool, error) {
 }
 
 func (d *esDatabase) CreateTable(tableName string, tableDefinition *db.TableDefinition, tableMigrationDDL string) error {
+	if tableDefinition != nil && tableDefinition.KNNEngine != "" && d.dialect.name() != db.OPENSEARCH {
+		return fmt.Errorf("index %s: k-NN engine is supported by opensearch only", tableName)
+	}
+
 	return createIndex(d.mig, tableName, tableDefinition, tableMigrationDDL)
 }
 
@@ -92,6 +108,10 @@ func (d *esDatabase) CreateIndex(indexName string, tableName string, columns []s
 	return nil
 }
 
+func (d *esDatabase) CreateUniqueIndex(indexName string, tableName string, columns []string) error {
+	return fmt.Errorf("unique indexes are not supported for %s dialect", d.dialect.name())
+}
+
 func (d *esDatabase) DropIndex(indexName string, tableName string) error {
 	return nil
 }
@@ -116,6 +136,22 @@ func (d *esDatabase) DropSequence(sequenceName string) error {
 	return nil
 }
 
+func (d *esDatabase) CreateSchema(schemaName string) error {
+	return fmt.Errorf("schema %s: schemas are not supported by elasticsearch", schemaName)
+}
+
+func (d *esDatabase) DropSchema(schemaName string) error {
+	return fmt.Errorf("schema %s: schemas are not supported by elasticsearch", schemaName)
+}
+
+func (d *esDatabase) Defragment(tableName string) error {
+	return fmt.Errorf("index %s: defragmentation is not supported by elasticsearch", tableName)
+}
+
+func (d *esDatabase) RunInSchemaSandbox(schemaName string, fn func() error) error { //nolint:revive
+	return fmt.Errorf("schema %s: schema sandbox is not supported by elasticsearch", schemaName)
+}
+
 func (d *esDatabase) GetTablesSchemaInfo(tableNames []string) ([]string, error) {
 	return getTablesSchemaInfo(d.rw, tableNames)
 }
@@ -124,6 +160,22 @@ func (d *esDatabase) GetTablesVolumeInfo(tableNames []string) ([]string, error)
 	return getTablesVolumeInfo(d.rw, tableNames)
 }
 
+func (d *esDatabase) GetIndexUsageStats(tableName string) ([]db.IndexUsageStat, error) {
+	return getIndexUsageStats(d.rw, tableName)
+}
+
+func (d *esDatabase) GetLockWaitStats() ([]db.LockWaitStat, error) {
+	return nil, nil
+}
+
+func (d *esDatabase) GetActiveQueries() ([]db.ActiveQuery, error) {
+	return nil, nil
+}
+
+func (d *esDatabase) GetCheckpointStats() (db.CheckpointStats, error) {
+	return db.CheckpointStats{}, nil
+}
+
 func (d *esDatabase) Context(ctx context.Context) *db.Context {
 	return &db.Context{Ctx: ctx}
 }
@@ -133,6 +185,10 @@ func (d *esDatabase) Session(c *db.Context) db.Session {
 		esGateway: esGateway{
 			q:   timedQuerier{q: d.rw, dbtime: atomic.NewInt64(c.DBtime.Nanoseconds()), queryLogger: d.queryLogger},
 			ctx: c,
+
+			bulkRetry:   d.bulkRetry,
+			queryLogger: d.queryLogger,
+			dryRun:      d.dryRun,
 		},
 	}
 }
@@ -195,6 +251,26 @@ func (tq timedQuerier) count(ctx context.Context, idxName indexName, request *Co
 	return tq.q.count(ctx, idxName, request)
 }
 
+func (tq timedQuerier) deleteByQuery(ctx context.Context, idxName indexName, request *DeleteByQueryRequest) (int64, error) {
+	defer accountTime(tq.dbtime, time.Now())
+
+	if tq.queryLogger != nil {
+		tq.queryLogger.Log("delete by query:\n%s", request.String())
+	}
+
+	return tq.q.deleteByQuery(ctx, idxName, request)
+}
+
+func (tq timedQuerier) aggregate(ctx context.Context, idxName indexName, request *AggregateRequest) (*AggregateResponse, error) {
+	defer accountTime(tq.dbtime, time.Now())
+
+	if tq.queryLogger != nil {
+		tq.queryLogger.Log("aggregate:\n%s", request.String())
+	}
+
+	return tq.q.aggregate(ctx, idxName, request)
+}
+
 type dialect interface {
 	name() db.DialectName
 }
@@ -30,3 +30,8 @@ func getTableSizeMB(q querier, tableName string) (int64, error) {
 func getTablesVolumeInfo(q querier, tableNames []string) ([]string, error) {
 	return nil, nil
 }
+
+// getIndexUsageStats returns the usage statistics of the indexes of a given table
+func getIndexUsageStats(q querier, tableName string) ([]db.IndexUsageStat, error) {
+	return nil, nil
+}
@@ -74,12 +74,17 @@ func (bi *BulkIndexResult) GetInsertStats(expectedSuccesses int) db.InsertStats
 	return dbs
 }
 
-func (g *esGateway) BulkInsert(tableName string, rows [][]interface{}, columnNames []string) error {
+func (g *esGateway) BulkInsert(tableName string, c *db.BulkInsertCtrl) error {
+	var rows, columnNames = c.Rows, c.ColumnNames
 	if len(rows) == 0 {
 		return nil
 	}
 
 	var idxName = indexName(tableName)
+	if c.IgnoreConflicts {
+		return fmt.Errorf("index %s: ignoring conflicts is not supported by elasticsearch", idxName)
+	}
+
 	var req = &BulkIndexRequest{
 		data: []json.RawMessage{},
 	}
@@ -119,12 +124,19 @@ func (g *esGateway) BulkInsert(tableName string, rows [][]interface{}, columnNam
 		req.With(idxName, id, serialized)
 	}
 
-	res, expectedSuccesses, err := g.q.insert(g.ctx.Ctx, idxName, req)
+	res, expectedSuccesses, retries, err := insertWithRetry(g.ctx.Ctx, g.q, idxName, req, g.bulkRetry, g.queryLogger)
+	g.ctx.TxRetries += retries
 	if err != nil {
 		return fmt.Errorf("error in bulk insert: %v", err)
 	}
 
-	var _ = res.GetInsertStats(expectedSuccesses)
+	var stats = res.GetInsertStats(expectedSuccesses)
+	stats.Retries = int64(retries)
 
 	return nil
 }
+
+// Upsert is not supported by Elasticsearch / OpenSearch as the documents have no unique keys except the _id
+func (g *esGateway) Upsert(idxName string, rows [][]interface{}, columnNames []string, conflictColumns []string, updateColumns []string) error { //nolint:revive
+	return fmt.Errorf("index %s: upsert is not supported by elasticsearch", idxName)
+}
@@ -61,7 +61,10 @@ func (suite *TestingSuite) TestInsert() {
 		})
 	}
 
-	if err := s.BulkInsert("perf_table", toInsert, []string{"@timestamp", "id", "uuid", "type", "policy_name", "resource_name", "accessors", "start_time"}); err != nil {
+	if err := s.BulkInsert("perf_table", &db.BulkInsertCtrl{
+		Rows:        toInsert,
+		ColumnNames: []string{"@timestamp", "id", "uuid", "type", "policy_name", "resource_name", "accessors", "start_time"},
+	}); err != nil {
 		suite.T().Error(err)
 		return
 	}
@@ -53,6 +53,7 @@ type indexSettings struct {
 	NumberOfShards     int    `json:"number_of_shards,omitempty"`
 	NumberOfReplicas   int    `json:"number_of_replicas,omitempty"`
 	IndexLifeCycleName string `json:"index.lifecycle.name,omitempty"`
+	KNN                bool   `json:"-"` // only for OpenSearch
 }
 
 type fieldType string
@@ -64,15 +65,17 @@ const (
 	fieldTypeBoolean     fieldType = "boolean"
 	fieldTypeDateNano    fieldType = "date_nanos"
 	fieldTypeDenseVector fieldType = "dense_vector"
+	fieldTypeKnnVector   fieldType = "knn_vector" // only for OpenSearch
 )
 
 type fieldSpec struct {
-	Type    fieldType
-	Dims    int
-	Indexed bool
+	Type      fieldType
+	Dims      int
+	Indexed   bool
+	KNNEngine string
 }
 
-func convertToEsType(t db.TableRow) fieldSpec {
+func convertToEsType(t db.TableRow, knnEngine string) fieldSpec {
 	var spec = fieldSpec{
 		Indexed: t.Indexed,
 	}
@@ -96,14 +99,26 @@ func convertToEsType(t db.TableRow) fieldSpec {
 	case db.DataTypeVector768Float32:
 		spec.Type = fieldTypeDenseVector
 		spec.Dims = 768
+	case db.DataTypeVectorFloat32:
+		spec.Type = fieldTypeDenseVector
+		spec.Dims = t.Dims
 	default:
 		spec.Type = fieldTypeKeyword
 	}
 
+	if spec.Type == fieldTypeDenseVector && knnEngine != "" {
+		spec.Type = fieldTypeKnnVector
+		spec.KNNEngine = knnEngine
+	}
+
 	return spec
 }
 
 func (s fieldSpec) MarshalJSON() ([]byte, error) {
+	if s.Type == fieldTypeKnnVector {
+		return []byte(fmt.Sprintf(`{"type":%q, "dimension":%d, "method":{"name":"hnsw", "space_type":"l2", "engine":%q}}`, s.Type, s.Dims, s.KNNEngine)), nil
+	}
+
 	if s.Dims > 0 {
 		if s.Indexed {
 			return []byte(fmt.Sprintf(`{"type":%q, "dims":%d}`, s.Type, s.Dims)), nil
@@ -200,6 +215,7 @@ func createIndex(mig migrator, indexName string, indexDefinition *db.TableDefini
 	var indexResilienceSettings = indexSettings{
 		NumberOfShards:   numberOfShards,
 		NumberOfReplicas: indexDefinition.Resilience.NumberOfReplicas,
+		KNN:              indexDefinition.KNNEngine != "",
 	}
 
 	var mappingTemplateName = fmt.Sprintf("mapping-%s", indexName)
@@ -217,7 +233,7 @@ func createIndex(mig migrator, indexName string, indexDefinition *db.TableDefini
 			continue
 		}
 
-		mp[row.Name] = convertToEsType(row)
+		mp[row.Name] = convertToEsType(row, indexDefinition.KNNEngine)
 	}
 
 	if err := mig.initComponentTemplate(mappingTemplateName, componentTemplate{
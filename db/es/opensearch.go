@@ -105,6 +105,8 @@ func (c *openSearchConnector) ConnectionPool(cfg db.Config) (db.Database, error)
 		mig:         mig,
 		dialect:     &openSearchDialect{},
 		queryLogger: cfg.QueryLogger,
+		bulkRetry:   newBulkRetryPolicy(cfg),
+		dryRun:      cfg.DryRun,
 	}, nil
 }
 
@@ -144,6 +146,11 @@ func (q *openSearchQuerier) insert(ctx context.Context, idxName indexName, query
 	})
 
 	if err != nil {
+		if resp != nil {
+			if httpResp := resp.Inspect().Response; httpResp != nil && httpResp.StatusCode == http.StatusTooManyRequests {
+				return nil, 0, newTooManyRequestsError(httpResp.Header, err.Error())
+			}
+		}
 		return nil, 0, fmt.Errorf("failed to perform insert: %v", err)
 	}
 
@@ -153,9 +160,57 @@ func (q *openSearchQuerier) insert(ctx context.Context, idxName indexName, query
 	}, 0, nil
 }
 
+// openSearchKnnQuery is the OpenSearch k-NN plugin query, {"knn": {"field": {"vector": [...], "k": 10}}}
+type openSearchKnnQuery struct {
+	Vector []float64    `json:"vector"`
+	K      int64        `json:"k"`
+	Filter *SearchQuery `json:"filter,omitempty"`
+}
+
+// openSearchKnnSearchRequest is the SearchRequest with the ES top-level knn section translated to the OpenSearch knn query
+type openSearchKnnSearchRequest struct {
+	Source bool                                     `json:"_source"`
+	Fields []string                                 `json:"fields"`
+	Query  map[string]map[string]openSearchKnnQuery `json:"query"`
+	Size   int64                                    `json:"size,omitempty"`
+	From   int64                                    `json:"from,omitempty"`
+}
+
+// openSearchKnnDefaultK is the amount of nearest neighbours returned if the request size is not set
+const openSearchKnnDefaultK = 10
+
+// openSearchRequest returns the OpenSearch compatible request, as OpenSearch has no top-level knn section and uses the knn query type instead
+func openSearchRequest(request *SearchRequest) interface{} {
+	if request.Knn == nil {
+		return request
+	}
+
+	var k = request.Size
+	if k == 0 {
+		k = openSearchKnnDefaultK
+	}
+
+	var knn = openSearchKnnQuery{
+		Vector: request.Knn.QueryVector,
+		K:      k,
+	}
+
+	if request.Query != nil && request.Query.Conditions != nil {
+		knn.Filter = request.Query
+	}
+
+	return &openSearchKnnSearchRequest{
+		Source: request.Source,
+		Fields: request.Fields,
+		Query:  map[string]map[string]openSearchKnnQuery{"knn": {request.Knn.Field: knn}},
+		Size:   request.Size,
+		From:   request.From,
+	}
+}
+
 func (q *openSearchQuerier) search(ctx context.Context, idxName indexName, request *SearchRequest) ([]map[string]interface{}, error) {
 	var buf bytes.Buffer
-	if err := json.NewEncoder(&buf).Encode(request); err != nil {
+	if err := json.NewEncoder(&buf).Encode(openSearchRequest(request)); err != nil {
 		return nil, fmt.Errorf("request encode error: %v", err)
 	}
 
@@ -204,6 +259,56 @@ func (q *openSearchQuerier) count(ctx context.Context, idxName indexName, reques
 	return int64(resp.Count), nil
 }
 
+func (q *openSearchQuerier) deleteByQuery(ctx context.Context, idxName indexName, request *DeleteByQueryRequest) (int64, error) {
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(request); err != nil {
+		return 0, fmt.Errorf("request encode error: %v", err)
+	}
+
+	var resp, err = q.client.Document.DeleteByQuery(ctx, opensearchapi.DocumentDeleteByQueryReq{
+		Indices: []string{string(idxName)},
+		Body:    &buf,
+	})
+	if err != nil {
+		if osStructError, ok := err.(*opensearch.StructError); ok && osStructError.Err.Type == "index_not_found_exception" {
+			return 0, nil
+		}
+
+		return 0, fmt.Errorf("failed to perform delete by query: %v", err)
+	}
+
+	return int64(resp.Deleted), nil
+}
+
+func (q *openSearchQuerier) aggregate(ctx context.Context, idxName indexName, request *AggregateRequest) (*AggregateResponse, error) {
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(request); err != nil {
+		return nil, fmt.Errorf("request encode error: %v", err)
+	}
+
+	var resp, err = q.client.Search(ctx, &opensearchapi.SearchReq{
+		Indices: []string{string(idxName)},
+		Body:    &buf,
+	})
+	if err != nil {
+		if osStructError, ok := err.(*opensearch.StructError); ok && osStructError.Err.Type == "index_not_found_exception" {
+			return &AggregateResponse{}, nil
+		}
+
+		return nil, fmt.Errorf("failed to perform aggregation: %v", err)
+	}
+
+	var result = AggregateResponse{}
+	result.Hits.Total.Value = int64(resp.Hits.Total.Value)
+	if len(resp.Aggregations) != 0 {
+		if err = json.Unmarshal(resp.Aggregations, &result.Aggregations); err != nil {
+			return nil, fmt.Errorf("aggregation response decode err: %v", err)
+		}
+	}
+
+	return &result, nil
+}
+
 type openSearchMigrator struct {
 	client    *opensearchapi.Client
 	ismClient *ism.Client
@@ -294,6 +399,7 @@ func (m *openSearchMigrator) initComponentTemplate(templateName string, template
 		NumberOfShards     int    `json:"number_of_shards,omitempty"`
 		NumberOfReplicas   int    `json:"number_of_replicas,omitempty"`
 		IndexLifeCycleName string `json:"opendistro.index_state_management.policy_id,omitempty"`
+		KNN                bool   `json:"index.knn,omitempty"`
 	}
 
 	type openSearchComponentTemplate struct {
@@ -307,6 +413,7 @@ func (m *openSearchMigrator) initComponentTemplate(templateName string, template
 			NumberOfShards:     template.Settings.NumberOfShards,
 			NumberOfReplicas:   template.Settings.NumberOfReplicas,
 			IndexLifeCycleName: template.Settings.IndexLifeCycleName,
+			KNN:                template.Settings.KNN,
 		}
 	}
 
@@ -0,0 +1,88 @@
+package es
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/acronis/perfkit/db"
+)
+
+// tooManyRequestsError is returned by the querier insert if the _bulk request is rejected with 429 Too Many Requests
+type tooManyRequestsError struct {
+	retryAfter time.Duration // Retry-After header value, 0 if the header is missing
+	msg        string
+}
+
+func (e *tooManyRequestsError) Error() string {
+	return fmt.Sprintf("bulk insert rejected with 429 Too Many Requests: %s", e.msg)
+}
+
+// newTooManyRequestsError creates tooManyRequestsError from the response header and message
+func newTooManyRequestsError(header http.Header, msg string) *tooManyRequestsError {
+	return &tooManyRequestsError{retryAfter: parseRetryAfter(header.Get("Retry-After"), time.Now()), msg: msg}
+}
+
+// parseRetryAfter parses the Retry-After header given in seconds or as HTTP date, returns 0 if the header is missing or malformed
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+
+	if date, err := http.ParseTime(value); err == nil && date.After(now) {
+		return date.Sub(now)
+	}
+
+	return 0
+}
+
+// bulkRetryPolicy defines the retries of the _bulk requests rejected with 429 Too Many Requests
+type bulkRetryPolicy struct {
+	maxRetries int           // 0 disables the retries
+	backoff    time.Duration // initial backoff doubled on every retry, the Retry-After header takes precedence
+}
+
+// newBulkRetryPolicy creates bulkRetryPolicy from the database configuration
+func newBulkRetryPolicy(cfg db.Config) bulkRetryPolicy {
+	return bulkRetryPolicy{maxRetries: cfg.ESMaxBulkRetries, backoff: cfg.ESBulkRetryBackoff}
+}
+
+// insertWithRetry performs the bulk insert retrying it with the exponential backoff on 429 Too Many Requests, returns the number of retries made
+func insertWithRetry(ctx context.Context, q querier, idxName indexName, query *BulkIndexRequest, policy bulkRetryPolicy,
+	logger db.Logger) (*BulkIndexResult, int, int, error) {
+	var backoff = policy.backoff
+	for retries := 0; ; retries++ {
+		var res, expectedSuccesses, err = q.insert(ctx, idxName, query)
+
+		var tooManyRequests *tooManyRequestsError
+		if !errors.As(err, &tooManyRequests) || retries >= policy.maxRetries {
+			return res, expectedSuccesses, retries, err
+		}
+
+		var delay = backoff
+		if tooManyRequests.retryAfter > 0 {
+			delay = tooManyRequests.retryAfter
+		}
+		backoff *= 2
+
+		if logger != nil {
+			logger.Log("bulk insert into %s: 429 Too Many Requests, retry %d/%d in %s", idxName, retries+1, policy.maxRetries, delay)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, 0, retries, ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package es
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// rejectingQuerier rejects the first rejections inserts with 429 Too Many Requests
+type rejectingQuerier struct {
+	querier
+	rejections int
+	calls      int
+	retryAfter string
+}
+
+func (q *rejectingQuerier) insert(context.Context, indexName, *BulkIndexRequest) (*BulkIndexResult, int, error) {
+	q.calls++
+	if q.calls <= q.rejections {
+		var header = http.Header{}
+		if q.retryAfter != "" {
+			header.Set("Retry-After", q.retryAfter)
+		}
+		return nil, 0, newTooManyRequestsError(header, "es_rejected_execution_exception")
+	}
+
+	return &BulkIndexResult{}, 0, nil
+}
+
+func TestInsertWithRetry(t *testing.T) {
+	var q = &rejectingQuerier{rejections: 2}
+	var _, _, retries, err = insertWithRetry(context.Background(), q, "test", &BulkIndexRequest{}, bulkRetryPolicy{maxRetries: 5, backoff: time.Millisecond}, nil)
+	if err != nil {
+		t.Fatalf("insertWithRetry() error: %v", err)
+	}
+	if retries != 2 || q.calls != 3 {
+		t.Errorf("insertWithRetry() retries = %d, calls = %d, want 2 and 3", retries, q.calls)
+	}
+}
+
+func TestInsertWithRetryExhausted(t *testing.T) {
+	var q = &rejectingQuerier{rejections: 10}
+	var _, _, retries, err = insertWithRetry(context.Background(), q, "test", &BulkIndexRequest{}, bulkRetryPolicy{maxRetries: 3, backoff: time.Millisecond}, nil)
+	if err == nil {
+		t.Fatalf("insertWithRetry() expected 429 error")
+	}
+	if retries != 3 || q.calls != 4 {
+		t.Errorf("insertWithRetry() retries = %d, calls = %d, want 3 and 4", retries, q.calls)
+	}
+}
+
+func TestInsertWithRetryAfter(t *testing.T) {
+	var q = &rejectingQuerier{rejections: 1, retryAfter: "1"}
+	var start = time.Now()
+	var _, _, _, err = insertWithRetry(context.Background(), q, "test", &BulkIndexRequest{}, bulkRetryPolicy{maxRetries: 1, backoff: time.Millisecond}, nil)
+	if err != nil {
+		t.Fatalf("insertWithRetry() error: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("insertWithRetry() didn't honor Retry-After, elapsed %s", elapsed)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	var now = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	for _, tc := range []struct {
+		value string
+		want  time.Duration
+	}{
+		{"", 0},
+		{"3", 3 * time.Second},
+		{"-1", 0},
+		{"garbage", 0},
+		{now.Add(5 * time.Second).Format(http.TimeFormat), 5 * time.Second},
+		{now.Add(-5 * time.Second).Format(http.TimeFormat), 0},
+	} {
+		if got := parseRetryAfter(tc.value, now); got != tc.want {
+			t.Errorf("parseRetryAfter(%q) = %s, want %s", tc.value, got, tc.want)
+		}
+	}
+}
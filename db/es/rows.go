@@ -73,6 +73,20 @@ func (r *esRows) Scan(dest ...interface{}) error {
 				return fmt.Errorf("%s : not equal type, in map '%T'", esFieldName, val)
 			}
 
+		case *float64:
+			switch numberType := val.(type) {
+			case json.Number:
+				var err error
+				*d, err = numberType.Float64()
+				if err != nil {
+					return fmt.Errorf("%s : failed to cast jsonNumber to float64 '%T': %v", esFieldName, numberType, err)
+				}
+			case float64:
+				*d = numberType
+			default:
+				return fmt.Errorf("%s : not equal type, in map '%T'", esFieldName, val)
+			}
+
 		case *bool:
 			boolVal, ok := val.(bool)
 			if !ok {
@@ -196,6 +196,47 @@ func (r *CountRequest) String() string {
 	return string(b)
 }
 
+// DeleteByQueryRequest is the _delete_by_query request body
+type DeleteByQueryRequest struct {
+	Query *SearchQuery `json:"query"`
+}
+
+func (r *DeleteByQueryRequest) String() string {
+	b, err := json.MarshalIndent(r, "", "   ")
+	if err != nil {
+		return "{}"
+	}
+	return string(b)
+}
+
+type AggregateRequest struct {
+	Query          *SearchQuery           `json:"query"`
+	Aggs           map[string]interface{} `json:"aggs,omitempty"`
+	Size           int64                  `json:"size"` // always 0, only the aggregations are needed
+	TrackTotalHits bool                   `json:"track_total_hits"`
+}
+
+func (r *AggregateRequest) String() string {
+	b, err := json.MarshalIndent(r, "", "   ")
+	if err != nil {
+		return "{}"
+	}
+	return string(b)
+}
+
+type AggregateValue struct {
+	Value *float64 `json:"value"` // nil if there are no documents to aggregate
+}
+
+type AggregateResponse struct {
+	Hits struct {
+		Total struct {
+			Value int64 `json:"value"`
+		} `json:"total"`
+	} `json:"hits"`
+	Aggregations map[string]AggregateValue `json:"aggregations"`
+}
+
 type SearchHit struct {
 	ID     string                 `json:"_id"`
 	Index  string                 `json:"_index"`
@@ -257,6 +298,10 @@ func (b searchQueryBuilder) searchRequest(c *db.SelectCtrl) (*SearchRequest, que
 		return nil, queryTypeSearch, true, nil
 	}
 
+	if len(c.Aggregates) != 0 {
+		return &SearchRequest{Query: q}, queryTypeAggs, false, nil
+	}
+
 	req := &SearchRequest{
 		Size:   c.Page.Limit,
 		From:   c.Page.Offset,
@@ -839,6 +884,65 @@ func (b searchQueryBuilder) fields(selectedFields []string) ([]string, bool, err
 	return result, false, nil
 }
 
+// aggregationName returns the name of the n-th SelectCtrl aggregate in the aggs request
+func aggregationName(n int) string {
+	return fmt.Sprintf("agg%d", n)
+}
+
+// aggregations converts the SelectCtrl aggregates to the aggs request, COUNT of all the documents is taken from the total hits instead
+func (b searchQueryBuilder) aggregations(aggregates []db.AggregateField) (map[string]interface{}, error) {
+	var aggs = make(map[string]interface{})
+	for i, agg := range aggregates {
+		var esFunction string
+		switch agg.Function {
+		case db.AggregateCount:
+			esFunction = "value_count"
+		case db.AggregateSum:
+			esFunction = "sum"
+		case db.AggregateAvg:
+			esFunction = "avg"
+		case db.AggregateMin:
+			esFunction = "min"
+		case db.AggregateMax:
+			esFunction = "max"
+		default:
+			return nil, fmt.Errorf("bad aggregate function '%v'", agg.Function)
+		}
+
+		if agg.Column == "" {
+			if agg.Function != db.AggregateCount {
+				return nil, fmt.Errorf("empty %v aggregate column", agg.Function)
+			}
+			continue
+		}
+
+		aggs[aggregationName(i)] = map[string]map[string]string{esFunction: {"field": agg.Column}}
+	}
+
+	return aggs, nil
+}
+
+// aggregateRows returns the single row of the aggregated values in the SelectCtrl aggregates order
+func aggregateRows(aggregates []db.AggregateField, resp *AggregateResponse) *esRows {
+	var row = make(map[string]interface{}, len(aggregates))
+	var columns = make([]string, 0, len(aggregates))
+	for i, agg := range aggregates {
+		var name = aggregationName(i)
+
+		var value interface{}
+		if agg.Column == "" {
+			value = float64(resp.Hits.Total.Value)
+		} else if aggValue, ok := resp.Aggregations[name]; ok && aggValue.Value != nil {
+			value = *aggValue.Value
+		}
+
+		row[name] = []interface{}{value}
+		columns = append(columns, name)
+	}
+
+	return &esRows{data: []map[string]interface{}{row}, requestedColumns: columns}
+}
+
 func (b searchQueryBuilder) filter(optimizeConditions bool, filterFields map[string][]string) (*conditions, bool, error) {
 	var bl = conditions{}
 
@@ -921,7 +1025,85 @@ func (g *esGateway) Select(idxName string, sc *db.SelectCtrl) (db.Rows, error) {
 		}
 
 		return &db.CountRows{Count: count}, nil
+	case queryTypeAggs:
+		var aggs map[string]interface{}
+		if aggs, err = queryBuilder.aggregations(sc.Aggregates); err != nil {
+			return nil, err
+		}
+
+		var resp *AggregateResponse
+		if resp, err = g.q.aggregate(g.ctx.Ctx, index, &AggregateRequest{Query: query.Query, Aggs: aggs, TrackTotalHits: true}); err != nil {
+			return nil, fmt.Errorf("failed to aggregate: %v", err)
+		}
+
+		return aggregateRows(sc.Aggregates, resp), nil
 	default:
 		return nil, fmt.Errorf("unsupported query type %v", qType)
 	}
 }
+
+// ExistsRow returns true if at least one document of the index matches the SelectCtrl conditions, it uses the _count API
+func (g *esGateway) ExistsRow(idxName string, sc *db.SelectCtrl) (bool, error) {
+	var index = indexName(idxName)
+
+	var queryBuilder, ok = indexQueryBuilders[index]
+	if !ok {
+		return false, fmt.Errorf("index %s is not supported", index)
+	}
+
+	var query, empty, err = queryBuilder.searchQuery(sc.OptimizeConditions, sc.Where)
+	if err != nil {
+		return false, err
+	}
+
+	if empty {
+		return false, nil
+	}
+
+	var count int64
+	if count, err = g.q.count(g.ctx.Ctx, index, &CountRequest{Query: query}); err != nil {
+		return false, fmt.Errorf("failed to count: %v", err)
+	}
+
+	return count > 0, nil
+}
+
+// Delete deletes the documents matching the conditions by the _delete_by_query API and returns the number of deleted documents,
+// the request is only logged in the dry-run mode
+func (g *esGateway) Delete(idxName string, dc *db.DeleteCtrl) (int64, error) {
+	var index = indexName(idxName)
+
+	var queryBuilder, ok = indexQueryBuilders[index]
+	if !ok {
+		return 0, fmt.Errorf("index %s is not supported", index)
+	}
+
+	var query, empty, err = queryBuilder.searchQuery(dc.OptimizeConditions, dc.Where)
+	if err != nil {
+		return 0, err
+	}
+
+	if empty {
+		return 0, nil
+	}
+
+	var request = &DeleteByQueryRequest{Query: query}
+	if g.dryRun {
+		if g.queryLogger != nil {
+			g.queryLogger.Log("dry-run: delete by query:\n%s", request.String())
+		}
+		return 0, nil
+	}
+
+	var deleted int64
+	if deleted, err = g.q.deleteByQuery(g.ctx.Ctx, index, request); err != nil {
+		return 0, fmt.Errorf("failed to delete by query: %v", err)
+	}
+
+	return deleted, nil
+}
+
+// UpdateReturning is not supported by Elasticsearch / OpenSearch
+func (g *esGateway) UpdateReturning(idxName string, uc *db.UpdateCtrl) (db.Rows, error) { //nolint:revive
+	return nil, fmt.Errorf("update is not supported for index %s", indexName(idxName))
+}
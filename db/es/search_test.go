@@ -1819,3 +1819,72 @@ func TestSelectors(t *testing.T) {
 	assert.Equal(t, valuesSel(expected), valuesSel(actual), "different inner fields")
 	assert.Equal(t, valueSel(expected), valueSel(actual), "different value fields")
 }
+
+func TestOpenSearchKnnRequest(t *testing.T) {
+	var req, qType, empty, err = testQueryBuilder.searchRequest(&db.SelectCtrl{
+		Fields: []string{"id"},
+		Order:  []string{"nearest(embedding;L2;[1,2,3])"},
+		Page:   db.Page{Limit: 5},
+	})
+	require.NoError(t, err)
+	require.False(t, empty)
+	require.Equal(t, queryTypeSearch, qType)
+
+	actual, err := json.Marshal(openSearchRequest(req))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"_source": false,
+		"fields": ["id"],
+		"query": {"knn": {"embedding": {"vector": [1, 2, 3], "k": 5}}},
+		"size": 5
+	}`, string(actual))
+
+	spec, err := json.Marshal(convertToEsType(db.TableRow{Name: "embedding", Type: db.DataTypeVectorFloat32, Dims: 128}, "faiss"))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"type": "knn_vector", "dimension": 128, "method": {"name": "hnsw", "space_type": "l2", "engine": "faiss"}}`, string(spec))
+}
+
+func TestAggregations(t *testing.T) {
+	var aggregates = []db.AggregateField{
+		{Function: db.AggregateCount},
+		{Function: db.AggregateSum, Column: "progress"},
+		{Function: db.AggregateAvg, Column: "completion_time_ns"},
+	}
+
+	var _, qType, empty, err = testQueryBuilder.searchRequest(&db.SelectCtrl{Aggregates: aggregates})
+	require.NoError(t, err)
+	require.False(t, empty)
+	require.Equal(t, queryTypeAggs, qType)
+
+	aggs, err := testQueryBuilder.aggregations(aggregates)
+	require.NoError(t, err)
+
+	actual, err := json.Marshal(aggs)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"agg1": {"sum": {"field": "progress"}},
+		"agg2": {"avg": {"field": "completion_time_ns"}}
+	}`, string(actual))
+
+	var resp AggregateResponse
+	require.NoError(t, json.Unmarshal([]byte(`{"hits": {"total": {"value": 3}}, "aggregations": {"agg1": {"value": 60}, "agg2": {"value": 12.5}}}`), &resp))
+
+	var rows = aggregateRows(aggregates, &resp)
+	require.True(t, rows.Next())
+
+	var count, sum int64
+	var avg float64
+	require.NoError(t, rows.Scan(&count, &sum, &avg))
+	assert.Equal(t, int64(3), count)
+	assert.Equal(t, int64(60), sum)
+	assert.Equal(t, 12.5, avg)
+	require.False(t, rows.Next())
+
+	// no documents to aggregate
+	rows = aggregateRows(aggregates, &AggregateResponse{})
+	require.True(t, rows.Next())
+	require.Error(t, rows.Scan(&count, &sum, &avg))
+
+	_, err = testQueryBuilder.aggregations([]db.AggregateField{{Function: db.AggregateMax}})
+	require.EqualError(t, err, "empty MAX aggregate column")
+}
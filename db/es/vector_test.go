@@ -60,10 +60,13 @@ func (suite *TestingSuite) TestVectorSearch() {
 	defer logDbTime(suite.T(), c)
 	defer vectorCleanup(suite.T(), d)
 
-	if err := s.BulkInsert("vector_perf_table", [][]interface{}{
-		{int64(1), "text1", []float32{0.5, 10, 6}},
-		{int64(2), "text2", []float32{-0.5, 10, 10}},
-	}, []string{"id", "text", "embedding"}); err != nil {
+	if err := s.BulkInsert("vector_perf_table", &db.BulkInsertCtrl{
+		Rows: [][]interface{}{
+			{int64(1), "text1", []float32{0.5, 10, 6}},
+			{int64(2), "text2", []float32{-0.5, 10, 10}},
+		},
+		ColumnNames: []string{"id", "text", "embedding"},
+	}); err != nil {
 		suite.T().Error(err)
 		return
 	}
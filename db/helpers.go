@@ -113,6 +113,7 @@ func DefaultCreateQueryPatchFunc(table string, query string, dialect Dialect) (s
 	query = strings.ReplaceAll(query, "{table}", table)
 
 	for _, logicalType := range []DataType{
+		DataTypeInt,
 		DataTypeBigInt,
 		DataTypeBigIntAutoIncPK,
 		DataTypeBigIntAutoInc,
@@ -141,6 +142,11 @@ func DefaultCreateQueryPatchFunc(table string, query string, dialect Dialect) (s
 		DataTypeNull,
 		DataTypeVector3Float32,
 		DataTypeVector768Float32,
+		DataTypeJSON,
+		DataTypeJSONB,
+		DataTypeJSONText,
+		DataTypeInet,
+		DataTypeCidr,
 		DataTypeEngine,
 	} {
 		var specificType = dialect.GetType(logicalType)
@@ -201,7 +207,7 @@ func PrintStack() {
 // WithAutoInc returns true if DBDriver should support 'autoinc' field as current time nanoseconds
 func WithAutoInc(name DialectName) bool {
 	switch name {
-	case CASSANDRA:
+	case CASSANDRA, SCYLLA:
 		return true
 	default:
 		return false
@@ -0,0 +1,911 @@
+//go:build mongo
+
+// Package mongo provides an implementation of the db.Database interface for MongoDB.
+//
+// The package is built with the 'mongo' build tag only, so the go.mongodb.org/mongo-driver dependency
+// is not required by the default build.
+package mongo
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+
+	"github.com/acronis/perfkit/db"
+)
+
+const (
+	defaultDatabaseName = "perfkit"  // database used if the connection string has no path
+	duplicateKeyCode    = 11000      // MongoDB E11000 duplicate key error code
+	sequenceValueField  = "value"    // field of the sequence document incremented by GetNextVal
+	sequenceDocumentID  = "sequence" // _id of the single document of the sequence collection
+)
+
+// errRawQueries is returned by the SQL-only methods of the db.DatabaseAccessor
+var errRawQueries = errors.New("raw queries are not supported by mongodb")
+
+// nolint: gochecknoinits // remove init() when we will have a better way to register connectors
+func init() {
+	if err := db.Register("mongodb", &mongoConnector{}); err != nil {
+		panic(err)
+	}
+}
+
+type mongoConnector struct{}
+
+// ConnectionPool connects to the database given by the path of the connection string
+func (c *mongoConnector) ConnectionPool(cfg db.Config) (db.Database, error) {
+	var u, err = url.Parse(cfg.ConnString)
+	if err != nil {
+		return nil, fmt.Errorf("db: mongodb: cannot parse connection url %v, err: %v", cfg.ConnString, err)
+	}
+
+	var dbName = strings.TrimPrefix(u.Path, "/")
+	if dbName == "" {
+		dbName = defaultDatabaseName
+	}
+
+	var opts = options.Client().ApplyURI(cfg.ConnString)
+	if cfg.MaxOpenConns > 0 {
+		opts.SetMaxPoolSize(uint64(cfg.MaxOpenConns))
+	}
+	if cfg.MaxConnLifetime > 0 {
+		opts.SetMaxConnIdleTime(cfg.MaxConnLifetime)
+	}
+
+	if cfg.TLSEnabled {
+		// nolint:gosec // TODO: TLS MinVersion too low
+		var tlsConfig = &tls.Config{}
+		if len(cfg.TLSCACert) != 0 {
+			var caCertPool = x509.NewCertPool()
+			caCertPool.AppendCertsFromPEM(cfg.TLSCACert)
+			tlsConfig.RootCAs = caCertPool
+		}
+		opts.SetTLSConfig(tlsConfig)
+	}
+
+	var client *mongo.Client
+	if client, err = mongo.Connect(context.Background(), opts); err != nil {
+		return nil, fmt.Errorf("db: cannot connect to mongodb at %v, err: %v", u.Redacted(), err)
+	}
+
+	return &mongoDatabase{
+		client:       client,
+		database:     client.Database(dbName),
+		maxOpenConns: cfg.MaxOpenConns,
+		queryTimeout: cfg.QueryTimeout,
+		dryRun:       cfg.DryRun,
+		queryLogger:  cfg.QueryLogger,
+	}, nil
+}
+
+func (c *mongoConnector) DialectName(scheme string) (db.DialectName, error) {
+	return db.MONGODB, nil
+}
+
+type mongoDatabase struct {
+	client       *mongo.Client
+	database     *mongo.Database
+	maxOpenConns int
+	queryTimeout time.Duration
+	dryRun       bool
+
+	queryLogger db.Logger
+}
+
+// Ping pings the DB
+func (d *mongoDatabase) Ping(ctx context.Context) error {
+	return d.client.Ping(ctx, readpref.Primary())
+}
+
+func (d *mongoDatabase) DialectName() db.DialectName {
+	return db.MONGODB
+}
+
+func (d *mongoDatabase) UseTruncate() bool {
+	return false
+}
+
+// GetVersion returns the server version reported by the buildInfo command
+func (d *mongoDatabase) GetVersion() (db.DialectName, string, error) {
+	var info bson.M
+	if err := d.database.RunCommand(context.Background(), bson.D{{Key: "buildInfo", Value: 1}}).Decode(&info); err != nil {
+		return "", "", fmt.Errorf("db: mongodb: cannot get version: %v", err)
+	}
+
+	return db.MONGODB, fmt.Sprintf("%v", info["version"]), nil
+}
+
+func (d *mongoDatabase) GetInfo(version string) (ret []string, dbInfo *db.Info, err error) {
+	return []string{"MongoDB " + version}, nil, nil
+}
+
+func (d *mongoDatabase) ApplyMigrations(tableName, tableMigrationSQL string) error {
+	return nil
+}
+
+func (d *mongoDatabase) TableExists(tableName string) (bool, error) {
+	var names, err = d.database.ListCollectionNames(context.Background(), bson.D{{Key: "name", Value: tableName}})
+	if err != nil {
+		return false, fmt.Errorf("collection %s: %v", tableName, err)
+	}
+
+	return len(names) != 0, nil
+}
+
+// CreateTable creates the collection, the table definition columns are not enforced as MongoDB is schemaless
+func (d *mongoDatabase) CreateTable(tableName string, tableDefinition *db.TableDefinition, tableMigrationDDL string) error {
+	if exists, err := d.TableExists(tableName); err != nil || exists {
+		return err
+	}
+
+	if err := d.database.CreateCollection(context.Background(), tableName); err != nil {
+		return fmt.Errorf("collection %s: %v", tableName, err)
+	}
+
+	return nil
+}
+
+func (d *mongoDatabase) DropTable(tableName string) error {
+	return d.database.Collection(tableName).Drop(context.Background())
+}
+
+func (d *mongoDatabase) IndexExists(indexName string, tableName string) (bool, error) {
+	var specs, err = d.database.Collection(tableName).Indexes().ListSpecifications(context.Background())
+	if err != nil {
+		return false, fmt.Errorf("collection %s: %v", tableName, err)
+	}
+
+	for _, s := range specs {
+		if s.Name == indexName {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func (d *mongoDatabase) createIndex(indexName string, tableName string, columns []string, unique bool) error {
+	var keys = make(bson.D, 0, len(columns))
+	for _, col := range columns {
+		keys = append(keys, bson.E{Key: col, Value: 1})
+	}
+
+	var model = mongo.IndexModel{Keys: keys, Options: options.Index().SetName(indexName).SetUnique(unique)}
+	if _, err := d.database.Collection(tableName).Indexes().CreateOne(context.Background(), model); err != nil {
+		return fmt.Errorf("collection %s: cannot create index %s: %v", tableName, indexName, err)
+	}
+
+	return nil
+}
+
+func (d *mongoDatabase) CreateIndex(indexName string, tableName string, columns []string, indexType db.IndexType) error {
+	return d.createIndex(indexName, tableName, columns, false)
+}
+
+func (d *mongoDatabase) CreateUniqueIndex(indexName string, tableName string, columns []string) error {
+	return d.createIndex(indexName, tableName, columns, true)
+}
+
+func (d *mongoDatabase) DropIndex(indexName string, tableName string) error {
+	if _, err := d.database.Collection(tableName).Indexes().DropOne(context.Background(), indexName); err != nil {
+		return fmt.Errorf("collection %s: cannot drop index %s: %v", tableName, indexName, err)
+	}
+
+	return nil
+}
+
+func (d *mongoDatabase) ReadConstraints() ([]db.Constraint, error) {
+	return nil, nil
+}
+
+func (d *mongoDatabase) AddConstraints(constraints []db.Constraint) error {
+	return nil
+}
+
+func (d *mongoDatabase) DropConstraints(constraints []db.Constraint) error {
+	return nil
+}
+
+// CreateSequence does nothing, the sequence document is upserted by the first GetNextVal call
+func (d *mongoDatabase) CreateSequence(sequenceName string) error {
+	return nil
+}
+
+func (d *mongoDatabase) DropSequence(sequenceName string) error {
+	return d.DropTable(sequenceName)
+}
+
+func (d *mongoDatabase) CreateSchema(schemaName string) error {
+	return fmt.Errorf("schema %s: schemas are not supported by mongodb", schemaName)
+}
+
+func (d *mongoDatabase) DropSchema(schemaName string) error {
+	return fmt.Errorf("schema %s: schemas are not supported by mongodb", schemaName)
+}
+
+// Defragment runs the compact command on the collection
+func (d *mongoDatabase) Defragment(tableName string) error {
+	if err := d.database.RunCommand(context.Background(), bson.D{{Key: "compact", Value: tableName}}).Err(); err != nil {
+		return fmt.Errorf("collection %s: compact failed: %v", tableName, err)
+	}
+
+	return nil
+}
+
+func (d *mongoDatabase) RunInSchemaSandbox(schemaName string, fn func() error) error { //nolint:revive
+	return fmt.Errorf("schema %s: schema sandbox is not supported by mongodb", schemaName)
+}
+
+func (d *mongoDatabase) GetTablesSchemaInfo(tableNames []string) ([]string, error) {
+	return nil, nil
+}
+
+// GetTablesVolumeInfo returns the documents count and the data size of the collections reported by the collStats command
+func (d *mongoDatabase) GetTablesVolumeInfo(tableNames []string) ([]string, error) {
+	var ret = []string{fmt.Sprintf("%-55s %15s %15s", "COLLECTION NAME", "DOCUMENTS", "SIZE MB")}
+	for _, name := range tableNames {
+		var stats struct {
+			Count int64 `bson:"count"`
+			Size  int64 `bson:"size"`
+		}
+		if err := d.database.RunCommand(context.Background(), bson.D{{Key: "collStats", Value: name}}).Decode(&stats); err != nil {
+			return nil, fmt.Errorf("collection %s: %v", name, err)
+		}
+
+		ret = append(ret, fmt.Sprintf("%-55s %15d %15d", name, stats.Count, stats.Size/1024/1024))
+	}
+
+	return ret, nil
+}
+
+func (d *mongoDatabase) GetIndexUsageStats(tableName string) ([]db.IndexUsageStat, error) {
+	return nil, nil
+}
+
+func (d *mongoDatabase) GetLockWaitStats() ([]db.LockWaitStat, error) {
+	return nil, nil
+}
+
+func (d *mongoDatabase) GetActiveQueries() ([]db.ActiveQuery, error) {
+	return nil, nil
+}
+
+func (d *mongoDatabase) GetCheckpointStats() (db.CheckpointStats, error) {
+	return db.CheckpointStats{}, nil
+}
+
+func (d *mongoDatabase) Context(ctx context.Context) *db.Context {
+	return &db.Context{Ctx: ctx}
+}
+
+func (d *mongoDatabase) Session(c *db.Context) db.Session {
+	return &mongoSession{
+		mongoGateway: mongoGateway{
+			database:     d.database,
+			ctx:          c,
+			queryTimeout: d.queryTimeout,
+			dryRun:       d.dryRun,
+			queryLogger:  d.queryLogger,
+		},
+	}
+}
+
+// RawSession returns the *mongo.Database
+func (d *mongoDatabase) RawSession() interface{} {
+	return d.database
+}
+
+func (d *mongoDatabase) Stats() *db.Stats {
+	return &db.Stats{MaxOpenConnections: d.maxOpenConns, InUse: d.client.NumberSessionsInProgress()}
+}
+
+func (d *mongoDatabase) Close() error {
+	if err := d.client.Disconnect(context.Background()); err != nil {
+		return fmt.Errorf("close failed: %w", err)
+	}
+
+	return nil
+}
+
+type mongoGateway struct {
+	database     *mongo.Database
+	ctx          *db.Context
+	queryTimeout time.Duration
+	dryRun       bool
+
+	queryLogger db.Logger
+}
+
+type mongoSession struct {
+	mongoGateway
+}
+
+// Transact runs fn as is, the multi-document transactions require a replica set and are not used by the benchmark
+func (s *mongoSession) Transact(fn func(tx db.DatabaseAccessor) error) error {
+	return fn(s)
+}
+
+func (s *mongoSession) RunInReadOnlyTransaction(fn func(tx db.DatabaseAccessor) error) error {
+	return fn(s)
+}
+
+// GetNextVal increments the value of the single document of the sequence collection
+func (s *mongoSession) GetNextVal(sequenceName string) (uint64, error) {
+	var ctx, cancel = s.queryCtx()
+	defer cancel()
+
+	var doc struct {
+		Value int64 `bson:"value"`
+	}
+
+	var opts = options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+	if err := s.database.Collection(sequenceName).FindOneAndUpdate(ctx,
+		bson.D{{Key: "_id", Value: sequenceDocumentID}},
+		bson.D{{Key: "$inc", Value: bson.D{{Key: sequenceValueField, Value: 1}}}},
+		opts).Decode(&doc); err != nil {
+		return 0, queryErr(ctx, fmt.Errorf("sequence %s: %v", sequenceName, err))
+	}
+
+	return uint64(doc.Value), nil
+}
+
+// queryCtx returns a context for a single query bounded by the client-side query timeout, if any
+func (g *mongoGateway) queryCtx() (context.Context, context.CancelFunc) {
+	if g.queryTimeout <= 0 {
+		return g.ctx.Ctx, func() {}
+	}
+
+	return context.WithTimeout(g.ctx.Ctx, g.queryTimeout)
+}
+
+// queryErr marks errors caused by the expired client-side query timeout with db.ErrQueryTimeout
+func queryErr(ctx context.Context, err error) error {
+	if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("%w: %v", db.ErrQueryTimeout, err)
+	}
+
+	return err
+}
+
+func (g *mongoGateway) log(format string, args ...interface{}) {
+	if g.queryLogger != nil {
+		g.queryLogger.Log(format, args...)
+	}
+}
+
+// StatementEnter is called before executing a statement
+func (g *mongoGateway) StatementEnter(query string, args ...interface{}) time.Time { //nolint:revive
+	return time.Now()
+}
+
+// StatementExit is called after executing a statement
+func (g *mongoGateway) StatementExit(statement string, startTime time.Time, err error, showRowsAffected bool, result db.Result, format string, args []interface{}, rows db.Rows, dest []interface{}) {
+}
+
+func (g *mongoGateway) Exec(format string, args ...interface{}) (db.Result, error) {
+	return nil, errRawQueries
+}
+
+func (g *mongoGateway) QueryRow(format string, args ...interface{}) db.Row {
+	return errRow{errRawQueries}
+}
+
+func (g *mongoGateway) Query(format string, args ...interface{}) (db.Rows, error) {
+	return nil, errRawQueries
+}
+
+func (g *mongoGateway) Prepare(query string) (db.Stmt, error) {
+	return nil, errRawQueries
+}
+
+// errRow is the db.Row failing to scan with the given error
+type errRow struct {
+	err error
+}
+
+func (r errRow) Scan(dest ...any) error {
+	return r.err
+}
+
+// BulkInsert inserts the rows by a single unordered bulk write, the duplicates are skipped if IgnoreConflicts is set
+func (g *mongoGateway) BulkInsert(tableName string, c *db.BulkInsertCtrl) error {
+	if len(c.Rows) == 0 {
+		return nil
+	}
+
+	var models = make([]mongo.WriteModel, 0, len(c.Rows))
+	for _, row := range c.Rows {
+		if len(row) != len(c.ColumnNames) {
+			return fmt.Errorf("collection %s: %d values given for %d columns", tableName, len(row), len(c.ColumnNames))
+		}
+
+		var doc = make(bson.D, 0, len(row))
+		for i, col := range c.ColumnNames {
+			doc = append(doc, bson.E{Key: col, Value: documentValue(row[i])})
+		}
+		models = append(models, mongo.NewInsertOneModel().SetDocument(doc))
+	}
+
+	g.log("bulk insert %s: %d documents", tableName, len(models))
+	if g.dryRun {
+		return nil
+	}
+
+	var ctx, cancel = g.queryCtx()
+	defer cancel()
+
+	var _, err = g.database.Collection(tableName).BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false))
+	if err == nil || (c.IgnoreConflicts && onlyDuplicates(err)) {
+		return nil
+	}
+
+	if mongo.IsDuplicateKeyError(err) {
+		err = fmt.Errorf("%w: %v", db.ErrUniqueViolation, err)
+	}
+
+	return queryErr(ctx, err)
+}
+
+// Upsert writes the rows by a single unordered bulk write of the upserting updates filtered by the conflict columns,
+// the update columns are set by $set and the rest of the columns by $setOnInsert
+func (g *mongoGateway) Upsert(tableName string, rows [][]interface{}, columnNames []string, conflictColumns []string, updateColumns []string) error {
+	if len(rows) == 0 {
+		return nil
+	}
+
+	if len(conflictColumns) == 0 {
+		return fmt.Errorf("collection %s: empty upsert conflict columns", tableName)
+	}
+
+	var isConflict, isUpdate = make(map[string]bool), make(map[string]bool)
+	for _, col := range conflictColumns {
+		isConflict[col] = true
+	}
+	for _, col := range updateColumns {
+		isUpdate[col] = true
+	}
+
+	var models = make([]mongo.WriteModel, 0, len(rows))
+	for _, row := range rows {
+		if len(row) != len(columnNames) {
+			return fmt.Errorf("collection %s: %d values given for %d columns", tableName, len(row), len(columnNames))
+		}
+
+		var filter, set, setOnInsert = bson.D{}, bson.D{}, bson.D{}
+		for i, col := range columnNames {
+			var e = bson.E{Key: col, Value: documentValue(row[i])}
+			switch {
+			case isConflict[col]:
+				// also set on insert to keep $setOnInsert non-empty, MongoDB rejects the empty update operators
+				filter, setOnInsert = append(filter, e), append(setOnInsert, e)
+			case isUpdate[col]:
+				set = append(set, e)
+			default:
+				setOnInsert = append(setOnInsert, e)
+			}
+		}
+
+		var update = bson.D{{Key: "$setOnInsert", Value: setOnInsert}}
+		if len(set) != 0 {
+			update = append(update, bson.E{Key: "$set", Value: set})
+		}
+		models = append(models, mongo.NewUpdateOneModel().SetFilter(filter).SetUpdate(update).SetUpsert(true))
+	}
+
+	g.log("bulk upsert %s: %d documents", tableName, len(models))
+	if g.dryRun {
+		return nil
+	}
+
+	var ctx, cancel = g.queryCtx()
+	defer cancel()
+
+	if _, err := g.database.Collection(tableName).BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false)); err != nil {
+		return queryErr(ctx, err)
+	}
+
+	return nil
+}
+
+// onlyDuplicates returns true if all the bulk write errors are caused by the unique index violations
+func onlyDuplicates(err error) bool {
+	var bwe mongo.BulkWriteException
+	if !errors.As(err, &bwe) || bwe.WriteConcernError != nil {
+		return false
+	}
+
+	for _, we := range bwe.WriteErrors {
+		if we.Code != duplicateKeyCode {
+			return false
+		}
+	}
+
+	return true
+}
+
+// documentValue converts the benchmark values to the BSON friendly ones
+func documentValue(v interface{}) interface{} {
+	if id, ok := v.(uuid.UUID); ok {
+		// google/uuid is an array, it would be stored as an array of numbers otherwise
+		return id.String()
+	}
+
+	return v
+}
+
+// Select finds the documents matching the conditions, COUNT(0) or no fields counts the documents instead
+func (g *mongoGateway) Select(tableName string, sc *db.SelectCtrl) (db.Rows, error) {
+	if len(sc.Aggregates) != 0 {
+		return nil, fmt.Errorf("collection %s: aggregates are not supported by mongodb", tableName)
+	}
+
+	var filter, err = whereFilter(sc.Where)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(sc.Fields) == 0 || (len(sc.Fields) == 1 && sc.Fields[0] == "COUNT(0)") {
+		var ctx, cancel = g.queryCtx()
+		defer cancel()
+
+		g.log("count %s: %v", tableName, filter)
+
+		var count int64
+		if count, err = g.database.Collection(tableName).CountDocuments(ctx, filter); err != nil {
+			return nil, queryErr(ctx, fmt.Errorf("failed to count: %v", err))
+		}
+
+		return &db.CountRows{Count: count}, nil
+	}
+
+	var opts *options.FindOptions
+	if opts, err = findOptions(sc); err != nil {
+		return nil, err
+	}
+
+	g.log("find %s: %v", tableName, filter)
+
+	var ctx, cancel = g.queryCtx()
+
+	var cursor *mongo.Cursor
+	if cursor, err = g.database.Collection(tableName).Find(ctx, filter, opts); err != nil {
+		cancel()
+		return nil, queryErr(ctx, fmt.Errorf("failed to find: %v", err))
+	}
+
+	return &mongoRows{ctx: ctx, cancel: cancel, cursor: cursor, fields: sc.Fields}, nil
+}
+
+// findOptions returns the projection of the SelectCtrl fields (without _id unless requested), the sort and the page of the Find
+func findOptions(sc *db.SelectCtrl) (*options.FindOptions, error) {
+	var projection = bson.D{{Key: "_id", Value: 0}}
+	for _, f := range sc.Fields {
+		if f == "" {
+			return nil, fmt.Errorf("empty request field")
+		}
+		if f == "_id" {
+			projection[0].Value = 1
+		} else {
+			projection = append(projection, bson.E{Key: f, Value: 1})
+		}
+	}
+
+	var opts = options.Find().SetProjection(projection)
+
+	var sort, err = orderSort(sc.Order)
+	if err != nil {
+		return nil, err
+	}
+	if len(sort) != 0 {
+		opts.SetSort(sort)
+	}
+
+	if sc.Page.Limit > 0 {
+		opts.SetLimit(sc.Page.Limit)
+	}
+	if sc.Page.Offset > 0 {
+		opts.SetSkip(sc.Page.Offset)
+	}
+
+	return opts, nil
+}
+
+// ExistsRow returns true if at least one document matches the SelectCtrl conditions
+func (g *mongoGateway) ExistsRow(tableName string, sc *db.SelectCtrl) (bool, error) {
+	var filter, err = whereFilter(sc.Where)
+	if err != nil {
+		return false, err
+	}
+
+	var ctx, cancel = g.queryCtx()
+	defer cancel()
+
+	var count int64
+	if count, err = g.database.Collection(tableName).CountDocuments(ctx, filter, options.Count().SetLimit(1)); err != nil {
+		return false, queryErr(ctx, fmt.Errorf("failed to count: %v", err))
+	}
+
+	return count > 0, nil
+}
+
+// UpdateReturning updates all the documents matching the conditions by UpdateMany, RETURNING is not supported
+func (g *mongoGateway) UpdateReturning(tableName string, uc *db.UpdateCtrl) (db.Rows, error) {
+	if len(uc.Set) == 0 {
+		return nil, fmt.Errorf("empty update set")
+	}
+
+	if len(uc.Returning) != 0 {
+		return nil, fmt.Errorf("RETURNING is not supported for %s dialect", db.MONGODB)
+	}
+
+	var set = make(bson.D, 0, len(uc.Set))
+	for col, val := range uc.Set {
+		if col == "" {
+			return nil, fmt.Errorf("empty update field")
+		}
+		set = append(set, bson.E{Key: col, Value: documentValue(val)})
+	}
+
+	var filter, err = whereFilter(uc.Where)
+	if err != nil {
+		return nil, err
+	}
+
+	g.log("update %s: %v set %v", tableName, filter, set)
+	if g.dryRun {
+		return &db.EmptyRows{}, nil
+	}
+
+	var ctx, cancel = g.queryCtx()
+	defer cancel()
+
+	if _, err = g.database.Collection(tableName).UpdateMany(ctx, filter, bson.D{{Key: "$set", Value: set}}); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			err = fmt.Errorf("%w: %v", db.ErrUniqueViolation, err)
+		}
+
+		return nil, queryErr(ctx, err)
+	}
+
+	return &db.EmptyRows{}, nil
+}
+
+// Delete deletes all the documents matching the conditions by DeleteMany
+func (g *mongoGateway) Delete(tableName string, dc *db.DeleteCtrl) (int64, error) {
+	var filter, err = whereFilter(dc.Where)
+	if err != nil {
+		return 0, err
+	}
+
+	g.log("delete %s: %v", tableName, filter)
+	if g.dryRun {
+		return 0, nil
+	}
+
+	var ctx, cancel = g.queryCtx()
+	defer cancel()
+
+	var res *mongo.DeleteResult
+	if res, err = g.database.Collection(tableName).DeleteMany(ctx, filter); err != nil {
+		return 0, queryErr(ctx, err)
+	}
+
+	return res.DeletedCount, nil
+}
+
+// whereFilter translates the SelectCtrl / UpdateCtrl / DeleteCtrl conditions to the MongoDB query filter:
+// the plain values are matched by $in, ne() by $nin, lt() / le() / gt() / ge() by the range operators
+// and like() / hlike() / tlike() by $regex; the integer looking values are compared as numbers
+func whereFilter(where map[string][]string) (bson.D, error) {
+	var filter = bson.D{}
+
+	for _, c := range db.SortFields(where) {
+		if c.Col == "" {
+			return nil, fmt.Errorf("empty condition field")
+		}
+
+		if len(c.Vals) == 1 {
+			switch c.Vals[0] {
+			case db.SpecialConditionIsNull:
+				filter = append(filter, bson.E{Key: c.Col, Value: nil})
+				continue
+			case db.SpecialConditionIsNotNull:
+				filter = append(filter, bson.E{Key: c.Col, Value: bson.D{{Key: "$ne", Value: nil}}})
+				continue
+			}
+		}
+
+		var in, nin bson.A
+		var ops bson.D
+		for _, v := range c.Vals {
+			var fnc, arg, err = db.ParseFunc(v)
+			if err != nil {
+				return nil, fmt.Errorf("%v on field '%v'", err, c.Col)
+			}
+
+			switch fnc {
+			case "":
+				in = append(in, filterValue(arg))
+			case "ne":
+				nin = append(nin, filterValue(arg))
+			case "lt", "le", "gt", "ge":
+				ops = append(ops, bson.E{Key: rangeOperator(fnc), Value: filterValue(arg)})
+			case "like":
+				ops = append(ops, bson.E{Key: "$regex", Value: primitive.Regex{Pattern: regexp.QuoteMeta(arg)}})
+			case "hlike":
+				ops = append(ops, bson.E{Key: "$regex", Value: primitive.Regex{Pattern: "^" + regexp.QuoteMeta(arg)}})
+			case "tlike":
+				ops = append(ops, bson.E{Key: "$regex", Value: primitive.Regex{Pattern: regexp.QuoteMeta(arg) + "$"}})
+			default:
+				return nil, fmt.Errorf("unsupported function '%v' on field '%v'", fnc, c.Col)
+			}
+		}
+
+		if len(in) != 0 {
+			ops = append(ops, bson.E{Key: "$in", Value: in})
+		}
+		if len(nin) != 0 {
+			ops = append(ops, bson.E{Key: "$nin", Value: nin})
+		}
+
+		filter = append(filter, bson.E{Key: c.Col, Value: ops})
+	}
+
+	return filter, nil
+}
+
+// rangeOperator returns the MongoDB comparison operator of the range function
+func rangeOperator(fnc string) string {
+	switch fnc {
+	case "le":
+		return "$lte"
+	case "ge":
+		return "$gte"
+	default:
+		return "$" + fnc
+	}
+}
+
+// filterValue returns the integer looking condition values as int64, so they match the numeric fields
+func filterValue(s string) interface{} {
+	if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return i
+	}
+
+	return s
+}
+
+// orderSort translates the SelectCtrl order functions to the sort document, the nulls placement is not configurable in MongoDB
+func orderSort(order []string) (bson.D, error) {
+	var sort bson.D
+	for _, o := range order {
+		var fnc, col, err = db.ParseFunc(o)
+		if err != nil {
+			return nil, err
+		}
+
+		if col == "" {
+			return nil, fmt.Errorf("empty order field")
+		}
+
+		switch fnc {
+		case "asc", "asc_nulls_first", "asc_nulls_last":
+			sort = append(sort, bson.E{Key: col, Value: 1})
+		case "desc", "desc_nulls_first", "desc_nulls_last":
+			sort = append(sort, bson.E{Key: col, Value: -1})
+		default:
+			return nil, fmt.Errorf("bad order function '%v'", fnc)
+		}
+	}
+
+	return sort, nil
+}
+
+// mongoRows iterates over the Find cursor, the requested fields are scanned in the SelectCtrl.Fields order
+type mongoRows struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	cursor *mongo.Cursor
+	fields []string
+
+	current bson.M
+	err     error
+}
+
+func (r *mongoRows) Next() bool {
+	if r.err != nil || !r.cursor.Next(r.ctx) {
+		return false
+	}
+
+	r.current = bson.M{}
+	if r.err = r.cursor.Decode(&r.current); r.err != nil {
+		return false
+	}
+
+	return true
+}
+
+func (r *mongoRows) Err() error {
+	if r.err != nil {
+		return r.err
+	}
+
+	return queryErr(r.ctx, r.cursor.Err())
+}
+
+func (r *mongoRows) Scan(dest ...interface{}) error {
+	if len(dest) != len(r.fields) {
+		return fmt.Errorf("internal error: mongoRows.Scan() - %d destinations given for %d fields", len(dest), len(r.fields))
+	}
+
+	for i, f := range r.fields {
+		if err := scanValue(dest[i], r.current[f]); err != nil {
+			return fmt.Errorf("field '%s': %v", f, err)
+		}
+	}
+
+	return nil
+}
+
+func (r *mongoRows) Close() error {
+	defer r.cancel()
+
+	return r.cursor.Close(r.ctx)
+}
+
+func (r *mongoRows) Dump() string {
+	return fmt.Sprintf("%v", r.current)
+}
+
+// scanValue stores the decoded BSON value to the dest pointer converting the numbers and strings if needed
+func scanValue(dest interface{}, value interface{}) error {
+	var dv = reflect.ValueOf(dest)
+	if dv.Kind() != reflect.Ptr || dv.IsNil() {
+		return fmt.Errorf("non-pointer passed to Scan: %v", dest)
+	}
+
+	switch v := value.(type) {
+	case primitive.ObjectID:
+		value = v.Hex()
+	case primitive.DateTime:
+		value = v.Time()
+	case primitive.Binary:
+		value = v.Data
+	}
+
+	var target = dv.Elem()
+	if value == nil {
+		target.Set(reflect.Zero(target.Type()))
+		return nil
+	}
+
+	var sv = reflect.ValueOf(value)
+	switch {
+	case sv.Type().AssignableTo(target.Type()):
+		target.Set(sv)
+	case sv.Kind() != reflect.String && target.Kind() != reflect.String && sv.Type().ConvertibleTo(target.Type()):
+		target.Set(sv.Convert(target.Type()))
+	case target.Kind() == reflect.String:
+		target.SetString(fmt.Sprintf("%v", value))
+	default:
+		return fmt.Errorf("conversion of %T to %v is not supported", value, target.Type())
+	}
+
+	return nil
+}
@@ -217,3 +217,36 @@ func terminateEmbeddedPostgres() error {
 
 	return nil
 }
+
+// Stop stops the running embedded Postgres instance keeping its data dir, so it can be started again by Start.
+// It is used to simulate the DB outages, the instance stays registered and is terminated by Terminate as usual.
+func Stop() error {
+	embeddedPostgresMutex.Lock()
+	defer embeddedPostgresMutex.Unlock()
+
+	if embeddedPostgresInstance == nil {
+		return fmt.Errorf("embedded Postgres DB is not launched")
+	}
+
+	if err := embeddedPostgresInstance.Stop(); err != nil {
+		return fmt.Errorf("embedded Postgres DB stop error: %v", err)
+	}
+
+	return nil
+}
+
+// Start starts the embedded Postgres instance stopped by Stop, the data dir of the instance is reused.
+func Start() error {
+	embeddedPostgresMutex.Lock()
+	defer embeddedPostgresMutex.Unlock()
+
+	if embeddedPostgresInstance == nil {
+		return fmt.Errorf("embedded Postgres DB is not launched")
+	}
+
+	if err := embeddedPostgresInstance.Start(); err != nil {
+		return fmt.Errorf("embedded Postgres DB start error: %v", err)
+	}
+
+	return nil
+}
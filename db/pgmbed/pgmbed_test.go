@@ -39,6 +39,17 @@ func TestLaunch(t *testing.T) {
 
 	t.Logf("connection string for second attempt of launching embedded postgres: %s", cs)
 
+	// Simulate the DB outage
+	if err = Stop(); err != nil {
+		t.Errorf("failed stopping: %v\n", err)
+		return
+	}
+
+	if err = Start(); err != nil {
+		t.Errorf("failed restarting: %v\n", err)
+		return
+	}
+
 	// Simulate closing connections
 	if err = Terminate(); err != nil {
 		t.Errorf("failed terminating: %v\n", err)
@@ -0,0 +1,1041 @@
+//go:build redis
+
+// Package redis provides an implementation of the db.Database interface for Redis.
+//
+// The valkey:// and valkeys:// connection strings are served by the same implementation, the database reports
+// the Valkey dialect so the Valkey-specific behaviour can be added without affecting Redis.
+//
+// The package is built with the 'redis' build tag only, so the github.com/redis/go-redis/v9 dependency
+// is not required by the default build.
+//
+// Every row is stored as the '<table>:<id>' hash, the ids of the rows are kept in the '<table>' sorted set
+// scored by the id, so the id conditions of the selects are served by ZRANGEBYSCORE.
+package redis
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	goredis "github.com/redis/go-redis/v9"
+
+	"github.com/acronis/perfkit/db"
+)
+
+const (
+	idColumn       = "id"   // the only column the rows can be selected by
+	sequenceSuffix = ":seq" // suffix of the key of the counter the ids of the table rows are generated by
+	scanBatchSize  = 1000   // number of the keys requested by a single SCAN call of DropTable
+)
+
+// errRawQueries is returned by the SQL-only methods of the db.DatabaseAccessor
+var errRawQueries = errors.New("raw queries are not supported by redis, use Exec to run a command")
+
+// nolint: gochecknoinits // remove init() when we will have a better way to register connectors
+func init() {
+	for _, redisNameStyle := range []string{"redis", "rediss", "valkey", "valkeys"} {
+		if err := db.Register(redisNameStyle, &redisConnector{}); err != nil {
+			panic(err)
+		}
+	}
+}
+
+type redisConnector struct{}
+
+// ConnectionPool creates the client of the database given by the connection string, rediss:// and valkeys:// enable TLS
+func (c *redisConnector) ConnectionPool(cfg db.Config) (db.Database, error) {
+	var dialect = db.REDIS
+	if connString, isValkey := strings.CutPrefix(cfg.ConnString, "valkey"); isValkey {
+		dialect = db.VALKEY
+		cfg.ConnString = "redis" + connString
+	}
+
+	var opts, err = goredis.ParseURL(cfg.ConnString)
+	if err != nil {
+		return nil, fmt.Errorf("db: redis: cannot parse connection url, err: %v", err)
+	}
+
+	if cfg.MaxOpenConns > 0 {
+		opts.PoolSize = cfg.MaxOpenConns
+	}
+	if cfg.MaxConnLifetime > 0 {
+		opts.ConnMaxLifetime = cfg.MaxConnLifetime
+	}
+
+	if cfg.TLSEnabled || len(cfg.TLSCACert) != 0 {
+		if opts.TLSConfig == nil {
+			// nolint:gosec // TODO: TLS MinVersion too low
+			opts.TLSConfig = &tls.Config{}
+		}
+		if len(cfg.TLSCACert) != 0 {
+			var caCertPool = x509.NewCertPool()
+			caCertPool.AppendCertsFromPEM(cfg.TLSCACert)
+			opts.TLSConfig.RootCAs = caCertPool
+		}
+	}
+
+	return &redisDatabase{
+		client:       goredis.NewClient(opts),
+		dialect:      dialect,
+		maxOpenConns: cfg.MaxOpenConns,
+		queryTimeout: cfg.QueryTimeout,
+		dryRun:       cfg.DryRun,
+		queryLogger:  cfg.QueryLogger,
+	}, nil
+}
+
+func (c *redisConnector) DialectName(scheme string) (db.DialectName, error) {
+	if strings.HasPrefix(scheme, "valkey") {
+		return db.VALKEY, nil
+	}
+
+	return db.REDIS, nil
+}
+
+type redisDatabase struct {
+	client       *goredis.Client
+	dialect      db.DialectName // db.REDIS or db.VALKEY, given by the connection string scheme
+	maxOpenConns int
+	queryTimeout time.Duration
+	dryRun       bool
+
+	queryLogger db.Logger
+}
+
+// Ping pings the DB
+func (d *redisDatabase) Ping(ctx context.Context) error {
+	return d.client.Ping(ctx).Err()
+}
+
+func (d *redisDatabase) DialectName() db.DialectName {
+	return d.dialect
+}
+
+func (d *redisDatabase) UseTruncate() bool {
+	return false
+}
+
+// GetVersion returns the redis_version reported by INFO server, Valkey reports its own valkey_version
+// along with the redis_version of the compatible Redis release, so the former is preferred for Valkey
+func (d *redisDatabase) GetVersion() (db.DialectName, string, error) {
+	var info, err = d.client.Info(context.Background(), "server").Result()
+	if err != nil {
+		return "", "", fmt.Errorf("db: redis: cannot get version: %v", err)
+	}
+
+	var redisVersion, valkeyVersion string
+	for _, line := range strings.Split(info, "\n") {
+		line = strings.TrimSpace(line)
+		if version, found := strings.CutPrefix(line, "redis_version:"); found {
+			redisVersion = version
+		} else if version, found = strings.CutPrefix(line, "valkey_version:"); found {
+			valkeyVersion = version
+		}
+	}
+
+	if d.dialect == db.VALKEY && valkeyVersion != "" {
+		return db.VALKEY, valkeyVersion, nil
+	}
+	if redisVersion == "" {
+		return "", "", fmt.Errorf("db: redis: no redis_version in the server info")
+	}
+
+	return d.dialect, redisVersion, nil
+}
+
+func (d *redisDatabase) GetInfo(version string) (ret []string, dbInfo *db.Info, err error) {
+	if d.dialect == db.VALKEY {
+		return []string{"Valkey " + version}, nil, nil
+	}
+
+	return []string{"Redis " + version}, nil, nil
+}
+
+func (d *redisDatabase) ApplyMigrations(tableName, tableMigrationSQL string) error {
+	return nil
+}
+
+// TableExists returns true if the sorted set of the table row ids exists, i.e. at least one row was inserted
+func (d *redisDatabase) TableExists(tableName string) (bool, error) {
+	var n, err = d.client.Exists(context.Background(), tableName).Result()
+	if err != nil {
+		return false, fmt.Errorf("table %s: %v", tableName, err)
+	}
+
+	return n != 0, nil
+}
+
+// CreateTable does nothing, the keys of the table are created by the first insert
+func (d *redisDatabase) CreateTable(tableName string, tableDefinition *db.TableDefinition, tableMigrationDDL string) error {
+	if d.queryLogger != nil {
+		d.queryLogger.Log("create table %s: skipped, redis is schemaless and the keys are created by the first insert", tableName)
+	}
+
+	return nil
+}
+
+// DropTable deletes the sorted set, the sequence and all the row hashes of the table
+func (d *redisDatabase) DropTable(tableName string) error {
+	var ctx = context.Background()
+	var cursor uint64
+	for {
+		var keys, next, err = d.client.Scan(ctx, cursor, tableName+":*", scanBatchSize).Result()
+		if err != nil {
+			return fmt.Errorf("table %s: %v", tableName, err)
+		}
+
+		if len(keys) != 0 {
+			if err = d.client.Del(ctx, keys...).Err(); err != nil {
+				return fmt.Errorf("table %s: %v", tableName, err)
+			}
+		}
+
+		if cursor = next; cursor == 0 {
+			break
+		}
+	}
+
+	return d.client.Del(ctx, tableName).Err()
+}
+
+func (d *redisDatabase) IndexExists(indexName string, tableName string) (bool, error) {
+	return false, nil
+}
+
+// CreateIndex does nothing, the rows are indexed by id only
+func (d *redisDatabase) CreateIndex(indexName string, tableName string, columns []string, indexType db.IndexType) error {
+	return nil
+}
+
+func (d *redisDatabase) CreateUniqueIndex(indexName string, tableName string, columns []string) error {
+	return nil
+}
+
+func (d *redisDatabase) DropIndex(indexName string, tableName string) error {
+	return nil
+}
+
+func (d *redisDatabase) ReadConstraints() ([]db.Constraint, error) {
+	return nil, nil
+}
+
+func (d *redisDatabase) AddConstraints(constraints []db.Constraint) error {
+	return nil
+}
+
+func (d *redisDatabase) DropConstraints(constraints []db.Constraint) error {
+	return nil
+}
+
+// CreateSequence does nothing, the counter is created by the first GetNextVal call
+func (d *redisDatabase) CreateSequence(sequenceName string) error {
+	return nil
+}
+
+func (d *redisDatabase) DropSequence(sequenceName string) error {
+	return d.client.Del(context.Background(), sequenceName).Err()
+}
+
+func (d *redisDatabase) CreateSchema(schemaName string) error {
+	return fmt.Errorf("schema %s: schemas are not supported by redis", schemaName)
+}
+
+func (d *redisDatabase) DropSchema(schemaName string) error {
+	return fmt.Errorf("schema %s: schemas are not supported by redis", schemaName)
+}
+
+func (d *redisDatabase) Defragment(tableName string) error {
+	return fmt.Errorf("table %s: defragmentation is not supported by redis", tableName)
+}
+
+func (d *redisDatabase) RunInSchemaSandbox(schemaName string, fn func() error) error { //nolint:revive
+	return fmt.Errorf("schema %s: schema sandbox is not supported by redis", schemaName)
+}
+
+func (d *redisDatabase) GetTablesSchemaInfo(tableNames []string) ([]string, error) {
+	return nil, nil
+}
+
+// GetTablesVolumeInfo returns the number of the rows of the tables given by the cardinality of their sorted sets
+func (d *redisDatabase) GetTablesVolumeInfo(tableNames []string) ([]string, error) {
+	var ret = []string{fmt.Sprintf("%-55s %15s", "TABLE NAME", "ROWS")}
+	for _, name := range tableNames {
+		var count, err = d.client.ZCard(context.Background(), name).Result()
+		if err != nil {
+			return nil, fmt.Errorf("table %s: %v", name, err)
+		}
+
+		ret = append(ret, fmt.Sprintf("%-55s %15d", name, count))
+	}
+
+	return ret, nil
+}
+
+func (d *redisDatabase) GetIndexUsageStats(tableName string) ([]db.IndexUsageStat, error) {
+	return nil, nil
+}
+
+func (d *redisDatabase) GetLockWaitStats() ([]db.LockWaitStat, error) {
+	return nil, nil
+}
+
+func (d *redisDatabase) GetActiveQueries() ([]db.ActiveQuery, error) {
+	return nil, nil
+}
+
+func (d *redisDatabase) GetCheckpointStats() (db.CheckpointStats, error) {
+	return db.CheckpointStats{}, nil
+}
+
+func (d *redisDatabase) Context(ctx context.Context) *db.Context {
+	return &db.Context{Ctx: ctx}
+}
+
+func (d *redisDatabase) Session(c *db.Context) db.Session {
+	return &redisSession{
+		redisGateway: redisGateway{
+			client:       d.client,
+			ctx:          c,
+			queryTimeout: d.queryTimeout,
+			dryRun:       d.dryRun,
+			queryLogger:  d.queryLogger,
+		},
+	}
+}
+
+// RawSession returns the *redis.Client
+func (d *redisDatabase) RawSession() interface{} {
+	return d.client
+}
+
+func (d *redisDatabase) Stats() *db.Stats {
+	var s = d.client.PoolStats()
+
+	return &db.Stats{
+		MaxOpenConnections: d.maxOpenConns,
+		OpenConnections:    int(s.TotalConns),
+		InUse:              int(s.TotalConns) - int(s.IdleConns),
+		Idle:               int(s.IdleConns),
+		WaitCount:          int64(s.Timeouts),
+	}
+}
+
+func (d *redisDatabase) Close() error {
+	if err := d.client.Close(); err != nil {
+		return fmt.Errorf("close failed: %w", err)
+	}
+
+	return nil
+}
+
+type redisGateway struct {
+	client       *goredis.Client
+	ctx          *db.Context
+	queryTimeout time.Duration
+	dryRun       bool
+
+	queryLogger db.Logger
+}
+
+type redisSession struct {
+	redisGateway
+}
+
+// Transact runs fn as is, MULTI / EXEC transactions can't read the data and are not used by the benchmark
+func (s *redisSession) Transact(fn func(tx db.DatabaseAccessor) error) error {
+	return fn(s)
+}
+
+func (s *redisSession) RunInReadOnlyTransaction(fn func(tx db.DatabaseAccessor) error) error {
+	return fn(s)
+}
+
+// GetNextVal increments the counter stored at the sequence name key
+func (s *redisSession) GetNextVal(sequenceName string) (uint64, error) {
+	var ctx, cancel = s.queryCtx()
+	defer cancel()
+
+	var val, err = s.client.Incr(ctx, sequenceName).Result()
+	if err != nil {
+		return 0, queryErr(ctx, fmt.Errorf("sequence %s: %v", sequenceName, err))
+	}
+
+	return uint64(val), nil
+}
+
+// queryCtx returns a context for a single command bounded by the client-side query timeout, if any
+func (g *redisGateway) queryCtx() (context.Context, context.CancelFunc) {
+	if g.queryTimeout <= 0 {
+		return g.ctx.Ctx, func() {}
+	}
+
+	return context.WithTimeout(g.ctx.Ctx, g.queryTimeout)
+}
+
+// queryErr marks errors caused by the expired client-side query timeout with db.ErrQueryTimeout
+func queryErr(ctx context.Context, err error) error {
+	if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("%w: %v", db.ErrQueryTimeout, err)
+	}
+
+	return err
+}
+
+func (g *redisGateway) log(format string, args ...interface{}) {
+	if g.queryLogger != nil {
+		g.queryLogger.Log(format, args...)
+	}
+}
+
+// StatementEnter is called before executing a statement
+func (g *redisGateway) StatementEnter(query string, args ...interface{}) time.Time { //nolint:revive
+	return time.Now()
+}
+
+// StatementExit is called after executing a statement
+func (g *redisGateway) StatementExit(statement string, startTime time.Time, err error, showRowsAffected bool, result db.Result, format string, args []interface{}, rows db.Rows, dest []interface{}) {
+}
+
+// Exec runs the raw command given by the whitespace separated words of format followed by args, e.g. Exec("HGET key", "field")
+func (g *redisGateway) Exec(format string, args ...interface{}) (db.Result, error) {
+	var words = strings.Fields(format)
+	if len(words) == 0 {
+		return nil, fmt.Errorf("empty command")
+	}
+
+	var cmd = make([]interface{}, 0, len(words)+len(args))
+	for _, w := range words {
+		cmd = append(cmd, w)
+	}
+	for _, a := range args {
+		cmd = append(cmd, hashValue(a))
+	}
+
+	g.log("%v", cmd)
+	if g.dryRun {
+		return execResult{}, nil
+	}
+
+	var ctx, cancel = g.queryCtx()
+	defer cancel()
+
+	var reply, err = g.client.Do(ctx, cmd...).Result()
+	if err != nil {
+		return nil, queryErr(ctx, err)
+	}
+
+	// the integer replies are the numbers of the affected keys or fields for the most of the commands
+	var affected, _ = reply.(int64)
+
+	return execResult{affected: affected}, nil
+}
+
+// execResult is the db.Result of the raw command
+type execResult struct {
+	affected int64
+}
+
+func (r execResult) LastInsertId() (int64, error) {
+	return 0, fmt.Errorf("LastInsertId is not supported by redis")
+}
+
+func (r execResult) RowsAffected() (int64, error) {
+	return r.affected, nil
+}
+
+func (g *redisGateway) QueryRow(format string, args ...interface{}) db.Row {
+	return errRow{errRawQueries}
+}
+
+func (g *redisGateway) Query(format string, args ...interface{}) (db.Rows, error) {
+	return nil, errRawQueries
+}
+
+func (g *redisGateway) Prepare(query string) (db.Stmt, error) {
+	return nil, errRawQueries
+}
+
+// errRow is the db.Row failing to scan with the given error
+type errRow struct {
+	err error
+}
+
+func (r errRow) Scan(dest ...any) error {
+	return r.err
+}
+
+// BulkInsert stores every row by HSET and adds its id to the table sorted set by ZADD in a single pipeline,
+// the ids are generated by INCRBY of the table sequence unless the id column is given;
+// the rows with the existing ids are overwritten as Redis has no unique constraints
+func (g *redisGateway) BulkInsert(tableName string, c *db.BulkInsertCtrl) error {
+	if len(c.Rows) == 0 {
+		return nil
+	}
+
+	var idPos = -1
+	for i, col := range c.ColumnNames {
+		if col == idColumn {
+			idPos = i
+		}
+	}
+
+	g.log("hset %s: %d rows", tableName, len(c.Rows))
+	if g.dryRun {
+		return nil
+	}
+
+	var ctx, cancel = g.queryCtx()
+	defer cancel()
+
+	var nextID int64
+	if idPos < 0 {
+		var lastID, err = g.client.IncrBy(ctx, tableName+sequenceSuffix, int64(len(c.Rows))).Result()
+		if err != nil {
+			return queryErr(ctx, fmt.Errorf("table %s: cannot generate ids: %v", tableName, err))
+		}
+		nextID = lastID - int64(len(c.Rows)) + 1
+	}
+
+	var pipe = g.client.Pipeline()
+	for _, row := range c.Rows {
+		if len(row) != len(c.ColumnNames) {
+			return fmt.Errorf("table %s: %d values given for %d columns", tableName, len(row), len(c.ColumnNames))
+		}
+
+		var id int64
+		var fields = make([]interface{}, 0, 2*len(row)+2)
+		if idPos < 0 {
+			id = nextID
+			nextID++
+			fields = append(fields, idColumn, id)
+		} else {
+			var err error
+			if id, err = strconv.ParseInt(fmt.Sprintf("%v", row[idPos]), 10, 64); err != nil {
+				return fmt.Errorf("table %s: non-integer id %v", tableName, row[idPos])
+			}
+		}
+
+		for i, col := range c.ColumnNames {
+			fields = append(fields, col, hashValue(row[i]))
+		}
+
+		pipe.HSet(ctx, rowKey(tableName, id), fields...)
+		pipe.ZAdd(ctx, tableName, goredis.Z{Score: float64(id), Member: id})
+	}
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return queryErr(ctx, err)
+	}
+
+	return nil
+}
+
+// rowKey returns the key of the hash the row is stored in
+func rowKey(tableName string, id int64) string {
+	return tableName + ":" + strconv.FormatInt(id, 10)
+}
+
+// hashValue converts the benchmark values to the ones accepted by the go-redis argument writer
+func hashValue(v interface{}) interface{} {
+	switch val := v.(type) {
+	case nil, string, []byte, time.Time:
+		return v
+	case uuid.UUID:
+		// google/uuid implements encoding.BinaryMarshaler, it would be stored as 16 raw bytes otherwise
+		return val.String()
+	}
+
+	// the named types (e.g. the tenant UUIDs) are rejected by go-redis, so they are converted to the underlying ones
+	var rv = reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.String:
+		return rv.String()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return rv.Uint()
+	case reflect.Float32, reflect.Float64:
+		return rv.Float()
+	case reflect.Bool:
+		return rv.Bool()
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// scoreRange is the closed or open (prefixed with '(') score interval of ZRANGEBYSCORE / ZCOUNT
+type scoreRange struct {
+	min, max string
+}
+
+// idRanges translates the id conditions to the score ranges: every plain value is a single id range
+// and lt() / le() / gt() / ge() narrow down a single range; the conditions on the other columns are not supported
+func idRanges(where map[string][]string) ([]scoreRange, error) {
+	var bounds = scoreRange{min: "-inf", max: "+inf"}
+	var ids []int64
+	var bounded bool
+
+	for _, c := range db.SortFields(where) {
+		if c.Col != idColumn {
+			return nil, fmt.Errorf("condition on field '%v' is not supported by redis, only '%s' is", c.Col, idColumn)
+		}
+
+		for _, v := range c.Vals {
+			var fnc, arg, err = db.ParseFunc(v)
+			if err != nil {
+				return nil, fmt.Errorf("%v on field '%v'", err, c.Col)
+			}
+
+			var id int64
+			if id, err = strconv.ParseInt(arg, 10, 64); err != nil {
+				return nil, fmt.Errorf("non-integer value '%v' on field '%v'", arg, c.Col)
+			}
+
+			switch fnc {
+			case "":
+				ids = append(ids, id)
+			case "lt":
+				bounds.max = "(" + arg
+			case "le":
+				bounds.max = arg
+			case "gt":
+				bounds.min = "(" + arg
+			case "ge":
+				bounds.min = arg
+			default:
+				return nil, fmt.Errorf("unsupported function '%v' on field '%v'", fnc, c.Col)
+			}
+			bounded = bounded || fnc != ""
+		}
+	}
+
+	if len(ids) == 0 {
+		return []scoreRange{bounds}, nil
+	}
+
+	if bounded {
+		return nil, fmt.Errorf("mixing of the plain values and the range functions on field '%s' is not supported by redis", idColumn)
+	}
+
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	var ranges = make([]scoreRange, 0, len(ids))
+	for _, id := range ids {
+		var s = strconv.FormatInt(id, 10)
+		ranges = append(ranges, scoreRange{min: s, max: s})
+	}
+
+	return ranges, nil
+}
+
+// descending returns true if the rows are ordered by desc(id), only the id ordering is supported
+func descending(order []string) (bool, error) {
+	var desc bool
+	for _, o := range order {
+		var fnc, col, err = db.ParseFunc(o)
+		if err != nil {
+			return false, err
+		}
+
+		if col != idColumn {
+			return false, fmt.Errorf("order by field '%v' is not supported by redis, only '%s' is", col, idColumn)
+		}
+
+		switch fnc {
+		case "asc", "asc_nulls_first", "asc_nulls_last":
+			desc = false
+		case "desc", "desc_nulls_first", "desc_nulls_last":
+			desc = true
+		default:
+			return false, fmt.Errorf("bad order function '%v'", fnc)
+		}
+	}
+
+	return desc, nil
+}
+
+// count returns the number of the table rows within the ranges by ZCOUNT
+func (g *redisGateway) count(ctx context.Context, tableName string, ranges []scoreRange) (int64, error) {
+	var pipe = g.client.Pipeline()
+	var cmds = make([]*goredis.IntCmd, 0, len(ranges))
+	for _, r := range ranges {
+		cmds = append(cmds, pipe.ZCount(ctx, tableName, r.min, r.max))
+	}
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, queryErr(ctx, fmt.Errorf("failed to count: %v", err))
+	}
+
+	var total int64
+	for _, cmd := range cmds {
+		total += cmd.Val()
+	}
+
+	return total, nil
+}
+
+// rangeIDs returns the ids of the table rows within the ranges by ZRANGEBYSCORE / ZREVRANGEBYSCORE,
+// the page is applied by LIMIT if there is a single range
+func (g *redisGateway) rangeIDs(ctx context.Context, tableName string, ranges []scoreRange, desc bool, page db.Page) ([]string, error) {
+	if desc {
+		ranges = append([]scoreRange{}, ranges...)
+		for i, j := 0, len(ranges)-1; i < j; i, j = i+1, j-1 {
+			ranges[i], ranges[j] = ranges[j], ranges[i]
+		}
+	}
+
+	var pipe = g.client.Pipeline()
+	var cmds = make([]*goredis.StringSliceCmd, 0, len(ranges))
+	for _, r := range ranges {
+		var by = &goredis.ZRangeBy{Min: r.min, Max: r.max}
+		if len(ranges) == 1 && (page.Limit > 0 || page.Offset > 0) {
+			by.Offset, by.Count = page.Offset, page.Limit
+			if by.Count <= 0 {
+				by.Count = -1
+			}
+		}
+
+		if desc {
+			cmds = append(cmds, pipe.ZRevRangeByScore(ctx, tableName, by))
+		} else {
+			cmds = append(cmds, pipe.ZRangeByScore(ctx, tableName, by))
+		}
+	}
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, queryErr(ctx, fmt.Errorf("failed to select ids: %v", err))
+	}
+
+	var ids []string
+	for _, cmd := range cmds {
+		ids = append(ids, cmd.Val()...)
+	}
+
+	if len(ranges) > 1 {
+		if page.Offset >= int64(len(ids)) {
+			return nil, nil
+		}
+		ids = ids[page.Offset:]
+		if page.Limit > 0 && int64(len(ids)) > page.Limit {
+			ids = ids[:page.Limit]
+		}
+	}
+
+	return ids, nil
+}
+
+// Select reads the rows with the ids matching the conditions by ZRANGEBYSCORE and their fields by HMGET,
+// COUNT(0) or no fields counts the rows by ZCOUNT instead
+func (g *redisGateway) Select(tableName string, sc *db.SelectCtrl) (db.Rows, error) {
+	if len(sc.Aggregates) != 0 {
+		return nil, fmt.Errorf("table %s: aggregates are not supported by redis", tableName)
+	}
+
+	var ranges, err = idRanges(sc.Where)
+	if err != nil {
+		return nil, err
+	}
+
+	var ctx, cancel = g.queryCtx()
+	defer cancel()
+
+	if len(sc.Fields) == 0 || (len(sc.Fields) == 1 && sc.Fields[0] == "COUNT(0)") {
+		g.log("zcount %s: %v", tableName, ranges)
+
+		var count int64
+		if count, err = g.count(ctx, tableName, ranges); err != nil {
+			return nil, err
+		}
+
+		return &db.CountRows{Count: count}, nil
+	}
+
+	var hashFields []string
+	for _, f := range sc.Fields {
+		if f == "" {
+			return nil, fmt.Errorf("empty request field")
+		}
+		if f != idColumn {
+			hashFields = append(hashFields, f)
+		}
+	}
+
+	var desc bool
+	if desc, err = descending(sc.Order); err != nil {
+		return nil, err
+	}
+
+	g.log("zrangebyscore %s: %v", tableName, ranges)
+
+	var ids []string
+	if ids, err = g.rangeIDs(ctx, tableName, ranges, desc, sc.Page); err != nil {
+		return nil, err
+	}
+
+	var rows = &redisRows{fields: sc.Fields, values: make([]map[string]interface{}, 0, len(ids)), pos: -1}
+	if len(ids) == 0 {
+		return rows, nil
+	}
+
+	var cmds = make([]*goredis.SliceCmd, 0, len(ids))
+	if len(hashFields) != 0 {
+		var pipe = g.client.Pipeline()
+		for _, id := range ids {
+			cmds = append(cmds, pipe.HMGet(ctx, tableName+":"+id, hashFields...))
+		}
+
+		if _, err = pipe.Exec(ctx); err != nil {
+			return nil, queryErr(ctx, fmt.Errorf("failed to read rows: %v", err))
+		}
+	}
+
+	for i, id := range ids {
+		var row = map[string]interface{}{idColumn: id}
+		if len(cmds) != 0 {
+			for j, v := range cmds[i].Val() {
+				row[hashFields[j]] = v
+			}
+		}
+		rows.values = append(rows.values, row)
+	}
+
+	return rows, nil
+}
+
+// ExistsRow returns true if at least one row matches the SelectCtrl conditions
+func (g *redisGateway) ExistsRow(tableName string, sc *db.SelectCtrl) (bool, error) {
+	var ranges, err = idRanges(sc.Where)
+	if err != nil {
+		return false, err
+	}
+
+	var ctx, cancel = g.queryCtx()
+	defer cancel()
+
+	var count int64
+	if count, err = g.count(ctx, tableName, ranges); err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+// UpdateReturning sets the fields of all the rows matching the conditions by HSET, RETURNING is not supported
+func (g *redisGateway) UpdateReturning(tableName string, uc *db.UpdateCtrl) (db.Rows, error) {
+	if len(uc.Set) == 0 {
+		return nil, fmt.Errorf("empty update set")
+	}
+
+	if len(uc.Returning) != 0 {
+		return nil, fmt.Errorf("RETURNING is not supported for %s dialect", db.REDIS)
+	}
+
+	var fields = make([]interface{}, 0, 2*len(uc.Set))
+	for col, val := range uc.Set {
+		if col == "" {
+			return nil, fmt.Errorf("empty update field")
+		}
+		if col == idColumn {
+			return nil, fmt.Errorf("update of field '%s' is not supported by redis", idColumn)
+		}
+		fields = append(fields, col, hashValue(val))
+	}
+
+	var ranges, err = idRanges(uc.Where)
+	if err != nil {
+		return nil, err
+	}
+
+	g.log("hset %s: %v set %v", tableName, ranges, fields)
+	if g.dryRun {
+		return &db.EmptyRows{}, nil
+	}
+
+	var ctx, cancel = g.queryCtx()
+	defer cancel()
+
+	var ids []string
+	if ids, err = g.rangeIDs(ctx, tableName, ranges, false, db.Page{}); err != nil {
+		return nil, err
+	}
+
+	if len(ids) == 0 {
+		return &db.EmptyRows{}, nil
+	}
+
+	var pipe = g.client.Pipeline()
+	for _, id := range ids {
+		pipe.HSet(ctx, tableName+":"+id, fields...)
+	}
+
+	if _, err = pipe.Exec(ctx); err != nil {
+		return nil, queryErr(ctx, err)
+	}
+
+	return &db.EmptyRows{}, nil
+}
+
+// Upsert stores the rows by BulkInsert as HSET overwrites the given fields of the existing hashes,
+// so the rows conflict by the id column only
+func (g *redisGateway) Upsert(tableName string, rows [][]interface{}, columnNames []string, conflictColumns []string, updateColumns []string) error {
+	for _, col := range conflictColumns {
+		if col != idColumn {
+			return fmt.Errorf("table %s: upsert conflicts are detected by the %s column only", tableName, idColumn)
+		}
+	}
+
+	return g.BulkInsert(tableName, &db.BulkInsertCtrl{Rows: rows, ColumnNames: columnNames})
+}
+
+// Delete deletes the hashes of all the rows matching the conditions and removes their ids from the table sorted set
+func (g *redisGateway) Delete(tableName string, dc *db.DeleteCtrl) (int64, error) {
+	var ranges, err = idRanges(dc.Where)
+	if err != nil {
+		return 0, err
+	}
+
+	g.log("del %s: %v", tableName, ranges)
+	if g.dryRun {
+		return 0, nil
+	}
+
+	var ctx, cancel = g.queryCtx()
+	defer cancel()
+
+	var ids []string
+	if ids, err = g.rangeIDs(ctx, tableName, ranges, false, db.Page{}); err != nil {
+		return 0, err
+	}
+
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	var keys = make([]string, 0, len(ids))
+	var members = make([]interface{}, 0, len(ids))
+	for _, id := range ids {
+		keys = append(keys, tableName+":"+id)
+		members = append(members, id)
+	}
+
+	var pipe = g.client.Pipeline()
+	var deleted = pipe.Del(ctx, keys...)
+	pipe.ZRem(ctx, tableName, members...)
+
+	if _, err = pipe.Exec(ctx); err != nil {
+		return 0, queryErr(ctx, err)
+	}
+
+	return deleted.Val(), nil
+}
+
+// redisRows iterates over the selected rows, the requested fields are scanned in the SelectCtrl.Fields order
+type redisRows struct {
+	fields []string
+	values []map[string]interface{}
+	pos    int
+}
+
+func (r *redisRows) Next() bool {
+	r.pos++
+
+	return r.pos < len(r.values)
+}
+
+func (r *redisRows) Err() error {
+	return nil
+}
+
+func (r *redisRows) Scan(dest ...interface{}) error {
+	if len(dest) != len(r.fields) {
+		return fmt.Errorf("internal error: redisRows.Scan() - %d destinations given for %d fields", len(dest), len(r.fields))
+	}
+
+	for i, f := range r.fields {
+		if err := scanValue(dest[i], r.values[r.pos][f]); err != nil {
+			return fmt.Errorf("field '%s': %v", f, err)
+		}
+	}
+
+	return nil
+}
+
+func (r *redisRows) Close() error {
+	return nil
+}
+
+func (r *redisRows) Dump() string {
+	if r.pos < 0 || r.pos >= len(r.values) {
+		return ""
+	}
+
+	return fmt.Sprintf("%v", r.values[r.pos])
+}
+
+// scanValue parses the hash field string to the dest pointer, the missing fields are scanned as zero values
+func scanValue(dest interface{}, value interface{}) error {
+	if scanner, ok := dest.(sql.Scanner); ok {
+		return scanner.Scan(value)
+	}
+
+	var dv = reflect.ValueOf(dest)
+	if dv.Kind() != reflect.Ptr || dv.IsNil() {
+		return fmt.Errorf("non-pointer passed to Scan: %v", dest)
+	}
+
+	var target = dv.Elem()
+	if value == nil {
+		target.Set(reflect.Zero(target.Type()))
+		return nil
+	}
+
+	var s = fmt.Sprintf("%v", value)
+	switch target.Kind() {
+	case reflect.String:
+		target.SetString(s)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var i, err = strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return err
+		}
+		target.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		var u, err = strconv.ParseUint(s, 10, 64)
+		if err != nil {
+			return err
+		}
+		target.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		var f, err = strconv.ParseFloat(s, 64)
+		if err != nil {
+			return err
+		}
+		target.SetFloat(f)
+	case reflect.Bool:
+		var b, err = strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		target.SetBool(b)
+	case reflect.Interface:
+		target.Set(reflect.ValueOf(s))
+	default:
+		if target.Type() == reflect.TypeOf([]byte(nil)) {
+			target.SetBytes([]byte(s))
+			return nil
+		}
+
+		return fmt.Errorf("conversion of %T to %v is not supported", value, target.Type())
+	}
+
+	return nil
+}
@@ -0,0 +1,141 @@
+package scylla
+
+import (
+	"encoding/binary"
+	"math/bits"
+	"strconv"
+
+	"github.com/gocql/gocql"
+	"github.com/google/uuid"
+)
+
+// shardingIgnoreMSB is the default number of the token most significant bits ignored by the ScyllaDB shard selection
+const shardingIgnoreMSB = 12
+
+// murmur3 constants of the Cassandra Murmur3Partitioner
+const (
+	murmurC1    uint64 = 0x87c37b91114253d5
+	murmurC2    uint64 = 0x4cf5ad432745937f
+	murmurFmix1 uint64 = 0xff51afd7ed558ccd
+	murmurFmix2 uint64 = 0xc4ceb9fe1a85ec53
+)
+
+// shardOf returns the shard of the node owning the token, the same as the ScyllaDB biased-token-round-robin sharding
+func shardOf(token int64, shardCount int) int {
+	var z = (uint64(token) ^ 1<<63) << shardingIgnoreMSB
+	var hi, _ = bits.Mul64(z, uint64(shardCount))
+
+	return int(hi)
+}
+
+// partitionKey serializes the partition key value the same way it is sent to the server,
+// the integer looking strings (e.g. the SelectCtrl conditions) are serialized as bigint
+func partitionKey(v interface{}) ([]byte, bool) {
+	switch val := v.(type) {
+	case int64:
+		return binary.BigEndian.AppendUint64(nil, uint64(val)), true
+	case int:
+		return binary.BigEndian.AppendUint64(nil, uint64(val)), true
+	case uint64:
+		return binary.BigEndian.AppendUint64(nil, val), true
+	case int32:
+		return binary.BigEndian.AppendUint32(nil, uint32(val)), true
+	case string:
+		if i, err := strconv.ParseInt(val, 10, 64); err == nil {
+			return binary.BigEndian.AppendUint64(nil, uint64(i)), true
+		}
+		return []byte(val), true
+	case []byte:
+		return val, true
+	case uuid.UUID:
+		return val[:], true
+	case gocql.UUID:
+		return val[:], true
+	default:
+		return nil, false
+	}
+}
+
+// murmur3Token returns the Murmur3Partitioner token of the serialized partition key
+func murmur3Token(data []byte) int64 {
+	var h1 = int64(murmur3H1(data))
+	if h1 == -1<<63 {
+		// the minimum token is reserved by Cassandra
+		return 1<<63 - 1
+	}
+
+	return h1
+}
+
+// murmur3H1 is the first half of the x64 128-bit murmur3 hash with the Cassandra quirk of the sign-extended tail bytes
+func murmur3H1(data []byte) uint64 {
+	var length = len(data)
+	var h1, h2 uint64
+
+	var nBlocks = length / 16
+	for i := 0; i < nBlocks; i++ {
+		var k1 = binary.LittleEndian.Uint64(data[i*16:])
+		var k2 = binary.LittleEndian.Uint64(data[i*16+8:])
+
+		k1 *= murmurC1
+		k1 = bits.RotateLeft64(k1, 31)
+		k1 *= murmurC2
+		h1 ^= k1
+
+		h1 = bits.RotateLeft64(h1, 27)
+		h1 += h2
+		h1 = h1*5 + 0x52dce729
+
+		k2 *= murmurC2
+		k2 = bits.RotateLeft64(k2, 33)
+		k2 *= murmurC1
+		h2 ^= k2
+
+		h2 = bits.RotateLeft64(h2, 31)
+		h2 += h1
+		h2 = h2*5 + 0x38495ab5
+	}
+
+	var tail = data[nBlocks*16:]
+	var k1, k2 uint64
+	for i := len(tail) - 1; i >= 8; i-- {
+		k2 ^= uint64(int64(int8(tail[i]))) << (8 * (i - 8))
+	}
+	if len(tail) > 8 {
+		k2 *= murmurC2
+		k2 = bits.RotateLeft64(k2, 33)
+		k2 *= murmurC1
+		h2 ^= k2
+	}
+	for i := min(len(tail), 8) - 1; i >= 0; i-- {
+		k1 ^= uint64(int64(int8(tail[i]))) << (8 * i)
+	}
+	if len(tail) > 0 {
+		k1 *= murmurC1
+		k1 = bits.RotateLeft64(k1, 31)
+		k1 *= murmurC2
+		h1 ^= k1
+	}
+
+	h1 ^= uint64(length)
+	h2 ^= uint64(length)
+
+	h1 += h2
+	h2 += h1
+
+	h1 = fmix64(h1)
+	h2 = fmix64(h2)
+
+	return h1 + h2
+}
+
+// fmix64 is the murmur3 finalization mix
+func fmix64(k uint64) uint64 {
+	k ^= k >> 33
+	k *= murmurFmix1
+	k ^= k >> 33
+	k *= murmurFmix2
+	k ^= k >> 33
+
+	return k
+}
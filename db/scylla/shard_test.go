@@ -0,0 +1,79 @@
+package scylla
+
+import (
+	"strconv"
+	"testing"
+)
+
+func TestMurmur3H1(t *testing.T) {
+	// generated by the java datastax murmur3 implementation, cover all the tail lengths
+	var expected = []uint64{
+		0x0000000000000000, // ""
+		0x2ac9debed546a380, // "0"
+		0x649e4eaa7fc1708e, // "01"
+		0xce68f60d7c353bdb, // "012"
+		0x0f95757ce7f38254, // "0123"
+		0x0f04e459497f3fc1, // "01234"
+		0x88c0a92586be0a27, // "012345"
+		0x13eb9fb82606f7a6, // "0123456"
+		0x8236039b7387354d, // "01234567"
+		0x4c1e87519fe738ba, // "012345678"
+		0x3f9652ac3effeb24, // "0123456789"
+		0x3f33760ded9006c6, // "01234567890"
+		0xaed70a6631854cb1, // "012345678901"
+		0x8a299a8f8e0e2da7, // "0123456789012"
+		0x624b675c779249a6, // "01234567890123"
+		0xa4b203bb1d90b9a3, // "012345678901234"
+		0xa3293ad698ecb99a, // "0123456789012345"
+		0xbc740023dbd50048, // "01234567890123456"
+		0x3fe5ab9837d25cdd, // "012345678901234567"
+		0x2d0338c1ca87d132, // "0123456789012345678"
+	}
+
+	var sample string
+	for i, exp := range expected {
+		if h := murmur3H1([]byte(sample)); h != exp {
+			t.Errorf("murmur3H1(%q) = %x, expected %x", sample, h, exp)
+		}
+		sample += strconv.Itoa(i % 10)
+	}
+
+	if h := murmur3H1([]byte("hello, world")); h != 0x342fac623a5ebc8e {
+		t.Errorf("murmur3H1(\"hello, world\") = %x", h)
+	}
+}
+
+func TestShardOf(t *testing.T) {
+	if s := shardOf(-1<<63, 8); s != 0 {
+		t.Errorf("shardOf(min token) = %d, expected 0", s)
+	}
+
+	if s := shardOf(1<<63-1, 8); s != 7 {
+		t.Errorf("shardOf(max token) = %d, expected 7", s)
+	}
+
+	// the ignored most significant bits make the adjacent tokens land on all the shards
+	var seen = map[int]bool{}
+	for i := int64(0); i < 64; i++ {
+		var s = shardOf(i<<(63-shardingIgnoreMSB-3), 8)
+		if s < 0 || s >= 8 {
+			t.Fatalf("shardOf() = %d, out of the range", s)
+		}
+		seen[s] = true
+	}
+	if len(seen) != 8 {
+		t.Errorf("shardOf() used %d of 8 shards", len(seen))
+	}
+}
+
+func TestPartitionKey(t *testing.T) {
+	var fromInt, _ = partitionKey(int64(42))
+	var fromString, _ = partitionKey("42")
+	if murmur3Token(fromInt) != murmur3Token(fromString) {
+		t.Errorf("partitionKey() of the integer looking string differs from the bigint one")
+	}
+
+	if _, ok := partitionKey(3.14); ok {
+		t.Errorf("partitionKey() of float succeeded")
+	}
+}
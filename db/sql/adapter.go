@@ -36,8 +36,8 @@ func (d *sqlQuerier) queryContext(ctx context.Context, query string, args ...int
 func (d *sqlQuerier) prepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
 	return d.be.PrepareContext(ctx, query)
 }
-func (d *sqlQuerier) begin(ctx context.Context) (transaction, error) {
-	be, err := d.be.BeginTx(ctx, nil)
+func (d *sqlQuerier) begin(ctx context.Context, opts *sql.TxOptions) (transaction, error) {
+	be, err := d.be.BeginTx(ctx, opts)
 	if err != nil {
 		return nil, err
 	}
@@ -120,6 +120,10 @@ func (d *cassandraDialect) getType(dataType db.DataType) string {
 		return ""
 	case db.DataTypeTenantUUIDBoundID:
 		return "varchar"
+	case db.DataTypeJSON, db.DataTypeJSONB, db.DataTypeJSONText:
+		return "text" // Use text for JSON documents
+	case db.DataTypeInet, db.DataTypeCidr:
+		return "text"
 	default:
 		return ""
 	}
@@ -133,10 +137,18 @@ func (d *cassandraDialect) isRetriable(err error) bool {
 	return false
 }
 
+func (d *cassandraDialect) isUniqueViolation(err error) bool {
+	return false
+}
+
 func (d *cassandraDialect) canRollback(err error) bool {
 	return true
 }
 
+func (d *cassandraDialect) readOnlyTxOptions() *sql.TxOptions {
+	return nil
+}
+
 func (d *cassandraDialect) table(table string) string {
 	if d.keySpace != "" {
 		return d.keySpace + "." + table
@@ -191,6 +203,18 @@ func (c *cassandraConnector) ConnectionPool(cfg db.Config) (db.Database, error)
 		Password: password,
 	}
 
+	if cfg.CassandraConsistency != "" {
+		if cassandraConfig.Consistency, err = parseCassandraConsistency(cfg.CassandraConsistency); err != nil {
+			return nil, fmt.Errorf("db: %v", err)
+		}
+	}
+
+	if keySpace != "" && cfg.CassandraReplicationFactor > 0 {
+		if err = createCassandraKeySpace(cassandraConfig, cfg.CassandraReplicationFactor); err != nil {
+			return nil, fmt.Errorf("db: cannot create cassandra keyspace %s at %v, err: %v", keySpace, sanitizeConn(cfg.ConnString), err)
+		}
+	}
+
 	var dsn = cql.ClusterConfigToConfigString(cassandraConfig)
 
 	dbo := &sqlDatabase{}
@@ -213,8 +237,48 @@ func (c *cassandraConnector) ConnectionPool(cfg db.Config) (db.Database, error)
 
 	dbo.dialect = &cassandraDialect{keySpace: keySpace}
 	dbo.queryLogger = cfg.QueryLogger
+	dbo.queryRecorder = cfg.QueryRecorder
+	dbo.queryTimeout = cfg.QueryTimeout
+	dbo.dryRun = cfg.DryRun
+	dbo.deadlockRetries = cfg.DeadlockRetries
+	dbo.maxRetries = cfg.MaxRetries
+	dbo.retryBaseDelay = cfg.RetryBaseDelay
+	dbo.retryLogger = cfg.RetryLogger
+	dbo.tracer = newTracer(cfg.TracerProvider)
+
+	return withReadReplica(cfg, dbo)
+}
+
+// parseCassandraConsistency parses the consistency level supported by the benchmarks
+func parseCassandraConsistency(level string) (gocql.Consistency, error) {
+	switch strings.ToUpper(level) {
+	case "ANY":
+		return gocql.Any, nil
+	case "ONE":
+		return gocql.One, nil
+	case "QUORUM":
+		return gocql.Quorum, nil
+	case "ALL":
+		return gocql.All, nil
+	default:
+		return gocql.Any, fmt.Errorf("unsupported cassandra consistency level '%s', supported levels are ANY, ONE, QUORUM, ALL", level)
+	}
+}
+
+// createCassandraKeySpace creates the config keyspace with given replication factor if it doesn't exist yet,
+// the replication of already existing keyspace is not changed
+func createCassandraKeySpace(cassandraConfig *gocql.ClusterConfig, replicationFactor int) error {
+	var keySpaceConfig = *cassandraConfig
+	keySpaceConfig.Keyspace = ""
+
+	var session, err = keySpaceConfig.CreateSession()
+	if err != nil {
+		return err
+	}
+	defer session.Close()
 
-	return dbo, nil
+	return session.Query(fmt.Sprintf("CREATE KEYSPACE IF NOT EXISTS %s WITH replication = {'class': 'SimpleStrategy', 'replication_factor': %d}",
+		cassandraConfig.Keyspace, replicationFactor)).Exec()
 }
 
 func (c *cassandraConnector) DialectName(scheme string) (db.DialectName, error) {
@@ -252,7 +316,7 @@ func (d *cassandraQuerier) queryContext(ctx context.Context, query string, args
 func (d *cassandraQuerier) prepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
 	return d.be.PrepareContext(ctx, query)
 }
-func (d *cassandraQuerier) begin(ctx context.Context) (transaction, error) {
+func (d *cassandraQuerier) begin(ctx context.Context, opts *sql.TxOptions) (transaction, error) { //nolint:revive
 	return &cassandraTransaction{d.be}, nil
 }
 
@@ -1,6 +1,7 @@
 package sql
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"math"
@@ -9,7 +10,8 @@ import (
 
 	"github.com/google/uuid"
 
-	_ "github.com/ClickHouse/clickhouse-go/v2" // clickhouse driver
+	"github.com/ClickHouse/clickhouse-go/v2" // clickhouse driver
+	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
 
 	"github.com/acronis/perfkit/db"
 )
@@ -20,7 +22,9 @@ func init() {
 	}
 }
 
-type clickHouseDialect struct{}
+type clickHouseDialect struct {
+	native driver.Conn // native protocol connection for the columnar inserts, nil if disabled
+}
 
 func (d *clickHouseDialect) name() db.DialectName {
 	return db.CLICKHOUSE
@@ -116,6 +120,10 @@ func (d *clickHouseDialect) getType(id db.DataType) string {
 		return "null"
 	case db.DataTypeTenantUUIDBoundID:
 		return "String"
+	case db.DataTypeJSON, db.DataTypeJSONB, db.DataTypeJSONText:
+		return "String" // Use String for JSON documents
+	case db.DataTypeInet, db.DataTypeCidr:
+		return "String"
 	default:
 		return ""
 	}
@@ -129,10 +137,18 @@ func (d *clickHouseDialect) isRetriable(err error) bool {
 	return false
 }
 
+func (d *clickHouseDialect) isUniqueViolation(err error) bool {
+	return false
+}
+
 func (d *clickHouseDialect) canRollback(err error) bool {
 	return true
 }
 
+func (d *clickHouseDialect) readOnlyTxOptions() *sql.TxOptions {
+	return nil
+}
+
 func (d *clickHouseDialect) table(table string) string {
 	return table
 }
@@ -147,6 +163,33 @@ func (d *clickHouseDialect) recommendations() []db.Recommendation {
 }
 
 func (d *clickHouseDialect) close() error {
+	if d.native != nil {
+		return d.native.Close()
+	}
+	return nil
+}
+
+// columnarInsert sends the rows by the native protocol batch filled column by column
+func (d *clickHouseDialect) columnarInsert(ctx context.Context, tableName string, rows [][]interface{}, columnNames []string) error {
+	var batch, err = d.native.PrepareBatch(ctx, fmt.Sprintf("INSERT INTO %s(%s)", d.table(tableName), strings.Join(columnNames, ", ")))
+	if err != nil {
+		return fmt.Errorf("prepare batch failed: %w", err)
+	}
+
+	for j := range columnNames {
+		var column = batch.Column(j)
+		for _, row := range rows {
+			if err = column.AppendRow(row[j]); err != nil {
+				_ = batch.Abort()
+				return fmt.Errorf("append to column %s failed: %w", columnNames[j], err)
+			}
+		}
+	}
+
+	if err = batch.Send(); err != nil {
+		return fmt.Errorf("send batch failed: %w", err)
+	}
+
 	return nil
 }
 
@@ -161,7 +204,8 @@ func (c *clickhouseConnector) ConnectionPool(cfg db.Config) (db.Database, error)
 	dbo := &sqlDatabase{}
 	var rwc *sql.DB
 
-	if rwc, err = sql.Open("clickhouse", cfg.ConnString); err != nil {
+	var dia = &clickHouseDialect{}
+	if rwc, err = openDB("clickhouse", cfg.ConnString, dia, cfg); err != nil {
 		return nil, fmt.Errorf("db: cannot connect to clickhouse db at %v, err: %v", sanitizeConn(cfg.ConnString), err)
 	}
 
@@ -182,10 +226,29 @@ func (c *clickhouseConnector) ConnectionPool(cfg db.Config) (db.Database, error)
 		rwc.SetConnMaxLifetime(maxConnLifetime)
 	}
 
-	dbo.dialect = &clickHouseDialect{}
-	dbo.queryLogger = cfg.QueryLogger
+	if cfg.ClickHouseColumnar {
+		var opts *clickhouse.Options
+		if opts, err = clickhouse.ParseDSN(cfg.ConnString); err != nil {
+			return nil, fmt.Errorf("db: cannot parse clickhouse dsn %v, err: %v", sanitizeConn(cfg.ConnString), err)
+		}
 
-	return dbo, nil
+		if dia.native, err = clickhouse.Open(opts); err != nil {
+			return nil, fmt.Errorf("db: cannot open native connection to clickhouse db at %v, err: %v", sanitizeConn(cfg.ConnString), err)
+		}
+	}
+
+	dbo.dialect = dia
+	dbo.queryLogger = cfg.QueryLogger
+	dbo.queryRecorder = cfg.QueryRecorder
+	dbo.queryTimeout = cfg.QueryTimeout
+	dbo.dryRun = cfg.DryRun
+	dbo.deadlockRetries = cfg.DeadlockRetries
+	dbo.maxRetries = cfg.MaxRetries
+	dbo.retryBaseDelay = cfg.RetryBaseDelay
+	dbo.retryLogger = cfg.RetryLogger
+	dbo.tracer = newTracer(cfg.TracerProvider)
+
+	return withReadReplica(cfg, dbo)
 }
 
 func (c *clickhouseConnector) DialectName(scheme string) (db.DialectName, error) {
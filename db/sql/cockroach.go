@@ -0,0 +1,69 @@
+package sql
+
+import (
+	"database/sql"
+	"errors"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/acronis/perfkit/db"
+)
+
+func init() {
+	if err := db.Register("cockroachdb", &crdbConnector{}); err != nil {
+		panic(err)
+	}
+}
+
+// crdbConnector connects to CockroachDB by the PostgreSQL driver, the dialect is detected by the server version
+// so the postgres:// connection strings pointing to CockroachDB work the same way
+type crdbConnector struct{}
+
+func (c *crdbConnector) ConnectionPool(cfg db.Config) (db.Database, error) {
+	cfg.ConnString = "postgres" + strings.TrimPrefix(cfg.ConnString, "cockroachdb")
+
+	return (&pgConnector{}).ConnectionPool(cfg)
+}
+
+func (c *crdbConnector) DialectName(scheme string) (db.DialectName, error) {
+	return db.COCKROACHDB, nil
+}
+
+// isCockroachDB returns true if the PostgreSQL wire protocol server is CockroachDB
+func isCockroachDB(rwc *sql.DB) (bool, error) {
+	var version string
+	if err := rwc.QueryRow("SELECT version()").Scan(&version); err != nil {
+		return false, err
+	}
+
+	return strings.Contains(version, "CockroachDB"), nil
+}
+
+// isSerializationFailure returns true if the transaction is aborted by SQLSTATE 40001 and has to be retried by the client
+func isSerializationFailure(err error) bool {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return pqErr.Code == "40001" // serialization_failure
+	}
+	return false
+}
+
+// serializationBackoffBase and serializationBackoffMax are the bounds of the exponential backoff of the serialization failure retries
+const (
+	serializationBackoffBase = 5 * time.Millisecond
+	serializationBackoffMax  = time.Second
+)
+
+// serializationBackoff returns the delay before the retry of the transaction aborted by the serialization failure,
+// the delay is doubled on every attempt and randomized by half not to make the conflicting transactions retry in lockstep
+func serializationBackoff(attempt int) time.Duration {
+	var delay = serializationBackoffMax
+	if attempt < 8 {
+		delay = min(serializationBackoffBase<<attempt, serializationBackoffMax)
+	}
+
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1)) //nolint:gosec
+}
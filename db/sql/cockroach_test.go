@@ -0,0 +1,86 @@
+package sql
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/lib/pq"
+
+	"github.com/acronis/perfkit/db"
+)
+
+func TestCockroachDialectName(t *testing.T) {
+	if name, err := db.GetDialectName("cockroachdb://root@localhost:26257/defaultdb"); err != nil || name != db.COCKROACHDB {
+		t.Errorf("GetDialectName() = %v, %v, expected %v", name, err, db.COCKROACHDB)
+	}
+
+	if name := (&pgDialect{cockroach: true}).name(); name != db.COCKROACHDB {
+		t.Errorf("name() = %v, expected %v", name, db.COCKROACHDB)
+	}
+}
+
+func TestCockroachSerializationFailureRetries(t *testing.T) {
+	var serializationFailure = fmt.Errorf("commit failed: %w", &pq.Error{Code: "40001"})
+
+	if (&pgDialect{}).isRetriable(serializationFailure) {
+		t.Errorf("PostgreSQL serialization failure is retriable")
+	}
+
+	if !(&pgDialect{cockroach: true}).isRetriable(serializationFailure) {
+		t.Errorf("CockroachDB serialization failure is not retriable")
+	}
+
+	var tests = []struct {
+		s    *esSession
+		err  error
+		want int
+	}{
+		{&esSession{maxRetries: 3, deadlockRetries: 5}, serializationFailure, 4},
+		{&esSession{maxRetries: 3, deadlockRetries: 5}, &pq.Error{Code: "40P01"}, 4},
+		{&esSession{maxRetries: 3}, &mysql.MySQLError{Number: 0x4bd}, 4},
+		{&esSession{deadlockRetries: 5}, &pq.Error{Code: "40P01"}, 6},
+		{&esSession{}, &pq.Error{Code: "40P01"}, 10},
+	}
+	for _, tt := range tests {
+		if n := tt.s.maxAttempts(tt.err); n != tt.want {
+			t.Errorf("maxAttempts(%v) = %d, expected %d", tt.err, n, tt.want)
+		}
+	}
+
+	for attempt := 0; attempt < 20; attempt++ {
+		var delay = serializationBackoff(attempt)
+		var upper = min(serializationBackoffBase<<min(attempt, 8), serializationBackoffMax)
+		if delay < upper/2 || delay > upper {
+			t.Errorf("serializationBackoff(%d) = %s, expected within [%s, %s]", attempt, delay, upper/2, upper)
+		}
+	}
+}
+
+func TestTransactionRetryBackoff(t *testing.T) {
+	var serializationFailure = &pq.Error{Code: "40001"}
+	if !(&pgDialect{retrySerialization: true}).isRetriable(serializationFailure) {
+		t.Errorf("PostgreSQL serialization failure is not retriable with MaxRetries set")
+	}
+
+	if (&pgDialect{retrySerialization: true}).isRetriable(&pq.Error{Code: "23505"}) {
+		t.Errorf("PostgreSQL unique violation is retriable")
+	}
+
+	var tests = []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{0, 10 * time.Millisecond},
+		{1, 20 * time.Millisecond},
+		{5, 320 * time.Millisecond},
+		{12, retryBackoffMax},
+		{100, retryBackoffMax},
+	}
+	for _, tt := range tests {
+		if delay := retryBackoff(10*time.Millisecond, tt.attempt); delay != tt.want {
+			t.Errorf("retryBackoff(10ms, %d) = %s, expected %s", tt.attempt, delay, tt.want)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+
+	"github.com/acronis/perfkit/db"
+)
+
+// openDB opens the connection pool of the registered driver, every new connection of the pool is passed to cfg.ConnectHook if set
+func openDB(driverName string, dsn string, dia dialect, cfg db.Config) (*sql.DB, error) {
+	var rwc, err = sql.Open(driverName, dsn)
+	if err != nil || cfg.ConnectHook == nil {
+		return rwc, err
+	}
+
+	var drv = rwc.Driver()
+	if err = rwc.Close(); err != nil {
+		return nil, err
+	}
+
+	var base driver.Connector = &dsnConnector{dsn: dsn, driver: drv}
+	if dc, ok := drv.(driver.DriverContext); ok {
+		if base, err = dc.OpenConnector(dsn); err != nil {
+			return nil, err
+		}
+	}
+
+	return sql.OpenDB(&hookConnector{base: base, dialect: dia, cfg: cfg}), nil
+}
+
+// dsnConnector is the driver.Connector of the drivers not implementing driver.DriverContext
+type dsnConnector struct {
+	dsn    string
+	driver driver.Driver
+}
+
+func (c *dsnConnector) Connect(context.Context) (driver.Conn, error) {
+	return c.driver.Open(c.dsn)
+}
+
+func (c *dsnConnector) Driver() driver.Driver {
+	return c.driver
+}
+
+// hookConnector runs the db.Config ConnectHook on every connection established by the base connector
+type hookConnector struct {
+	base    driver.Connector
+	dialect dialect
+	cfg     db.Config
+}
+
+func (c *hookConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	var conn, err = c.base.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = c.runHook(conn); err != nil {
+		conn.Close() //nolint:errcheck
+		return nil, err
+	}
+
+	return conn, nil
+}
+
+func (c *hookConnector) Driver() driver.Driver {
+	return c.base.Driver()
+}
+
+// runHook passes the hook a database bound to the given connection only, the connection is kept open afterwards
+func (c *hookConnector) runHook(conn driver.Conn) error {
+	var rwc = sql.OpenDB(&singleConnConnector{conn: conn, driver: c.base.Driver()})
+	rwc.SetMaxOpenConns(1)
+	defer rwc.Close() //nolint:errcheck
+
+	var d = &sqlDatabase{
+		rw:            &sqlQuerier{rwc},
+		t:             &sqlQuerier{rwc},
+		dialect:       c.dialect,
+		queryLogger:   c.cfg.QueryLogger,
+		queryRecorder: c.cfg.QueryRecorder,
+		queryTimeout:  c.cfg.QueryTimeout,
+	}
+
+	return c.cfg.ConnectHook(d)
+}
+
+// singleConnConnector hands out the same connection once, so the pool of the hook can't open the other ones
+type singleConnConnector struct {
+	conn   driver.Conn
+	driver driver.Driver
+	used   bool
+}
+
+func (c *singleConnConnector) Connect(context.Context) (driver.Conn, error) {
+	if c.used {
+		return nil, errors.New("db: the connect hook connection is already closed")
+	}
+	c.used = true
+
+	return nonClosingConn{c.conn}, nil
+}
+
+func (c *singleConnConnector) Driver() driver.Driver {
+	return c.driver
+}
+
+// nonClosingConn keeps the connection open when the pool of the hook is closed
+type nonClosingConn struct {
+	driver.Conn
+}
+
+func (nonClosingConn) Close() error {
+	return nil
+}
@@ -0,0 +1,51 @@
+package sql
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/acronis/perfkit/db"
+)
+
+func TestConnectHook(t *testing.T) {
+	var calls int
+	var hook = func(d db.Database) error {
+		calls++
+		// TEMP tables are visible to the connection they are created by only
+		_, err := d.Session(d.Context(context.Background())).Exec("CREATE TEMP TABLE hooked (id int)")
+		return err
+	}
+
+	var d, err = db.Open(db.Config{ConnString: sqliteConnString, MaxOpenConns: 2, ConnectHook: hook})
+	if err != nil {
+		t.Fatalf("db.Open() error: %v", err)
+	}
+	defer d.Close() //nolint:errcheck
+
+	var s = d.Session(d.Context(context.Background()))
+	if _, err = s.Exec("INSERT INTO hooked (id) VALUES (1)"); err != nil {
+		t.Fatalf("Exec() error: %v", err)
+	}
+
+	if err = s.Transact(func(tx db.DatabaseAccessor) error {
+		var id int
+		return tx.QueryRow("SELECT id FROM hooked").Scan(&id)
+	}); err != nil {
+		t.Fatalf("Transact() error: %v", err)
+	}
+
+	if calls == 0 {
+		t.Errorf("connect hook was not called")
+	}
+}
+
+func TestConnectHookError(t *testing.T) {
+	var hook = func(db.Database) error {
+		return errors.New("hook failed")
+	}
+
+	if _, err := db.Open(db.Config{ConnString: sqliteConnString, MaxOpenConns: 1, ConnectHook: hook}); err == nil {
+		t.Errorf("db.Open() succeeded with failing connect hook")
+	}
+}
@@ -146,8 +146,16 @@ func (c *dbrConnector) ConnectionPool(cfg db.Config) (db.Database, error) {
 
 	dbo.dialect = dia
 	dbo.queryLogger = cfg.QueryLogger
-
-	return dbo, nil
+	dbo.queryRecorder = cfg.QueryRecorder
+	dbo.queryTimeout = cfg.QueryTimeout
+	dbo.dryRun = cfg.DryRun
+	dbo.deadlockRetries = cfg.DeadlockRetries
+	dbo.maxRetries = cfg.MaxRetries
+	dbo.retryBaseDelay = cfg.RetryBaseDelay
+	dbo.retryLogger = cfg.RetryLogger
+	dbo.tracer = newTracer(cfg.TracerProvider)
+
+	return withReadReplica(cfg, dbo)
 }
 
 func (c *dbrConnector) DialectName(scheme string) (db.DialectName, error) {
@@ -220,8 +228,8 @@ func (d *dbrQuerier) queryContext(ctx context.Context, query string, args ...int
 func (d *dbrQuerier) prepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
 	return d.be.PrepareContext(ctx, query)
 }
-func (d *dbrQuerier) begin(ctx context.Context) (transaction, error) {
-	be, err := d.be.BeginTx(ctx, nil)
+func (d *dbrQuerier) begin(ctx context.Context, opts *sql.TxOptions) (transaction, error) {
+	be, err := d.be.BeginTx(ctx, opts)
 	if err != nil {
 		return nil, err
 	}
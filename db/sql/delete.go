@@ -0,0 +1,64 @@
+package sql
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/acronis/perfkit/db"
+)
+
+func (b selectBuilder) sqlDelete(d dialect, c *db.DeleteCtrl) (string, bool, error) {
+	var where, whereArgs, empty, err = b.sqlConditions(d, c.OptimizeConditions, c.Where)
+	if err != nil {
+		return "", false, err
+	}
+
+	if empty {
+		return "", true, nil
+	}
+
+	var qry = fmt.Sprintf("DELETE FROM %s %s", d.table(b.tableName), where)
+
+	return sqlf(d, qry, whereArgs...), false, nil
+}
+
+// Delete deletes rows matching the conditions and returns the number of deleted rows,
+// the query is only logged in the dry-run mode
+func (g *sqlGateway) Delete(tableName string, dc *db.DeleteCtrl) (int64, error) {
+	var queryBuilder, ok = tableQueryBuilders[tableName]
+	if !ok {
+		return 0, fmt.Errorf("table %s is not supported", tableName)
+	}
+
+	var query, empty, err = queryBuilder.sqlDelete(g.dialect, dc)
+	if err != nil {
+		return 0, err
+	}
+
+	if empty {
+		return 0, nil
+	}
+
+	if g.dryRun {
+		if g.queryLogger != nil {
+			g.queryLogger.Log("dry-run: %s", query)
+		}
+		return 0, nil
+	}
+
+	var ctx, cancel = g.queryCtx()
+	defer cancel()
+
+	var result sql.Result
+	if result, err = g.rw.execContext(ctx, query); err != nil {
+		return 0, queryErr(ctx, err)
+	}
+
+	var deleted int64
+	if deleted, err = result.RowsAffected(); err != nil {
+		// e.g. Cassandra doesn't report the affected rows
+		return -1, nil
+	}
+
+	return deleted, nil
+}
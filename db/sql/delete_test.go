@@ -0,0 +1,24 @@
+package sql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/acronis/perfkit/db"
+)
+
+func TestSqlDelete(t *testing.T) {
+	var b = selectBuilder{
+		tableName: "perf_table",
+		queryable: map[string]filterFunction{"id": idCond()},
+	}
+
+	var qry, empty, err = b.sqlDelete(&pgDialect{}, &db.DeleteCtrl{Where: map[string][]string{"id": {"lt(10)"}}})
+	require.NoError(t, err)
+	require.False(t, empty)
+	require.Equal(t, "DELETE FROM perf_table WHERE perf_table.id < 10", qry)
+
+	_, _, err = b.sqlDelete(&pgDialect{}, &db.DeleteCtrl{Where: map[string][]string{"name": {"x"}}})
+	require.EqualError(t, err, "bad condition field 'name'")
+}
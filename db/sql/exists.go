@@ -0,0 +1,71 @@
+package sql
+
+import (
+	"fmt"
+
+	"github.com/acronis/perfkit/db"
+)
+
+// sqlExists builds the query returning whether a row matching the conditions exists,
+// the result is boolean for the EXISTS based dialects and the rows count for Cassandra and ClickHouse
+func (b selectBuilder) sqlExists(d dialect, c *db.SelectCtrl) (string, bool, error) {
+	var where, args, empty, err = b.sqlConditions(d, c.OptimizeConditions, c.Where)
+	if err != nil {
+		return "", false, err
+	}
+
+	if empty {
+		return "", true, nil
+	}
+
+	var qry string
+	switch d.name() {
+	case db.CASSANDRA, db.CLICKHOUSE:
+		qry = fmt.Sprintf("SELECT COUNT(*) FROM %s %s LIMIT 1", d.table(b.tableName), where)
+	case db.MSSQL:
+		qry = fmt.Sprintf("SELECT CASE WHEN EXISTS (SELECT 1 FROM %s %s) THEN 1 ELSE 0 END", d.table(b.tableName), where)
+	default:
+		qry = fmt.Sprintf("SELECT EXISTS (SELECT 1 FROM %s %s LIMIT 1)", d.table(b.tableName), where)
+	}
+
+	return sqlf(d, qry, args...), false, nil
+}
+
+// ExistsRow returns true if at least one row of the table matches the SelectCtrl conditions
+func (g *sqlGateway) ExistsRow(tableName string, sc *db.SelectCtrl) (bool, error) {
+	var queryBuilder, ok = tableQueryBuilders[tableName]
+	if !ok {
+		return false, fmt.Errorf("table %s is not supported", tableName)
+	}
+
+	var query, empty, err = queryBuilder.sqlExists(g.dialect, sc)
+	if err != nil {
+		return false, err
+	}
+
+	if empty {
+		return false, nil
+	}
+
+	var ctx, cancel = g.queryCtx()
+	defer cancel()
+
+	var row = g.rw.queryRowContext(ctx, query)
+
+	switch g.dialect.name() {
+	case db.CASSANDRA, db.CLICKHOUSE:
+		var count int64
+		if err = row.Scan(&count); err != nil {
+			return false, queryErr(ctx, err)
+		}
+
+		return count > 0, nil
+	default:
+		var exists bool
+		if err = row.Scan(&exists); err != nil {
+			return false, queryErr(ctx, err)
+		}
+
+		return exists, nil
+	}
+}
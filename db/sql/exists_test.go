@@ -0,0 +1,35 @@
+package sql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/acronis/perfkit/db"
+)
+
+func TestSqlExists(t *testing.T) {
+	var b = selectBuilder{
+		tableName: "perf_table",
+		queryable: map[string]filterFunction{"id": idCond()},
+	}
+
+	var c = &db.SelectCtrl{
+		Fields: []string{"id"},
+		Where:  map[string][]string{"id": {"1"}},
+		Order:  []string{"desc(id)"},
+	}
+
+	var qry, empty, err = b.sqlExists(&pgDialect{}, c)
+	require.NoError(t, err)
+	require.False(t, empty)
+	require.Equal(t, "SELECT EXISTS (SELECT 1 FROM perf_table WHERE perf_table.id = 1 LIMIT 1)", qry)
+
+	qry, _, err = b.sqlExists(&msDialect{}, c)
+	require.NoError(t, err)
+	require.Equal(t, "SELECT CASE WHEN EXISTS (SELECT 1 FROM perf_table WHERE perf_table.id = 1) THEN 1 ELSE 0 END", qry)
+
+	qry, _, err = b.sqlExists(&cassandraDialect{}, c)
+	require.NoError(t, err)
+	require.Equal(t, "SELECT COUNT(*) FROM perf_table WHERE perf_table.id = 1 LIMIT 1", qry)
+}
@@ -7,6 +7,7 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/acronis/perfkit/db"
 )
@@ -17,7 +18,7 @@ func getVersion(q querier, d dialect) (db.DialectName, string, error) {
 	var query string
 
 	switch d.name() {
-	case db.POSTGRES:
+	case db.POSTGRES, db.COCKROACHDB:
 		query = "SELECT version();"
 	case db.MYSQL, db.CLICKHOUSE:
 		query = "SELECT VERSION();"
@@ -207,7 +208,7 @@ func getInfo(q querier, d dialect, version string) ([]string, *db.Info, error) {
 		if err = rows.Err(); err != nil {
 			return nil, nil, fmt.Errorf("error during row iteration: %s", err)
 		}
-	case db.SQLITE, db.CLICKHOUSE:
+	case db.SQLITE, db.CLICKHOUSE, db.COCKROACHDB:
 		//
 	default:
 		return nil, nil, fmt.Errorf("unsupported driver: %s", d.name())
@@ -233,7 +234,7 @@ func getTablesSchemaInfo(q querier, d dialect, tableNames []string) ([]string, e
 
 		var listColumnsQuery string
 		switch d.name() {
-		case db.POSTGRES, db.MYSQL, db.MSSQL:
+		case db.POSTGRES, db.COCKROACHDB, db.MYSQL, db.MSSQL:
 			listColumnsQuery = fmt.Sprintf("SELECT column_name, data_type FROM information_schema.columns WHERE table_name = '%s'", table)
 		case db.CLICKHOUSE:
 			listColumnsQuery = fmt.Sprintf("SELECT name AS column_name, type AS data_type FROM system.columns WHERE table = '%s'", table)
@@ -282,7 +283,7 @@ func getTablesSchemaInfo(q querier, d dialect, tableNames []string) ([]string, e
 
 		var listIndexesQuery string
 		switch d.name() {
-		case db.POSTGRES:
+		case db.POSTGRES, db.COCKROACHDB:
 			listIndexesQuery = fmt.Sprintf("SELECT indexname, indexdef FROM pg_indexes WHERE tablename = '%s'", table)
 		case db.MYSQL:
 			listIndexesQuery = fmt.Sprintf("SELECT TABLE_NAME, NON_UNIQUE, INDEX_NAME, SEQ_IN_INDEX, COLUMN_NAME, COLLATION, "+
@@ -312,7 +313,7 @@ func getTablesSchemaInfo(q querier, d dialect, tableNames []string) ([]string, e
 		for indexes.Next() {
 			var indexName, indexDef string
 			switch d.name() {
-			case db.POSTGRES:
+			case db.POSTGRES, db.COCKROACHDB:
 				if err = indexes.Scan(&indexName, &indexDef); err != nil {
 					return nil, fmt.Errorf("error: %s\nquery: %s", err, listIndexesQuery)
 				}
@@ -465,3 +466,185 @@ func getTablesVolumeInfo(q querier, d dialect, tableNames []string) ([]string, e
 
 	return ret, nil
 }
+
+// getIndexUsageStats returns the usage statistics of the indexes of a given table
+func getIndexUsageStats(q querier, d dialect, tableName string) ([]db.IndexUsageStat, error) {
+	var query string
+	var args = []interface{}{tableName}
+
+	switch d.name() {
+	case db.POSTGRES:
+		query = `SELECT i.indexrelname, i.relname, COALESCE(t.seq_scan, 0), COALESCE(i.idx_scan, 0), COALESCE(i.idx_tup_read, 0), COALESCE(i.idx_tup_fetch, 0)
+			FROM pg_stat_user_indexes i
+				JOIN pg_stat_user_tables t ON i.relid = t.relid
+			WHERE i.relname = '%s' AND i.schemaname = COALESCE(NULLIF('%s', ''), current_schema())
+			ORDER BY i.indexrelname;`
+		args = append(args, d.schema())
+	case db.MYSQL:
+		query = `SELECT s.INDEX_NAME, s.TABLE_NAME,
+				(SELECT COALESCE(SUM(f.COUNT_STAR), 0)
+					FROM performance_schema.table_io_waits_summary_by_index_usage f
+					WHERE f.OBJECT_SCHEMA = s.TABLE_SCHEMA AND f.OBJECT_NAME = s.TABLE_NAME AND f.INDEX_NAME IS NULL),
+				COALESCE(u.COUNT_STAR, 0), COALESCE(u.COUNT_READ, 0), COALESCE(u.COUNT_FETCH, 0)
+			FROM (SELECT DISTINCT TABLE_SCHEMA, TABLE_NAME, INDEX_NAME
+					FROM information_schema.STATISTICS
+					WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = '%s') s
+				LEFT JOIN performance_schema.table_io_waits_summary_by_index_usage u
+					ON u.OBJECT_SCHEMA = s.TABLE_SCHEMA AND u.OBJECT_NAME = s.TABLE_NAME AND u.INDEX_NAME = s.INDEX_NAME
+			ORDER BY s.INDEX_NAME;`
+	default:
+		return nil, nil
+	}
+
+	var rows, err = q.queryContext(context.Background(), fmt.Sprintf(query, args...))
+	if err != nil {
+		return nil, fmt.Errorf("error getting index usage stats: %w", err)
+	}
+	defer rows.Close()
+
+	var stats []db.IndexUsageStat
+	for rows.Next() {
+		var stat db.IndexUsageStat
+		if err = rows.Scan(&stat.IndexName, &stat.TableName, &stat.SeqScans, &stat.IdxScans, &stat.IdxTupRead, &stat.IdxTupFetch); err != nil {
+			return nil, fmt.Errorf("error scanning index usage stats: %w", err)
+		}
+		stats = append(stats, stat)
+	}
+
+	return stats, rows.Err()
+}
+
+// getLockWaitStats returns the sessions currently waiting for the locks held by the other sessions
+func getLockWaitStats(q querier, d dialect) ([]db.LockWaitStat, error) {
+	var query string
+
+	switch d.name() {
+	case db.POSTGRES:
+		query = `SELECT w.pid, COALESCE(w.query, ''), COALESCE(bl.pid, 0), COALESCE(bl.query, ''),
+				COALESCE(EXTRACT(EPOCH FROM clock_timestamp() - w.query_start), 0)
+			FROM pg_stat_activity w
+				LEFT JOIN LATERAL unnest(pg_blocking_pids(w.pid)) AS bp(pid) ON true
+				LEFT JOIN pg_stat_activity bl ON bl.pid = bp.pid
+			WHERE w.wait_event_type = 'Lock'
+			ORDER BY w.pid;`
+	case db.MYSQL:
+		// TIMER_WAIT is measured in picoseconds, data_lock_waits provides the blocking thread for InnoDB row locks only
+		query = `SELECT COALESCE(t.PROCESSLIST_ID, 0), COALESCE(t.PROCESSLIST_INFO, ''), COALESCE(bt.PROCESSLIST_ID, 0), COALESCE(bt.PROCESSLIST_INFO, ''),
+				COALESCE(w.TIMER_WAIT, 0) / 1000000000000
+			FROM performance_schema.events_waits_current w
+				JOIN performance_schema.threads t ON t.THREAD_ID = w.THREAD_ID
+				LEFT JOIN performance_schema.data_lock_waits lw ON lw.REQUESTING_THREAD_ID = w.THREAD_ID
+				LEFT JOIN performance_schema.threads bt ON bt.THREAD_ID = lw.BLOCKING_THREAD_ID
+			WHERE w.EVENT_NAME LIKE 'wait/lock/%' AND w.END_EVENT_ID IS NULL
+			ORDER BY t.PROCESSLIST_ID;`
+	default:
+		return nil, nil
+	}
+
+	var rows, err = q.queryContext(context.Background(), query)
+	if err != nil {
+		return nil, fmt.Errorf("error getting lock wait stats: %w", err)
+	}
+	defer rows.Close()
+
+	var stats []db.LockWaitStat
+	for rows.Next() {
+		var stat db.LockWaitStat
+		var waitSeconds float64
+		if err = rows.Scan(&stat.PID, &stat.WaitingQuery, &stat.BlockingPID, &stat.BlockingQuery, &waitSeconds); err != nil {
+			return nil, fmt.Errorf("error scanning lock wait stats: %w", err)
+		}
+		stat.WaitDuration = time.Duration(waitSeconds * float64(time.Second))
+		stats = append(stats, stat)
+	}
+
+	return stats, rows.Err()
+}
+
+// getActiveQueries returns the queries of the other sessions running longer than db.ActiveQueryMinDuration
+func getActiveQueries(q querier, d dialect) ([]db.ActiveQuery, error) {
+	var query string
+
+	switch d.name() {
+	case db.POSTGRES:
+		query = `SELECT pid, COALESCE(usename, ''), COALESCE(state, ''), COALESCE(query, ''),
+				EXTRACT(EPOCH FROM clock_timestamp() - query_start), COALESCE(wait_event, '')
+			FROM pg_stat_activity
+			WHERE state <> 'idle' AND pid <> pg_backend_pid() AND query_start IS NOT NULL
+				AND EXTRACT(EPOCH FROM clock_timestamp() - query_start) > %v
+			ORDER BY query_start;`
+	case db.MYSQL:
+		// SHOW PROCESSLIST reports the time in seconds only, so the current statements are taken from performance_schema,
+		// TIMER_WAIT is measured in picoseconds and is the elapsed time for the statements still running
+		query = `SELECT t.PROCESSLIST_ID, COALESCE(t.PROCESSLIST_USER, ''), COALESCE(t.PROCESSLIST_STATE, ''), COALESCE(s.SQL_TEXT, ''),
+				s.TIMER_WAIT / 1000000000000, COALESCE(w.EVENT_NAME, '')
+			FROM performance_schema.events_statements_current s
+				JOIN performance_schema.threads t ON t.THREAD_ID = s.THREAD_ID
+				LEFT JOIN performance_schema.events_waits_current w ON w.THREAD_ID = s.THREAD_ID AND w.END_EVENT_ID IS NULL
+			WHERE s.END_EVENT_ID IS NULL AND t.PROCESSLIST_ID <> CONNECTION_ID()
+				AND s.TIMER_WAIT / 1000000000000 > %v
+			ORDER BY s.TIMER_WAIT DESC;`
+	case db.MSSQL:
+		query = `SELECT r.session_id, COALESCE(s.login_name, ''), r.status, COALESCE(t.text, ''),
+				r.total_elapsed_time / 1000.0, COALESCE(r.wait_type, '')
+			FROM sys.dm_exec_requests r
+				JOIN sys.dm_exec_sessions s ON s.session_id = r.session_id
+				OUTER APPLY sys.dm_exec_sql_text(r.sql_handle) t
+			WHERE s.is_user_process = 1 AND r.session_id <> @@SPID
+				AND r.total_elapsed_time / 1000.0 > %v
+			ORDER BY r.total_elapsed_time DESC;`
+	default:
+		return nil, nil
+	}
+
+	var rows, err = q.queryContext(context.Background(), fmt.Sprintf(query, db.ActiveQueryMinDuration.Seconds()))
+	if err != nil {
+		return nil, fmt.Errorf("error getting active queries: %w", err)
+	}
+	defer rows.Close()
+
+	var queries []db.ActiveQuery
+	for rows.Next() {
+		var aq db.ActiveQuery
+		var seconds float64
+		if err = rows.Scan(&aq.PID, &aq.User, &aq.State, &aq.Query, &seconds, &aq.WaitEvent); err != nil {
+			return nil, fmt.Errorf("error scanning active queries: %w", err)
+		}
+		aq.Duration = time.Duration(seconds * float64(time.Second))
+		queries = append(queries, aq)
+	}
+
+	return queries, rows.Err()
+}
+
+// getCheckpointStats returns the cumulative checkpoint statistics of the PostgreSQL server, other dialects return empty stats
+func getCheckpointStats(q querier, d dialect) (db.CheckpointStats, error) {
+	if d.name() != db.POSTGRES {
+		return db.CheckpointStats{}, nil
+	}
+
+	// PostgreSQL 17 moved the checkpoint counters from pg_stat_bgwriter to pg_stat_checkpointer
+	var hasCheckpointer bool
+	if err := q.queryRowContext(context.Background(), "SELECT to_regclass('pg_catalog.pg_stat_checkpointer') IS NOT NULL;").Scan(&hasCheckpointer); err != nil {
+		return db.CheckpointStats{}, fmt.Errorf("error getting checkpoint stats: %w", err)
+	}
+
+	var query = `SELECT checkpoints_timed, checkpoints_req, checkpoint_write_time, checkpoint_sync_time, buffers_checkpoint
+			FROM pg_stat_bgwriter;`
+	if hasCheckpointer {
+		query = `SELECT num_timed, num_requested, write_time, sync_time, buffers_written
+			FROM pg_stat_checkpointer;`
+	}
+
+	var stats db.CheckpointStats
+	var writeMs, syncMs float64
+	if err := q.queryRowContext(context.Background(), query).Scan(&stats.CheckpointsTimed, &stats.CheckpointsReq, &writeMs, &syncMs, &stats.BuffersCheckpoint); err != nil {
+		return db.CheckpointStats{}, fmt.Errorf("error getting checkpoint stats: %w", err)
+	}
+
+	// the write and sync times are reported in milliseconds
+	stats.CheckpointWriteTime = time.Duration(writeMs * float64(time.Millisecond))
+	stats.CheckpointSyncTime = time.Duration(syncMs * float64(time.Millisecond))
+
+	return stats, nil
+}
@@ -79,4 +79,36 @@ func (suite *TestingSuite) TestDatabaseInfoProvider() {
 
 		suite.T().Log(strings.Join(volumeInfo, "\n"))
 	}
+
+	var indexStats []db.IndexUsageStat
+	if indexStats, err = d.GetIndexUsageStats("perf_table"); err != nil {
+		suite.T().Error(err)
+		return
+	}
+
+	suite.T().Log(indexStats)
+
+	var lockWaits []db.LockWaitStat
+	if lockWaits, err = d.GetLockWaitStats(); err != nil {
+		suite.T().Error(err)
+		return
+	}
+
+	suite.T().Log(lockWaits)
+
+	var activeQueries []db.ActiveQuery
+	if activeQueries, err = d.GetActiveQueries(); err != nil {
+		suite.T().Error(err)
+		return
+	}
+
+	suite.T().Log(activeQueries)
+
+	var checkpointStats db.CheckpointStats
+	if checkpointStats, err = d.GetCheckpointStats(); err != nil {
+		suite.T().Error(err)
+		return
+	}
+
+	suite.T().Log(checkpointStats)
 }
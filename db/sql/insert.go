@@ -8,11 +8,39 @@ import (
 )
 
 // BulkInsert inserts rows into a table
-func (g *sqlGateway) BulkInsert(tableName string, rows [][]interface{}, columnNames []string) error {
+func (g *sqlGateway) BulkInsert(tableName string, c *db.BulkInsertCtrl) error {
+	var rows, columnNames = c.Rows, c.ColumnNames
 	if len(rows) == 0 {
 		return nil
 	}
 
+	if c.IgnoreConflicts {
+		switch g.dialect.name() {
+		case db.POSTGRES, db.COCKROACHDB, db.SQLITE, db.MYSQL:
+		case db.MSSQL:
+			return g.bulkInsertIgnoringDuplicates(tableName, rows, columnNames)
+		default:
+			return fmt.Errorf("ignoring conflicts is not supported for %s dialect", g.dialect.name())
+		}
+	}
+
+	if ch, ok := g.dialect.(*clickHouseDialect); ok && ch.native != nil {
+		for _, row := range rows {
+			if len(row) != len(columnNames) {
+				return fmt.Errorf("row length doesn't match column names length")
+			}
+		}
+
+		var ctx, cancel = g.queryCtx()
+		defer cancel()
+
+		if err := queryErr(ctx, ch.columnarInsert(ctx, tableName, rows, columnNames)); err != nil {
+			return fmt.Errorf("DB exec failed: %w", err)
+		}
+
+		return nil
+	}
+
 	var values []string
 	for _, row := range rows {
 		if len(row) != len(columnNames) {
@@ -37,17 +65,68 @@ func (g *sqlGateway) BulkInsert(tableName string, rows [][]interface{}, columnNa
 		}
 		query = fmt.Sprintf("BEGIN BATCH\n%s\nAPPLY BATCH;", strings.Join(insertQueries, "\n"))
 	} else {
-		query = fmt.Sprintf("INSERT INTO %s(%s) VALUES %s;",
+		var insert, onConflict = "INSERT", ""
+		if c.IgnoreConflicts {
+			switch g.dialect.name() {
+			case db.MYSQL:
+				insert = "INSERT IGNORE"
+			default:
+				onConflict = " ON CONFLICT DO NOTHING"
+			}
+		}
+
+		query = fmt.Sprintf("%s INTO %s(%s) VALUES %s%s;",
+			insert,
 			g.dialect.table(tableName),
 			strings.Join(columnNames, ", "),
-			strings.Join(values, ", "))
+			strings.Join(values, ", "),
+			onConflict)
 	}
 
-	var _, err = g.rw.execContext(g.ctx, query)
+	var ctx, cancel = g.queryCtx()
+	defer cancel()
+
+	var _, err = g.rw.execContext(ctx, query)
 
-	if err != nil {
+	if err = uniqueViolationErr(g.dialect, queryErr(ctx, err)); err != nil {
 		return fmt.Errorf("DB exec failed: %w", err)
 	}
 
 	return nil
 }
+
+// bulkInsertIgnoringDuplicates inserts rows one by one skipping the duplicates,
+// as MSSQL has no ON CONFLICT clause and MERGE requires the unique key to be known
+func (g *sqlGateway) bulkInsertIgnoringDuplicates(tableName string, rows [][]interface{}, columnNames []string) error {
+	var ms, ok = g.dialect.(*msDialect)
+	if !ok {
+		return fmt.Errorf("ignoring conflicts by the application is not supported for %s dialect", g.dialect.name())
+	}
+
+	for _, row := range rows {
+		if len(row) != len(columnNames) {
+			return fmt.Errorf("row length doesn't match column names length")
+		}
+
+		var valuesInRow []string
+		for _, col := range row {
+			valuesInRow = append(valuesInRow, sqlf(g.dialect, "%v", col))
+		}
+
+		var query = fmt.Sprintf("INSERT INTO %s(%s) VALUES (%s);",
+			g.dialect.table(tableName),
+			strings.Join(columnNames, ", "),
+			strings.Join(valuesInRow, ", "))
+
+		var ctx, cancel = g.queryCtx()
+		var _, err = g.rw.execContext(ctx, query)
+		err = queryErr(ctx, err)
+		cancel()
+
+		if err != nil && !ms.isUniqueViolation(err) {
+			return fmt.Errorf("DB exec failed: %w", err)
+		}
+	}
+
+	return nil
+}
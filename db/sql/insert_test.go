@@ -1,15 +1,20 @@
 package sql
 
-import "github.com/acronis/perfkit/db"
+import (
+	"errors"
+
+	"github.com/acronis/perfkit/db"
+)
 
 func (suite *TestingSuite) TestInsert() {
 	d, s, c := suite.makeTestSession()
 	defer logDbTime(suite.T(), c)
 	defer cleanup(suite.T(), d)
 
-	if err := s.BulkInsert("perf_table", [][]interface{}{
-		{2, 2, "test"},
-	}, []string{"origin", "type", "name"}); err != nil {
+	if err := s.BulkInsert("perf_table", &db.BulkInsertCtrl{
+		Rows:        [][]interface{}{{2, 2, "test"}},
+		ColumnNames: []string{"origin", "type", "name"},
+	}); err != nil {
 		suite.T().Error(err)
 		return
 	}
@@ -142,3 +147,77 @@ func (suite *TestingSuite) TestSelect() {
 		}
 	}
 }
+
+func (suite *TestingSuite) TestInsertIgnoreConflicts() {
+	d, s, c := suite.makeTestSession()
+	defer logDbTime(suite.T(), c)
+	defer cleanup(suite.T(), d)
+
+	switch d.DialectName() {
+	case db.CASSANDRA, db.CLICKHOUSE:
+		suite.T().Skip("no unique constraints")
+	}
+
+	var columns = []string{"origin", "type", "name"}
+	if err := s.BulkInsert("perf_table", &db.BulkInsertCtrl{
+		Rows:        [][]interface{}{{2, 2, "test"}},
+		ColumnNames: columns,
+	}); err != nil {
+		suite.T().Error(err)
+		return
+	}
+
+	if err := s.BulkInsert("perf_table", &db.BulkInsertCtrl{
+		Rows:            [][]interface{}{{2, 2, "test"}, {3, 4, "perf"}},
+		ColumnNames:     columns,
+		IgnoreConflicts: true,
+	}); err != nil {
+		suite.T().Error(err)
+		return
+	}
+
+	var rowNum int64
+	if err := s.QueryRow("SELECT COUNT(0) FROM perf_table").Scan(&rowNum); err != nil {
+		suite.T().Error(err)
+		return
+	}
+
+	if rowNum != 2 {
+		suite.T().Error("unexpected number of rows", rowNum)
+	}
+}
+
+func (suite *TestingSuite) TestInsertUniqueViolation() {
+	d, s, c := suite.makeTestSession()
+	defer logDbTime(suite.T(), c)
+	defer cleanup(suite.T(), d)
+
+	switch d.DialectName() {
+	case db.CASSANDRA, db.CLICKHOUSE:
+		if err := d.CreateUniqueIndex("perf_unique_index", "perf_table", []string{"name"}); err == nil {
+			suite.T().Error("unique index must not be supported")
+		}
+		return
+	}
+
+	if err := d.CreateUniqueIndex("perf_unique_index", "perf_table", []string{"name"}); err != nil {
+		suite.T().Error("create unique index", err)
+		return
+	}
+
+	var columns = []string{"origin", "type", "name"}
+	if err := s.BulkInsert("perf_table", &db.BulkInsertCtrl{
+		Rows:        [][]interface{}{{2, 2, "test"}},
+		ColumnNames: columns,
+	}); err != nil {
+		suite.T().Error(err)
+		return
+	}
+
+	if err := s.BulkInsert("perf_table", &db.BulkInsertCtrl{
+		Rows:        [][]interface{}{{3, 4, "test"}},
+		ColumnNames: columns,
+	}); !errors.Is(err, db.ErrUniqueViolation) {
+		suite.T().Errorf("expected unique violation error, got %v", err)
+	}
+}
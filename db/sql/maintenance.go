@@ -30,8 +30,11 @@ func applyMigrations(q querier, d dialect, tableName, tableMigrationSQL string)
 	case db.MYSQL:
 		// Percona (or MySQL?) fails to create all the steps within single transaction
 		migrationQueries = strings.Split(tableMigrationSQL, ";")
-	case db.CASSANDRA:
+	case db.CASSANDRA, db.CLICKHOUSE:
 		migrationQueries = strings.Split(tableMigrationSQL, ";")
+	case db.COCKROACHDB:
+		// CockroachDB makes SERIAL columns of the random unique_rowid() values by default, the tests expect the PostgreSQL-like sequential ids
+		migrationQueries = []string{"SET serial_normalization = 'sql_sequence'", tableMigrationSQL}
 	default:
 		migrationQueries = []string{tableMigrationSQL}
 	}
@@ -78,7 +81,7 @@ func tableExists(q querier, d dialect, name string) (bool, error) {
 			  AND table_schema = DATABASE();
 			`
 
-	case db.POSTGRES:
+	case db.POSTGRES, db.COCKROACHDB:
 		if name == "information_schema" {
 			return true, nil
 		}
@@ -156,7 +159,13 @@ func constructSQLDDLQuery(d dialect, tableName string, tableDefinition *db.Table
 
 	var query = fmt.Sprintf("CREATE TABLE %v (", d.table(tableName))
 	for i, row := range tableDefinition.TableRows {
-		query += fmt.Sprintf("%v %v", row.Name, d.getType(row.Type))
+		var columnType = d.getType(row.Type)
+		if row.Type == db.DataTypeVectorFloat32 && row.Dims > 0 && d.name() == db.POSTGRES {
+			// pgvector column of the arbitrary dimensions
+			columnType = fmt.Sprintf("vector(%d)", row.Dims)
+		}
+
+		query += fmt.Sprintf("%v %v", row.Name, columnType)
 		if row.NotNull {
 			if d.name() != db.CASSANDRA {
 				query += " NOT NULL"
@@ -233,7 +242,7 @@ func dropTable(q querier, d dialect, name string, useTruncate bool) error {
 			return fmt.Errorf("error checking table existence: %v", err)
 		} else if exists {
 			switch d.name() {
-			case db.POSTGRES:
+			case db.POSTGRES, db.COCKROACHDB:
 				query = "TRUNCATE TABLE %v CASCADE"
 			default:
 				query = "TRUNCATE TABLE %v"
@@ -275,7 +284,7 @@ func indexExists(q querier, d dialect, indexName, tableName string) (bool, error
 			  AND table_schema = DATABASE();
 			`
 
-	case db.POSTGRES:
+	case db.POSTGRES, db.COCKROACHDB:
 		qry = `
 			SELECT COUNT(*)
 			FROM pg_index ix
@@ -341,6 +350,29 @@ func createIndex(q querier, d dialect, indexName string, tableName string, colum
 	return err
 }
 
+// createUniqueIndex creates a unique index if it doesn't exist for a given table and columns
+func createUniqueIndex(q querier, d dialect, indexName string, tableName string, columns []string) error {
+	switch d.name() {
+	case db.CLICKHOUSE, db.CASSANDRA:
+		return fmt.Errorf("unique indexes are not supported for %s dialect", d.name())
+	}
+
+	if tableName == "" || len(columns) == 0 {
+		return nil
+	}
+
+	if exists, err := indexExists(q, d, indexName, tableName); err != nil {
+		return fmt.Errorf("error checking index existence: %v", err)
+	} else if exists {
+		return nil
+	}
+
+	var qry = fmt.Sprintf("CREATE UNIQUE INDEX %v ON %v (%v)", indexName, d.table(tableName), strings.Join(columns, ", "))
+	var _, err = q.execContext(context.Background(), qry)
+
+	return err
+}
+
 // dropIndex drops an index if it exists
 func dropIndex(q querier, d dialect, indexName, tableName string) error {
 	if exists, err := indexExists(q, d, indexName, tableName); err != nil {
@@ -353,7 +385,7 @@ func dropIndex(q querier, d dialect, indexName, tableName string) error {
 	switch d.name() {
 	case db.SQLITE:
 		qry = fmt.Sprintf("DROP INDEX %v;", indexName)
-	case db.POSTGRES:
+	case db.POSTGRES, db.COCKROACHDB:
 		if d.schema() != "" {
 			qry = fmt.Sprintf("DROP INDEX %v.%v;", d.schema(), indexName)
 		} else {
@@ -460,7 +492,7 @@ func createSequence(q querier, d dialect, sequenceName string) error {
 			_, err = q.execContext(context.Background(), fmt.Sprintf("INSERT INTO %s (value, sequence_id) VALUES (1, 1)", sequenceName))
 			return err
 		}
-	case db.MYSQL, db.POSTGRES:
+	case db.MYSQL, db.POSTGRES, db.COCKROACHDB:
 		_, err := q.execContext(context.Background(), fmt.Sprintf("CREATE SEQUENCE IF NOT EXISTS %v", sequenceName))
 		return err
 	case db.MSSQL:
@@ -482,7 +514,7 @@ func dropSequence(q querier, d dialect, sequenceName string) error {
 	switch d.name() {
 	case db.SQLITE:
 		return dropTable(q, d, sequenceName, false)
-	case db.MYSQL, db.POSTGRES, db.MSSQL:
+	case db.MYSQL, db.POSTGRES, db.COCKROACHDB, db.MSSQL:
 		_, err := q.execContext(context.Background(), fmt.Sprintf("DROP SEQUENCE IF EXISTS %v", sequenceName))
 		return err
 	case db.CLICKHOUSE, db.CASSANDRA:
@@ -494,6 +526,56 @@ func dropSequence(q querier, d dialect, sequenceName string) error {
 	return nil
 }
 
+// createSchema creates a schema (PostgreSQL) or a database (MySQL) if it doesn't exist
+func createSchema(q querier, d dialect, schemaName string) error {
+	switch d.name() {
+	case db.POSTGRES, db.COCKROACHDB:
+		_, err := q.execContext(context.Background(), fmt.Sprintf("CREATE SCHEMA IF NOT EXISTS %v", schemaName))
+		return err
+	case db.MYSQL:
+		_, err := q.execContext(context.Background(), fmt.Sprintf("CREATE DATABASE IF NOT EXISTS %v", schemaName))
+		return err
+	default:
+		return fmt.Errorf("schema sandbox is not supported for driver: %s", d.name())
+	}
+}
+
+// dropSchema drops a schema (PostgreSQL) or a database (MySQL) with all its content if it exists
+func dropSchema(q querier, d dialect, schemaName string) error {
+	switch d.name() {
+	case db.POSTGRES, db.COCKROACHDB:
+		_, err := q.execContext(context.Background(), fmt.Sprintf("DROP SCHEMA IF EXISTS %v CASCADE", schemaName))
+		return err
+	case db.MYSQL:
+		_, err := q.execContext(context.Background(), fmt.Sprintf("DROP DATABASE IF EXISTS %v", schemaName))
+		return err
+	default:
+		return fmt.Errorf("schema sandbox is not supported for driver: %s", d.name())
+	}
+}
+
+// defragment rebuilds the table and its indexes, SQLite rebuilds the whole database file,
+// the query must not be run in the transaction as VACUUM can't run inside the transaction block
+func defragment(q querier, d dialect, tableName string) error {
+	var qry string
+	switch d.name() {
+	case db.POSTGRES:
+		qry = fmt.Sprintf("VACUUM (FULL, ANALYZE) %v", d.table(tableName))
+	case db.MYSQL:
+		qry = fmt.Sprintf("OPTIMIZE TABLE %v", d.table(tableName))
+	case db.MSSQL:
+		qry = fmt.Sprintf("ALTER INDEX ALL ON %v REBUILD", d.table(tableName))
+	case db.SQLITE:
+		qry = "VACUUM"
+	default:
+		return fmt.Errorf("defragmentation is not supported for driver: %s", d.name())
+	}
+
+	_, err := q.execContext(context.Background(), qry)
+
+	return err
+}
+
 // getTableMigrationSQL returns a table migration query for a given driver
 func getTableMigrationSQL(tableMigrationSQL string, dialect db.DialectName, engine string) (string, error) { //nolint:unused
 	switch dialect {
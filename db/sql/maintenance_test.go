@@ -1,6 +1,7 @@
 package sql
 
 import (
+	"errors"
 	"time"
 
 	"github.com/acronis/perfkit/db"
@@ -91,3 +92,49 @@ func (suite *TestingSuite) TestSqlSchemaInit() {
 		return
 	}
 }
+
+func (suite *TestingSuite) TestSchemaSandbox() {
+	d, _, c := suite.makeTestSession()
+	defer logDbTime(suite.T(), c)
+	defer cleanup(suite.T(), d)
+
+	if d.DialectName() != db.POSTGRES && d.DialectName() != db.MYSQL {
+		return
+	}
+
+	var called bool
+	if err := d.RunInSchemaSandbox("perf_sandbox", func() error {
+		called = true
+		return nil
+	}); err != nil {
+		suite.T().Error(err)
+		return
+	}
+
+	if !called {
+		suite.T().Error("sandbox function was not called")
+		return
+	}
+
+	var fnErr = errors.New("sandbox function error")
+	if err := d.RunInSchemaSandbox("perf_sandbox", func() error { return fnErr }); !errors.Is(err, fnErr) {
+		suite.T().Errorf("expected sandbox function error, got %v", err)
+	}
+}
+
+func (suite *TestingSuite) TestDefragment() {
+	d, _, c := suite.makeTestSession()
+	defer logDbTime(suite.T(), c)
+	defer cleanup(suite.T(), d)
+
+	switch d.DialectName() {
+	case db.POSTGRES, db.MYSQL, db.MSSQL, db.SQLITE:
+		if err := d.Defragment("perf_table"); err != nil {
+			suite.T().Error("defragment", err)
+		}
+	default:
+		if err := d.Defragment("perf_table"); err == nil {
+			suite.T().Error("defragment must not be supported")
+		}
+	}
+}
@@ -2,6 +2,7 @@ package sql
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
 	"math"
 	"strings"
@@ -115,6 +116,10 @@ func (d *msDialect) getType(id db.DataType) string {
 		return "null"
 	case db.DataTypeTenantUUIDBoundID:
 		return "VARCHAR(64)"
+	case db.DataTypeJSON, db.DataTypeJSONB, db.DataTypeJSONText:
+		return "NVARCHAR(MAX)"
+	case db.DataTypeInet, db.DataTypeCidr:
+		return "VARCHAR(45)"
 	default:
 		return ""
 	}
@@ -133,6 +138,15 @@ func (d *msDialect) isDeadlock(err error) bool {
 	return false
 }
 
+// isUniqueViolation returns true if the error is caused by PRIMARY KEY / UNIQUE constraint or unique index violation
+func (d *msDialect) isUniqueViolation(err error) bool {
+	var msErr mssql.Error
+	if errors.As(err, &msErr) {
+		return msErr.Number == 2627 || msErr.Number == 2601
+	}
+	return false
+}
+
 func (d *msDialect) isRetriable(err error) bool {
 	return d.isDeadlock(err)
 }
@@ -141,6 +155,11 @@ func (d *msDialect) canRollback(err error) bool {
 	return !d.isDeadlock(err) // mssql destroys deadlocked transaction by itself, rollback from application results in error
 }
 
+func (d *msDialect) readOnlyTxOptions() *sql.TxOptions {
+	// MSSQL has no read-only transactions, so snapshot isolation is used to avoid shared locks acquisition
+	return &sql.TxOptions{Isolation: sql.LevelSnapshot}
+}
+
 func (d *msDialect) table(table string) string {
 	return table
 }
@@ -165,7 +184,8 @@ func (c *msConnector) ConnectionPool(cfg db.Config) (db.Database, error) {
 	var err error
 	var rwc *sql.DB
 
-	if rwc, err = sql.Open("sqlserver", cfg.ConnString); err != nil {
+	var dia = &msDialect{}
+	if rwc, err = openDB("sqlserver", cfg.ConnString, dia, cfg); err != nil {
 		return nil, fmt.Errorf("sql: cannot connect to sql server db at %v, err: %v", sanitizeConn(cfg.ConnString), err)
 	}
 
@@ -182,10 +202,18 @@ func (c *msConnector) ConnectionPool(cfg db.Config) (db.Database, error) {
 		rwc.SetConnMaxLifetime(cfg.MaxConnLifetime)
 	}
 
-	dbo.dialect = &msDialect{}
+	dbo.dialect = dia
 	dbo.queryLogger = cfg.QueryLogger
-
-	return dbo, nil
+	dbo.queryRecorder = cfg.QueryRecorder
+	dbo.queryTimeout = cfg.QueryTimeout
+	dbo.dryRun = cfg.DryRun
+	dbo.deadlockRetries = cfg.DeadlockRetries
+	dbo.maxRetries = cfg.MaxRetries
+	dbo.retryBaseDelay = cfg.RetryBaseDelay
+	dbo.retryLogger = cfg.RetryLogger
+	dbo.tracer = newTracer(cfg.TracerProvider)
+
+	return withReadReplica(cfg, dbo)
 }
 
 func (c *msConnector) DialectName(scheme string) (db.DialectName, error) {
@@ -3,6 +3,7 @@ package sql
 import (
 	"bytes"
 	"database/sql"
+	"errors"
 	"fmt"
 	"math"
 	"strconv"
@@ -148,6 +149,14 @@ func (d *mysqlDialect) getType(id db.DataType) string {
 		return "not null"
 	case db.DataTypeNull:
 		return "null"
+	case db.DataTypeJSON:
+		return "JSON"
+	case db.DataTypeJSONB:
+		return "JSON" // MySQL JSON is already stored in binary format
+	case db.DataTypeJSONText:
+		return "LONGTEXT"
+	case db.DataTypeInet, db.DataTypeCidr:
+		return "VARCHAR(45)" // max length of the textual IPv6 address
 	}
 
 	if d.sqlEngine == "xpand-allnodes" {
@@ -175,10 +184,24 @@ func (d *mysqlDialect) isRetriable(err error) bool {
 	return false
 }
 
+// isUniqueViolation returns true if the error is caused by PRIMARY KEY / UNIQUE constraint or unique index violation
+func (d *mysqlDialect) isUniqueViolation(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		return mysqlErr.Number == 1062 // ER_DUP_ENTRY
+	}
+	return false
+}
+
 func (d *mysqlDialect) canRollback(err error) bool {
 	return err != mysql.ErrInvalidConn
 }
 
+func (d *mysqlDialect) readOnlyTxOptions() *sql.TxOptions {
+	// MySQL runs the transaction as START TRANSACTION READ ONLY
+	return &sql.TxOptions{ReadOnly: true}
+}
+
 func (d *mysqlDialect) table(table string) string {
 	return table
 }
@@ -250,7 +273,8 @@ func (c *mysqlConnector) ConnectionPool(cfg db.Config) (db.Database, error) {
 	var rwc *sql.DB
 
 	dsn := cs + "?" + "maxAllowedPacket=" + strconv.Itoa(cfg.MaxPacketSize) + "&parseTime=true"
-	if rwc, err = sql.Open("mysql", dsn); err != nil {
+	var dia = &mysqlDialect{}
+	if rwc, err = openDB("mysql", dsn, dia, cfg); err != nil {
 		return nil, fmt.Errorf("db: cannot connect to mysql db at %v, err: %v", sanitizeConn(cfg.ConnString), err)
 	}
 
@@ -271,10 +295,18 @@ func (c *mysqlConnector) ConnectionPool(cfg db.Config) (db.Database, error) {
 		rwc.SetConnMaxLifetime(maxConnLifetime)
 	}
 
-	dbo.dialect = &mysqlDialect{}
+	dbo.dialect = dia
 	dbo.queryLogger = cfg.QueryLogger
-
-	return dbo, nil
+	dbo.queryRecorder = cfg.QueryRecorder
+	dbo.queryTimeout = cfg.QueryTimeout
+	dbo.dryRun = cfg.DryRun
+	dbo.deadlockRetries = cfg.DeadlockRetries
+	dbo.maxRetries = cfg.MaxRetries
+	dbo.retryBaseDelay = cfg.RetryBaseDelay
+	dbo.retryLogger = cfg.RetryLogger
+	dbo.tracer = newTracer(cfg.TracerProvider)
+
+	return withReadReplica(cfg, dbo)
 }
 
 func (c *mysqlConnector) DialectName(scheme string) (db.DialectName, error) {
@@ -30,9 +30,16 @@ func init() {
 type pgDialect struct {
 	schemaName string
 	embedded   bool
+	cockroach  bool // the server is CockroachDB speaking the PostgreSQL wire protocol, see isCockroachDB
+
+	retrySerialization bool // retry the PostgreSQL transactions aborted by the serialization failure, set by db.Config.MaxRetries
 }
 
 func (d *pgDialect) name() db.DialectName {
+	if d.cockroach {
+		return db.COCKROACHDB
+	}
+
 	return db.POSTGRES
 }
 
@@ -138,6 +145,16 @@ func (d *pgDialect) getType(id db.DataType) string {
 		return "vector(3)"
 	case db.DataTypeVector768Float32: // For pgvector
 		return "vector(768)"
+	case db.DataTypeJSON:
+		return "JSONB"
+	case db.DataTypeJSONB:
+		return "JSONB"
+	case db.DataTypeJSONText:
+		return "JSON"
+	case db.DataTypeInet:
+		return "INET"
+	case db.DataTypeCidr:
+		return "CIDR"
 	default:
 		return ""
 	}
@@ -153,6 +170,16 @@ func (d *pgDialect) isRetriable(err error) bool {
 			return true
 		}
 	}
+	// CockroachDB runs all the transactions as SERIALIZABLE and expects the client to retry them
+	return (d.cockroach || d.retrySerialization) && isSerializationFailure(err)
+}
+
+// isUniqueViolation returns true if the error is caused by PRIMARY KEY / UNIQUE constraint or unique index violation
+func (d *pgDialect) isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return pqErr.Code == "23505" // unique_violation
+	}
 	return false
 }
 
@@ -161,6 +188,11 @@ func (d *pgDialect) canRollback(err error) bool {
 	return !errors.Is(err, context.Canceled)
 }
 
+func (d *pgDialect) readOnlyTxOptions() *sql.TxOptions {
+	// PostgreSQL runs the transaction as BEGIN READ ONLY
+	return &sql.TxOptions{ReadOnly: true}
+}
+
 func (d *pgDialect) table(table string) string {
 	if d.schemaName != "" {
 		return d.schemaName + "." + table
@@ -173,8 +205,12 @@ func (d *pgDialect) schema() string {
 	return d.schemaName
 }
 
-// Recommendations returns PostgreSQL recommendations for DB settings
+// Recommendations returns PostgreSQL recommendations for DB settings, there are none for CockroachDB
 func (d *pgDialect) recommendations() []db.Recommendation {
+	if d.cockroach {
+		return nil
+	}
+
 	return []db.Recommendation{
 		{Setting: "shared_buffers", Meaning: "primary DB cache", MinVal: int64(1 * db.GByte), RecommendedVal: int64(4 * db.GByte)},
 		{Setting: "effective_cache_size", Meaning: "OS cache", MinVal: int64(2 * db.GByte), RecommendedVal: int64(8 * db.GByte)},
@@ -230,7 +266,7 @@ func postgresSchemaAndConnString(cs string) (string, string, error) {
 	return schemaName, cs, nil
 }
 
-func initializePostgresDB(cs string) (string, dialect, error) {
+func initializePostgresDB(cs string) (string, *pgDialect, error) {
 	var schemaName, cleanedConnectionString, err = postgresSchemaAndConnString(cs)
 	if err != nil {
 		return "", nil, fmt.Errorf("db: postgres: %v", err)
@@ -262,7 +298,7 @@ func (c *pgConnector) ConnectionPool(cfg db.Config) (db.Database, error) {
 		return nil, err
 	}
 
-	if rwc, err = sql.Open("postgres", cs); err != nil {
+	if rwc, err = openDB("postgres", cs, dia, cfg); err != nil {
 		return nil, fmt.Errorf("db: cannot connect to postgresql db at %v, err: %v", sanitizeConn(cfg.ConnString), err)
 	}
 
@@ -270,6 +306,12 @@ func (c *pgConnector) ConnectionPool(cfg db.Config) (db.Database, error) {
 		return nil, fmt.Errorf("db: failed ping postgresql db at %v, err: %v", sanitizeConn(cfg.ConnString), err)
 	}
 
+	if dia.cockroach, err = isCockroachDB(rwc); err != nil {
+		return nil, fmt.Errorf("db: cannot get postgresql db version at %v, err: %v", sanitizeConn(cfg.ConnString), err)
+	}
+
+	dia.retrySerialization = cfg.MaxRetries > 0
+
 	dbo.rw = &sqlQuerier{rwc}
 	dbo.t = &sqlQuerier{rwc}
 
@@ -281,8 +323,16 @@ func (c *pgConnector) ConnectionPool(cfg db.Config) (db.Database, error) {
 
 	dbo.dialect = dia
 	dbo.queryLogger = cfg.QueryLogger
-
-	return dbo, nil
+	dbo.queryRecorder = cfg.QueryRecorder
+	dbo.queryTimeout = cfg.QueryTimeout
+	dbo.dryRun = cfg.DryRun
+	dbo.deadlockRetries = cfg.DeadlockRetries
+	dbo.maxRetries = cfg.MaxRetries
+	dbo.retryBaseDelay = cfg.RetryBaseDelay
+	dbo.retryLogger = cfg.RetryLogger
+	dbo.tracer = newTracer(cfg.TracerProvider)
+
+	return withReadReplica(cfg, dbo)
 }
 
 func (c *pgConnector) DialectName(scheme string) (db.DialectName, error) {
@@ -1,7 +1,10 @@
 package sql
 
 import (
+	"context"
 	"database/sql"
+	"errors"
+	"fmt"
 
 	"github.com/acronis/perfkit/db"
 )
@@ -18,17 +21,83 @@ func (r *sqlResult) RowsAffected() (int64, error) {
 	return r.result.RowsAffected()
 }
 
+// queryCtx returns a context for a single query bounded by the client-side query timeout, if any
+func (g *sqlGateway) queryCtx() (context.Context, context.CancelFunc) {
+	if g.queryTimeout <= 0 {
+		return g.ctx, func() {}
+	}
+
+	return context.WithTimeout(g.ctx, g.queryTimeout)
+}
+
+// queryErr marks errors caused by the expired client-side query timeout with db.ErrQueryTimeout
+func queryErr(ctx context.Context, err error) error {
+	if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("%w: %v", db.ErrQueryTimeout, err)
+	}
+
+	return err
+}
+
+// uniqueViolationErr marks errors caused by the unique constraint or unique index violation with db.ErrUniqueViolation
+func uniqueViolationErr(d dialect, err error) error {
+	if err != nil && d.isUniqueViolation(err) {
+		return fmt.Errorf("%w: %v", db.ErrUniqueViolation, err)
+	}
+
+	return err
+}
+
+// sqlRow cancels the query context once the row is scanned
+type sqlRow struct {
+	row    *sql.Row
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+func (r *sqlRow) Scan(dest ...any) error {
+	defer r.cancel()
+
+	return queryErr(r.ctx, r.row.Scan(dest...))
+}
+
 func (g *sqlGateway) Exec(format string, args ...interface{}) (db.Result, error) {
-	var sqlRes, err = g.rw.execContext(g.ctx, format, args...)
-	return &sqlResult{result: sqlRes}, err
+	var ctx, cancel = g.queryCtx()
+	defer cancel()
+
+	var spanCtx, span = g.startSpan(ctx, queryOperation(format), format)
+	var sqlRes, err = g.rw.execContext(spanCtx, format, args...)
+	endSpan(span, err)
+
+	return &sqlResult{result: sqlRes}, uniqueViolationErr(g.dialect, queryErr(ctx, err))
 }
 
 func (g *sqlGateway) QueryRow(format string, args ...interface{}) db.Row {
-	var row = g.rw.queryRowContext(g.ctx, format, args...)
-	return row
+	if g.queryTimeout <= 0 {
+		var spanCtx, span = g.startSpan(g.ctx, queryOperation(format), format)
+		defer endSpan(span, nil)
+
+		return g.rw.queryRowContext(spanCtx, format, args...)
+	}
+
+	var ctx, cancel = g.queryCtx()
+	var spanCtx, span = g.startSpan(ctx, queryOperation(format), format)
+	var row = g.rw.queryRowContext(spanCtx, format, args...)
+	endSpan(span, nil)
+
+	return &sqlRow{row: row, ctx: ctx, cancel: cancel}
 }
 
 func (g *sqlGateway) Query(format string, args ...interface{}) (db.Rows, error) {
-	var rows, err = g.rw.queryContext(g.ctx, format, args...)
-	return &sqlRows{rows: rows}, err
+	var ctx, cancel = g.queryCtx()
+	var spanCtx, span = g.startSpan(ctx, queryOperation(format), format)
+	var rows, err = g.reader().queryContext(spanCtx, format, args...)
+	endSpan(span, err)
+
+	if err != nil {
+		cancel()
+		return &sqlRows{rows: rows}, queryErr(ctx, err)
+	}
+
+	return &sqlRows{rows: rows, ctx: ctx, cancel: cancel}, nil
 }
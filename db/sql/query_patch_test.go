@@ -107,3 +107,61 @@ func TestDefaultCreateQueryPatchFuncWithCassandra(t *testing.T) {
 		t.Errorf("DefaultCreateQueryPatchFunc() got = %v, want %v", result, expected)
 	}
 }
+
+func TestDefaultCreateQueryPatchFuncJSONTypes(t *testing.T) {
+	var table = "test_table"
+	var query = "CREATE TABLE {table} (a {$json}, b {$jsonb}, c {$json_text})"
+
+	var tests = []struct {
+		name     string
+		dia      dialect
+		expected string
+	}{
+		{"postgres", &pgDialect{}, "CREATE TABLE test_table (a JSONB, b JSONB, c JSON)"},
+		{"mysql", &mysqlDialect{sqlEngine: "innodb"}, "CREATE TABLE test_table (a JSON, b JSON, c LONGTEXT)"},
+		{"sqlite", &sqliteDialect{}, "CREATE TABLE test_table (a TEXT, b TEXT, c TEXT)"},
+		{"mssql", &msDialect{}, "CREATE TABLE test_table (a NVARCHAR(MAX), b NVARCHAR(MAX), c NVARCHAR(MAX))"},
+	}
+
+	for _, tt := range tests {
+		var result, err = db.DefaultCreateQueryPatchFunc(table, query, &sqlDialect{dia: tt.dia})
+		if err != nil {
+			t.Errorf("%s: DefaultCreateQueryPatchFunc() error = %v", tt.name, err)
+
+			continue
+		}
+
+		if result != tt.expected {
+			t.Errorf("%s: DefaultCreateQueryPatchFunc() got = %v, want %v", tt.name, result, tt.expected)
+		}
+	}
+}
+
+func TestDefaultCreateQueryPatchFuncNetworkTypes(t *testing.T) {
+	var table = "test_table"
+	var query = "CREATE TABLE {table} (a {$inet}, b {$cidr})"
+
+	var tests = []struct {
+		name     string
+		dia      dialect
+		expected string
+	}{
+		{"postgres", &pgDialect{}, "CREATE TABLE test_table (a INET, b CIDR)"},
+		{"mysql", &mysqlDialect{sqlEngine: "innodb"}, "CREATE TABLE test_table (a VARCHAR(45), b VARCHAR(45))"},
+		{"sqlite", &sqliteDialect{}, "CREATE TABLE test_table (a VARCHAR(45), b VARCHAR(45))"},
+		{"cassandra", &cassandraDialect{}, "CREATE TABLE test_table (a text, b text)"},
+	}
+
+	for _, tt := range tests {
+		var result, err = db.DefaultCreateQueryPatchFunc(table, query, &sqlDialect{dia: tt.dia})
+		if err != nil {
+			t.Errorf("%s: DefaultCreateQueryPatchFunc() error = %v", tt.name, err)
+
+			continue
+		}
+
+		if result != tt.expected {
+			t.Errorf("%s: DefaultCreateQueryPatchFunc() got = %v, want %v", tt.name, result, tt.expected)
+		}
+	}
+}
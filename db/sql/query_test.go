@@ -1,6 +1,13 @@
 package sql
 
-import "github.com/acronis/perfkit/db"
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/acronis/perfkit/db"
+)
 
 func (suite *TestingSuite) TestQuery() {
 	d, s, c := suite.makeTestSession()
@@ -122,3 +129,91 @@ func (suite *TestingSuite) TestQuery() {
 		}
 	}
 }
+
+func (suite *TestingSuite) TestRunInReadOnlyTransaction() {
+	d, s, c := suite.makeTestSession()
+	defer logDbTime(suite.T(), c)
+	defer cleanup(suite.T(), d)
+
+	if err := s.RunInReadOnlyTransaction(func(tx db.DatabaseAccessor) error {
+		var resp int
+		return tx.QueryRow("SELECT 1;").Scan(&resp)
+	}); err != nil {
+		suite.T().Error(err)
+	}
+
+	switch d.DialectName() {
+	case db.POSTGRES, db.MYSQL:
+		if err := s.RunInReadOnlyTransaction(func(tx db.DatabaseAccessor) error {
+			_, err := tx.Exec("INSERT INTO perf_table (origin, type, name) VALUES (7, 8, 'read-only');")
+			return err
+		}); err == nil {
+			suite.T().Error("write in read-only transaction succeeded")
+		}
+	}
+}
+
+func TestQueryErrMarksClientTimeout(t *testing.T) {
+	var queryFailed = errors.New("query failed")
+
+	if err := queryErr(context.Background(), queryFailed); errors.Is(err, db.ErrQueryTimeout) {
+		t.Errorf("queryErr() marked error without timeout: %v", err)
+	}
+
+	var ctx, cancel = context.WithTimeout(context.Background(), time.Nanosecond)
+	defer cancel()
+	<-ctx.Done()
+
+	if err := queryErr(ctx, queryFailed); !errors.Is(err, db.ErrQueryTimeout) {
+		t.Errorf("queryErr() got = %v, want %v", err, db.ErrQueryTimeout)
+	}
+
+	if err := queryErr(ctx, nil); err != nil {
+		t.Errorf("queryErr() got = %v, want nil", err)
+	}
+}
+
+// testQueryRecorder collects the recorded queries
+type testQueryRecorder struct {
+	queries []string
+}
+
+func (r *testQueryRecorder) RecordQuery(query string, args []interface{}, duration time.Duration) { //nolint:revive
+	r.queries = append(r.queries, query)
+}
+
+func TestQueryRecorder(t *testing.T) {
+	var recorder = &testQueryRecorder{}
+	var d, err = db.Open(db.Config{ConnString: sqliteConnString, MaxOpenConns: 1, QueryRecorder: recorder})
+	if err != nil {
+		t.Fatalf("db.Open() error: %v", err)
+	}
+	defer d.Close() //nolint:errcheck
+
+	var s = d.Session(d.Context(context.Background()))
+	if _, err = s.Exec("CREATE TABLE recorded (id int)"); err != nil {
+		t.Fatalf("Exec() error: %v", err)
+	}
+
+	if err = s.Transact(func(tx db.DatabaseAccessor) error {
+		_, txErr := tx.Exec("INSERT INTO recorded (id) VALUES (1)")
+		return txErr
+	}); err != nil {
+		t.Fatalf("Transact() error: %v", err)
+	}
+
+	var id int
+	if err = s.QueryRow("SELECT id FROM recorded").Scan(&id); err != nil {
+		t.Fatalf("QueryRow() error: %v", err)
+	}
+
+	var want = []string{"CREATE TABLE recorded (id int)", "INSERT INTO recorded (id) VALUES (1)", "SELECT id FROM recorded"}
+	if len(recorder.queries) != len(want) {
+		t.Fatalf("recorded queries = %v, want %v", recorder.queries, want)
+	}
+	for i := range want {
+		if recorder.queries[i] != want[i] {
+			t.Errorf("recorded query %d = %q, want %q", i, recorder.queries[i], want[i])
+		}
+	}
+}
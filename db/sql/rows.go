@@ -1,6 +1,7 @@
 package sql
 
 import (
+	"context"
 	"database/sql"
 	"strings"
 )
@@ -8,6 +9,9 @@ import (
 // Rows is a struct for storing DB rows (as a slice of Row) and current index
 type sqlRows struct {
 	rows *sql.Rows
+
+	ctx    context.Context    // query context, if bounded by the client-side query timeout
+	cancel context.CancelFunc // releases the query context on Close
 }
 
 func (r *sqlRows) Next() bool {
@@ -15,6 +19,10 @@ func (r *sqlRows) Next() bool {
 }
 
 func (r *sqlRows) Err() error {
+	if r.ctx != nil {
+		return queryErr(r.ctx, r.rows.Err())
+	}
+
 	return r.rows.Err()
 }
 
@@ -23,6 +31,10 @@ func (r *sqlRows) Scan(dest ...interface{}) error {
 }
 
 func (r *sqlRows) Close() error {
+	if r.cancel != nil {
+		defer r.cancel()
+	}
+
 	return r.rows.Close()
 }
 
@@ -62,7 +62,7 @@ func (g *sqlGateway) addExplainPrefix(query string) (string, error) {
 	switch g.dialect.name() {
 	case db.MYSQL:
 		return "EXPLAIN " + query, nil
-	case db.POSTGRES:
+	case db.POSTGRES, db.COCKROACHDB:
 		return "EXPLAIN ANALYZE " + query, nil
 	case db.SQLITE:
 		return "EXPLAIN QUERY PLAN " + query, nil
@@ -112,7 +112,7 @@ func (g *sqlGateway) explain(rows *sql.Rows, query string, args ...interface{})
 				g.queryLogger.Log("  %-15s: %s\n", cols[i], string(col))
 			}
 			g.queryLogger.Log("\n")
-		case db.POSTGRES:
+		case db.POSTGRES, db.COCKROACHDB:
 			var explainOutput string
 			if err = rows.Scan(&explainOutput); err != nil {
 				return fmt.Errorf("DB query result scan failed: %s\nError: %s", query, err)
@@ -142,7 +142,7 @@ type selectBuilder struct {
 	queryable map[string]filterFunction
 }
 
-func (b selectBuilder) sqlOrder(fields []string, values []string) (string, error) {
+func (b selectBuilder) sqlOrder(d dialect, fields []string, values []string) (string, error) {
 	var result = ""
 	if len(fields) == 0 { // select count
 		return result, nil
@@ -158,13 +158,21 @@ func (b selectBuilder) sqlOrder(fields []string, values []string) (string, error
 			return "", fmt.Errorf("empty order field")
 		}
 
-		var dir string
+		var dir, nulls string
 
 		switch fnc {
 		case "asc":
 			dir = "ASC"
 		case "desc":
 			dir = "DESC"
+		case "asc_nulls_first":
+			dir, nulls = "ASC", "FIRST"
+		case "asc_nulls_last":
+			dir, nulls = "ASC", "LAST"
+		case "desc_nulls_first":
+			dir, nulls = "DESC", "FIRST"
+		case "desc_nulls_last":
+			dir, nulls = "DESC", "LAST"
 		case "nearest":
 			dir = "NEAREST"
 		case "":
@@ -179,7 +187,12 @@ func (b selectBuilder) sqlOrder(fields []string, values []string) (string, error
 				return "", fmt.Errorf("number of args %d doesn't match number of conditions 1", len(args))
 			}
 
-			orderStatement = fmt.Sprintf("%v.%v %v", b.tableName, args[0], dir)
+			var column = fmt.Sprintf("%v.%v", b.tableName, args[0])
+			if nulls == "" {
+				orderStatement = fmt.Sprintf("%v %v", column, dir)
+			} else if orderStatement, err = orderNulls(d, column, dir, nulls); err != nil {
+				return "", err
+			}
 		} else if dir == "NEAREST" {
 			if len(args) != 3 {
 				return "", fmt.Errorf("number of args %d doesn't match number of conditions for nearest function, should be 3", len(args))
@@ -207,6 +220,26 @@ func (b selectBuilder) sqlOrder(fields []string, values []string) (string, error
 	return result, nil
 }
 
+// orderNulls returns the ORDER BY statement placing the NULL values of the column first or last,
+// MySQL and MSSQL have no NULLS FIRST / NULLS LAST modifiers, so the order is emulated by the preceding IS NULL sort key
+func orderNulls(d dialect, column string, dir string, nulls string) (string, error) {
+	var nullsDir = "ASC" // the NULL values have the greater sort key, so ASC puts them last
+	if nulls == "FIRST" {
+		nullsDir = "DESC"
+	}
+
+	switch d.name() {
+	case db.MYSQL:
+		return fmt.Sprintf("ISNULL(%v) %v, %v %v", column, nullsDir, column, dir), nil
+	case db.MSSQL:
+		return fmt.Sprintf("CASE WHEN %v IS NULL THEN 1 ELSE 0 END %v, %v %v", column, nullsDir, column, dir), nil
+	case db.CASSANDRA:
+		return "", fmt.Errorf("NULLS %v order is not supported by %v", nulls, d.name())
+	default:
+		return fmt.Sprintf("%v %v NULLS %v", column, dir, nulls), nil
+	}
+}
+
 func (b selectBuilder) sqlSelectionAlias(fields []string, alias string) (string, error) {
 	if len(fields) == 1 && fields[0] == "COUNT(0)" { // select count
 		return "SELECT COUNT(0)", nil
@@ -237,6 +270,29 @@ func (b selectBuilder) sqlSelectionAlias(fields []string, alias string) (string,
 	return "SELECT " + strings.Join(columns, ", "), nil
 }
 
+func (b selectBuilder) sqlAggregates(aggregates []db.AggregateField) (string, error) {
+	var columns []string
+	for _, agg := range aggregates {
+		switch agg.Function {
+		case db.AggregateCount, db.AggregateSum, db.AggregateAvg, db.AggregateMin, db.AggregateMax:
+		default:
+			return "", fmt.Errorf("bad aggregate function '%v'", agg.Function)
+		}
+
+		if agg.Column == "" {
+			if agg.Function != db.AggregateCount {
+				return "", fmt.Errorf("empty %v aggregate column", agg.Function)
+			}
+			columns = append(columns, "COUNT(*)")
+			continue
+		}
+
+		columns = append(columns, fmt.Sprintf("%v(%v.%v)", agg.Function, b.tableName, agg.Column))
+	}
+
+	return "SELECT " + strings.Join(columns, ", "), nil
+}
+
 func (b selectBuilder) sqlConditions(d dialect, optimizeConditions bool, fields map[string][]string) (string, []interface{}, bool, error) {
 	var fmtString = ""
 	var fmtArgs []interface{}
@@ -379,7 +435,11 @@ func (b selectBuilder) sql(d dialect, c *db.SelectCtrl) (string, bool, error) {
 	var args []interface{}
 	var empty bool
 
-	if selectWhat, err = b.sqlSelectionAlias(c.Fields, b.tableName); err != nil {
+	if len(c.Aggregates) != 0 {
+		if selectWhat, err = b.sqlAggregates(c.Aggregates); err != nil {
+			return "", false, err
+		}
+	} else if selectWhat, err = b.sqlSelectionAlias(c.Fields, b.tableName); err != nil {
 		return "", false, err
 	}
 
@@ -391,7 +451,12 @@ func (b selectBuilder) sql(d dialect, c *db.SelectCtrl) (string, bool, error) {
 		return "", true, nil
 	}
 
-	if order, err = b.sqlOrder(c.Fields, c.Order); err != nil {
+	if len(c.Aggregates) != 0 {
+		fromWhere := fmt.Sprintf("FROM %s", d.table(b.tableName))
+		return sqlf(d, b.build(selectWhat, fromWhere, where, "", ""), args...), false, nil
+	}
+
+	if order, err = b.sqlOrder(d, c.Fields, c.Order); err != nil {
 		return "", false, err
 	}
 
@@ -890,8 +955,12 @@ func (g *sqlGateway) Select(tableName string, sc *db.SelectCtrl) (db.Rows, error
 		return &db.EmptyRows{}, nil
 	}
 
+	var ctx, cancel = g.queryCtx()
 	var rows *sql.Rows
-	rows, err = g.rw.queryContext(g.ctx, query)
+	if rows, err = g.reader().queryContext(ctx, query); err != nil {
+		cancel()
+		return nil, queryErr(ctx, err)
+	}
 
-	return &sqlRows{rows: rows}, nil
+	return &sqlRows{rows: rows, ctx: ctx, cancel: cancel}, nil
 }
@@ -3,9 +3,13 @@ package sql
 import (
 	"fmt"
 	"reflect"
+	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/acronis/perfkit/db"
 )
 
 func (suite *TestingSuite) TestStringNeCond() {
@@ -335,3 +339,75 @@ func (suite *TestingSuite) TestTimeCond() {
 		}
 	}
 }
+
+func TestSqlAggregates(t *testing.T) {
+	var b = selectBuilder{
+		tableName: "perf_table",
+		queryable: map[string]filterFunction{"id": idCond()},
+	}
+
+	var c = &db.SelectCtrl{
+		Aggregates: []db.AggregateField{
+			{Function: db.AggregateCount},
+			{Function: db.AggregateSum, Column: "progress"},
+			{Function: db.AggregateAvg, Column: "completion_time_ns"},
+		},
+		Where: map[string][]string{"id": {"gt(1)"}},
+		Order: []string{"desc(id)"},
+		Page:  db.Page{Limit: 10},
+	}
+
+	var qry, empty, err = b.sql(&pgDialect{}, c)
+	require.NoError(t, err)
+	require.False(t, empty)
+	require.Equal(t, "SELECT COUNT(*), SUM(perf_table.progress), AVG(perf_table.completion_time_ns) FROM perf_table WHERE perf_table.id > 1  ", qry)
+
+	_, _, err = b.sql(&pgDialect{}, &db.SelectCtrl{Aggregates: []db.AggregateField{{Function: db.AggregateSum}}})
+	require.EqualError(t, err, "empty SUM aggregate column")
+
+	_, _, err = b.sql(&pgDialect{}, &db.SelectCtrl{Aggregates: []db.AggregateField{{Function: "MEDIAN", Column: "progress"}}})
+	require.EqualError(t, err, "bad aggregate function 'MEDIAN'")
+}
+
+func TestSqlOrderNulls(t *testing.T) {
+	var b = selectBuilder{
+		tableName: "perf_table",
+		queryable: map[string]filterFunction{"id": idCond()},
+	}
+
+	type testOrder struct {
+		dialect  dialect
+		order    string
+		expected string
+	}
+
+	var tests = []testOrder{
+		{&pgDialect{}, "asc_nulls_first(name)", "ORDER BY perf_table.name ASC NULLS FIRST"},
+		{&pgDialect{}, "asc_nulls_last(name)", "ORDER BY perf_table.name ASC NULLS LAST"},
+		{&pgDialect{}, "desc_nulls_first(name)", "ORDER BY perf_table.name DESC NULLS FIRST"},
+		{&pgDialect{}, "desc_nulls_last(name)", "ORDER BY perf_table.name DESC NULLS LAST"},
+		{&sqliteDialect{}, "asc_nulls_first(name)", "ORDER BY perf_table.name ASC NULLS FIRST"},
+		{&sqliteDialect{}, "asc_nulls_last(name)", "ORDER BY perf_table.name ASC NULLS LAST"},
+		{&sqliteDialect{}, "desc_nulls_first(name)", "ORDER BY perf_table.name DESC NULLS FIRST"},
+		{&sqliteDialect{}, "desc_nulls_last(name)", "ORDER BY perf_table.name DESC NULLS LAST"},
+		{&mysqlDialect{}, "asc_nulls_first(name)", "ORDER BY ISNULL(perf_table.name) DESC, perf_table.name ASC"},
+		{&mysqlDialect{}, "asc_nulls_last(name)", "ORDER BY ISNULL(perf_table.name) ASC, perf_table.name ASC"},
+		{&mysqlDialect{}, "desc_nulls_first(name)", "ORDER BY ISNULL(perf_table.name) DESC, perf_table.name DESC"},
+		{&mysqlDialect{}, "desc_nulls_last(name)", "ORDER BY ISNULL(perf_table.name) ASC, perf_table.name DESC"},
+		{&msDialect{}, "asc_nulls_first(name)", "ORDER BY CASE WHEN perf_table.name IS NULL THEN 1 ELSE 0 END DESC, perf_table.name ASC"},
+		{&msDialect{}, "desc_nulls_last(name)", "ORDER BY CASE WHEN perf_table.name IS NULL THEN 1 ELSE 0 END ASC, perf_table.name DESC"},
+	}
+
+	for _, test := range tests {
+		var order, err = b.sqlOrder(test.dialect, []string{"id"}, []string{test.order})
+		require.NoError(t, err)
+		require.Equal(t, test.expected, order, "unexpected order for %s: %s", test.dialect.name(), test.order)
+	}
+
+	var order, err = b.sqlOrder(&pgDialect{}, []string{"id"}, []string{"desc_nulls_last(name)", "asc(id)"})
+	require.NoError(t, err)
+	require.Equal(t, "ORDER BY perf_table.name DESC NULLS LAST, perf_table.id ASC", order)
+
+	_, err = b.sqlOrder(&cassandraDialect{}, []string{"id"}, []string{"asc_nulls_last(name)"})
+	require.EqualError(t, err, "NULLS LAST order is not supported by cassandra")
+}
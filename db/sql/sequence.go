@@ -11,11 +11,11 @@ func (s *esSession) GetNextVal(sequenceName string) (uint64, error) {
 	var nextVal uint64
 
 	switch s.dialect.name() {
-	case db.POSTGRES, db.MSSQL, db.MYSQL:
+	case db.POSTGRES, db.COCKROACHDB, db.MSSQL, db.MYSQL:
 		var query string
 
 		switch s.dialect.name() {
-		case db.POSTGRES:
+		case db.POSTGRES, db.COCKROACHDB:
 			query = "SELECT NEXTVAL('" + sequenceName + "')"
 		case db.MYSQL:
 			query = "SELECT NEXTVAL(" + sequenceName + ")"
@@ -2,7 +2,9 @@ package sql
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math/rand"
 	"net/url"
 	"time"
 
@@ -10,6 +12,8 @@ import (
 	"database/sql/driver"
 
 	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/atomic"
 
 	"github.com/acronis/perfkit/db"
@@ -43,11 +47,15 @@ type transaction interface {
 }
 
 type transactor interface {
-	begin(ctx context.Context) (transaction, error)
+	begin(ctx context.Context, opts *sql.TxOptions) (transaction, error)
 }
 
 func inTx(ctx context.Context, t transactor, d dialect, fn func(q querier, d dialect) error) error {
-	tx, err := t.begin(ctx)
+	return inTxWithOptions(ctx, t, d, nil, fn)
+}
+
+func inTxWithOptions(ctx context.Context, t transactor, d dialect, opts *sql.TxOptions, fn func(q querier, d dialect) error) error {
+	tx, err := t.begin(ctx, opts)
 	if err != nil {
 		return err
 	}
@@ -85,34 +93,109 @@ type sqlGateway struct {
 	InsideTX   bool
 	MaxRetries int
 
-	queryLogger db.Logger
+	queryLogger  db.Logger
+	queryTimeout time.Duration
+	dryRun       bool
+	tracer       trace.Tracer // see db.Config.TracerProvider
+	ro           querier      // read replica serving Select and Query outside of the transactions, see db.Config.ReadConnString
+}
+
+// reader returns the querier of the read-only queries, the read replica if it's configured and the gateway is not in the transaction
+func (g *sqlGateway) reader() querier {
+	if g.ro == nil || g.InsideTX {
+		return g.rw
+	}
+
+	return g.ro
 }
 
 type esSession struct {
 	sqlGateway
 	t transactor
+
+	dbCtx                *db.Context
+	deadlockRetries      int
+	maxRetries           int           // see db.Config.MaxRetries
+	retryBaseDelay       time.Duration // see db.Config.RetryBaseDelay
+	retryLogger          db.Logger
 }
 
-func (s *esSession) Transact(fn func(tx db.DatabaseAccessor) error) error {
-	var err error
-	var maxRetries = s.MaxRetries
-	if maxRetries == 0 {
-		maxRetries = 10
+// retryBackoffMax is the upper bound of the transaction retry delay set by db.Config.RetryBaseDelay
+const retryBackoffMax = 30 * time.Second
+
+// retryBackoff returns base * 2^attempt delay capped at retryBackoffMax
+func retryBackoff(base time.Duration, attempt int) time.Duration {
+	var delay = base
+	for i := 0; i < attempt && delay < retryBackoffMax; i++ {
+		delay *= 2
 	}
 
-	for i := 0; i < maxRetries; i++ {
-		err = inTx(s.ctx, s.t, s.dialect, func(q querier, dl dialect) error {
-			gw := sqlGateway{s.ctx, q, dl, true, s.MaxRetries, s.queryLogger}
+	return min(delay, retryBackoffMax)
+}
+
+// deadlockBackoff returns a short random delay before the retry of a deadlocked transaction
+func deadlockBackoff() time.Duration {
+	return time.Duration(1+rand.Intn(10)) * time.Millisecond //nolint:gosec
+}
+
+func (s *esSession) Transact(fn func(tx db.DatabaseAccessor) error) error {
+	return s.transact(nil, fn)
+}
+
+func (s *esSession) RunInReadOnlyTransaction(fn func(tx db.DatabaseAccessor) error) error {
+	return s.transact(s.dialect.readOnlyTxOptions(), fn)
+}
+
+func (s *esSession) transact(opts *sql.TxOptions, fn func(tx db.DatabaseAccessor) error) (err error) {
+	// the queries of the transaction are traced as the children of the transaction span
+	var ctx, span = s.startSpan(s.ctx, "TRANSACTION", "")
+	defer func() { endSpan(span, err) }()
+
+	for i := 0; ; i++ {
+		err = inTxWithOptions(ctx, s.t, s.dialect, opts, func(q querier, dl dialect) error {
+			gw := sqlGateway{ctx, q, dl, true, s.MaxRetries, s.queryLogger, s.queryTimeout, s.dryRun, s.tracer, nil}
 			return fn(&gw) // bad but will work for now?
 		})
 
-		if !s.dialect.isRetriable(err) {
+		if !s.dialect.isRetriable(err) || i+1 >= s.maxAttempts(err) {
 			break
 		}
+
+		s.dbCtx.TxRetries++
+
+		var delay time.Duration
+		switch {
+		case s.retryBaseDelay > 0:
+			delay = retryBackoff(s.retryBaseDelay, i)
+		case isSerializationFailure(err):
+			delay = serializationBackoff(i)
+		case s.deadlockRetries > 0:
+			delay = deadlockBackoff()
+		}
+
+		if s.retryLogger != nil {
+			s.retryLogger.Log("transaction retry #%d in %s: %v", i+1, delay, err)
+		}
+		if span != nil {
+			span.AddEvent("retry", trace.WithAttributes(attribute.Int("retry", i+1), attribute.String("error", err.Error())))
+		}
+		time.Sleep(delay)
 	}
 	return err
 }
 
+// maxAttempts returns the number of the transaction attempts made on the retriable error
+func (s *esSession) maxAttempts(err error) int {
+	switch {
+	case s.maxRetries > 0:
+		return s.maxRetries + 1
+	case s.deadlockRetries > 0:
+		return s.deadlockRetries + 1
+	default:
+		return 10
+	}
+}
+
 // database is a wrapper for DB connection
 type sqlDatabase struct {
 	rw          accessor
@@ -123,10 +206,47 @@ type sqlDatabase struct {
 	queryLogger      db.Logger
 	readedRowsLogger db.Logger
 	queryTimeLogger  db.Logger
+	queryRecorder    db.QueryRecorder
+
+	queryTimeout         time.Duration
+	deadlockRetries      int
+	maxRetries           int
+	retryBaseDelay       time.Duration
+	retryLogger          db.Logger
+	dryRun               bool
+	tracer               trace.Tracer
+	replica              *sqlDatabase // see db.Config.ReadConnString
 
 	lastQuery string
 }
 
+// withReadReplica opens the read replica pool of db.Config.ReadConnString, if any, used by the sessions of dbo
+func withReadReplica(cfg db.Config, dbo *sqlDatabase) (db.Database, error) {
+	if cfg.ReadConnString == "" {
+		return dbo, nil
+	}
+
+	var replicaCfg = cfg
+	replicaCfg.ConnString, replicaCfg.ReadConnString = cfg.ReadConnString, ""
+
+	var replica, err = db.Open(replicaCfg)
+	if err != nil {
+		dbo.Close() //nolint:errcheck
+		return nil, fmt.Errorf("db: cannot open read replica, err: %v", err)
+	}
+
+	var sqlReplica, ok = replica.(*sqlDatabase)
+	if !ok || sqlReplica.dialect.name() != dbo.dialect.name() {
+		replica.Close() //nolint:errcheck
+		dbo.Close()     //nolint:errcheck
+		return nil, fmt.Errorf("db: read replica at %v is not %s database", sanitizeConn(cfg.ReadConnString), dbo.dialect.name())
+	}
+
+	dbo.replica = sqlReplica
+
+	return dbo, nil
+}
+
 // Ping pings the DB
 func (d *sqlDatabase) Ping(ctx context.Context) error {
 	var err = d.rw.ping(ctx)
@@ -185,6 +305,12 @@ func (d *sqlDatabase) CreateIndex(indexName string, tableName string, columns []
 	})
 }
 
+func (d *sqlDatabase) CreateUniqueIndex(indexName string, tableName string, columns []string) error {
+	return inTx(context.Background(), d.t, d.dialect, func(q querier, dia dialect) error {
+		return createUniqueIndex(q, dia, indexName, tableName, columns)
+	})
+}
+
 func (d *sqlDatabase) DropIndex(indexName string, tableName string) error {
 	return inTx(context.Background(), d.t, d.dialect, func(q querier, dia dialect) error {
 		return dropIndex(q, dia, indexName, tableName)
@@ -219,6 +345,45 @@ func (d *sqlDatabase) DropSequence(sequenceName string) error {
 	})
 }
 
+func (d *sqlDatabase) CreateSchema(schemaName string) error {
+	return inTx(context.Background(), d.t, d.dialect, func(q querier, dia dialect) error {
+		return createSchema(q, dia, schemaName)
+	})
+}
+
+func (d *sqlDatabase) DropSchema(schemaName string) error {
+	return inTx(context.Background(), d.t, d.dialect, func(q querier, dia dialect) error {
+		return dropSchema(q, dia, schemaName)
+	})
+}
+
+func (d *sqlDatabase) Defragment(tableName string) error {
+	var start = time.Now()
+	if err := defragment(d.rw, d.dialect, tableName); err != nil {
+		return err
+	}
+
+	if d.queryTimeLogger != nil {
+		d.queryTimeLogger.Log("defragment of table %s took %.3f sec", tableName, time.Since(start).Seconds())
+	}
+
+	return nil
+}
+
+func (d *sqlDatabase) RunInSchemaSandbox(schemaName string, fn func() error) error {
+	if err := d.CreateSchema(schemaName); err != nil {
+		return fmt.Errorf("create schema %s failed: %w", schemaName, err)
+	}
+
+	var fnErr = fn()
+
+	if err := d.DropSchema(schemaName); err != nil {
+		return fmt.Errorf("drop schema %s failed: %w", schemaName, err)
+	}
+
+	return fnErr
+}
+
 func (d *sqlDatabase) GetTablesSchemaInfo(tableNames []string) ([]string, error) {
 	return getTablesSchemaInfo(d.rw, d.dialect, tableNames)
 }
@@ -227,19 +392,44 @@ func (d *sqlDatabase) GetTablesVolumeInfo(tableNames []string) ([]string, error)
 	return getTablesVolumeInfo(d.rw, d.dialect, tableNames)
 }
 
+func (d *sqlDatabase) GetIndexUsageStats(tableName string) ([]db.IndexUsageStat, error) {
+	return getIndexUsageStats(d.rw, d.dialect, tableName)
+}
+
+func (d *sqlDatabase) GetLockWaitStats() ([]db.LockWaitStat, error) {
+	return getLockWaitStats(d.rw, d.dialect)
+}
+
+func (d *sqlDatabase) GetActiveQueries() ([]db.ActiveQuery, error) {
+	return getActiveQueries(d.rw, d.dialect)
+}
+
+func (d *sqlDatabase) GetCheckpointStats() (db.CheckpointStats, error) {
+	return getCheckpointStats(d.rw, d.dialect)
+}
+
 func accountTime(t *atomic.Int64, since time.Time) {
 	t.Add(time.Since(since).Nanoseconds())
 }
 
+// recordQuery passes the query executed since given time to the recorder, if any
+func recordQuery(r db.QueryRecorder, query string, args []interface{}, since time.Time) {
+	if r != nil {
+		r.RecordQuery(query, args, time.Since(since))
+	}
+}
+
 type timedQuerier struct {
 	dbtime *atomic.Int64 // Do not move
 	q      querier
 
-	queryLogger db.Logger
+	queryLogger   db.Logger
+	queryRecorder db.QueryRecorder
 }
 
 func (tq timedQuerier) execContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
 	defer accountTime(tq.dbtime, time.Now())
+	defer recordQuery(tq.queryRecorder, query, args, time.Now())
 
 	if tq.queryLogger != nil {
 		tq.queryLogger.Log(query)
@@ -250,6 +440,7 @@ func (tq timedQuerier) execContext(ctx context.Context, query string, args ...in
 
 func (tq timedQuerier) queryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
 	defer accountTime(tq.dbtime, time.Now())
+	defer recordQuery(tq.queryRecorder, query, args, time.Now())
 
 	if tq.queryLogger != nil {
 		tq.queryLogger.Log(query)
@@ -260,6 +451,7 @@ func (tq timedQuerier) queryRowContext(ctx context.Context, query string, args .
 
 func (tq timedQuerier) queryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
 	defer accountTime(tq.dbtime, time.Now())
+	defer recordQuery(tq.queryRecorder, query, args, time.Now())
 
 	if tq.queryLogger != nil {
 		tq.queryLogger.Log(query, args...)
@@ -283,11 +475,13 @@ type timedTransaction struct {
 	committime *atomic.Int64 // *time.Duration
 	tx         transaction
 
-	queryLogger db.Logger
+	queryLogger   db.Logger
+	queryRecorder db.QueryRecorder
 }
 
 func (ttx timedTransaction) execContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
 	defer accountTime(ttx.dbtime, time.Now())
+	defer recordQuery(ttx.queryRecorder, query, args, time.Now())
 
 	if ttx.queryLogger != nil {
 		ttx.queryLogger.Log(query)
@@ -298,6 +492,7 @@ func (ttx timedTransaction) execContext(ctx context.Context, query string, args
 
 func (ttx timedTransaction) queryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
 	defer accountTime(ttx.dbtime, time.Now())
+	defer recordQuery(ttx.queryRecorder, query, args, time.Now())
 
 	if ttx.queryLogger != nil {
 		ttx.queryLogger.Log(query)
@@ -308,6 +503,7 @@ func (ttx timedTransaction) queryRowContext(ctx context.Context, query string, a
 
 func (ttx timedTransaction) queryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
 	defer accountTime(ttx.dbtime, time.Now())
+	defer recordQuery(ttx.queryRecorder, query, args, time.Now())
 
 	if ttx.queryLogger != nil {
 		ttx.queryLogger.Log(query)
@@ -352,27 +548,29 @@ type timedTransactor struct {
 	committime *atomic.Int64
 	t          transactor
 
-	queryLogger db.Logger
+	queryLogger   db.Logger
+	queryRecorder db.QueryRecorder
 }
 
-func (tt timedTransactor) begin(ctx context.Context) (transaction, error) {
+func (tt timedTransactor) begin(ctx context.Context, opts *sql.TxOptions) (transaction, error) {
 	defer accountTime(tt.begintime, time.Now())
 
 	if tt.queryLogger != nil {
 		tt.queryLogger.Log("BEGIN")
 	}
 
-	var t, err = tt.t.begin(ctx)
+	var t, err = tt.t.begin(ctx, opts)
 
 	if err != nil {
 		return t, err
 	}
 
 	return timedTransaction{
-		tx:          t,
-		dbtime:      atomic.NewInt64(tt.dbtime.Load()),
-		committime:  atomic.NewInt64(tt.committime.Load()),
-		queryLogger: tt.queryLogger,
+		tx:            t,
+		dbtime:        atomic.NewInt64(tt.dbtime.Load()),
+		committime:    atomic.NewInt64(tt.committime.Load()),
+		queryLogger:   tt.queryLogger,
+		queryRecorder: tt.queryRecorder,
 	}, nil
 }
 
@@ -381,21 +579,38 @@ func (d *sqlDatabase) Context(ctx context.Context) *db.Context {
 }
 
 func (d *sqlDatabase) Session(c *db.Context) db.Session {
+	var dbtime = atomic.NewInt64(c.DBtime.Nanoseconds())
+
+	var ro querier
+	if d.replica != nil {
+		ro = timedQuerier{q: d.replica.rw, dbtime: dbtime, queryLogger: d.queryLogger, queryRecorder: d.queryRecorder}
+	}
+
 	return &esSession{
 		sqlGateway: sqlGateway{
-			ctx:         c.Ctx,
-			rw:          timedQuerier{q: d.rw, dbtime: atomic.NewInt64(c.DBtime.Nanoseconds()), queryLogger: d.queryLogger},
-			dialect:     d.dialect,
-			InsideTX:    false,
-			queryLogger: d.queryLogger,
+			ctx:          c.Ctx,
+			rw:           timedQuerier{q: d.rw, dbtime: dbtime, queryLogger: d.queryLogger, queryRecorder: d.queryRecorder},
+			ro:           ro,
+			dialect:      d.dialect,
+			InsideTX:     false,
+			queryLogger:  d.queryLogger,
+			queryTimeout: d.queryTimeout,
+			dryRun:       d.dryRun,
+			tracer:       d.tracer,
 		},
 		t: timedTransactor{
-			t:           d.t,
-			begintime:   atomic.NewInt64(c.BeginTime.Nanoseconds()),
-			dbtime:      atomic.NewInt64(c.DBtime.Nanoseconds()),
-			committime:  atomic.NewInt64(c.CommitTime.Nanoseconds()),
-			queryLogger: d.queryLogger,
+			t:             d.t,
+			begintime:     atomic.NewInt64(c.BeginTime.Nanoseconds()),
+			dbtime:        atomic.NewInt64(c.DBtime.Nanoseconds()),
+			committime:    atomic.NewInt64(c.CommitTime.Nanoseconds()),
+			queryLogger:   d.queryLogger,
+			queryRecorder: d.queryRecorder,
 		},
+		dbCtx:                c,
+		deadlockRetries:      d.deadlockRetries,
+		maxRetries:           d.maxRetries,
+		retryBaseDelay:       d.retryBaseDelay,
+		retryLogger:          d.retryLogger,
 	}
 }
 
@@ -412,16 +627,35 @@ func (d *sqlDatabase) RawSession() interface{} {
 
 func (d *sqlDatabase) Stats() *db.Stats {
 	sqlStats := d.rw.stats()
-	return &db.Stats{OpenConnections: sqlStats.OpenConnections, Idle: sqlStats.Idle, InUse: sqlStats.InUse}
+	var stats = &db.Stats{
+		MaxOpenConnections: sqlStats.MaxOpenConnections,
+		OpenConnections:    sqlStats.OpenConnections,
+		Idle:               sqlStats.Idle,
+		InUse:              sqlStats.InUse,
+		WaitCount:          sqlStats.WaitCount,
+	}
+
+	if d.replica != nil {
+		stats.Read = d.replica.Stats()
+	}
+
+	return stats
 }
 
+// Close closes the read replica pool, if any, and the primary one, the primary pool is closed even if the replica close fails
 func (d *sqlDatabase) Close() error {
-	var err = d.rw.close()
-	if err != nil {
-		return fmt.Errorf("close failed: %w", err)
+	var replicaErr error
+	if d.replica != nil {
+		if err := d.replica.Close(); err != nil {
+			replicaErr = fmt.Errorf("read replica close failed: %w", err)
+		}
+	}
+
+	if err := d.rw.close(); err != nil {
+		return errors.Join(replicaErr, fmt.Errorf("close failed: %w", err))
 	}
 
-	return d.dialect.close()
+	return errors.Join(replicaErr, d.dialect.close())
 }
 
 type dialect interface {
@@ -435,7 +669,9 @@ type dialect interface {
 	getType(dataType db.DataType) string
 	randFunc() string
 	isRetriable(err error) bool
+	isUniqueViolation(err error) bool
 	canRollback(err error) bool
+	readOnlyTxOptions() *sql.TxOptions
 	table(table string) string
 	schema() string
 	recommendations() []db.Recommendation
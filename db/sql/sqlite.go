@@ -2,6 +2,7 @@ package sql
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
 	"path/filepath"
 	"strings"
@@ -9,7 +10,7 @@ import (
 
 	"github.com/google/uuid"
 
-	_ "github.com/mattn/go-sqlite3" // sqlite3 driver
+	"github.com/mattn/go-sqlite3" // sqlite3 driver
 
 	"github.com/acronis/perfkit/db"
 )
@@ -122,6 +123,10 @@ func (d *sqliteDialect) getType(id db.DataType) string {
 		return "null"
 	case db.DataTypeTenantUUIDBoundID:
 		return "TEXT"
+	case db.DataTypeJSON, db.DataTypeJSONB, db.DataTypeJSONText:
+		return "TEXT"
+	case db.DataTypeInet, db.DataTypeCidr:
+		return "VARCHAR(45)"
 	default:
 		return ""
 	}
@@ -135,10 +140,23 @@ func (d *sqliteDialect) isRetriable(err error) bool {
 	return false
 }
 
+// isUniqueViolation returns true if the error is caused by PRIMARY KEY / UNIQUE constraint or unique index violation
+func (d *sqliteDialect) isUniqueViolation(err error) bool {
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &sqliteErr) {
+		return sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique || sqliteErr.ExtendedCode == sqlite3.ErrConstraintPrimaryKey
+	}
+	return false
+}
+
 func (d *sqliteDialect) canRollback(err error) bool {
 	return true
 }
 
+func (d *sqliteDialect) readOnlyTxOptions() *sql.TxOptions {
+	return &sql.TxOptions{ReadOnly: true}
+}
+
 func (d *sqliteDialect) table(table string) string {
 	return table
 }
@@ -178,7 +196,7 @@ func (c *sqliteConnector) ConnectionPool(cfg db.Config) (db.Database, error) {
 	dbo := &sqlDatabase{}
 	var rwc *sql.DB
 
-	if rwc, err = sql.Open("sqlite3", path); err != nil {
+	if rwc, err = openDB("sqlite3", path, &dia, cfg); err != nil {
 		return nil, fmt.Errorf("db: cannot open sqlite db at %v, err: %v", sanitizeConn(cfg.ConnString), err)
 	}
 
@@ -205,8 +223,16 @@ func (c *sqliteConnector) ConnectionPool(cfg db.Config) (db.Database, error) {
 
 	dbo.dialect = &dia
 	dbo.queryLogger = cfg.QueryLogger
-
-	return dbo, nil
+	dbo.queryRecorder = cfg.QueryRecorder
+	dbo.queryTimeout = cfg.QueryTimeout
+	dbo.dryRun = cfg.DryRun
+	dbo.deadlockRetries = cfg.DeadlockRetries
+	dbo.maxRetries = cfg.MaxRetries
+	dbo.retryBaseDelay = cfg.RetryBaseDelay
+	dbo.retryLogger = cfg.RetryLogger
+	dbo.tracer = newTracer(cfg.TracerProvider)
+
+	return withReadReplica(cfg, dbo)
 }
 
 func (c *sqliteConnector) DialectName(scheme string) (db.DialectName, error) {
@@ -0,0 +1,86 @@
+package sql
+
+import (
+	"context"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/acronis/perfkit/db"
+)
+
+// tracerName is the instrumentation scope name of the query spans
+const tracerName = "github.com/acronis/perfkit/db/sql"
+
+// newTracer returns the tracer of the query spans, or nil if the tracing is not configured by db.Config.TracerProvider
+func newTracer(tp trace.TracerProvider) trace.Tracer {
+	if tp == nil {
+		return nil
+	}
+
+	return tp.Tracer(tracerName)
+}
+
+// dbSystem returns the OpenTelemetry db.system attribute of the dialect
+func dbSystem(name db.DialectName) attribute.KeyValue {
+	switch name {
+	case db.POSTGRES:
+		return semconv.DBSystemPostgreSQL
+	case db.COCKROACHDB:
+		return semconv.DBSystemCockroachdb
+	case db.MYSQL:
+		return semconv.DBSystemMySQL
+	case db.MSSQL:
+		return semconv.DBSystemMSSQL
+	case db.SQLITE, db.SQLITE3:
+		return semconv.DBSystemSqlite
+	case db.CLICKHOUSE:
+		return semconv.DBSystemClickhouse
+	case db.CASSANDRA:
+		return semconv.DBSystemCassandra
+	default:
+		return semconv.DBSystemOtherSQL
+	}
+}
+
+// queryOperation returns the first keyword of the query (SELECT, INSERT, etc.) used as the db.operation attribute
+func queryOperation(query string) string {
+	var fields = strings.Fields(query)
+	if len(fields) == 0 {
+		return ""
+	}
+
+	return strings.ToUpper(fields[0])
+}
+
+// startSpan starts the client span of the operation as a child of the span of ctx, if any,
+// the span is named by the operation following the OpenTelemetry DB semantic conventions;
+// nil span is returned if the tracing is not configured
+func (g *sqlGateway) startSpan(ctx context.Context, operation string, query string) (context.Context, trace.Span) {
+	if g.tracer == nil {
+		return ctx, nil
+	}
+
+	var attrs = []attribute.KeyValue{dbSystem(g.dialect.name()), semconv.DBOperation(operation)}
+	if query != "" {
+		attrs = append(attrs, semconv.DBStatement(query))
+	}
+
+	return g.tracer.Start(ctx, operation, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+}
+
+// endSpan records the error of the operation, if any, and ends the span started by startSpan
+func endSpan(span trace.Span, err error) {
+	if span == nil {
+		return
+	}
+
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,96 @@
+package sql
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/embedded"
+	"go.opentelemetry.io/otel/trace/noop"
+
+	"github.com/acronis/perfkit/db"
+)
+
+type testSpan struct {
+	name   string
+	parent string
+	attrs  map[attribute.Key]string
+}
+
+// testTracer records the started spans, the span name is passed as the parent to the children by the context
+type testTracer struct {
+	embedded.Tracer
+	spans []testSpan
+}
+
+type testSpanKey struct{}
+
+func (t *testTracer) Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	var span = testSpan{name: name, attrs: make(map[attribute.Key]string)}
+	span.parent, _ = ctx.Value(testSpanKey{}).(string)
+	var cfg = trace.NewSpanStartConfig(opts...)
+	for _, kv := range cfg.Attributes() {
+		span.attrs[kv.Key] = kv.Value.Emit()
+	}
+	t.spans = append(t.spans, span)
+
+	return context.WithValue(ctx, testSpanKey{}, name), noop.Span{}
+}
+
+type testTracerProvider struct {
+	embedded.TracerProvider
+	tracer *testTracer
+}
+
+func (p *testTracerProvider) Tracer(string, ...trace.TracerOption) trace.Tracer {
+	return p.tracer
+}
+
+func TestSessionTracing(t *testing.T) {
+	var tracer = &testTracer{}
+	var d, err = db.Open(db.Config{ConnString: sqliteConnString, MaxOpenConns: 1, TracerProvider: &testTracerProvider{tracer: tracer}})
+	if err != nil {
+		t.Fatalf("db.Open() error: %v", err)
+	}
+	defer d.Close() //nolint:errcheck
+
+	var s = d.Session(d.Context(context.WithValue(context.Background(), testSpanKey{}, "benchmark")))
+	if _, err = s.Exec("CREATE TABLE traced (id int)"); err != nil {
+		t.Fatalf("Exec() error: %v", err)
+	}
+
+	if err = s.Transact(func(tx db.DatabaseAccessor) error {
+		var _, txErr = tx.Exec("INSERT INTO traced (id) VALUES (1)")
+		return txErr
+	}); err != nil {
+		t.Fatalf("Transact() error: %v", err)
+	}
+
+	var id int
+	if err = s.QueryRow("select id FROM traced").Scan(&id); err != nil {
+		t.Fatalf("QueryRow() error: %v", err)
+	}
+
+	var expected = []testSpan{
+		{name: "CREATE", parent: "benchmark", attrs: map[attribute.Key]string{"db.system": "sqlite", "db.operation": "CREATE", "db.statement": "CREATE TABLE traced (id int)"}},
+		{name: "TRANSACTION", parent: "benchmark", attrs: map[attribute.Key]string{"db.system": "sqlite", "db.operation": "TRANSACTION"}},
+		{name: "INSERT", parent: "TRANSACTION", attrs: map[attribute.Key]string{"db.system": "sqlite", "db.operation": "INSERT", "db.statement": "INSERT INTO traced (id) VALUES (1)"}},
+		{name: "SELECT", parent: "benchmark", attrs: map[attribute.Key]string{"db.system": "sqlite", "db.operation": "SELECT", "db.statement": "select id FROM traced"}},
+	}
+
+	if len(tracer.spans) != len(expected) {
+		t.Fatalf("%d spans started, expected %d: %+v", len(tracer.spans), len(expected), tracer.spans)
+	}
+
+	for i, span := range tracer.spans {
+		if span.name != expected[i].name || span.parent != expected[i].parent {
+			t.Errorf("span #%d is %s with parent %s, expected %s with parent %s", i, span.name, span.parent, expected[i].name, expected[i].parent)
+		}
+		for key, value := range expected[i].attrs {
+			if span.attrs[key] != value {
+				t.Errorf("span #%d attribute %s = %q, expected %q", i, key, span.attrs[key], value)
+			}
+		}
+	}
+}
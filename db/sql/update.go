@@ -0,0 +1,95 @@
+package sql
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/acronis/perfkit/db"
+)
+
+func (b selectBuilder) sqlUpdate(d dialect, c *db.UpdateCtrl) (string, bool, error) {
+	if len(c.Set) == 0 {
+		return "", false, fmt.Errorf("empty update set")
+	}
+
+	if len(c.Returning) != 0 && d.name() != db.POSTGRES && d.name() != db.COCKROACHDB {
+		return "", false, fmt.Errorf("RETURNING is not supported for %s dialect", d.name())
+	}
+
+	var columns = make([]string, 0, len(c.Set))
+	for col := range c.Set {
+		if col == "" {
+			return "", false, fmt.Errorf("empty update field")
+		}
+		columns = append(columns, col)
+	}
+	sort.Strings(columns)
+
+	var assignments = make([]string, 0, len(columns))
+	var args = make([]interface{}, 0, len(columns))
+	for _, col := range columns {
+		assignments = append(assignments, col+" = %v")
+		args = append(args, c.Set[col])
+	}
+
+	var where, whereArgs, empty, err = b.sqlConditions(d, c.OptimizeConditions, c.Where)
+	if err != nil {
+		return "", false, err
+	}
+
+	if empty {
+		return "", true, nil
+	}
+
+	var returning string
+	if len(c.Returning) != 0 {
+		for _, col := range c.Returning {
+			if col == "" {
+				return "", false, fmt.Errorf("empty returning field")
+			}
+		}
+		returning = "RETURNING " + strings.Join(c.Returning, ", ")
+	}
+
+	var qry = fmt.Sprintf("UPDATE %s SET %s %s %s", d.table(b.tableName), strings.Join(assignments, ", "), where, returning)
+
+	return sqlf(d, qry, append(args, whereArgs...)...), false, nil
+}
+
+// UpdateReturning updates rows matching the conditions and returns the columns listed in UpdateCtrl.Returning
+// (PostgreSQL only), if no returning columns requested the update is executed and empty rows are returned
+func (g *sqlGateway) UpdateReturning(tableName string, uc *db.UpdateCtrl) (db.Rows, error) {
+	var queryBuilder, ok = tableQueryBuilders[tableName]
+	if !ok {
+		return nil, fmt.Errorf("table %s is not supported", tableName)
+	}
+
+	var query, empty, err = queryBuilder.sqlUpdate(g.dialect, uc)
+	if err != nil {
+		return nil, err
+	}
+
+	if empty {
+		return &db.EmptyRows{}, nil
+	}
+
+	var ctx, cancel = g.queryCtx()
+	if len(uc.Returning) == 0 {
+		defer cancel()
+		if _, err = g.rw.execContext(ctx, query); err != nil {
+			return nil, queryErr(ctx, err)
+		}
+
+		return &db.EmptyRows{}, nil
+	}
+
+	var rows *sql.Rows
+	if rows, err = g.rw.queryContext(ctx, query); err != nil {
+		cancel()
+		return nil, queryErr(ctx, err)
+	}
+
+	return &sqlRows{rows: rows, ctx: ctx, cancel: cancel}, nil
+}
@@ -0,0 +1,35 @@
+package sql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/acronis/perfkit/db"
+)
+
+func TestSqlUpdateReturning(t *testing.T) {
+	var b = selectBuilder{
+		tableName: "perf_table",
+		queryable: map[string]filterFunction{"id": idCond()},
+	}
+
+	var c = &db.UpdateCtrl{
+		Set:       map[string]interface{}{"progress": 10, "completion_time_ns": int64(42)},
+		Where:     map[string][]string{"id": {"1"}},
+		Returning: []string{"completion_time_ns"},
+	}
+
+	var qry, empty, err = b.sqlUpdate(&pgDialect{}, c)
+	require.NoError(t, err)
+	require.False(t, empty)
+	require.Equal(t, "UPDATE perf_table SET completion_time_ns = 42, progress = 10 WHERE perf_table.id = 1 RETURNING completion_time_ns", qry)
+
+	_, _, err = b.sqlUpdate(&mysqlDialect{}, c)
+	require.EqualError(t, err, "RETURNING is not supported for mysql dialect")
+
+	c.Returning = nil
+	qry, _, err = b.sqlUpdate(&mysqlDialect{}, c)
+	require.NoError(t, err)
+	require.Equal(t, "UPDATE perf_table SET completion_time_ns = 42, progress = 10 WHERE perf_table.id = 1 ", qry)
+}
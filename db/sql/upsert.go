@@ -0,0 +1,126 @@
+package sql
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/acronis/perfkit/db"
+)
+
+// sqlUpsert returns the dialect specific insert-or-update query:
+// INSERT ... ON CONFLICT (...) DO UPDATE SET ... for PostgreSQL, CockroachDB and SQLite,
+// INSERT ... ON DUPLICATE KEY UPDATE ... for MySQL and MERGE ... for MSSQL
+func sqlUpsert(d dialect, tableName string, rows [][]interface{}, columnNames []string, conflictColumns []string, updateColumns []string) (string, error) {
+	if len(columnNames) == 0 {
+		return "", fmt.Errorf("empty upsert columns")
+	}
+
+	if len(conflictColumns) == 0 {
+		return "", fmt.Errorf("empty upsert conflict columns")
+	}
+
+	var values = make([]string, 0, len(rows))
+	for _, row := range rows {
+		if len(row) != len(columnNames) {
+			return "", fmt.Errorf("row length doesn't match column names length")
+		}
+		var valuesInRow = make([]string, 0, len(row))
+		for _, col := range row {
+			valuesInRow = append(valuesInRow, sqlf(d, "%v", col))
+		}
+		values = append(values, fmt.Sprintf("(%s)", strings.Join(valuesInRow, ", ")))
+	}
+
+	var table, columns = d.table(tableName), strings.Join(columnNames, ", ")
+
+	switch d.name() {
+	case db.POSTGRES, db.COCKROACHDB, db.SQLITE:
+		var action = "DO NOTHING"
+		if len(updateColumns) != 0 {
+			var sets = make([]string, 0, len(updateColumns))
+			for _, col := range updateColumns {
+				sets = append(sets, fmt.Sprintf("%s = EXCLUDED.%s", col, col))
+			}
+			action = "DO UPDATE SET " + strings.Join(sets, ", ")
+		}
+
+		return fmt.Sprintf("INSERT INTO %s(%s) VALUES %s ON CONFLICT (%s) %s;",
+			table, columns, strings.Join(values, ", "), strings.Join(conflictColumns, ", "), action), nil
+
+	case db.MYSQL:
+		// MySQL finds the conflicts by any unique key, so the conflict columns are not referred by the query
+		if len(updateColumns) == 0 {
+			return fmt.Sprintf("INSERT IGNORE INTO %s(%s) VALUES %s;", table, columns, strings.Join(values, ", ")), nil
+		}
+
+		var sets = make([]string, 0, len(updateColumns))
+		for _, col := range updateColumns {
+			sets = append(sets, fmt.Sprintf("%s = VALUES(%s)", col, col))
+		}
+
+		return fmt.Sprintf("INSERT INTO %s(%s) VALUES %s ON DUPLICATE KEY UPDATE %s;",
+			table, columns, strings.Join(values, ", "), strings.Join(sets, ", ")), nil
+
+	case db.MSSQL:
+		var on = make([]string, 0, len(conflictColumns))
+		for _, col := range conflictColumns {
+			on = append(on, fmt.Sprintf("target.%s = source.%s", col, col))
+		}
+
+		var matched string
+		if len(updateColumns) != 0 {
+			var sets = make([]string, 0, len(updateColumns))
+			for _, col := range updateColumns {
+				sets = append(sets, fmt.Sprintf("target.%s = source.%s", col, col))
+			}
+			matched = " WHEN MATCHED THEN UPDATE SET " + strings.Join(sets, ", ")
+		}
+
+		var sourceColumns = make([]string, 0, len(columnNames))
+		for _, col := range columnNames {
+			sourceColumns = append(sourceColumns, "source."+col)
+		}
+
+		// HOLDLOCK makes MERGE safe for the concurrent upserts of the same key
+		return fmt.Sprintf("MERGE INTO %s WITH (HOLDLOCK) AS target USING (VALUES %s) AS source (%s) ON %s%s WHEN NOT MATCHED THEN INSERT (%s) VALUES (%s);",
+			table, strings.Join(values, ", "), columns, strings.Join(on, " AND "), matched, columns, strings.Join(sourceColumns, ", ")), nil
+
+	default:
+		return "", fmt.Errorf("upsert is not supported for %s dialect", d.name())
+	}
+}
+
+// Upsert inserts the rows updating the conflicting ones, Cassandra INSERT overwrites the existing rows by itself,
+// the query is only logged in the dry-run mode
+func (g *sqlGateway) Upsert(tableName string, rows [][]interface{}, columnNames []string, conflictColumns []string, updateColumns []string) error {
+	if len(rows) == 0 {
+		return nil
+	}
+
+	if g.dialect.name() == db.CASSANDRA {
+		return g.BulkInsert(tableName, &db.BulkInsertCtrl{Rows: rows, ColumnNames: columnNames})
+	}
+
+	var query, err = sqlUpsert(g.dialect, tableName, rows, columnNames, conflictColumns, updateColumns)
+	if err != nil {
+		return err
+	}
+
+	if g.dryRun {
+		if g.queryLogger != nil {
+			g.queryLogger.Log("dry-run: %s", query)
+		}
+		return nil
+	}
+
+	var ctx, cancel = g.queryCtx()
+	defer cancel()
+
+	_, err = g.rw.execContext(ctx, query)
+
+	if err = uniqueViolationErr(g.dialect, queryErr(ctx, err)); err != nil {
+		return fmt.Errorf("DB exec failed: %w", err)
+	}
+
+	return nil
+}
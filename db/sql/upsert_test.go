@@ -0,0 +1,36 @@
+package sql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSqlUpsert(t *testing.T) {
+	var rows = [][]interface{}{{1, "a"}, {2, "b"}}
+	var columns = []string{"id", "name"}
+
+	var qry, err = sqlUpsert(&pgDialect{}, "perf_table", rows, columns, []string{"id"}, []string{"name"})
+	require.NoError(t, err)
+	require.Equal(t, "INSERT INTO perf_table(id, name) VALUES (1, 'a'), (2, 'b') ON CONFLICT (id) DO UPDATE SET name = EXCLUDED.name;", qry)
+
+	qry, err = sqlUpsert(&sqliteDialect{}, "perf_table", rows, columns, []string{"id"}, nil)
+	require.NoError(t, err)
+	require.Equal(t, "INSERT INTO perf_table(id, name) VALUES (1, 'a'), (2, 'b') ON CONFLICT (id) DO NOTHING;", qry)
+
+	qry, err = sqlUpsert(&mysqlDialect{}, "perf_table", rows, columns, []string{"id"}, []string{"name"})
+	require.NoError(t, err)
+	require.Equal(t, "INSERT INTO perf_table(id, name) VALUES (1, 'a'), (2, 'b') ON DUPLICATE KEY UPDATE name = VALUES(name);", qry)
+
+	qry, err = sqlUpsert(&msDialect{}, "perf_table", rows, columns, []string{"id"}, []string{"name"})
+	require.NoError(t, err)
+	require.Equal(t, "MERGE INTO perf_table WITH (HOLDLOCK) AS target USING (VALUES (1, 'a'), (2, 'b')) AS source (id, name) "+
+		"ON target.id = source.id WHEN MATCHED THEN UPDATE SET target.name = source.name "+
+		"WHEN NOT MATCHED THEN INSERT (id, name) VALUES (source.id, source.name);", qry)
+
+	_, err = sqlUpsert(&clickHouseDialect{}, "perf_table", rows, columns, []string{"id"}, []string{"name"})
+	require.EqualError(t, err, "upsert is not supported for clickhouse dialect")
+
+	_, err = sqlUpsert(&pgDialect{}, "perf_table", [][]interface{}{{1}}, columns, []string{"id"}, nil)
+	require.EqualError(t, err, "row length doesn't match column names length")
+}
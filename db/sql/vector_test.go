@@ -58,10 +58,13 @@ func (suite *TestingSuite) TestVectorSearch() {
 	defer logDbTime(suite.T(), c)
 	defer vectorCleanup(suite.T(), d)
 
-	if err := s.BulkInsert("vector_perf_table", [][]interface{}{
-		{[]float32{1, 2, 3}},
-		{[]float32{4, 5, 6}},
-	}, []string{"embedding"}); err != nil {
+	if err := s.BulkInsert("vector_perf_table", &db.BulkInsertCtrl{
+		Rows: [][]interface{}{
+			{[]float32{1, 2, 3}},
+			{[]float32{4, 5, 6}},
+		},
+		ColumnNames: []string{"embedding"},
+	}); err != nil {
 		suite.T().Error(err)
 		return
 	}
@@ -88,3 +91,17 @@ func (suite *TestingSuite) TestVectorSearch() {
 		}
 	}
 }
+
+func TestPGVectorArbitraryDims(t *testing.T) {
+	var query = constructSQLDDLQuery(&pgDialect{}, "vector_perf_table", &db.TableDefinition{
+		TableRows: []db.TableRow{
+			{Name: "id", Type: db.DataTypeBigIntAutoIncPK},
+			{Name: "embedding", Type: db.DataTypeVectorFloat32, Dims: 1536},
+		},
+	})
+
+	var expected = "CREATE TABLE vector_perf_table (id BIGSERIAL PRIMARY KEY, embedding vector(1536))"
+	if query != expected {
+		t.Errorf("constructSQLDDLQuery() got = %v, want %v", query, expected)
+	}
+}